@@ -36,6 +36,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/cloudprober/cloudprober/common/tlsconfig"
 	"github.com/cloudprober/cloudprober/config"
@@ -74,7 +75,9 @@ const (
 )
 
 var (
-	disableSysMetrics = flag.Bool("disable_sys_metrics", false, "Disable system metrics probe")
+	disableSysMetrics    = flag.Bool("disable_sys_metrics", false, "Disable system metrics probe")
+	configReloadInterval = flag.Duration("config_reload_interval", 0, "If set, check config for changes at this interval and reload it if it has changed. Only probes, surfacers and servers are reloaded.")
+	enableReloadEndpoint = flag.Bool("enable_reload_endpoint", false, "Enable the /-/reload endpoint on the default HTTP server. POST requests to this endpoint reload the config. Note that this endpoint is not authenticated.")
 )
 
 // Global prober.Prober instance protected by a mutex.
@@ -85,6 +88,7 @@ var cloudProber struct {
 	configSource    config.ConfigSource
 	config          *configpb.ProberConfig
 	cancelInitCtx   context.CancelFunc
+	l               *logger.Logger
 	sync.RWMutex
 }
 
@@ -164,6 +168,34 @@ func setDebugHandlers(srvMux *http.ServeMux) {
 	srvMux.HandleFunc("/debug/pprof/trace", pprof.Trace)
 }
 
+// addDefaultSystemProbe adds the default system probe to the config on Linux,
+// unless system metrics are disabled or a system probe is already
+// configured.
+func addDefaultSystemProbe(cfg *configpb.ProberConfig) {
+	if runtime.GOOS != "linux" || *disableSysMetrics {
+		return
+	}
+
+	// Be careful about imports, we want to check if system probe is configured.
+	// We iterate over probes to see if any of them is a system probe.
+	for _, p := range cfg.GetProbe() {
+		if p.GetType() == probes_configpb.ProbeDef_SYSTEM {
+			return
+		}
+	}
+
+	// Add default system probe
+	cfg.Probe = append(cfg.Probe, &probes_configpb.ProbeDef{
+		Name:     proto.String("sys_metrics"),
+		Type:     probes_configpb.ProbeDef_SYSTEM.Enum(),
+		Interval: proto.String("10s"),
+		Timeout:  proto.String("5s"),
+		Probe: &probes_configpb.ProbeDef_SystemProbe{
+			SystemProbe: &system_configpb.ProbeConf{},
+		},
+	})
+}
+
 // InitFromConfig initializes Cloudprober using the provided config.
 // Deprecated: This function is kept only for compatibility reasons. It's
 // recommended to use Init() or InitWithConfigSource() instead.
@@ -209,30 +241,7 @@ func initWithConfigSource(configSrc config.ConfigSource) error {
 		return err
 	}
 
-	if runtime.GOOS == "linux" {
-		// Be careful about imports, we want to check if system probe is configured.
-		// We iterate over probes to see if any of them is a system probe.
-		sysProbeConfigured := false
-		for _, p := range cfg.GetProbe() {
-			if p.GetType() == probes_configpb.ProbeDef_SYSTEM {
-				sysProbeConfigured = true
-				break
-			}
-		}
-
-		if !*disableSysMetrics && !sysProbeConfigured {
-			// Add default system probe
-			cfg.Probe = append(cfg.Probe, &probes_configpb.ProbeDef{
-				Name:     proto.String("sys_metrics"),
-				Type:     probes_configpb.ProbeDef_SYSTEM.Enum(),
-				Interval: proto.String("10s"),
-				Timeout:  proto.String("5s"),
-				Probe: &probes_configpb.ProbeDef_SystemProbe{
-					SystemProbe: &system_configpb.ProbeConf{},
-				},
-			})
-		}
-	}
+	addDefaultSystemProbe(cfg)
 
 	globalLogger := logger.NewWithAttrs(slog.String("component", "global"))
 
@@ -299,21 +308,104 @@ func initWithConfigSource(configSrc config.ConfigSource) error {
 	cloudProber.defaultServerLn = ln
 	cloudProber.defaultGRPCLn = grpcLn
 	cloudProber.cancelInitCtx = cancelFunc
+	cloudProber.l = globalLogger
+
+	return nil
+}
+
+// ReloadConfig re-reads the config from the config source and applies it to
+// the running prober. Only probes, surfacers and servers that have changed
+// are restarted. If the new config can't be read or applied, an error is
+// returned and the prober keeps running with what it had.
+func ReloadConfig() error {
+	cloudProber.Lock()
+	defer cloudProber.Unlock()
+
+	if cloudProber.prober == nil {
+		return errors.New("cloudprober is not initialized")
+	}
+
+	cfg, err := cloudProber.configSource.GetConfig()
+	if err != nil {
+		return fmt.Errorf("error reading config: %v", err)
+	}
+	addDefaultSystemProbe(cfg)
+
+	if err := cloudProber.prober.Reload(cfg); err != nil {
+		return fmt.Errorf("error reloading config: %v", err)
+	}
+	cloudProber.config = cfg
+	cloudProber.l.Info("Config reloaded successfully")
 
 	return nil
 }
 
+// watchConfig checks the config source for changes at the given interval and
+// reloads the config when it changes.
+func watchConfig(ctx context.Context, cs config.ReloadableConfigSource, interval time.Duration, l *logger.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		cloudProber.RLock()
+		changed, err := cs.ConfigChanged(ctx)
+		cloudProber.RUnlock()
+		if err != nil {
+			l.Warningf("Error checking config for changes: %v", err)
+			continue
+		}
+		if !changed {
+			continue
+		}
+
+		l.Info("Config changed, reloading it")
+		if err := ReloadConfig(); err != nil {
+			l.Errorf("Config reload failed: %v", err)
+		}
+	}
+}
+
+// reloadHandler reloads the config on POST requests. It's registered only if
+// --enable_reload_endpoint is set.
+func reloadHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "config reload requires a POST request", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := ReloadConfig(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprintf(w, "OK")
+}
+
 // RunOnce runs requested probes once and print probe results to stdout.
 func RunOnce(ctx context.Context, names, format, indent string) error {
+	return RunOnceWithTargets(ctx, names, "", format, indent)
+}
+
+// RunOnceWithTargets is similar to RunOnce, but if targets (comma-separated
+// list of host names) is not empty, probes are run against these targets
+// instead of their configured targets.
+func RunOnceWithTargets(ctx context.Context, names, targets, format, indent string) error {
 	cloudProber.RLock()
 	defer cloudProber.RUnlock()
 
-	var probeNames []string
+	var probeNames, targetNames []string
 	// avoid getting '[""]' as probe names
 	if names != "" {
 		probeNames = strings.Split(names, ",")
 	}
-	prrs, err := cloudProber.prober.Run(ctx, probeNames)
+	if targets != "" {
+		targetNames = strings.Split(targets, ",")
+	}
+	prrs, err := cloudProber.prober.RunWithTargets(ctx, probeNames, targetNames)
 	fmt.Println(singlerun.FormatProbeRunResults(prrs, singlerun.Format(format), indent))
 
 	// In CLI case, aggregate the probe run errors, so we can more easily show to users.
@@ -369,6 +461,17 @@ func Start(ctx context.Context) {
 	srvMux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintf(w, "OK")
 	})
+	if *enableReloadEndpoint {
+		srvMux.HandleFunc("/-/reload", reloadHandler)
+	}
+
+	if *configReloadInterval > 0 {
+		if cs, ok := cloudProber.configSource.(config.ReloadableConfigSource); ok {
+			go watchConfig(ctx, cs, *configReloadInterval, cloudProber.l)
+		} else {
+			cloudProber.l.Warningf("Config source doesn't support change detection, --config_reload_interval will be ignored.")
+		}
+	}
 }
 
 // GetConfig returns the prober config.
@@ -16,8 +16,13 @@ package cloudprober
 
 import (
 	"context"
+	"fmt"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"sort"
 	"testing"
 	"time"
 
@@ -260,3 +265,89 @@ func TestCloudproberConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestReloadConfig(t *testing.T) {
+	ports := freePortsT(t, 1)
+
+	udpProbe := func(name string) *probepb.ProbeDef {
+		return &probepb.ProbeDef{
+			Name: proto.String(name),
+			Type: probepb.ProbeDef_UDP.Enum(),
+			Targets: &targetspb.TargetsDef{
+				Type: &targetspb.TargetsDef_HostNames{
+					HostNames: "localhost",
+				},
+			},
+			Probe: &probepb.ProbeDef_UdpProbe{
+				UdpProbe: &udpprobepb.ProbeConf{
+					Port: proto.Int32(31234),
+				},
+			},
+		}
+	}
+
+	cfgFile := filepath.Join(t.TempDir(), "cloudprober.cfg")
+	writeConfig := func(probeNames ...string) {
+		cfg := &configpb.ProberConfig{
+			Port:          proto.Int32(ports[0]),
+			DisableJitter: proto.Bool(true),
+		}
+		for _, name := range probeNames {
+			cfg.Probe = append(cfg.Probe, udpProbe(name))
+		}
+		os.WriteFile(cfgFile, []byte(prototext.Format(cfg)), 0644)
+	}
+
+	probeNames := func() []string {
+		probes, _, _ := GetInfo()
+		var names []string
+		for name := range probes {
+			if name != "sys_metrics" {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	writeConfig("p1", "p2")
+	if err := InitWithConfigSource(config.ConfigSourceWithFile(cfgFile)); err != nil {
+		t.Fatalf("Error initializing cloudprober: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		// Wait required for the cloudprober instance to fully shut down.
+		time.Sleep(time.Second)
+	}()
+	Start(ctx)
+	assert.Equal(t, []string{"p1", "p2"}, probeNames())
+
+	writeConfig("p2", "p3")
+	assert.NoError(t, ReloadConfig())
+	assert.Equal(t, []string{"p2", "p3"}, probeNames())
+	assert.Len(t, GetConfig().GetProbe(), len(GetProber().Probes))
+
+	// Bad config should be rejected, leaving the running config as is.
+	os.WriteFile(cfgFile, []byte("probe {"), 0644)
+	assert.Error(t, ReloadConfig())
+	assert.Equal(t, []string{"p2", "p3"}, probeNames())
+
+	// Reload endpoint is not registered unless explicitly enabled.
+	resp, err := http.Post(fmt.Sprintf("http://localhost:%d/-/reload", ports[0]), "", nil)
+	if assert.NoError(t, err) {
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	}
+
+	// Reload handler accepts only POST requests.
+	w := httptest.NewRecorder()
+	reloadHandler(w, httptest.NewRequest(http.MethodGet, "/-/reload", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	writeConfig("p4")
+	w = httptest.NewRecorder()
+	reloadHandler(w, httptest.NewRequest(http.MethodPost, "/-/reload", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"p4"}, probeNames())
+}
@@ -52,6 +52,7 @@ var (
 	// Run once flags
 	runOnce           = flag.Bool("run_once", false, "Run a single probe and exit")
 	runOnceProbeNames = flag.String("run_once_probe_names", "", "Comma-separated list of probe names to run")
+	runOnceTargets    = flag.String("run_once_targets", "", "Comma-separated list of targets to run probes against, instead of their configured targets")
 	runOnceOutFormat  = flag.String("run_once_output_format", "text", "Run once output format (text, json)")
 	runOnceOutIndent  = flag.String("run_once_output_indent", "  ", "Run once output indent")
 )
@@ -180,7 +181,7 @@ func main() {
 	}
 
 	if *runOnce {
-		err := cloudprober.RunOnce(startCtx, *runOnceProbeNames, *runOnceOutFormat, *runOnceOutIndent)
+		err := cloudprober.RunOnceWithTargets(startCtx, *runOnceProbeNames, *runOnceTargets, *runOnceOutFormat, *runOnceOutIndent)
 		if err != nil {
 			l.Criticalf("Error running run-once probe. Err: %v", err)
 		}
@@ -188,6 +189,18 @@ func main() {
 	}
 	cloudprober.Start(startCtx)
 
+	// Reload config on SIGHUP.
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+	go func() {
+		for range hupChan {
+			l.Info("Received SIGHUP, reloading config")
+			if err := cloudprober.ReloadConfig(); err != nil {
+				l.Errorf("Config reload failed. Err: %v", err)
+			}
+		}
+	}()
+
 	// Wait forever
 	select {}
 }
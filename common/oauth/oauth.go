@@ -20,6 +20,7 @@ package oauth
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -91,3 +92,39 @@ func TokenSourceFromConfig(c *configpb.Config, l *logger.Logger) (oauth2.TokenSo
 
 	return newTokenSource(c, refreshExpiryBuffer, l)
 }
+
+// tokenString returns the access token from the token source, falling back to
+// the ID token if access token is not set.
+func tokenString(ts oauth2.TokenSource, l *logger.Logger) (string, error) {
+	tok, err := ts.Token()
+	if err != nil {
+		return "", err
+	}
+	l.Debug("Got OAuth token, len: ", strconv.FormatInt(int64(len(tok.AccessToken)), 10), ", expirationTime: ", tok.Expiry.String())
+
+	if tok.AccessToken != "" {
+		return tok.AccessToken, nil
+	}
+
+	idToken, ok := tok.Extra("id_token").(string)
+	if ok {
+		return idToken, nil
+	}
+
+	return "", fmt.Errorf("got unknown token: %v", tok)
+}
+
+// AuthorizationHeader returns the Authorization header value for the token
+// from the token source, formatted using the config's token_type_format.
+//
+// Note: We don't return an error if there is an error in getting the token.
+// Error is logged and "<token-missing>" is used in place of the token, so
+// that OAuth refresh failures show up in probe failures.
+func AuthorizationHeader(ts oauth2.TokenSource, c *configpb.Config, l *logger.Logger) string {
+	tok, err := tokenString(ts, l)
+	if err != nil {
+		l.Error("Error getting OAuth token: ", err.Error())
+		tok = "<token-missing>"
+	}
+	return fmt.Sprintf(c.GetTokenTypeFormat(), tok)
+}
@@ -21,6 +21,8 @@ import (
 	"testing"
 
 	configpb "github.com/cloudprober/cloudprober/common/oauth/proto"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -108,3 +110,56 @@ func TestGoogleCredentials(t *testing.T) {
 		t.Errorf("Config: %v, Unexpected error: %v", c, err)
 	}
 }
+
+type errTokenSource struct{}
+
+func (errTokenSource) Token() (*oauth2.Token, error) {
+	return nil, fmt.Errorf("token error")
+}
+
+func TestAuthorizationHeader(t *testing.T) {
+	idToken := (&oauth2.Token{}).WithExtra(map[string]interface{}{"id_token": "id-tok"})
+
+	tests := []struct {
+		name string
+		ts   oauth2.TokenSource
+		conf *configpb.Config
+		want string
+	}{
+		{
+			name: "access_token",
+			ts:   oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "tok"}),
+			conf: &configpb.Config{},
+			want: "Bearer tok",
+		},
+		{
+			name: "id_token",
+			ts:   oauth2.StaticTokenSource(idToken),
+			conf: &configpb.Config{},
+			want: "Bearer id-tok",
+		},
+		{
+			name: "token_type_format",
+			ts:   oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "tok"}),
+			conf: &configpb.Config{TokenTypeFormat: proto.String("Token %s")},
+			want: "Token tok",
+		},
+		{
+			name: "unknown_token",
+			ts:   oauth2.StaticTokenSource(&oauth2.Token{}),
+			conf: &configpb.Config{},
+			want: "Bearer <token-missing>",
+		},
+		{
+			name: "token_error",
+			ts:   errTokenSource{},
+			conf: &configpb.Config{},
+			want: "Bearer <token-missing>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, AuthorizationHeader(tt.ts, tt.conf, nil))
+		})
+	}
+}
@@ -65,6 +65,9 @@ var configTestVars = map[string]any{
 
 func DefaultConfigSource(opts ...Option) ConfigSource {
 	opts = append(opts, WithSurfacerConfig(*surfacersConfigFile))
+	if file.IsRemote(*configFile) {
+		return RemoteConfigSource(*configFile, opts...)
+	}
 	return ConfigSourceWithFile(*configFile, opts...)
 }
 
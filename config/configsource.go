@@ -15,6 +15,7 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"os"
 
@@ -33,6 +34,17 @@ type ConfigSource interface {
 	ParsedConfig() string
 }
 
+// ReloadableConfigSource is implemented by config sources that can tell if
+// the underlying config has changed since it was last read. Cloudprober uses
+// it to decide when to reload the config.
+type ReloadableConfigSource interface {
+	ConfigSource
+
+	// ConfigChanged reports whether the config has changed since the last
+	// successful GetConfig call.
+	ConfigChanged(ctx context.Context) (bool, error)
+}
+
 type Option func(ConfigSource) ConfigSource
 
 func WithBaseVars(vars map[string]any) Option {
@@ -116,39 +128,70 @@ func (dcs *defaultConfigSource) GetConfig() (*configpb.ProberConfig, error) {
 		dcs.getGCECustomMetadata = readFromGCEMetadata
 	}
 
-	configStr, configFormat, err := dcs.configContent()
+	rawConfig, configFormat, err := dcs.configContent()
 	if err != nil {
 		return nil, err
 	}
-	dcs.rawConfig = configStr
 
-	dcs.cfg = &configpb.ProberConfig{}
-	if dcs.parsedConfig, err = processConfigText(dcs.rawConfig, configFormat, tmplVars, dcs.cfg, dcs.l); err != nil {
+	// We update the config source's state only if the config is processed
+	// successfully. This allows a failed reload to leave the running config
+	// untouched.
+	cfg := &configpb.ProberConfig{}
+	parsedConfig, err := processConfigText(rawConfig, configFormat, tmplVars, cfg, dcs.l)
+	if err != nil {
 		return nil, fmt.Errorf("error processing config. Err: %v", err)
 	}
 
 	if dcs.surfacersConfigFileName != "" {
-		var sConfigText string
-		var err error
-
-		sConfigText, err = readConfigFile(dcs.surfacersConfigFileName)
+		sConfigText, err := readConfigFile(dcs.surfacersConfigFileName)
 		if err != nil {
 			return nil, fmt.Errorf("error reading surfacers config file: %v", err)
 		}
-		dcs.rawConfig += "\n\n" + sConfigText
+		rawConfig += "\n\n" + sConfigText
 
 		sConfig, fileFmt := &configpb.SurfacersConfig{}, formatFromFileName(dcs.surfacersConfigFileName)
 		parsedSConfig, err := processConfigText(sConfigText, fileFmt, tmplVars, sConfig, dcs.l)
 		if err != nil {
 			return nil, fmt.Errorf("error processing surfacers config. Err: %v", err)
 		}
-		dcs.parsedConfig += "\n\n" + parsedSConfig
-		dcs.cfg.Surfacer = append(dcs.cfg.Surfacer, sConfig.GetSurfacer()...)
+		parsedConfig += "\n\n" + parsedSConfig
+		cfg.Surfacer = append(cfg.Surfacer, sConfig.GetSurfacer()...)
 	}
 
+	dcs.rawConfig, dcs.parsedConfig, dcs.cfg = rawConfig, parsedConfig, cfg
 	return dcs.cfg, nil
 }
 
+// ConfigChanged reports whether the config content has changed since the
+// last successful GetConfig call. We compare the raw config content, instead
+// of file modification times, so that changes to included files and GCE
+// metadata are also detected.
+func (dcs *defaultConfigSource) ConfigChanged(ctx context.Context) (bool, error) {
+	if dcs.cfg == nil {
+		return false, fmt.Errorf("config has not been read yet")
+	}
+
+	// Built-in default config never changes.
+	if dcs.fileName == "" && !metadata.OnGCE() {
+		return false, nil
+	}
+
+	rawConfig, _, err := dcs.configContent()
+	if err != nil {
+		return false, err
+	}
+
+	if dcs.surfacersConfigFileName != "" {
+		sConfigText, err := readConfigFile(dcs.surfacersConfigFileName)
+		if err != nil {
+			return false, fmt.Errorf("error reading surfacers config file: %v", err)
+		}
+		rawConfig += "\n\n" + sConfigText
+	}
+
+	return rawConfig != dcs.rawConfig, nil
+}
+
 func (dcs *defaultConfigSource) RawConfig() string {
 	return dcs.rawConfig
 }
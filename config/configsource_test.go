@@ -15,6 +15,9 @@
 package config
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
@@ -150,3 +153,55 @@ surfacer {
 		})
 	}
 }
+
+func TestDefaultConfigSourceConfigChanged(t *testing.T) {
+	cfgFile := filepath.Join(t.TempDir(), "cloudprober.cfg")
+	writeConfig := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(cfgFile, []byte(content), 0644); err != nil {
+			t.Fatalf("error writing config file: %v", err)
+		}
+	}
+
+	oldCfg := `probe {
+  name: "p1"
+  type: DNS
+}`
+	writeConfig(oldCfg)
+
+	dcs := &defaultConfigSource{fileName: cfgFile}
+	_, err := dcs.ConfigChanged(context.Background())
+	assert.Error(t, err, "config not read yet")
+
+	_, err = dcs.GetConfig()
+	assert.NoError(t, err)
+
+	changed, err := dcs.ConfigChanged(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	// Bad config: config changed, but GetConfig fails and the previous
+	// config is retained.
+	writeConfig("probe {")
+	changed, err = dcs.ConfigChanged(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	_, err = dcs.GetConfig()
+	assert.Error(t, err)
+	assert.Equal(t, oldCfg, dcs.RawConfig())
+	assert.Equal(t, "p1", dcs.cfg.GetProbe()[0].GetName())
+
+	newCfg := `probe {
+  name: "p2"
+  type: DNS
+}`
+	writeConfig(newCfg)
+	cfg, err := dcs.GetConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "p2", cfg.GetProbe()[0].GetName())
+	assert.Equal(t, newCfg, dcs.RawConfig())
+
+	changed, err = dcs.ConfigChanged(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, changed)
+}
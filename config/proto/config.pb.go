@@ -148,16 +148,27 @@ type ProberConfig struct {
 	//	  }
 	//	}
 	GlobalArtifactsOptions *proto6.ArtifactsOptions `protobuf:"bytes,103,opt,name=global_artifacts_options,json=globalArtifactsOptions" json:"global_artifacts_options,omitempty"`
-	unknownFields          protoimpl.UnknownFields
-	sizeCache              protoimpl.SizeCache
+	// Local directory to persist Cloudprober's in-memory state across
+	// restarts. If configured, ongoing alerts (and alerts history) and the
+	// status page (probestatus surfacer) timeseries are saved in this directory
+	// and are reloaded on start. This makes sure that ongoing alerts are
+	// resolved properly (resolve notifications are sent) after a restart.
+	// Directory is created if it doesn't exist.
+	StateDir *string `protobuf:"bytes,106,opt,name=state_dir,json=stateDir" json:"state_dir,omitempty"`
+	// How often to save the status page timeseries to the state_dir. Alerts
+	// state is saved whenever it changes.
+	StateSaveIntervalSec *int32 `protobuf:"varint,107,opt,name=state_save_interval_sec,json=stateSaveIntervalSec,def=60" json:"state_save_interval_sec,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
 }
 
 // Default values for ProberConfig fields.
 const (
-	Default_ProberConfig_DisableJitter       = bool(false)
-	Default_ProberConfig_SysvarsIntervalMsec = int32(10000)
-	Default_ProberConfig_SysvarsEnvVar       = string("SYSVARS")
-	Default_ProberConfig_StopTimeSec         = int32(5)
+	Default_ProberConfig_DisableJitter        = bool(false)
+	Default_ProberConfig_SysvarsIntervalMsec  = int32(10000)
+	Default_ProberConfig_SysvarsEnvVar        = string("SYSVARS")
+	Default_ProberConfig_StopTimeSec          = int32(5)
+	Default_ProberConfig_StateSaveIntervalSec = int32(60)
 )
 
 func (x *ProberConfig) Reset() {
@@ -295,6 +306,20 @@ func (x *ProberConfig) GetGlobalArtifactsOptions() *proto6.ArtifactsOptions {
 	return nil
 }
 
+func (x *ProberConfig) GetStateDir() string {
+	if x != nil && x.StateDir != nil {
+		return *x.StateDir
+	}
+	return ""
+}
+
+func (x *ProberConfig) GetStateSaveIntervalSec() int32 {
+	if x != nil && x.StateSaveIntervalSec != nil {
+		return *x.StateSaveIntervalSec
+	}
+	return Default_ProberConfig_StateSaveIntervalSec
+}
+
 type SharedTargets struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Name          *string                `protobuf:"bytes,1,req,name=name" json:"name,omitempty"`
@@ -396,7 +421,7 @@ var File_github_com_cloudprober_cloudprober_config_proto_config_proto protorefle
 
 const file_github_com_cloudprober_cloudprober_config_proto_config_proto_rawDesc = "" +
 	"\n" +
-	"<github.com/cloudprober/cloudprober/config/proto/config.proto\x12\vcloudprober\x1aFgithub.com/cloudprober/cloudprober/common/tlsconfig/proto/config.proto\x1aNgithub.com/cloudprober/cloudprober/probes/browser/artifacts/proto/config.proto\x1a<github.com/cloudprober/cloudprober/probes/proto/config.proto\x1aIgithub.com/cloudprober/cloudprober/internal/rds/server/proto/config.proto\x1aFgithub.com/cloudprober/cloudprober/internal/servers/proto/config.proto\x1aHgithub.com/cloudprober/cloudprober/internal/surfacers/proto/config.proto\x1a>github.com/cloudprober/cloudprober/targets/proto/targets.proto\"\xdb\x06\n" +
+	"<github.com/cloudprober/cloudprober/config/proto/config.proto\x12\vcloudprober\x1aFgithub.com/cloudprober/cloudprober/common/tlsconfig/proto/config.proto\x1aNgithub.com/cloudprober/cloudprober/probes/browser/artifacts/proto/config.proto\x1a<github.com/cloudprober/cloudprober/probes/proto/config.proto\x1aIgithub.com/cloudprober/cloudprober/internal/rds/server/proto/config.proto\x1aFgithub.com/cloudprober/cloudprober/internal/servers/proto/config.proto\x1aHgithub.com/cloudprober/cloudprober/internal/surfacers/proto/config.proto\x1a>github.com/cloudprober/cloudprober/targets/proto/targets.proto\"\xb3\a\n" +
 	"\fProberConfig\x122\n" +
 	"\x05probe\x18\x01 \x03(\v2\x1c.cloudprober.probes.ProbeDefR\x05probe\x12=\n" +
 	"\bsurfacer\x18\x02 \x03(\v2!.cloudprober.surfacer.SurfacerDefR\bsurfacer\x126\n" +
@@ -413,7 +438,9 @@ const file_github_com_cloudprober_cloudprober_config_proto_config_proto_rawDesc
 	"\x0fsysvars_env_var\x18b \x01(\t:\aSYSVARSR\rsysvarsEnvVar\x12%\n" +
 	"\rstop_time_sec\x18c \x01(\x05:\x015R\vstopTimeSec\x12_\n" +
 	"\x16global_targets_options\x18d \x01(\v2).cloudprober.targets.GlobalTargetsOptionsR\x14globalTargetsOptions\x12p\n" +
-	"\x18global_artifacts_options\x18g \x01(\v26.cloudprober.probes.browser.artifacts.ArtifactsOptionsR\x16globalArtifactsOptions\"^\n" +
+	"\x18global_artifacts_options\x18g \x01(\v26.cloudprober.probes.browser.artifacts.ArtifactsOptionsR\x16globalArtifactsOptions\x12\x1b\n" +
+	"\tstate_dir\x18j \x01(\tR\bstateDir\x129\n" +
+	"\x17state_save_interval_sec\x18k \x01(\x05:\x0260R\x14stateSaveIntervalSec\"^\n" +
 	"\rSharedTargets\x12\x12\n" +
 	"\x04name\x18\x01 \x02(\tR\x04name\x129\n" +
 	"\atargets\x18\x02 \x02(\v2\x1f.cloudprober.targets.TargetsDefR\atargets\"P\n" +
@@ -0,0 +1,129 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"fmt"
+
+	configpb "github.com/cloudprober/cloudprober/config/proto"
+	"github.com/cloudprober/cloudprober/internal/file"
+)
+
+// remoteConfigSource is a config source for configs stored on a remote
+// backend: GCS (gs://), S3 (s3://) or an HTTP(S) server. It uses the remote
+// object's version (ETag, or modification time if ETag is not available) to
+// cheaply find out if config has changed, and validates the new config before
+// reporting it as changed. That way, a bad config pushed to a central
+// location is never handed to the prober.
+type remoteConfigSource struct {
+	*defaultConfigSource
+
+	// version of the config returned by the last successful GetConfig call.
+	version string
+	// badVersion is the last version that failed validation. We remember it
+	// to not re-validate (and re-report) the same bad config on every check.
+	badVersion string
+}
+
+// RemoteConfigSource returns a config source that reads config from the given
+// remote URL, e.g. gs://bucket/cloudprober.cfg, and can detect changes to it.
+// If the URL is not a remote URL, a regular file based config source is
+// returned.
+func RemoteConfigSource(configURL string, opts ...Option) ConfigSource {
+	cs := ConfigSourceWithFile(configURL, opts...)
+	dcs, ok := cs.(*defaultConfigSource)
+	if !ok || !file.IsRemote(configURL) {
+		return cs
+	}
+	return &remoteConfigSource{defaultConfigSource: dcs}
+}
+
+// objectVersion returns the current version of the remote config object. If
+// remote backend provides neither ETag nor modification time, an empty string
+// is returned.
+func (rcs *remoteConfigSource) objectVersion(ctx context.Context) (string, error) {
+	etag, err := file.ETag(ctx, rcs.fileName)
+	if err != nil {
+		return "", fmt.Errorf("error getting remote config's version: %v", err)
+	}
+	if etag != "" {
+		return "etag:" + etag, nil
+	}
+
+	modTime, err := file.ModTime(ctx, rcs.fileName)
+	if err != nil {
+		rcs.l.Debugf("Couldn't get remote config's modification time, will compare config content instead. Err: %v", err)
+		return "", nil
+	}
+	return "mtime:" + modTime.String(), nil
+}
+
+func (rcs *remoteConfigSource) GetConfig() (*configpb.ProberConfig, error) {
+	// We get the version before reading the config. If config changes in
+	// between, we'll see it as changed next time and reload it again.
+	version, err := rcs.objectVersion(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := rcs.defaultConfigSource.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	rcs.version = version
+	return cfg, nil
+}
+
+// validate checks the current remote config in the same way as ConfigTest,
+// without changing the state of the config source.
+func (rcs *remoteConfigSource) validate() error {
+	return ConfigTest(&defaultConfigSource{
+		fileName:                rcs.fileName,
+		surfacersConfigFileName: rcs.surfacersConfigFileName,
+		baseVars:                rcs.baseVars,
+		getGCECustomMetadata:    rcs.getGCECustomMetadata,
+		l:                       rcs.l,
+	})
+}
+
+// ConfigChanged reports whether the remote config has changed since the last
+// successful GetConfig call, and the new config is valid. If new config is
+// not valid, an error is returned.
+func (rcs *remoteConfigSource) ConfigChanged(ctx context.Context) (bool, error) {
+	if rcs.cfg == nil {
+		return false, fmt.Errorf("config has not been read yet")
+	}
+
+	version, err := rcs.objectVersion(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if version == "" {
+		changed, err := rcs.defaultConfigSource.ConfigChanged(ctx)
+		if err != nil || !changed {
+			return false, err
+		}
+	} else if version == rcs.version || version == rcs.badVersion {
+		return false, nil
+	}
+
+	if err := rcs.validate(); err != nil {
+		rcs.badVersion = version
+		return false, fmt.Errorf("remote config has changed but it's not valid, ignoring it: %v", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,156 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testConfigServer struct {
+	mu      sync.Mutex
+	content string
+	etag    string
+	modTime time.Time
+}
+
+func (tcs *testConfigServer) set(content, etag string, modTime time.Time) {
+	tcs.mu.Lock()
+	defer tcs.mu.Unlock()
+	tcs.content, tcs.etag, tcs.modTime = content, etag, modTime
+}
+
+func (tcs *testConfigServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tcs.mu.Lock()
+	defer tcs.mu.Unlock()
+	if tcs.etag != "" {
+		w.Header().Set("ETag", tcs.etag)
+	}
+	if !tcs.modTime.IsZero() {
+		w.Header().Set("Last-Modified", tcs.modTime.Format(http.TimeFormat))
+	}
+	w.Write([]byte(tcs.content))
+}
+
+func TestRemoteConfigSource(t *testing.T) {
+	cfg1 := `probe {
+  name: "dns_1"
+  type: DNS
+}`
+	cfg2 := `probe {
+  name: "dns_2"
+  type: DNS
+}`
+	badCfg := `probe {
+  name: "dns_3"
+  type: DNS_BAD
+}`
+	t0 := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		version func(i int) (etag string, modTime time.Time)
+	}{
+		{
+			name: "etag",
+			version: func(i int) (string, time.Time) {
+				return fmt.Sprintf("\"v%d\"", i), time.Time{}
+			},
+		},
+		{
+			name: "modtime",
+			version: func(i int) (string, time.Time) {
+				return "", t0.Add(time.Duration(i) * time.Minute)
+			},
+		},
+		{
+			name: "content",
+			version: func(i int) (string, time.Time) {
+				return "", time.Time{}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tcs := &testConfigServer{}
+			ts := httptest.NewServer(tcs)
+			defer ts.Close()
+
+			setConfig := func(content string, i int) {
+				etag, modTime := tt.version(i)
+				tcs.set(content, etag, modTime)
+			}
+			setConfig(cfg1, 1)
+
+			cs := RemoteConfigSource(ts.URL + "/cloudprober.cfg")
+			rcs, ok := cs.(*remoteConfigSource)
+			if !ok {
+				t.Fatalf("RemoteConfigSource() returned %T, want *remoteConfigSource", cs)
+			}
+
+			ctx := context.Background()
+			_, err := rcs.ConfigChanged(ctx)
+			assert.Error(t, err, "ConfigChanged before GetConfig")
+
+			cfg, err := rcs.GetConfig()
+			assert.NoError(t, err)
+			assert.Equal(t, "dns_1", cfg.GetProbe()[0].GetName())
+
+			changed, err := rcs.ConfigChanged(ctx)
+			assert.NoError(t, err)
+			assert.False(t, changed)
+
+			// Bad config: reported as error once, and then ignored.
+			setConfig(badCfg, 2)
+			changed, err = rcs.ConfigChanged(ctx)
+			assert.Error(t, err)
+			assert.False(t, changed)
+			if tt.name != "content" {
+				changed, err = rcs.ConfigChanged(ctx)
+				assert.NoError(t, err)
+				assert.False(t, changed)
+			}
+			assert.Equal(t, cfg1, rcs.RawConfig(), "raw config changed after bad config")
+
+			// Good config.
+			setConfig(cfg2, 3)
+			changed, err = rcs.ConfigChanged(ctx)
+			assert.NoError(t, err)
+			assert.True(t, changed)
+
+			cfg, err = rcs.GetConfig()
+			assert.NoError(t, err)
+			assert.Equal(t, "dns_2", cfg.GetProbe()[0].GetName())
+
+			changed, err = rcs.ConfigChanged(ctx)
+			assert.NoError(t, err)
+			assert.False(t, changed)
+		})
+	}
+}
+
+func TestRemoteConfigSourceLocalFile(t *testing.T) {
+	cs := RemoteConfigSource("testdata/cloudprober.cfg")
+	_, ok := cs.(*defaultConfigSource)
+	assert.True(t, ok, "expected default config source for local file, got %T", cs)
+}
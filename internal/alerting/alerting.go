@@ -53,9 +53,10 @@ type AlertHandler struct {
 	notifyCh     chan *alertinfo.AlertInfo // Used only for testing for now.
 	notifier     *notifier.Notifier
 
-	mu      sync.Mutex
-	targets map[string]*targetState
-	l       *logger.Logger
+	mu       sync.Mutex
+	targets  map[string]*targetState
+	suppress func(endpoint.Endpoint) bool
+	l        *logger.Logger
 }
 
 // processConfig processes the alerting config and returns the updated config.
@@ -147,6 +148,9 @@ func (ah *AlertHandler) notify(ep endpoint.Endpoint, ts *targetState, totalFailu
 
 	ah.notifier.Notify(context.Background(), alertInfo)
 	globalState.add(alertKey, alertInfo)
+	if err := globalState.save(); err != nil {
+		ah.l.Warningf("Error saving alerts state: %v", err)
+	}
 }
 
 func (ah *AlertHandler) resolveAlertCondition(ts *targetState, ep endpoint.Endpoint) {
@@ -164,10 +168,28 @@ func (ah *AlertHandler) resolveAlertCondition(ts *targetState, ep endpoint.Endpo
 
 	ah.notifier.NotifyResolve(context.Background(), ai)
 	globalState.resolve(key)
+	if err := globalState.save(); err != nil {
+		ah.l.Warningf("Error saving alerts state: %v", err)
+	}
+}
+
+// SetSuppressFunc sets a function that is called before sending alert
+// notifications for a target. If it returns true, the notification is not
+// sent. It's used, for example, to suppress alerts while a probe's upstream
+// dependency is failing.
+func (ah *AlertHandler) SetSuppressFunc(f func(endpoint.Endpoint) bool) {
+	ah.mu.Lock()
+	defer ah.mu.Unlock()
+	ah.suppress = f
 }
 
 // handleAlertCondition handles the alert condition.
 func (ah *AlertHandler) handleAlertCondition(ts *targetState, ep endpoint.Endpoint, timestamp time.Time, totalFailures int) {
+	if ah.suppress != nil && ah.suppress(ep) {
+		ah.l.Infof("ALERT suppressed (%s): target (%s), failures (%d), upstream dependency is failing", ah.name, ep.Name, totalFailures)
+		return
+	}
+
 	// Ongoing alert. Notify if the repeat interval has passed.
 	if ts.alerted {
 		if time.Since(ts.alertTS) > time.Duration(ah.c.GetRepeatIntervalSec())*time.Second {
@@ -214,6 +236,18 @@ func (ah *AlertHandler) Record(ep endpoint.Endpoint, em *metrics.EventMetrics) {
 			lastTotal:   total,
 			lastSuccess: success,
 		}
+		// If there was an ongoing alert for this target before the restart,
+		// pick it up. We consider all the recent probes failed, so that alert
+		// is resolved only after the target has been healthy for a while.
+		if ai := globalState.takeRestored(ah.globalKey(ep)); ai != nil {
+			ah.l.Infof("ALERT restored (%s): target (%s), failing since (%v)", ah.name, ep.Name, ai.FailingSince)
+			for i := range ts.failures {
+				ts.failures[i] = true
+			}
+			ts.alerted = true
+			ts.failingSince = ai.FailingSince
+			ts.alertTS = time.Now()
+		}
 		ah.targets[key] = ts
 		return
 	}
@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/cloudprober/cloudprober/internal/alerting/alertinfo"
+	"github.com/cloudprober/cloudprober/internal/statestore"
 )
 
 var statusTmpl = template.Must(template.New("status").Parse(`
@@ -88,10 +89,24 @@ type resolvedAlert struct {
 
 var maxAlertsHistory = 20
 
+// stateStoreName is the name under which alerts state is saved in the state
+// store.
+const stateStoreName = "alerts"
+
 type state struct {
 	mu             sync.RWMutex
 	currentAlerts  map[string]*alertinfo.AlertInfo
 	resolvedAlerts []resolvedAlert
+
+	// restored tracks the current alerts that were loaded from the state
+	// store and have not yet been picked up by their alert handlers.
+	restored map[string]bool
+}
+
+// savedState is the alerts state saved in the state store.
+type savedState struct {
+	CurrentAlerts  map[string]*alertinfo.AlertInfo
+	ResolvedAlerts []resolvedAlert
 }
 
 func (st *state) get(key string) *alertinfo.AlertInfo {
@@ -121,6 +136,68 @@ func (st *state) resolve(key string) {
 	delete(st.currentAlerts, key)
 }
 
+// takeRestored returns the alert for the key if it was restored from the
+// state store and hasn't been picked up yet.
+func (st *state) takeRestored(key string) *alertinfo.AlertInfo {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if !st.restored[key] {
+		return nil
+	}
+	delete(st.restored, key)
+	return st.currentAlerts[key]
+}
+
+// save saves the alerts state to the state store, if state store is enabled.
+func (st *state) save() error {
+	if !statestore.Enabled() {
+		return nil
+	}
+
+	st.mu.RLock()
+	ss := &savedState{
+		CurrentAlerts:  make(map[string]*alertinfo.AlertInfo, len(st.currentAlerts)),
+		ResolvedAlerts: append([]resolvedAlert{}, st.resolvedAlerts...),
+	}
+	for k, ai := range st.currentAlerts {
+		ss.CurrentAlerts[k] = ai
+	}
+	st.mu.RUnlock()
+
+	return statestore.Save(stateStoreName, ss)
+}
+
+// load loads the alerts state from the state store. Loaded alerts are merged
+// with the existing state.
+func (st *state) load() error {
+	ss := &savedState{}
+	found, err := statestore.Load(stateStoreName, ss)
+	if err != nil || !found {
+		return err
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if st.currentAlerts == nil {
+		st.currentAlerts = make(map[string]*alertinfo.AlertInfo)
+	}
+	if st.restored == nil {
+		st.restored = make(map[string]bool)
+	}
+	for k, ai := range ss.CurrentAlerts {
+		if st.currentAlerts[k] != nil {
+			continue
+		}
+		st.currentAlerts[k] = ai
+		st.restored[k] = true
+	}
+	if len(st.resolvedAlerts) == 0 {
+		st.resolvedAlerts = ss.ResolvedAlerts
+	}
+	return nil
+}
+
 func (st *state) list() ([]*alertinfo.AlertInfo, []resolvedAlert) {
 	st.mu.RLock()
 	defer st.mu.RUnlock()
@@ -165,3 +242,12 @@ var globalState = state{
 func StatusHTML() (string, error) {
 	return globalState.statusHTML()
 }
+
+// LoadState loads the alerts state (ongoing alerts and alerts history) saved
+// in the state store by the previous Cloudprober run. Restored alerts are
+// picked up by the alert handlers when they see the alert's target for the
+// first time, so that they can send resolve notifications when the targets
+// recover. It should be called before the probes start.
+func LoadState() error {
+	return globalState.load()
+}
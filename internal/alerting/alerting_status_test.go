@@ -19,8 +19,12 @@ import (
 	"time"
 
 	"github.com/cloudprober/cloudprober/internal/alerting/alertinfo"
+	configpb "github.com/cloudprober/cloudprober/internal/alerting/proto"
+	"github.com/cloudprober/cloudprober/internal/statestore"
+	"github.com/cloudprober/cloudprober/metrics"
 	"github.com/cloudprober/cloudprober/targets/endpoint"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestUpdateState(t *testing.T) {
@@ -262,3 +266,67 @@ func TestStatusHTML(t *testing.T) {
 		})
 	}
 }
+
+func resetGlobalState() {
+	globalState.mu.Lock()
+	defer globalState.mu.Unlock()
+	globalState.currentAlerts = make(map[string]*alertinfo.AlertInfo)
+	globalState.resolvedAlerts = nil
+	globalState.restored = nil
+}
+
+func TestSaveAndLoadState(t *testing.T) {
+	require.NoError(t, statestore.Init(t.TempDir(), 0))
+	defer statestore.Init("", 0)
+	resetGlobalState()
+	defer resetGlobalState()
+
+	ep := endpoint.Endpoint{Name: "target1"}
+	em := func(total, success int64) *metrics.EventMetrics {
+		return metrics.NewEventMetrics(time.Now()).
+			AddMetric("total", metrics.NewInt(total)).
+			AddMetric("success", metrics.NewInt(success))
+	}
+
+	ah, err := NewAlertHandler(&configpb.AlertConf{}, "test-probe", nil)
+	require.NoError(t, err)
+	ah.notifyCh = make(chan *alertinfo.AlertInfo, 10)
+	ah.Record(ep, em(1, 1))
+	ah.Record(ep, em(2, 1))
+	require.Len(t, ah.notifyCh, 1)
+	failingSince := ah.targets[ep.Key()].failingSince
+
+	// Simulate restart: clear in-memory state and load it from the store.
+	resetGlobalState()
+	require.NoError(t, LoadState())
+	currentAlerts, _ := globalState.list()
+	require.Len(t, currentAlerts, 1)
+	assert.Equal(t, "target1", currentAlerts[0].Target.Name)
+
+	ah, err = NewAlertHandler(&configpb.AlertConf{}, "test-probe", nil)
+	require.NoError(t, err)
+	ah.notifyCh = make(chan *alertinfo.AlertInfo, 10)
+
+	// Counters are reset after restart.
+	ah.Record(ep, em(1, 0))
+	ts := ah.targets[ep.Key()]
+	assert.True(t, ts.alerted, "alert should be restored")
+	assert.True(t, ts.failingSince.Equal(failingSince), "failing since")
+	assert.Nil(t, globalState.takeRestored(ah.globalKey(ep)), "alert should be picked up only once")
+
+	// Target recovers, alert should be resolved.
+	ah.Record(ep, em(2, 1))
+	assert.False(t, ts.alerted)
+	assert.Len(t, ah.notifyCh, 0, "no new alert notifications")
+	currentAlerts, resolvedAlerts := globalState.list()
+	assert.Len(t, currentAlerts, 0)
+	require.Len(t, resolvedAlerts, 1)
+	assert.Equal(t, "target1", resolvedAlerts[0].AlertInfo.Target.Name)
+
+	// Resolution is persisted as well.
+	resetGlobalState()
+	require.NoError(t, LoadState())
+	currentAlerts, resolvedAlerts = globalState.list()
+	assert.Len(t, currentAlerts, 0)
+	assert.Len(t, resolvedAlerts, 1)
+}
@@ -42,21 +42,50 @@ var global = struct {
 
 type readFunc func(ctx context.Context, path string) ([]byte, error)
 type modTimeFunc func(ctx context.Context, path string) (time.Time, error)
+type etagFunc func(ctx context.Context, path string) (string, error)
 
 var zeroTime = time.Time{}
 
 var prefixToReadfunc = map[string]readFunc{
 	"gs://":    readFileFromGCS,
 	"s3://":    readFileFromS3,
-	"http://":  readFileFromHTTP,
-	"https://": readFileFromHTTP,
+	"http://":  withScheme("http://", readFileFromHTTP),
+	"https://": withScheme("https://", readFileFromHTTP),
 }
 
 var prefixToModTimeFunc = map[string]modTimeFunc{
 	"gs://":    gcsModTime,
 	"s3://":    s3ModTime,
-	"http://":  httpModTime,
-	"https://": httpModTime,
+	"http://":  withScheme("http://", httpModTime),
+	"https://": withScheme("https://", httpModTime),
+}
+
+var prefixToETagFunc = map[string]etagFunc{
+	"gs://":    gcsETag,
+	"s3://":    s3ETag,
+	"http://":  withScheme("http://", httpETag),
+	"https://": withScheme("https://", httpETag),
+}
+
+// withScheme adds the scheme back to the path before calling f. We strip the
+// prefix before calling backend functions, but HTTP functions need the full
+// URL.
+func withScheme[T any](scheme string, f func(context.Context, string) (T, error)) func(context.Context, string) (T, error) {
+	return func(ctx context.Context, path string) (T, error) {
+		return f(ctx, scheme+path)
+	}
+}
+
+// IsRemote returns true if the file is not on the local disk, i.e. its path
+// starts with one of the supported remote prefixes (gs://, s3://, http://,
+// https://).
+func IsRemote(fname string) bool {
+	for prefix := range prefixToReadfunc {
+		if strings.HasPrefix(fname, prefix) {
+			return true
+		}
+	}
+	return false
 }
 
 func parseObjectURL(objectPath string) (bucket, object string, err error) {
@@ -93,24 +122,43 @@ func readFileFromHTTP(ctx context.Context, fileURL string) ([]byte, error) {
 	return io.ReadAll(res.Body)
 }
 
-func httpModTime(ctx context.Context, fileURL string) (time.Time, error) {
+func httpHead(ctx context.Context, fileURL string) (*http.Response, error) {
 	req, err := http.NewRequestWithContext(ctx, "HEAD", fileURL, nil)
 	if err != nil {
-		return zeroTime, err
+		return nil, err
 	}
 	res, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return zeroTime, err
+		return nil, err
 	}
 
 	if res.StatusCode != http.StatusOK {
-		return zeroTime, fmt.Errorf("got error while retrieving HTTP object, http status: %s, status code: %d", res.Status, res.StatusCode)
+		res.Body.Close()
+		return nil, fmt.Errorf("got error while retrieving HTTP object, http status: %s, status code: %d", res.Status, res.StatusCode)
+	}
+	return res, nil
+}
+
+func httpModTime(ctx context.Context, fileURL string) (time.Time, error) {
+	res, err := httpHead(ctx, fileURL)
+	if err != nil {
+		return zeroTime, err
 	}
 
 	defer res.Body.Close()
 	return httpLastModified(res)
 }
 
+func httpETag(ctx context.Context, fileURL string) (string, error) {
+	res, err := httpHead(ctx, fileURL)
+	if err != nil {
+		return "", err
+	}
+
+	defer res.Body.Close()
+	return res.Header.Get("ETag"), nil
+}
+
 func substituteEnvVariables(content []byte) []byte {
 	return []byte(os.ExpandEnv(string(content)))
 }
@@ -188,3 +236,16 @@ func ModTime(ctx context.Context, fname string) (time.Time, error) {
 	}
 	return statInfo.ModTime(), nil
 }
+
+// ETag returns file's entity tag, as reported by the remote backend. ETag
+// changes whenever file content changes, which makes it a more reliable change
+// indicator than the modification time. An empty string is returned for local
+// files and for remote files that don't have an ETag.
+func ETag(ctx context.Context, fname string) (string, error) {
+	for prefix, f := range prefixToETagFunc {
+		if strings.HasPrefix(fname, prefix) {
+			return f(ctx, fname[len(prefix):])
+		}
+	}
+	return "", nil
+}
@@ -16,6 +16,8 @@ package file
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
@@ -113,3 +115,43 @@ func TestReadWithCache(t *testing.T) {
 	time.Sleep(time.Second)
 	readAndVerify(testContent+"-updated-2", 1*time.Second)
 }
+
+func TestHTTPFile(t *testing.T) {
+	modTime := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/cloudprober.cfg" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", modTime.Format(http.TimeFormat))
+		w.Write([]byte("http-content"))
+	}))
+	defer ts.Close()
+
+	fileURL := ts.URL + "/cloudprober.cfg"
+	assert.True(t, IsRemote(fileURL))
+
+	b, err := ReadFile(context.Background(), fileURL)
+	assert.NoError(t, err)
+	assert.Equal(t, "http-content", string(b))
+
+	mt, err := ModTime(context.Background(), fileURL)
+	assert.NoError(t, err)
+	assert.True(t, modTime.Equal(mt), "ModTime()=%v, want=%v", mt, modTime)
+
+	etag, err := ETag(context.Background(), fileURL)
+	assert.NoError(t, err)
+	assert.Equal(t, `"v1"`, etag)
+
+	_, err = ETag(context.Background(), ts.URL+"/not-found")
+	assert.Error(t, err)
+
+	// Local files don't have an ETag.
+	localFile := createTempFile(t, []byte("local-content"))
+	defer os.Remove(localFile)
+	assert.False(t, IsRemote(localFile))
+	etag, err = ETag(context.Background(), localFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "", etag)
+}
@@ -78,3 +78,13 @@ func gcsModTime(ctx context.Context, objectPath string) (time.Time, error) {
 	defer res.Body.Close()
 	return httpLastModified(res)
 }
+
+func gcsETag(ctx context.Context, objectPath string) (string, error) {
+	res, err := gcsRequest(ctx, "HEAD", objectPath)
+	if err != nil {
+		return "", err
+	}
+
+	defer res.Body.Close()
+	return res.Header.Get("ETag"), nil
+}
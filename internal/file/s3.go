@@ -55,15 +55,15 @@ func readFileFromS3(ctx context.Context, objectPath string) ([]byte, error) {
 	return io.ReadAll(result.Body)
 }
 
-func s3ModTime(ctx context.Context, objectPath string) (time.Time, error) {
+func s3HeadObject(ctx context.Context, objectPath string) (*s3.HeadObjectOutput, error) {
 	bucket, object, err := parseObjectURL(objectPath)
 	if err != nil {
-		return time.Time{}, err
+		return nil, err
 	}
 
 	sdkConfig, err := config.LoadDefaultConfig(ctx)
 	if err != nil {
-		return time.Time{}, fmt.Errorf("failed to load default config: %v", err)
+		return nil, fmt.Errorf("failed to load default config: %v", err)
 	}
 	s3Client := s3.NewFromConfig(sdkConfig)
 
@@ -72,8 +72,25 @@ func s3ModTime(ctx context.Context, objectPath string) (time.Time, error) {
 		Key:    aws.String(object),
 	})
 	if err != nil {
-		return time.Time{}, fmt.Errorf("failed to retrieve file (%s): %v", objectPath, err)
+		return nil, fmt.Errorf("failed to retrieve file (%s): %v", objectPath, err)
+	}
+	return result, nil
+}
+
+func s3ModTime(ctx context.Context, objectPath string) (time.Time, error) {
+	result, err := s3HeadObject(ctx, objectPath)
+	if err != nil {
+		return time.Time{}, err
 	}
 
 	return *result.LastModified, nil
 }
+
+func s3ETag(ctx context.Context, objectPath string) (string, error) {
+	result, err := s3HeadObject(ctx, objectPath)
+	if err != nil {
+		return "", err
+	}
+
+	return aws.ToString(result.ETag), nil
+}
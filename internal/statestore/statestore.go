@@ -0,0 +1,138 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package statestore implements a simple local store for the Cloudprober's
+in-memory state that should survive restarts, for example, ongoing alerts and
+the probestatus timeseries. State is stored as JSON files in a local directory
+configured through the ProberConfig's state_dir field. If state directory is
+not configured, store is disabled and Save and Load are no-ops.
+*/
+package statestore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DefaultSaveInterval is the default interval for saving state periodically.
+const DefaultSaveInterval = time.Minute
+
+type store struct {
+	mu           sync.RWMutex
+	dir          string
+	saveInterval time.Duration
+}
+
+var st store
+
+// Init initializes the state store with the given directory. State directory
+// is created if it doesn't exist already. An empty dir disables the store.
+func Init(dir string, saveInterval time.Duration) error {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("error creating state directory (%s): %v", dir, err)
+		}
+	}
+	if saveInterval <= 0 {
+		saveInterval = DefaultSaveInterval
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.dir, st.saveInterval = dir, saveInterval
+	return nil
+}
+
+// Enabled returns true if state store is enabled.
+func Enabled() bool {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	return st.dir != ""
+}
+
+// SaveInterval returns the interval at which components should save their
+// state, if they save it periodically.
+func SaveInterval() time.Duration {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	if st.saveInterval == 0 {
+		return DefaultSaveInterval
+	}
+	return st.saveInterval
+}
+
+func filePath(name string) string {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	if st.dir == "" {
+		return ""
+	}
+	return filepath.Join(st.dir, name+".json")
+}
+
+// Save saves the given value as JSON under the given name. File is written
+// atomically: we write to a temporary file first and then rename it.
+func Save(name string, v any) error {
+	path := filePath(name)
+	if path == "" {
+		return nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("error encoding %s state: %v", name, err)
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), "."+name+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("error creating temporary file for %s state: %v", name, err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(b); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("error writing %s state: %v", name, err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("error writing %s state: %v", name, err)
+	}
+	return os.Rename(tmpFile.Name(), path)
+}
+
+// Load loads the state saved under the given name into v. It returns false if
+// store is disabled or there is no saved state for the name.
+func Load(name string, v any) (bool, error) {
+	path := filePath(name)
+	if path == "" {
+		return false, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("error reading %s state: %v", name, err)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return false, fmt.Errorf("error decoding %s state from %s: %v", name, path, err)
+	}
+	return true, nil
+}
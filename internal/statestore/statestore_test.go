@@ -0,0 +1,71 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testState struct {
+	Name   string
+	Counts map[string]int64
+}
+
+func TestSaveLoad(t *testing.T) {
+	defer Init("", 0)
+
+	// Disabled store.
+	require.NoError(t, Init("", 0))
+	assert.False(t, Enabled())
+	assert.Equal(t, DefaultSaveInterval, SaveInterval())
+	assert.NoError(t, Save("test", &testState{Name: "t"}))
+	found, err := Load("test", &testState{})
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	dir := filepath.Join(t.TempDir(), "state")
+	require.NoError(t, Init(dir, 10*time.Second))
+	assert.True(t, Enabled())
+	assert.Equal(t, 10*time.Second, SaveInterval())
+
+	// Nothing saved yet.
+	var got testState
+	found, err = Load("test", &got)
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	want := testState{Name: "t1", Counts: map[string]int64{"a": 1, "b": 2}}
+	require.NoError(t, Save("test", &want))
+	found, err = Load("test", &got)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, want, got)
+
+	// No temporary files left behind.
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	// Corrupted state.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.json"), []byte("{"), 0644))
+	_, err = Load("bad", &got)
+	assert.Error(t, err)
+}
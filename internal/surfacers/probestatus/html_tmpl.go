@@ -72,13 +72,18 @@ populateD();
 {{$durations := .Durations}}
 {{$statusTable := .StatusTable}}
 {{$debugData := .DebugData}}
+{{$failedResponses := .FailedResponses}}
 
 <h3> Success Ratio </h3>
 <div id="probe-selector" style="background: #E1F6FF; padding: 5px 5px; border-radius: 2px 2px"></div>
 
 {{range $probeName := .ProbeNames}}
 <p>
-  <b>Probe: {{$probeName}}</b><br>
+  <b>Probe: {{$probeName}}</b>
+  {{if index $failedResponses $probeName}}
+    (<a href="{{$.LinkPrefix}}failed-responses?probe={{$probeName}}">failed responses</a>)
+  {{end}}
+  <br>
 
   <table class="status-list">
     <tr><td></td>
@@ -23,6 +23,7 @@ import (
 	"bytes"
 	"context"
 	"embed"
+	"errors"
 	"fmt"
 	"html/template"
 	"net/http"
@@ -30,10 +31,12 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cloudprober/cloudprober/internal/statestore"
 	configpb "github.com/cloudprober/cloudprober/internal/surfacers/probestatus/proto"
 	"github.com/cloudprober/cloudprober/internal/sysvars"
 	"github.com/cloudprober/cloudprober/logger"
 	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/probes/common/failedresponses"
 	"github.com/cloudprober/cloudprober/state"
 	"github.com/cloudprober/cloudprober/surfacers/options"
 	"github.com/cloudprober/cloudprober/web/resources"
@@ -46,6 +49,10 @@ var statusTmpl = template.Must(template.New("statusTmpl").Parse(htmlTmpl))
 
 const (
 	metricsBufferSize = 10000
+
+	// stateStoreName is the name under which surfacer's state is saved in
+	// the state store.
+	stateStoreName = "probestatus"
 )
 
 var dropAfterNoDataFor = 6 * time.Hour
@@ -175,15 +182,30 @@ func New(ctx context.Context, config *configpb.SurfacerConf, opts *options.Optio
 	ps.dashDurations, ps.dashDurationsText = dashboardDurations(ps.resolution * time.Duration(ps.c.GetTimeseriesSize()))
 	ps.pageCache = newPageCache(int(ps.c.GetCacheTimeSec()))
 
+	if err := ps.loadState(); err != nil {
+		ps.l.Warningf("Error loading probestatus state, starting fresh: %v", err)
+	}
+
 	// Start a goroutine to process the incoming EventMetrics as well as
 	// the incoming web queries. To avoid data access race conditions, we do
 	// one thing at a time.
 	go func() {
+		// Save state periodically, if state store is enabled.
+		var saveTickerCh <-chan time.Time
+		if statestore.Enabled() {
+			saveTicker := time.NewTicker(statestore.SaveInterval())
+			defer saveTicker.Stop()
+			saveTickerCh = saveTicker.C
+		}
+
 		for {
 			select {
 			case <-ctx.Done():
 				ps.l.Infof("Context canceled, stopping the input/output processing loop.")
+				ps.saveState()
 				return
+			case <-saveTickerCh:
+				ps.saveState()
 			case em := <-ps.emChan:
 				ps.record(em)
 			case hw := <-ps.queryChan:
@@ -195,39 +217,42 @@ func New(ctx context.Context, config *configpb.SurfacerConf, opts *options.Optio
 		}
 	}()
 
-	state.AddWebHandler(config.GetUrl(), func(w http.ResponseWriter, r *http.Request) {
+	// Web handlers are bound to the surfacer's context, so that they go away
+	// along with the processing loop, e.g. when the surfacer is replaced on
+	// config reload.
+	if err := state.AddWebHandler(config.GetUrl(), func(w http.ResponseWriter, r *http.Request) {
 		// doneChan is used to track the completion of the response writing. This is
 		// required as response is written in a different goroutine.
 		doneChan := make(chan struct{}, 1)
 		ps.queryChan <- &httpWriter{w, r, doneChan}
 		<-doneChan
-	})
+	}, state.WithContext(ctx)); err != nil {
+		return nil, fmt.Errorf("error adding probestatus handler: %v", err)
+	}
 
 	redirectHTML := fmt.Sprintf(`<html><meta http-equiv="refresh" content="0; url=%s"></html>`, strings.TrimLeft(config.GetUrl(), "/"))
 
-	// Make sure older path /probestatus is redirected to the new path.
-	if !state.IsHandled("/probestatus") {
-		if err := state.AddWebHandler("/probestatus", func(w http.ResponseWriter, r *http.Request) {
-			fmt.Fprint(w, redirectHTML)
-		}); err != nil {
-			return nil, fmt.Errorf("error setting up /probestatus redirect: %v", err)
-		}
+	// Make sure older path /probestatus is redirected to the new path. We
+	// skip redirects for the paths that are already handled by someone else.
+	err := state.AddWebHandler("/probestatus", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, redirectHTML)
+	}, state.WithContext(ctx))
+	if err != nil && !errors.Is(err, state.ErrAlreadyRegistered) {
+		return nil, fmt.Errorf("error setting up /probestatus redirect: %v", err)
 	}
 
-	if !state.IsHandled("/") {
-		err := state.AddWebHandler("/", func(w http.ResponseWriter, r *http.Request) {
-			if r.URL.Path != "/" {
-				http.NotFound(w, r)
-				return
-			}
-			fmt.Fprint(w, redirectHTML)
-		})
-		if err != nil {
-			return nil, fmt.Errorf("error adding / handler: %v", err)
+	err = state.AddWebHandler("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
 		}
+		fmt.Fprint(w, redirectHTML)
+	}, state.WithContext(ctx))
+	if err != nil && !errors.Is(err, state.ErrAlreadyRegistered) {
+		return nil, fmt.Errorf("error adding / handler: %v", err)
 	}
 
-	if err := state.AddWebHandler(config.GetUrl()+"/static/", http.StripPrefix(config.GetUrl(), http.FileServer(http.FS(content))).ServeHTTP); err != nil {
+	if err := state.AddWebHandler(config.GetUrl()+"/static/", http.StripPrefix(config.GetUrl(), http.FileServer(http.FS(content))).ServeHTTP, state.WithContext(ctx)); err != nil {
 		return nil, fmt.Errorf("error adding static file handler: %v", err)
 	}
 
@@ -356,10 +381,83 @@ func (ps *Surfacer) record(em *metrics.EventMetrics) {
 		ps.probeTargets[probeName] = append(ps.probeTargets[probeName], targetName)
 	}
 
-	targetTS.addDatum(em.Timestamp, &datum{
+	targetTS.addDatum(em.Timestamp, targetTS.adjustForReset(&datum{
 		total:   total.Int64(),
 		success: success.Int64(),
-	})
+	}))
+}
+
+// savedState is the surfacer state saved in the state store.
+type savedState struct {
+	Resolution time.Duration
+	Probes     []*savedProbe
+}
+
+type savedProbe struct {
+	Name    string
+	Targets []*savedTarget
+}
+
+type savedTarget struct {
+	Name       string
+	Timeseries *savedTimeseries
+}
+
+// saveState saves the timeseries to the state store. Must be called from the
+// event loop goroutine.
+func (ps *Surfacer) saveState() {
+	if !statestore.Enabled() {
+		return
+	}
+
+	ss := &savedState{Resolution: ps.resolution}
+	for _, probeName := range ps.probeNames {
+		sp := &savedProbe{Name: probeName}
+		for _, targetName := range ps.probeTargets[probeName] {
+			if ts := ps.metrics[probeName][targetName]; ts != nil {
+				sp.Targets = append(sp.Targets, &savedTarget{Name: targetName, Timeseries: ts.toSaved()})
+			}
+		}
+		ss.Probes = append(ss.Probes, sp)
+	}
+
+	if err := statestore.Save(stateStoreName, ss); err != nil {
+		ps.l.Warningf("Error saving probestatus state: %v", err)
+	}
+}
+
+// loadState restores the timeseries from the state store. It should be
+// called before starting the event loop.
+func (ps *Surfacer) loadState() error {
+	ss := &savedState{}
+	found, err := statestore.Load(stateStoreName, ss)
+	if err != nil || !found {
+		return err
+	}
+	if ss.Resolution != ps.resolution {
+		return fmt.Errorf("saved state resolution (%v) doesn't match the current resolution (%v)", ss.Resolution, ps.resolution)
+	}
+
+	for _, sp := range ss.Probes {
+		if ps.metrics[sp.Name] != nil {
+			continue
+		}
+		probeTS := make(map[string]*timeseries)
+		ps.metrics[sp.Name] = probeTS
+		ps.probeNames = append(ps.probeNames, sp.Name)
+
+		for _, st := range sp.Targets {
+			if st.Timeseries == nil || len(probeTS) >= int(ps.c.GetMaxTargetsPerProbe()) {
+				continue
+			}
+			ts := newTimeseries(ps.resolution, int(ps.c.GetTimeseriesSize()), ps.l)
+			ts.restore(st.Timeseries)
+			probeTS[st.Name] = ts
+			ps.probeTargets[sp.Name] = append(ps.probeTargets[sp.Name], st.Name)
+		}
+	}
+	ps.l.Infof("Restored probestatus state for %d probes", len(ss.Probes))
+	return nil
 }
 
 func (ps *Surfacer) deleteTargetWithNoLock(probeName, targetName string) {
@@ -476,6 +574,11 @@ func (ps *Surfacer) writeData(hw *httpWriter) {
 		graphData[probeName] = template.JS(gd.JSONBytes(ps.l))
 	}
 
+	failedResponses := make(map[string]bool)
+	for _, probeName := range failedresponses.Probes() {
+		failedResponses[probeName] = true
+	}
+
 	var statusBuf bytes.Buffer
 
 	// TODO(manugarg): We should stop supporting custom URL for the status
@@ -493,17 +596,21 @@ func (ps *Surfacer) writeData(hw *httpWriter) {
 		Header      template.HTML
 		LinkPrefix  string
 		StartTime   fmt.Stringer
+
+		// Probes that keep failed responses.
+		FailedResponses map[string]bool
 	}{
-		BaseURL:     linkPrefix + strings.TrimLeft(ps.c.GetUrl(), "/"),
-		Durations:   ps.dashDurationsText,
-		ProbeNames:  probes,
-		AllProbes:   ps.probeNames,
-		StatusTable: statusTable,
-		GraphData:   graphData,
-		DebugData:   debugData,
-		Header:      resources.Header(linkPrefix),
-		LinkPrefix:  linkPrefix,
-		StartTime:   ps.startTime,
+		BaseURL:         linkPrefix + strings.TrimLeft(ps.c.GetUrl(), "/"),
+		Durations:       ps.dashDurationsText,
+		ProbeNames:      probes,
+		AllProbes:       ps.probeNames,
+		StatusTable:     statusTable,
+		GraphData:       graphData,
+		DebugData:       debugData,
+		Header:          resources.Header(linkPrefix),
+		LinkPrefix:      linkPrefix,
+		StartTime:       ps.startTime,
+		FailedResponses: failedResponses,
 	})
 	if err != nil {
 		ps.l.Errorf("Error executing probe status template: %v", err)
@@ -21,11 +21,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cloudprober/cloudprober/internal/statestore"
 	configpb "github.com/cloudprober/cloudprober/internal/surfacers/probestatus/proto"
 	"github.com/cloudprober/cloudprober/metrics"
 	"github.com/cloudprober/cloudprober/state"
 	"github.com/cloudprober/cloudprober/surfacers/options"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -337,3 +339,53 @@ func TestSurfacerWriteData(t *testing.T) {
 		})
 	}
 }
+
+func TestSaveAndLoadState(t *testing.T) {
+	state.SetDefaultHTTPServeMux(http.NewServeMux())
+	defer state.SetDefaultHTTPServeMux(nil)
+
+	require.NoError(t, statestore.Init(t.TempDir(), 0))
+	defer statestore.Init("", 0)
+
+	conf := &configpb.SurfacerConf{
+		TimeseriesSize:     proto.Int32(10),
+		MaxTargetsPerProbe: proto.Int32(2),
+	}
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	ps, err := New(ctx, conf, &options.Options{}, nil)
+	require.NoError(t, err)
+
+	start := time.Now().Truncate(time.Minute).Add(-5 * time.Minute)
+	for i := 0; i < 5; i++ {
+		ts := start.Add(time.Duration(i) * time.Minute)
+		ps.Write(ctx, testEM(t, ts, "p1", "t1", (i+1)*100, (i+1)*90, 0))
+		ps.Write(ctx, testEM(t, ts, "p2", "t2", (i+1)*10, (i+1)*10, 0))
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	// Canceling the context saves the state.
+	cancelFunc()
+	time.Sleep(50 * time.Millisecond)
+
+	state.SetDefaultHTTPServeMux(http.NewServeMux())
+	ctx, cancelFunc = context.WithCancel(context.Background())
+	defer cancelFunc()
+	ps2, err := New(ctx, conf, &options.Options{}, nil)
+	require.NoError(t, err)
+
+	result, err := ps2.QueryStatus(ctx, nil, time.Time{}, 4*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, []*ProbeStatusData{
+		{Name: "p1", TargetStatuses: []*TargetStatusData{{TargetName: "t1", Total: 400, Success: 360}}},
+		{Name: "p2", TargetStatuses: []*TargetStatusData{{TargetName: "t2", Total: 40, Success: 40}}},
+	}, result)
+
+	// Resolution change, state is not loaded.
+	state.SetDefaultHTTPServeMux(http.NewServeMux())
+	ps3, err := New(ctx, &configpb.SurfacerConf{ResolutionSec: proto.Int32(30)}, &options.Options{}, nil)
+	require.NoError(t, err)
+	result, err = ps3.QueryStatus(ctx, nil, time.Time{}, 4*time.Minute)
+	require.NoError(t, err)
+	assert.Len(t, result, 0)
+}
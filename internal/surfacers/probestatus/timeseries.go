@@ -27,6 +27,11 @@ type timeseries struct {
 	currentTS            time.Time
 	startTime            time.Time
 	l                    *logger.Logger
+
+	// Used to keep the timeseries monotonic across counter resets, e.g. when
+	// timeseries is restored after a restart.
+	lastRaw *datum
+	offset  datum
 }
 
 func (ts *timeseries) shallowCopy() *timeseries {
@@ -83,6 +88,70 @@ func (ts *timeseries) addDatum(t time.Time, d *datum) {
 	}
 }
 
+// adjustForReset adjusts the incoming cumulative datum for the counter
+// resets. If counters go down, we assume that they were reset and add the last
+// known values to all the subsequent data.
+func (ts *timeseries) adjustForReset(d *datum) *datum {
+	if ts.lastRaw != nil && d.total < ts.lastRaw.total {
+		if latest := ts.a[ts.latestIdx]; latest != nil {
+			ts.offset = *latest
+		}
+	}
+	ts.lastRaw = d
+	return &datum{
+		total:   d.total + ts.offset.total,
+		success: d.success + ts.offset.success,
+	}
+}
+
+// savedTimeseries is the timeseries representation used for the state store.
+type savedTimeseries struct {
+	StartTime time.Time
+	CurrentTS time.Time
+	// Total and success values, from the oldest to the latest.
+	Total   []int64
+	Success []int64
+}
+
+func (ts *timeseries) toSaved() *savedTimeseries {
+	sts := &savedTimeseries{
+		StartTime: ts.startTime,
+		CurrentTS: ts.currentTS,
+	}
+	if ts.a[ts.latestIdx] == nil {
+		return sts
+	}
+	for i := ts.oldestIdx; ; i = (i + 1) % len(ts.a) {
+		if d := ts.a[i]; d != nil {
+			sts.Total = append(sts.Total, d.total)
+			sts.Success = append(sts.Success, d.success)
+		}
+		if i == ts.latestIdx {
+			break
+		}
+	}
+	return sts
+}
+
+// restore replays the saved data into the timeseries. Saved data points are
+// assumed to be ts.res apart.
+func (ts *timeseries) restore(sts *savedTimeseries) {
+	if len(sts.Total) != len(sts.Success) {
+		return
+	}
+	if !sts.StartTime.IsZero() {
+		ts.startTime = sts.StartTime
+	}
+	n := len(sts.Total)
+	for i := 0; i < n; i++ {
+		t := sts.CurrentTS.Add(-time.Duration(n-1-i) * ts.res)
+		ts.addDatum(t, &datum{total: sts.Total[i], success: sts.Success[i]})
+	}
+	if n > 0 {
+		ts.lastRaw = &datum{total: sts.Total[n-1], success: sts.Success[n-1]}
+	}
+}
+
 func (ts *timeseries) agoIndex(durationCount int) int {
 	// This happens before first rotation, and after that whenever rotation
 	// happens.
@@ -283,3 +283,54 @@ func TestComputeDeltaEndTime(t *testing.T) {
 		}
 	})
 }
+
+func TestTimeseriesAdjustForReset(t *testing.T) {
+	ts := newTimeseries(time.Minute, 10, nil)
+	start := time.Now().Truncate(time.Minute)
+
+	inputs := []datum{{total: 10, success: 9}, {total: 20, success: 18}, {total: 5, success: 5}, {total: 15, success: 14}, {total: 2, success: 1}}
+	want := []datum{{total: 10, success: 9}, {total: 20, success: 18}, {total: 25, success: 23}, {total: 35, success: 32}, {total: 37, success: 33}}
+	for i, d := range inputs {
+		d := d
+		ts.addDatum(start.Add(time.Duration(i)*time.Minute), ts.adjustForReset(&d))
+		assert.Equal(t, want[i], *ts.a[ts.latestIdx], "datum %d", i)
+	}
+}
+
+func TestTimeseriesSaveRestore(t *testing.T) {
+	ts := newTimeseries(time.Minute, 5, nil)
+	start := time.Now().Truncate(time.Minute).Add(-10 * time.Minute)
+
+	// Empty timeseries.
+	sts := ts.toSaved()
+	assert.Len(t, sts.Total, 0)
+
+	// 7 data points, the first 2 will roll over.
+	for i := 0; i < 7; i++ {
+		ts.addDatum(start.Add(time.Duration(i)*time.Minute), &datum{total: int64(10 * (i + 1)), success: int64(9 * (i + 1))})
+	}
+	sts = ts.toSaved()
+	assert.Equal(t, []int64{30, 40, 50, 60, 70}, sts.Total)
+	assert.Equal(t, []int64{27, 36, 45, 54, 63}, sts.Success)
+	assert.Equal(t, ts.currentTS, sts.CurrentTS)
+
+	restored := newTimeseries(time.Minute, 5, nil)
+	restored.restore(sts)
+	assert.Equal(t, ts.currentTS, restored.currentTS)
+	assert.Equal(t, ts.startTime, restored.startTime)
+	for _, td := range []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute} {
+		wantT, wantS := ts.computeDelta(time.Time{}, td)
+		gotT, gotS := restored.computeDelta(time.Time{}, td)
+		assert.Equal(t, wantT, gotT, "total delta for %v", td)
+		assert.Equal(t, wantS, gotS, "success delta for %v", td)
+	}
+
+	// Counters reset after restore.
+	restored.addDatum(start.Add(7*time.Minute), restored.adjustForReset(&datum{total: 10, success: 10}))
+	assert.Equal(t, datum{total: 80, success: 73}, *restored.a[restored.latestIdx])
+
+	// Restoring into a smaller timeseries keeps the latest data.
+	small := newTimeseries(time.Minute, 3, nil)
+	small.restore(sts)
+	assert.Equal(t, []int64{50, 60, 70}, small.toSaved().Total)
+}
@@ -238,13 +238,16 @@ func New(ctx context.Context, config *configpb.SurfacerConf, opts *options.Optio
 		}
 	}()
 
+	// Handler is bound to the surfacer's context, so that it goes away along
+	// with the processing loop, e.g. when the surfacer is replaced on config
+	// reload.
 	err := state.AddWebHandler(ps.c.GetMetricsUrl(), func(w http.ResponseWriter, r *http.Request) {
 		// doneChan is used to track the completion of the response writing. This is
 		// required as response is written in a different goroutine.
 		doneChan := make(chan struct{}, 1)
 		ps.queryChan <- &httpWriter{w, doneChan}
 		<-doneChan
-	})
+	}, state.WithContext(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,154 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dns provides a DNS validator for the Cloudprober's validator
+// framework.
+package dns
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	configpb "github.com/cloudprober/cloudprober/internal/validators/dns/proto"
+	"github.com/cloudprober/cloudprober/logger"
+	"github.com/miekg/dns"
+)
+
+// Validator implements a validator for DNS responses.
+type Validator struct {
+	c     *configpb.Validator
+	rcode int
+}
+
+// Init initializes the DNS validator.
+func (v *Validator) Init(config interface{}) error {
+	c, ok := config.(*configpb.Validator)
+	if !ok {
+		return fmt.Errorf("%v is not a valid DNS validator config", config)
+	}
+
+	if c.String() == "" {
+		return errors.New("empty DNS validator config")
+	}
+
+	v.c = c
+	v.rcode = -1
+	if c.GetRcode() != "" {
+		rcode, ok := dns.StringToRcode[strings.ToUpper(c.GetRcode())]
+		if !ok {
+			return fmt.Errorf("invalid rcode: %s", c.GetRcode())
+		}
+		v.rcode = rcode
+	}
+
+	if c.GetExactAnswers() && len(c.GetAnswer()) == 0 {
+		return errors.New("exact_answers requires at least one answer")
+	}
+	if c.MinAnswers != nil && c.MaxAnswers != nil && c.GetMinAnswers() > c.GetMaxAnswers() {
+		return fmt.Errorf("min_answers (%d) cannot be greater than max_answers (%d)", c.GetMinAnswers(), c.GetMaxAnswers())
+	}
+	if c.MinTtl != nil && c.MaxTtl != nil && c.GetMinTtl() > c.GetMaxTtl() {
+		return fmt.Errorf("min_ttl (%d) cannot be greater than max_ttl (%d)", c.GetMinTtl(), c.GetMaxTtl())
+	}
+
+	return nil
+}
+
+// ExpectsRcode returns true if validator explicitly checks the response code.
+func (v *Validator) ExpectsRcode() bool {
+	return v.rcode != -1
+}
+
+// rdata returns the record data part of the record's text representation.
+func rdata(rr dns.RR) string {
+	return strings.ToLower(strings.TrimPrefix(rr.String(), rr.Header().String()))
+}
+
+// normalize converts the configured answer to the format used by rdata(),
+// e.g. "2001:DB8:0::1" becomes "2001:db8::1" and "mx.example.com" becomes
+// "mx.example.com.".
+func normalize(qtype uint16, answer string) string {
+	rr, err := dns.NewRR(". 0 IN " + dns.TypeToString[qtype] + " " + answer)
+	if err != nil || rr == nil {
+		return strings.ToLower(answer)
+	}
+	return rdata(rr)
+}
+
+// Validate the provided input and return true if input is valid. Validate
+// expects the input to be of the type: *dns.Msg.
+func (v *Validator) Validate(input interface{}, l *logger.Logger) (bool, error) {
+	msg, ok := input.(*dns.Msg)
+	if !ok || msg == nil {
+		return false, fmt.Errorf("input %v is not of type *dns.Msg", input)
+	}
+
+	if v.rcode != -1 && msg.Rcode != v.rcode {
+		l.Errorf("DNS validation failure: rcode %s, expected: %s", dns.RcodeToString[msg.Rcode], dns.RcodeToString[v.rcode])
+		return false, nil
+	}
+
+	var qtype uint16
+	if len(msg.Question) > 0 {
+		qtype = msg.Question[0].Qtype
+	}
+
+	var answers []string
+	for _, rr := range msg.Answer {
+		if rr == nil || (qtype != 0 && rr.Header().Rrtype != qtype) {
+			continue
+		}
+		if v.c.MinTtl != nil && rr.Header().Ttl < v.c.GetMinTtl() {
+			l.Errorf("DNS validation failure: TTL %d is less than min_ttl (%d) for record: %s", rr.Header().Ttl, v.c.GetMinTtl(), rr.String())
+			return false, nil
+		}
+		if v.c.MaxTtl != nil && rr.Header().Ttl > v.c.GetMaxTtl() {
+			l.Errorf("DNS validation failure: TTL %d is greater than max_ttl (%d) for record: %s", rr.Header().Ttl, v.c.GetMaxTtl(), rr.String())
+			return false, nil
+		}
+		answers = append(answers, rdata(rr))
+	}
+
+	if v.c.MinAnswers != nil && len(answers) < int(v.c.GetMinAnswers()) {
+		l.Errorf("DNS validation failure: got %d answers, min_answers: %d", len(answers), v.c.GetMinAnswers())
+		return false, nil
+	}
+	if v.c.MaxAnswers != nil && len(answers) > int(v.c.GetMaxAnswers()) {
+		l.Errorf("DNS validation failure: got %d answers, max_answers: %d", len(answers), v.c.GetMaxAnswers())
+		return false, nil
+	}
+
+	var expected []string
+	for _, answer := range v.c.GetAnswer() {
+		expected = append(expected, normalize(qtype, answer))
+	}
+	for _, want := range expected {
+		if !slices.Contains(answers, want) {
+			l.Errorf("DNS validation failure: answer %q not found in answers: %v", want, answers)
+			return false, nil
+		}
+	}
+	if v.c.GetExactAnswers() {
+		for _, got := range answers {
+			if !slices.Contains(expected, got) {
+				l.Errorf("DNS validation failure: unexpected answer %q, expected answers: %v", got, expected)
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
+}
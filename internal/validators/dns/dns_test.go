@@ -0,0 +1,178 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dns
+
+import (
+	"testing"
+
+	configpb "github.com/cloudprober/cloudprober/internal/validators/dns/proto"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func testMsg(t *testing.T, qtype uint16, rcode int, answers ...string) *dns.Msg {
+	t.Helper()
+	msg := new(dns.Msg)
+	msg.SetQuestion("www.example.com.", qtype)
+	msg.Rcode = rcode
+	for _, a := range answers {
+		rr, err := dns.NewRR(a)
+		if err != nil {
+			t.Fatalf("error parsing record %s: %v", a, err)
+		}
+		msg.Answer = append(msg.Answer, rr)
+	}
+	return msg
+}
+
+func TestInit(t *testing.T) {
+	tests := []struct {
+		name    string
+		conf    *configpb.Validator
+		wantErr bool
+	}{
+		{name: "empty", conf: &configpb.Validator{}, wantErr: true},
+		{name: "valid", conf: &configpb.Validator{Rcode: "nxdomain"}},
+		{name: "bad_rcode", conf: &configpb.Validator{Rcode: "NOTANRCODE"}, wantErr: true},
+		{name: "exact_without_answers", conf: &configpb.Validator{ExactAnswers: true}, wantErr: true},
+		{name: "bad_answer_range", conf: &configpb.Validator{MinAnswers: proto.Int32(3), MaxAnswers: proto.Int32(2)}, wantErr: true},
+		{name: "bad_ttl_range", conf: &configpb.Validator{MinTtl: proto.Uint32(300), MaxTtl: proto.Uint32(60)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &Validator{}
+			err := v.Init(tt.conf)
+			assert.Equal(t, tt.wantErr, err != nil, "error: %v", err)
+		})
+	}
+
+	v := &Validator{}
+	assert.NoError(t, v.Init(&configpb.Validator{Answer: []string{"1.2.3.4"}}))
+	assert.False(t, v.ExpectsRcode())
+	assert.NoError(t, v.Init(&configpb.Validator{Rcode: "NXDOMAIN"}))
+	assert.True(t, v.ExpectsRcode())
+}
+
+func TestValidate(t *testing.T) {
+	aMsg := testMsg(t, dns.TypeA, dns.RcodeSuccess,
+		"www.example.com. 300 IN CNAME web.example.com.",
+		"web.example.com. 300 IN A 192.168.1.1",
+		"web.example.com. 120 IN A 192.168.1.2",
+	)
+
+	tests := []struct {
+		name string
+		conf *configpb.Validator
+		msg  *dns.Msg
+		want bool
+	}{
+		{
+			name: "rcode_match",
+			conf: &configpb.Validator{Rcode: "NXDOMAIN"},
+			msg:  testMsg(t, dns.TypeA, dns.RcodeNameError),
+			want: true,
+		},
+		{
+			name: "rcode_mismatch",
+			conf: &configpb.Validator{Rcode: "NOERROR"},
+			msg:  testMsg(t, dns.TypeA, dns.RcodeServerFailure),
+		},
+		{
+			name: "answers_present",
+			conf: &configpb.Validator{Answer: []string{"192.168.1.2"}},
+			msg:  aMsg,
+			want: true,
+		},
+		{
+			name: "answer_missing",
+			conf: &configpb.Validator{Answer: []string{"192.168.1.3"}},
+			msg:  aMsg,
+		},
+		{
+			name: "exact_answers",
+			conf: &configpb.Validator{Answer: []string{"192.168.1.2", "192.168.1.1"}, ExactAnswers: true},
+			msg:  aMsg,
+			want: true,
+		},
+		{
+			name: "exact_answers_extra",
+			conf: &configpb.Validator{Answer: []string{"192.168.1.1"}, ExactAnswers: true},
+			msg:  aMsg,
+		},
+		{
+			name: "answer_normalized",
+			conf: &configpb.Validator{Answer: []string{"10 MX.example.com"}},
+			msg:  testMsg(t, dns.TypeMX, dns.RcodeSuccess, "www.example.com. 300 IN MX 10 mx.example.com."),
+			want: true,
+		},
+		{
+			name: "ipv6_normalized",
+			conf: &configpb.Validator{Answer: []string{"2001:DB8:0:0::1"}},
+			msg:  testMsg(t, dns.TypeAAAA, dns.RcodeSuccess, "www.example.com. 300 IN AAAA 2001:db8::1"),
+			want: true,
+		},
+		{
+			name: "answer_count_ok",
+			conf: &configpb.Validator{MinAnswers: proto.Int32(2), MaxAnswers: proto.Int32(2)},
+			msg:  aMsg,
+			want: true,
+		},
+		{
+			name: "too_few_answers",
+			conf: &configpb.Validator{MinAnswers: proto.Int32(3)},
+			msg:  aMsg,
+		},
+		{
+			name: "too_many_answers",
+			conf: &configpb.Validator{MaxAnswers: proto.Int32(1)},
+			msg:  aMsg,
+		},
+		{
+			name: "ttl_in_range",
+			conf: &configpb.Validator{MinTtl: proto.Uint32(60), MaxTtl: proto.Uint32(300)},
+			msg:  aMsg,
+			want: true,
+		},
+		{
+			name: "ttl_too_low",
+			conf: &configpb.Validator{MinTtl: proto.Uint32(200)},
+			msg:  aMsg,
+		},
+		{
+			name: "ttl_too_high",
+			conf: &configpb.Validator{MaxTtl: proto.Uint32(200)},
+			msg:  aMsg,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &Validator{}
+			if err := v.Init(tt.conf); err != nil {
+				t.Fatalf("error initializing validator: %v", err)
+			}
+			got, err := v.Validate(tt.msg, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	v := &Validator{}
+	v.Init(&configpb.Validator{Rcode: "NOERROR"})
+	_, err := v.Validate("not a dns message", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,216 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.5
+// source: github.com/cloudprober/cloudprober/internal/validators/dns/proto/config.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// DNS validator configuration. It validates the DNS response message
+// directly, instead of its text representation. For DNS validator to succeed,
+// all conditions specified in the validator should succeed.
+//
+// Answer, count and TTL conditions consider only the answer records that
+// match the question type, e.g. CNAME and RRSIG records are ignored for an "A"
+// query.
+//
+// Example:
+//
+//	validator {
+//	  name: "www_answers"
+//	  dns_validator {
+//	    answer: "192.168.1.1"
+//	    answer: "192.168.1.2"
+//	    exact_answers: true
+//	    min_ttl: 60
+//	  }
+//	}
+type Validator struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Expected response code, e.g. "NOERROR", "NXDOMAIN", "SERVFAIL". Note that
+	// DNS probe considers responses with a code other than NOERROR as failures,
+	// unless they are explicitly expected by a DNS validator.
+	Rcode string `protobuf:"bytes,1,opt,name=rcode,proto3" json:"rcode,omitempty"`
+	// Records that should be present in the answer section, in the record data
+	// format, e.g. "192.168.1.1" for A records and "10 mx.example.com." for MX
+	// records.
+	Answer []string `protobuf:"bytes,2,rep,name=answer,proto3" json:"answer,omitempty"`
+	// If true, answers should exactly match the "answer" field above, i.e. no
+	// other answers are allowed.
+	ExactAnswers bool `protobuf:"varint,3,opt,name=exact_answers,json=exactAnswers,proto3" json:"exact_answers,omitempty"`
+	// Minimum and maximum number of answers.
+	MinAnswers *int32 `protobuf:"varint,4,opt,name=min_answers,json=minAnswers,proto3,oneof" json:"min_answers,omitempty"`
+	MaxAnswers *int32 `protobuf:"varint,5,opt,name=max_answers,json=maxAnswers,proto3,oneof" json:"max_answers,omitempty"`
+	// Minimum and maximum TTL, checked for every answer.
+	MinTtl        *uint32 `protobuf:"varint,6,opt,name=min_ttl,json=minTtl,proto3,oneof" json:"min_ttl,omitempty"`
+	MaxTtl        *uint32 `protobuf:"varint,7,opt,name=max_ttl,json=maxTtl,proto3,oneof" json:"max_ttl,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Validator) Reset() {
+	*x = Validator{}
+	mi := &file_github_com_cloudprober_cloudprober_internal_validators_dns_proto_config_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Validator) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Validator) ProtoMessage() {}
+
+func (x *Validator) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_internal_validators_dns_proto_config_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Validator.ProtoReflect.Descriptor instead.
+func (*Validator) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_internal_validators_dns_proto_config_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Validator) GetRcode() string {
+	if x != nil {
+		return x.Rcode
+	}
+	return ""
+}
+
+func (x *Validator) GetAnswer() []string {
+	if x != nil {
+		return x.Answer
+	}
+	return nil
+}
+
+func (x *Validator) GetExactAnswers() bool {
+	if x != nil {
+		return x.ExactAnswers
+	}
+	return false
+}
+
+func (x *Validator) GetMinAnswers() int32 {
+	if x != nil && x.MinAnswers != nil {
+		return *x.MinAnswers
+	}
+	return 0
+}
+
+func (x *Validator) GetMaxAnswers() int32 {
+	if x != nil && x.MaxAnswers != nil {
+		return *x.MaxAnswers
+	}
+	return 0
+}
+
+func (x *Validator) GetMinTtl() uint32 {
+	if x != nil && x.MinTtl != nil {
+		return *x.MinTtl
+	}
+	return 0
+}
+
+func (x *Validator) GetMaxTtl() uint32 {
+	if x != nil && x.MaxTtl != nil {
+		return *x.MaxTtl
+	}
+	return 0
+}
+
+var File_github_com_cloudprober_cloudprober_internal_validators_dns_proto_config_proto protoreflect.FileDescriptor
+
+const file_github_com_cloudprober_cloudprober_internal_validators_dns_proto_config_proto_rawDesc = "" +
+	"\n" +
+	"Mgithub.com/cloudprober/cloudprober/internal/validators/dns/proto/config.proto\x12\x1acloudprober.validators.dns\"\x9e\x02\n" +
+	"\tValidator\x12\x14\n" +
+	"\x05rcode\x18\x01 \x01(\tR\x05rcode\x12\x16\n" +
+	"\x06answer\x18\x02 \x03(\tR\x06answer\x12#\n" +
+	"\rexact_answers\x18\x03 \x01(\bR\fexactAnswers\x12$\n" +
+	"\vmin_answers\x18\x04 \x01(\x05H\x00R\n" +
+	"minAnswers\x88\x01\x01\x12$\n" +
+	"\vmax_answers\x18\x05 \x01(\x05H\x01R\n" +
+	"maxAnswers\x88\x01\x01\x12\x1c\n" +
+	"\amin_ttl\x18\x06 \x01(\rH\x02R\x06minTtl\x88\x01\x01\x12\x1c\n" +
+	"\amax_ttl\x18\a \x01(\rH\x03R\x06maxTtl\x88\x01\x01B\x0e\n" +
+	"\f_min_answersB\x0e\n" +
+	"\f_max_answersB\n" +
+	"\n" +
+	"\b_min_ttlB\n" +
+	"\n" +
+	"\b_max_ttlBBZ@github.com/cloudprober/cloudprober/internal/validators/dns/protob\x06proto3"
+
+var (
+	file_github_com_cloudprober_cloudprober_internal_validators_dns_proto_config_proto_rawDescOnce sync.Once
+	file_github_com_cloudprober_cloudprober_internal_validators_dns_proto_config_proto_rawDescData []byte
+)
+
+func file_github_com_cloudprober_cloudprober_internal_validators_dns_proto_config_proto_rawDescGZIP() []byte {
+	file_github_com_cloudprober_cloudprober_internal_validators_dns_proto_config_proto_rawDescOnce.Do(func() {
+		file_github_com_cloudprober_cloudprober_internal_validators_dns_proto_config_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_github_com_cloudprober_cloudprober_internal_validators_dns_proto_config_proto_rawDesc), len(file_github_com_cloudprober_cloudprober_internal_validators_dns_proto_config_proto_rawDesc)))
+	})
+	return file_github_com_cloudprober_cloudprober_internal_validators_dns_proto_config_proto_rawDescData
+}
+
+var file_github_com_cloudprober_cloudprober_internal_validators_dns_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_github_com_cloudprober_cloudprober_internal_validators_dns_proto_config_proto_goTypes = []any{
+	(*Validator)(nil), // 0: cloudprober.validators.dns.Validator
+}
+var file_github_com_cloudprober_cloudprober_internal_validators_dns_proto_config_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() {
+	file_github_com_cloudprober_cloudprober_internal_validators_dns_proto_config_proto_init()
+}
+func file_github_com_cloudprober_cloudprober_internal_validators_dns_proto_config_proto_init() {
+	if File_github_com_cloudprober_cloudprober_internal_validators_dns_proto_config_proto != nil {
+		return
+	}
+	file_github_com_cloudprober_cloudprober_internal_validators_dns_proto_config_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_github_com_cloudprober_cloudprober_internal_validators_dns_proto_config_proto_rawDesc), len(file_github_com_cloudprober_cloudprober_internal_validators_dns_proto_config_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_github_com_cloudprober_cloudprober_internal_validators_dns_proto_config_proto_goTypes,
+		DependencyIndexes: file_github_com_cloudprober_cloudprober_internal_validators_dns_proto_config_proto_depIdxs,
+		MessageInfos:      file_github_com_cloudprober_cloudprober_internal_validators_dns_proto_config_proto_msgTypes,
+	}.Build()
+	File_github_com_cloudprober_cloudprober_internal_validators_dns_proto_config_proto = out.File
+	file_github_com_cloudprober_cloudprober_internal_validators_dns_proto_config_proto_goTypes = nil
+	file_github_com_cloudprober_cloudprober_internal_validators_dns_proto_config_proto_depIdxs = nil
+}
@@ -19,6 +19,7 @@ package validators
 import (
 	"fmt"
 
+	"github.com/cloudprober/cloudprober/internal/validators/dns"
 	"github.com/cloudprober/cloudprober/internal/validators/http"
 	"github.com/cloudprober/cloudprober/internal/validators/integrity"
 	"github.com/cloudprober/cloudprober/internal/validators/json"
@@ -36,6 +37,10 @@ import (
 type Validator struct {
 	Name     string
 	Validate func(input *Input, l *logger.Logger) (bool, error)
+
+	// ChecksStatus is set if validator checks the response status (e.g. DNS
+	// rcode) itself. Probes use it to skip their default status check.
+	ChecksStatus bool
 }
 
 // Init initializes the validators defined in the config.
@@ -78,6 +83,17 @@ func initValidator(validatorConf *configpb.Validator) (validator *Validator, err
 		}
 		return
 
+	case *configpb.Validator_DnsValidator:
+		v := &dns.Validator{}
+		if err := v.Init(validatorConf.GetDnsValidator()); err != nil {
+			return nil, err
+		}
+		validator.Validate = func(input *Input, l *logger.Logger) (bool, error) {
+			return v.Validate(input.Response, l)
+		}
+		validator.ChecksStatus = v.ExpectsRcode()
+		return
+
 	case *configpb.Validator_IntegrityValidator:
 		v := &integrity.Validator{}
 		if err := v.Init(validatorConf.GetIntegrityValidator()); err != nil {
@@ -92,8 +92,14 @@ func TestInit(t *testing.T) {
 						pattern_num_bytes: 8
 					}
 				`,
+				`
+					name: "dns_answers"
+					dns_validator {
+						answer: "192.168.1.1"
+					}
+				`,
 			},
-			wantNames: []string{"http_status_200s", "found_string", "valid_json", "integrity"},
+			wantNames: []string{"http_status_200s", "found_string", "valid_json", "integrity", "dns_answers"},
 		},
 		{
 			name: "missing name",
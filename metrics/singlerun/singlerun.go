@@ -145,6 +145,35 @@ func FormatProbeRunResults(probeResults map[string][]*ProbeRunResult, format For
 	return ""
 }
 
+// resultMetricsToProto converts EventMetrics produced by a probe run to
+// ResultMetric protos, one per metric.
+func resultMetricsToProto(ems []*metrics.EventMetrics) []*pb.ResultMetric {
+	var out []*pb.ResultMetric
+	for _, em := range ems {
+		var labels []*pb.Label
+		for _, k := range em.LabelsKeys() {
+			if k == "probe" || k == "dst" {
+				continue
+			}
+			labels = append(labels, &pb.Label{Key: proto.String(k), Value: proto.String(em.Label(k))})
+		}
+
+		for _, name := range em.MetricsKeys() {
+			val := em.Metric(name)
+			rm := &pb.ResultMetric{
+				Name:  proto.String(name),
+				Value: proto.String(val.String()),
+				Label: labels,
+			}
+			if numVal, ok := val.(metrics.NumValue); ok {
+				rm.NumValue = proto.Float64(numVal.Float64())
+			}
+			out = append(out, rm)
+		}
+	}
+	return out
+}
+
 // ProbeRunResultsToProto converts probe run results to their protobuf form.
 func ProbeRunResultsToProto(results map[string][]*ProbeRunResult) map[string]*pb.ProbeResults {
 	ret := make(map[string]*pb.ProbeResults)
@@ -169,6 +198,7 @@ func ProbeRunResultsToProto(results map[string][]*ProbeRunResult) map[string]*pb
 			if r.Error != nil {
 				pbRunResult.Error = proto.String(r.Error.Error())
 			}
+			pbRunResult.ResultMetrics = resultMetricsToProto(r.Metrics)
 			pbProbeResults.RunResult = append(pbProbeResults.RunResult, pbRunResult)
 		}
 		ret[probeName] = pbProbeResults
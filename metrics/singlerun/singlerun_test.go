@@ -21,8 +21,10 @@ import (
 	"time"
 
 	"github.com/cloudprober/cloudprober/metrics"
+	pb "github.com/cloudprober/cloudprober/prober/proto"
 	"github.com/cloudprober/cloudprober/targets/endpoint"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
 )
 
 func testPrrs(em *metrics.EventMetrics) map[string][]*ProbeRunResult {
@@ -89,3 +91,43 @@ func TestJsonFormatProbeRunResults(t *testing.T) {
 	em := metrics.NewEventMetrics(time.Now()).AddMetric("rtt", metrics.NewFloat(123))
 	assert.Equal(t, fmt.Sprintf(expectedJsonFormatOutput, em.String()), jsonFormatProbeRunResults(testPrrs(em), "  "))
 }
+
+func TestProbeRunResultsToProto(t *testing.T) {
+	em := metrics.NewEventMetrics(time.Now()).
+		AddMetric("rtt", metrics.NewFloat(123)).
+		AddMetric("resp-code", metrics.NewMap("code").IncKey("200")).
+		AddLabel("ptype", "ping").
+		AddLabel("probe", "ping").
+		AddLabel("dst", "1.2.3.4")
+
+	got := ProbeRunResultsToProto(testPrrs(em))
+
+	assert.Len(t, got["http"].GetRunResult(), 1)
+	assert.False(t, got["http"].GetRunResult()[0].GetSuccess())
+	assert.Equal(t, "connection timeout", got["http"].GetRunResult()[0].GetError())
+	assert.Len(t, got["http"].GetRunResult()[0].GetResultMetrics(), 0)
+
+	assert.Len(t, got["ping"].GetRunResult(), 1)
+	pingResult := got["ping"].GetRunResult()[0]
+	assert.True(t, pingResult.GetSuccess())
+	assert.Equal(t, int64(123000), pingResult.GetLatencyUsec())
+
+	ptypeLabel := []*pb.Label{{Key: proto.String("ptype"), Value: proto.String("ping")}}
+	wantMetrics := []*pb.ResultMetric{
+		{
+			Name:     proto.String("rtt"),
+			Value:    proto.String("123.000"),
+			NumValue: proto.Float64(123),
+			Label:    ptypeLabel,
+		},
+		{
+			Name:  proto.String("resp-code"),
+			Value: proto.String("map:code,200:1"),
+			Label: ptypeLabel,
+		},
+	}
+	assert.Len(t, pingResult.GetResultMetrics(), len(wantMetrics))
+	for i, want := range wantMetrics {
+		assert.True(t, proto.Equal(want, pingResult.GetResultMetrics()[i]), "got: %v, want: %v", pingResult.GetResultMetrics()[i], want)
+	}
+}
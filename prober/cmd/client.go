@@ -17,10 +17,15 @@
 //
 // go run ./cmd/client.go --server localhost:9314 --add_probe newprobe.cfg
 // go run ./cmd/client.go --server localhost:9314 --rm_probe newprobe
+// go run ./cmd/client.go --server localhost:9314 --update_probe newprobe.cfg
+// go run ./cmd/client.go --server localhost:9314 --pause_probe newprobe
+// go run ./cmd/client.go --server localhost:9314 --resume_probe newprobe
+// go run ./cmd/client.go --server localhost:9314 --watch_results
 package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"os"
 
@@ -34,11 +39,30 @@ import (
 )
 
 var (
-	server   = flag.String("server", "", "gRPC server address")
-	addProbe = flag.String("add_probe", "", "Path to probe config to add")
-	rmProbe  = flag.String("rm_probe", "", "Probe name to remove")
+	server      = flag.String("server", "", "gRPC server address")
+	addProbe    = flag.String("add_probe", "", "Path to probe config to add")
+	rmProbe     = flag.String("rm_probe", "", "Probe name to remove")
+	updateProbe = flag.String("update_probe", "", "Path to probe config to update an existing probe with")
+	pauseProbe  = flag.String("pause_probe", "", "Probe name to pause")
+	resumeProbe = flag.String("resume_probe", "", "Probe name to resume")
+	watch       = flag.Bool("watch_results", false, "Stream probe results until interrupted")
 )
 
+func readProbeConfig(fileName string) *configpb.ProbeDef {
+	b, err := os.ReadFile(fileName)
+	if err != nil {
+		log.Fatalf("Failed to read the config file: %v", err)
+	}
+
+	log.Printf("Read probe config: %s", string(b))
+
+	cfg := &configpb.ProbeDef{}
+	if err := prototext.Unmarshal(b, cfg); err != nil {
+		log.Fatal(err)
+	}
+	return cfg
+}
+
 func main() {
 	flag.Parse()
 
@@ -61,21 +85,46 @@ func main() {
 	}
 
 	if *addProbe != "" {
-		b, err := os.ReadFile(*addProbe)
+		cfg := readProbeConfig(*addProbe)
+		_, err = client.AddProbe(context.Background(), &pb.AddProbeRequest{ProbeConfig: cfg})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if *updateProbe != "" {
+		cfg := readProbeConfig(*updateProbe)
+		_, err = client.UpdateProbe(context.Background(), &pb.UpdateProbeRequest{ProbeConfig: cfg})
 		if err != nil {
-			log.Fatalf("Failed to read the config file: %v", err)
+			log.Fatal(err)
 		}
+	}
 
-		log.Printf("Read probe config: %s", string(b))
+	if *pauseProbe != "" {
+		_, err := client.PauseProbe(context.Background(), &pb.PauseProbeRequest{ProbeName: pauseProbe})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
 
-		cfg := &configpb.ProbeDef{}
-		if err := prototext.Unmarshal(b, cfg); err != nil {
+	if *resumeProbe != "" {
+		_, err := client.ResumeProbe(context.Background(), &pb.ResumeProbeRequest{ProbeName: resumeProbe})
+		if err != nil {
 			log.Fatal(err)
 		}
+	}
 
-		_, err = client.AddProbe(context.Background(), &pb.AddProbeRequest{ProbeConfig: cfg})
+	if *watch {
+		stream, err := client.WatchProbeResults(context.Background(), &pb.WatchProbeResultsRequest{})
 		if err != nil {
 			log.Fatal(err)
 		}
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				log.Fatal(err)
+			}
+			fmt.Println(prototext.Format(resp))
+		}
 	}
 }
@@ -0,0 +1,253 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prober
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	pb "github.com/cloudprober/cloudprober/prober/proto"
+	probes_configpb "github.com/cloudprober/cloudprober/probes/proto"
+	"github.com/cloudprober/cloudprober/state"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+var enableHTTPAPI = flag.Bool("enable_http_api", false, "Expose the Cloudprober management API (list, add, remove, run probes, etc) as a JSON API on the default HTTP server, under "+apiPathPrefix)
+
+// apiPathPrefix is the URL path prefix for the HTTP/JSON management API.
+const apiPathPrefix = "/api/v1/probes"
+
+// maxAPIRequestBodySize limits the size of the probe definition that can be
+// sent to the HTTP API.
+const maxAPIRequestBodySize = 1 << 20
+
+// httpStatusFromCode maps gRPC status codes to HTTP status codes.
+func httpStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists:
+		return http.StatusConflict
+	case codes.FailedPrecondition:
+		return http.StatusPreconditionFailed
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Canceled, codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeAPIError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatusFromCode(st.Code()))
+	json.NewEncoder(w).Encode(struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}{st.Code().String(), st.Message()})
+}
+
+func writeAPIResponse(w http.ResponseWriter, resp proto.Message) {
+	b, err := protojson.Marshal(resp)
+	if err != nil {
+		writeAPIError(w, status.Errorf(codes.Internal, "error marshaling response: %v", err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
+
+// queryList returns the values of a query parameter. Parameter can be
+// repeated and each value can be a comma-separated list.
+func queryList(r *http.Request, key string) []string {
+	var out []string
+	for _, v := range r.URL.Query()[key] {
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
+
+func readProbeDef(r *http.Request) (*probes_configpb.ProbeDef, error) {
+	b, err := io.ReadAll(io.LimitReader(r.Body, maxAPIRequestBodySize))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "error reading request body: %v", err)
+	}
+	p := &probes_configpb.ProbeDef{}
+	if err := protojson.Unmarshal(b, p); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "error parsing probe definition: %v", err)
+	}
+	return p, nil
+}
+
+// apiHandler returns an HTTP handler that converts HTTP request to a gRPC
+// request using toReq, calls the gRPC method and writes its response as JSON.
+func apiHandler[Req, Resp proto.Message](method string, toReq func(*http.Request) (Req, error), call func(context.Context, Req) (Resp, error)) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			w.Header().Set("Allow", method)
+			http.Error(w, fmt.Sprintf("%s requires a %s request", r.URL.Path, method), http.StatusMethodNotAllowed)
+			return
+		}
+
+		req, err := toReq(r)
+		if err != nil {
+			writeAPIError(w, err)
+			return
+		}
+
+		resp, err := call(r.Context(), req)
+		if err != nil {
+			writeAPIError(w, err)
+			return
+		}
+		writeAPIResponse(w, resp)
+	}
+}
+
+func listProbesRequest(r *http.Request) (*pb.ListProbesRequest, error) {
+	return &pb.ListProbesRequest{}, nil
+}
+
+func addProbeRequest(r *http.Request) (*pb.AddProbeRequest, error) {
+	p, err := readProbeDef(r)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.AddProbeRequest{ProbeConfig: p}, nil
+}
+
+func updateProbeRequest(r *http.Request) (*pb.UpdateProbeRequest, error) {
+	p, err := readProbeDef(r)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.UpdateProbeRequest{ProbeConfig: p}, nil
+}
+
+func probeNameParam(r *http.Request) (*string, error) {
+	name := r.URL.Query().Get("probe")
+	if name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "probe query parameter is required")
+	}
+	return &name, nil
+}
+
+func removeProbeRequest(r *http.Request) (*pb.RemoveProbeRequest, error) {
+	name, err := probeNameParam(r)
+	return &pb.RemoveProbeRequest{ProbeName: name}, err
+}
+
+func pauseProbeRequest(r *http.Request) (*pb.PauseProbeRequest, error) {
+	name, err := probeNameParam(r)
+	return &pb.PauseProbeRequest{ProbeName: name}, err
+}
+
+func resumeProbeRequest(r *http.Request) (*pb.ResumeProbeRequest, error) {
+	name, err := probeNameParam(r)
+	return &pb.ResumeProbeRequest{ProbeName: name}, err
+}
+
+func runProbeRequest(r *http.Request) (*pb.RunProbeRequest, error) {
+	return &pb.RunProbeRequest{
+		ProbeName: queryList(r, "probe"),
+		Target:    queryList(r, "target"),
+	}, nil
+}
+
+func probeStatusRequest(r *http.Request) (*pb.GetProbeStatusRequest, error) {
+	req := &pb.GetProbeStatusRequest{ProbeName: queryList(r, "probe")}
+
+	if v := r.URL.Query().Get("time_window_minutes"); v != "" {
+		i, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid time_window_minutes: %v", err)
+		}
+		req.TimeWindowMinutes = proto.Int32(int32(i))
+	}
+
+	if v := r.URL.Query().Get("end_time_sec"); v != "" {
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid end_time_sec: %v", err)
+		}
+		req.EndTimeSec = proto.Int64(i)
+	}
+	return req, nil
+}
+
+func failedResponsesRequest(r *http.Request) (*pb.GetFailedResponsesRequest, error) {
+	return &pb.GetFailedResponsesRequest{
+		ProbeName: queryList(r, "probe"),
+		Target:    queryList(r, "target"),
+	}, nil
+}
+
+// registerHTTPAPI exposes the Cloudprober gRPC service methods as a JSON API
+// on the default HTTP server:
+//
+//	GET  /api/v1/probes                           List probes.
+//	POST /api/v1/probes/add                       Add probe (ProbeDef as JSON body).
+//	POST /api/v1/probes/update                    Update probe (ProbeDef as JSON body).
+//	POST /api/v1/probes/remove?probe=<name>       Remove probe.
+//	POST /api/v1/probes/pause?probe=<name>        Pause probe.
+//	POST /api/v1/probes/resume?probe=<name>       Resume probe.
+//	POST /api/v1/probes/run?probe=<names>&target=<targets>
+//	                                              Run probes once.
+//	GET  /api/v1/probes/status?probe=<names>&time_window_minutes=<n>
+//	                                              Get probe status.
+//	GET  /api/v1/probes/failed-responses?probe=<names>&target=<targets>
+//	                                              Get recent failed responses.
+func (pr *Prober) registerHTTPAPI() error {
+	handlers := []struct {
+		path    string
+		handler func(http.ResponseWriter, *http.Request)
+	}{
+		{"", apiHandler(http.MethodGet, listProbesRequest, pr.ListProbes)},
+		{"/add", apiHandler(http.MethodPost, addProbeRequest, pr.AddProbe)},
+		{"/update", apiHandler(http.MethodPost, updateProbeRequest, pr.UpdateProbe)},
+		{"/remove", apiHandler(http.MethodPost, removeProbeRequest, pr.RemoveProbe)},
+		{"/pause", apiHandler(http.MethodPost, pauseProbeRequest, pr.PauseProbe)},
+		{"/resume", apiHandler(http.MethodPost, resumeProbeRequest, pr.ResumeProbe)},
+		{"/run", apiHandler(http.MethodPost, runProbeRequest, pr.RunProbe)},
+		{"/status", apiHandler(http.MethodGet, probeStatusRequest, pr.GetProbeStatus)},
+		{"/failed-responses", apiHandler(http.MethodGet, failedResponsesRequest, pr.GetFailedResponses)},
+	}
+
+	for _, h := range handlers {
+		if err := state.AddWebHandler(apiPathPrefix+h.path, h.handler); err != nil {
+			return fmt.Errorf("error registering HTTP API handler for %s: %v", apiPathPrefix+h.path, err)
+		}
+	}
+	return nil
+}
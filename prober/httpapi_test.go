@@ -0,0 +1,117 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prober
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	configpb "github.com/cloudprober/cloudprober/config/proto"
+	pb "github.com/cloudprober/cloudprober/prober/proto"
+	"github.com/cloudprober/cloudprober/state"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestHTTPAPI(t *testing.T) {
+	oldEnableHTTPAPI := *enableHTTPAPI
+	*enableHTTPAPI = true
+	defer func() { *enableHTTPAPI = oldEnableHTTPAPI }()
+
+	mux := http.NewServeMux()
+	state.SetDefaultHTTPServeMux(mux)
+	defer state.SetDefaultHTTPServeMux(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pr, err := Init(ctx, &configpb.ProberConfig{}, nil)
+	if err != nil {
+		t.Fatalf("error while initializing prober: %v", err)
+	}
+	pr.Start(ctx)
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	call := func(method, path, body string) (int, string) {
+		t.Helper()
+		req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		assert.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("error making request: %v", err)
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(b)
+	}
+
+	probeJSON, err := protojson.Marshal(testProbeDef("api-probe"))
+	assert.NoError(t, err)
+
+	// Wrong method.
+	code, _ := call("GET", "/api/v1/probes/add", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+
+	// Bad body.
+	code, body := call("POST", "/api/v1/probes/add", "{bad json")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, body, "InvalidArgument")
+
+	code, body = call("POST", "/api/v1/probes/add", string(probeJSON))
+	assert.Equal(t, http.StatusOK, code, body)
+	verifyProbeRunningStatus(t, pr.Probes["api-probe"].Probe.(*testProbe), true)
+
+	code, _ = call("POST", "/api/v1/probes/add", string(probeJSON))
+	assert.Equal(t, http.StatusConflict, code)
+
+	code, body = call("POST", "/api/v1/probes/pause?probe=api-probe", "")
+	assert.Equal(t, http.StatusOK, code, body)
+
+	code, body = call("GET", "/api/v1/probes", "")
+	assert.Equal(t, http.StatusOK, code)
+	listResp := &pb.ListProbesResponse{}
+	assert.NoError(t, protojson.Unmarshal([]byte(body), listResp))
+	assert.Len(t, listResp.GetProbe(), 1)
+	assert.Equal(t, "api-probe", listResp.GetProbe()[0].GetName())
+	assert.True(t, listResp.GetProbe()[0].GetPaused())
+
+	code, body = call("GET", "/api/v1/probes/status?probe=api-probe&time_window_minutes=5", "")
+	assert.Equal(t, http.StatusOK, code, body)
+
+	code, _ = call("GET", "/api/v1/probes/status?time_window_minutes=abc", "")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = call("POST", "/api/v1/probes/remove", "")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, body = call("POST", "/api/v1/probes/remove?probe=api-probe", "")
+	assert.Equal(t, http.StatusOK, code, body)
+	assert.Nil(t, pr.Probes["api-probe"])
+
+	code, _ = call("POST", "/api/v1/probes/remove?probe=api-probe", "")
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestQueryList(t *testing.T) {
+	req := httptest.NewRequest("GET", "/api/v1/probes/run?probe=p1,p2&probe=p3&target=t1", nil)
+	assert.Equal(t, []string{"p1", "p2", "p3"}, queryList(req, "probe"))
+	assert.Equal(t, []string{"t1"}, queryList(req, "target"))
+	assert.Nil(t, queryList(req, "other"))
+}
@@ -29,12 +29,15 @@ import (
 	"math/rand"
 	"regexp"
 	"slices"
+	"strings"
 	"sync"
 	"time"
 
 	configpb "github.com/cloudprober/cloudprober/config/proto"
+	"github.com/cloudprober/cloudprober/internal/alerting"
 	rdsserver "github.com/cloudprober/cloudprober/internal/rds/server"
 	"github.com/cloudprober/cloudprober/internal/servers"
+	"github.com/cloudprober/cloudprober/internal/statestore"
 	"github.com/cloudprober/cloudprober/internal/surfacers/probestatus"
 	"github.com/cloudprober/cloudprober/internal/sysvars"
 	"github.com/cloudprober/cloudprober/logger"
@@ -42,6 +45,7 @@ import (
 	"github.com/cloudprober/cloudprober/metrics/singlerun"
 	spb "github.com/cloudprober/cloudprober/prober/proto"
 	"github.com/cloudprober/cloudprober/probes"
+	"github.com/cloudprober/cloudprober/probes/common/dependency"
 	"github.com/cloudprober/cloudprober/probes/options"
 	probes_configpb "github.com/cloudprober/cloudprober/probes/proto"
 	"github.com/cloudprober/cloudprober/state"
@@ -49,8 +53,10 @@ import (
 	"github.com/cloudprober/cloudprober/targets"
 	"github.com/cloudprober/cloudprober/targets/endpoint"
 	"github.com/cloudprober/cloudprober/targets/lameduck"
+	targetspb "github.com/cloudprober/cloudprober/targets/proto"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 var (
@@ -80,9 +86,18 @@ type Prober struct {
 	// Per-probe cancelFunc map.
 	probeCancelFunc map[string]context.CancelFunc
 
+	// Per-server state, used to stop servers on config reload.
+	serverStates map[*servers.ServerInfo]*serverState
+
 	// dataChan for passing metrics between probes and main goroutine.
 	dataChan chan *metrics.EventMetrics
 
+	// WatchProbeResults subscribers.
+	watchers resultWatchers
+
+	// Cross-probe state used by the probes that depend on other probes.
+	depTracker *dependency.Tracker
+
 	// Required for all gRPC server implementations.
 	spb.UnimplementedCloudproberServer
 }
@@ -98,32 +113,43 @@ func runOnThisHost(runOn string, hostname string) (bool, error) {
 	return r.MatchString(hostname), nil
 }
 
-func (pr *Prober) addProbe(p *probes_configpb.ProbeDef) error {
-	pr.mu.Lock()
-	defer pr.mu.Unlock()
-
+// createProbe creates a probe from the given probe definition. It returns
+// nil ProbeInfo if the probe is not supposed to run on this host.
+func (pr *Prober) createProbe(p *probes_configpb.ProbeDef) (*probes.ProbeInfo, error) {
 	// Check if this probe is supposed to run here.
 	runHere, err := runOnThisHost(p.GetRunOn(), sysvars.GetVar("hostname"))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if !runHere {
-		return nil
-	}
-
-	if pr.Probes[p.GetName()] != nil {
-		return status.Errorf(codes.AlreadyExists, "probe %s is already defined", p.GetName())
+		return nil, nil
 	}
 
 	opts, err := options.BuildProbeOptions(p, pr.ldLister, pr.c, pr.l)
 	if err != nil {
-		return status.Error(codes.Unknown, err.Error())
+		return nil, status.Error(codes.Unknown, err.Error())
 	}
+	opts.SetDependencyTracker(pr.depTracker)
 
 	pr.l.Infof("Creating a %s probe: %s", p.GetType(), p.GetName())
 	probeInfo, err := probes.CreateProbe(p, opts)
 	if err != nil {
-		return status.Error(codes.Unknown, err.Error())
+		return nil, status.Error(codes.Unknown, err.Error())
+	}
+	return probeInfo, nil
+}
+
+func (pr *Prober) addProbe(p *probes_configpb.ProbeDef) error {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	if pr.Probes[p.GetName()] != nil {
+		return status.Errorf(codes.AlreadyExists, "probe %s is already defined", p.GetName())
+	}
+
+	probeInfo, err := pr.createProbe(p)
+	if err != nil || probeInfo == nil {
+		return err
 	}
 	pr.Probes[p.GetName()] = probeInfo
 
@@ -138,10 +164,16 @@ func (pr *Prober) startProbe(name string) {
 	pr.mu.Lock()
 	defer pr.mu.Unlock()
 
+	// Probe may have been removed, or already started (e.g. by a config
+	// reload), before we got here.
+	p := pr.Probes[name]
+	if p == nil || pr.probeCancelFunc[name] != nil {
+		return
+	}
+
 	probeCtx, cancelFunc := context.WithCancel(pr.startCtx)
 	pr.probeCancelFunc[name] = cancelFunc
 
-	p := pr.Probes[name]
 	go func() {
 		if delay := p.ProbeDef.GetStartupDelayMsec(); delay > 0 {
 			select {
@@ -154,6 +186,16 @@ func (pr *Prober) startProbe(name string) {
 	}()
 }
 
+// stopProbe cancels the probe's context, if probe has been started. It must
+// be called with pr.mu held.
+func (pr *Prober) stopProbe(name string) {
+	if cancelFunc := pr.probeCancelFunc[name]; cancelFunc != nil {
+		cancelFunc()
+		delete(pr.probeCancelFunc, name)
+	}
+	pr.depTracker.RemoveProbe(name)
+}
+
 func randomDuration(duration time.Duration) time.Duration {
 	if duration == 0 {
 		return 0
@@ -232,6 +274,35 @@ func (pr *Prober) startProbesWithJitter() {
 
 // Run runs requested 'probeNames' once.
 func (pr *Prober) Run(ctx context.Context, probeNames []string) (map[string][]*singlerun.ProbeRunResult, error) {
+	return pr.RunWithTargets(ctx, probeNames, nil)
+}
+
+// probeWithTargets creates a new instance of the given probe that uses the
+// given targets, instead of the probe's configured targets. Targets are
+// specified in the same format as host_names targets.
+func (pr *Prober) probeWithTargets(pi *probes.ProbeInfo, targets []string) (probes.Probe, error) {
+	p := proto.Clone(pi.ProbeDef).(*probes_configpb.ProbeDef)
+	p.Targets = &targetspb.TargetsDef{
+		Type: &targetspb.TargetsDef_HostNames{
+			HostNames: strings.Join(targets, ","),
+		},
+	}
+
+	opts, err := options.BuildProbeOptions(p, pr.ldLister, pr.c, pr.l)
+	if err != nil {
+		return nil, err
+	}
+	probeInfo, err := probes.CreateProbe(p, opts)
+	if err != nil {
+		return nil, err
+	}
+	return probeInfo.Probe, nil
+}
+
+// RunWithTargets runs requested 'probeNames' once. If 'targets' is not
+// empty, probes are run against these targets instead of their configured
+// targets.
+func (pr *Prober) RunWithTargets(ctx context.Context, probeNames []string, targets []string) (map[string][]*singlerun.ProbeRunResult, error) {
 	pr.mu.RLock()
 	defer pr.mu.RUnlock()
 
@@ -253,7 +324,14 @@ func (pr *Prober) Run(ctx context.Context, probeNames []string) (map[string][]*s
 		if len(probeNames) > 0 && !slices.Contains(probeNames, name) {
 			continue
 		}
-		p, ok := pr.Probes[name].Probe.(probes.ProbeWithRunOnce)
+		probe := pr.Probes[name].Probe
+		if len(targets) > 0 {
+			var err error
+			if probe, err = pr.probeWithTargets(pr.Probes[name], targets); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "error creating probe %s with targets %v: %v", name, targets, err)
+			}
+		}
+		p, ok := probe.(probes.ProbeWithRunOnce)
 		if !ok {
 			pr.l.Warningf("probe %s doesn't support single run", name)
 			continue
@@ -291,13 +369,19 @@ func (pr *Prober) Start(ctx context.Context) {
 		for {
 			em = <-pr.dataChan
 
+			// Surfacers may get updated on config reload.
+			pr.mu.RLock()
+			surfacers := pr.Surfacers
+			pr.mu.RUnlock()
+
 			// Replicate the surfacer message to every surfacer we have
 			// registered. Note that s.Write() is expected to be
 			// non-blocking to avoid blocking of EventMetrics message
 			// processing.
-			for _, surfacer := range pr.Surfacers {
+			for _, surfacer := range surfacers {
 				surfacer.Write(pr.startCtx, em)
 			}
+			pr.watchers.publish(em)
 		}
 	}()
 
@@ -306,7 +390,7 @@ func (pr *Prober) Start(ctx context.Context) {
 
 	// Start servers, each in its own goroutine
 	for _, s := range pr.Servers {
-		go s.Start(ctx, pr.dataChan)
+		pr.startServer(s)
 	}
 
 	if pr.c.GetDisableJitter() {
@@ -321,8 +405,9 @@ func (pr *Prober) Start(ctx context.Context) {
 // Init initialize prober with the given config file.
 func Init(ctx context.Context, cfg *configpb.ProberConfig, l *logger.Logger) (*Prober, error) {
 	pr := &Prober{
-		c: cfg,
-		l: l,
+		c:          cfg,
+		l:          l,
+		depTracker: dependency.NewTracker(),
 	}
 
 	// Initialize cloudprober gRPC service if configured.
@@ -332,6 +417,14 @@ func Init(ctx context.Context, cfg *configpb.ProberConfig, l *logger.Logger) (*P
 		spb.RegisterCloudproberServer(srv, pr)
 	}
 
+	// Expose the same service as a JSON API on the default HTTP server, if
+	// enabled.
+	if *enableHTTPAPI {
+		if err := pr.registerHTTPAPI(); err != nil {
+			return nil, err
+		}
+	}
+
 	// Initialize RDS server, if configured and attach to the default gRPC server.
 	// Note that we can still attach services to the default gRPC server as it's
 	// started later in Start().
@@ -364,6 +457,15 @@ func Init(ctx context.Context, cfg *configpb.ProberConfig, l *logger.Logger) (*P
 		}
 	}
 
+	// Initialize state store and restore the alerts state. This needs to
+	// happen before probes and surfacers are initialized.
+	if err := statestore.Init(pr.c.GetStateDir(), time.Duration(pr.c.GetStateSaveIntervalSec())*time.Second); err != nil {
+		return nil, err
+	}
+	if err := alerting.LoadState(); err != nil {
+		pr.l.Warningf("Error loading alerts state: %v", err)
+	}
+
 	var err error
 
 	// Initialize shared targets
@@ -385,16 +487,27 @@ func Init(ctx context.Context, cfg *configpb.ProberConfig, l *logger.Logger) (*P
 	}
 
 	// Initialize servers
-	pr.Servers, err = servers.Init(ctx, pr.c.GetServer())
-	if err != nil {
-		return nil, fmt.Errorf("error while initializing servers: %v", err)
+	pr.serverStates = make(map[*servers.ServerInfo]*serverState)
+	for _, serverDef := range pr.c.GetServer() {
+		si, err := pr.initServer(ctx, serverDef)
+		if err != nil {
+			return nil, fmt.Errorf("error while initializing servers: %v", err)
+		}
+		pr.Servers = append(pr.Servers, si)
 	}
 
 	pr.Surfacers, err = surfacers.Init(ctx, pr.c.GetSurfacer())
 	if err != nil {
 		return nil, fmt.Errorf("error while initializing surfacers: %v", err)
 	}
+	pr.setProbeStatusSurfacer()
 
+	return pr, nil
+}
+
+// setProbeStatusSurfacer caches a reference to the probestatus surfacer.
+func (pr *Prober) setProbeStatusSurfacer() {
+	pr.probeStatusSurfacer = nil
 	for _, si := range pr.Surfacers {
 		if si.Type == "PROBESTATUS" {
 			if ps, ok := si.UnwrapSurfacer().(*probestatus.Surfacer); ok {
@@ -403,6 +516,4 @@ func Init(ctx context.Context, cfg *configpb.ProberConfig, l *logger.Logger) (*P
 			break
 		}
 	}
-
-	return pr, nil
 }
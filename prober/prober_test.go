@@ -17,7 +17,10 @@ package prober
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -311,3 +314,42 @@ func TestStartProbe_CancelDuringDelay(t *testing.T) {
 		// Good
 	}
 }
+
+func TestProberRunWithTargets(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	httpProbe := &probes_configpb.ProbeDef{
+		Name: proto.String("http-probe"),
+		Type: probes_configpb.ProbeDef_HTTP.Enum(),
+		Targets: &targetspb.TargetsDef{
+			Type: &targetspb.TargetsDef_HostNames{
+				HostNames: "unreachable.invalid",
+			},
+		},
+	}
+
+	pr := &Prober{
+		Probes: make(map[string]*probes.ProbeInfo),
+		c:      &configpb.ProberConfig{},
+		l:      logger.New(),
+	}
+	probeInfo, err := pr.createProbe(httpProbe)
+	assert.NoError(t, err)
+	pr.Probes["http-probe"] = probeInfo
+
+	target := strings.TrimPrefix(ts.URL, "http://")
+	out, err := pr.RunWithTargets(context.Background(), nil, []string{target})
+	assert.NoError(t, err)
+	assert.Len(t, out["http-probe"], 1)
+
+	prr := out["http-probe"][0]
+	assert.Equal(t, target, prr.Target.Dst())
+	assert.True(t, prr.Success, "probe run failed: %v", prr.Error)
+	assert.NotEmpty(t, prr.Metrics)
+
+	// Configured probe should not have changed.
+	assert.Equal(t, "unreachable.invalid", pr.Probes["http-probe"].ProbeDef.GetTargets().GetHostNames())
+}
@@ -23,6 +23,62 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type EventMetrics_Kind int32
+
+const (
+	EventMetrics_CUMULATIVE EventMetrics_Kind = 0
+	EventMetrics_GAUGE      EventMetrics_Kind = 1
+)
+
+// Enum value maps for EventMetrics_Kind.
+var (
+	EventMetrics_Kind_name = map[int32]string{
+		0: "CUMULATIVE",
+		1: "GAUGE",
+	}
+	EventMetrics_Kind_value = map[string]int32{
+		"CUMULATIVE": 0,
+		"GAUGE":      1,
+	}
+)
+
+func (x EventMetrics_Kind) Enum() *EventMetrics_Kind {
+	p := new(EventMetrics_Kind)
+	*p = x
+	return p
+}
+
+func (x EventMetrics_Kind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EventMetrics_Kind) Descriptor() protoreflect.EnumDescriptor {
+	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_enumTypes[0].Descriptor()
+}
+
+func (EventMetrics_Kind) Type() protoreflect.EnumType {
+	return &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_enumTypes[0]
+}
+
+func (x EventMetrics_Kind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *EventMetrics_Kind) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = EventMetrics_Kind(num)
+	return nil
+}
+
+// Deprecated: Use EventMetrics_Kind.Descriptor instead.
+func (EventMetrics_Kind) EnumDescriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{27, 0}
+}
+
 type AddProbeRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ProbeConfig   *proto.ProbeDef        `protobuf:"bytes,1,opt,name=probe_config,json=probeConfig" json:"probe_config,omitempty"`
@@ -183,28 +239,28 @@ func (*RemoveProbeResponse) Descriptor() ([]byte, []int) {
 	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{3}
 }
 
-type RunProbeRequest struct {
+type UpdateProbeRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// If empty, all configured probes are run.
-	ProbeName     []string `protobuf:"bytes,1,rep,name=probe_name,json=probeName" json:"probe_name,omitempty"`
+	// Probe is looked up by probe_config.name.
+	ProbeConfig   *proto.ProbeDef `protobuf:"bytes,1,opt,name=probe_config,json=probeConfig" json:"probe_config,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RunProbeRequest) Reset() {
-	*x = RunProbeRequest{}
+func (x *UpdateProbeRequest) Reset() {
+	*x = UpdateProbeRequest{}
 	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RunProbeRequest) String() string {
+func (x *UpdateProbeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RunProbeRequest) ProtoMessage() {}
+func (*UpdateProbeRequest) ProtoMessage() {}
 
-func (x *RunProbeRequest) ProtoReflect() protoreflect.Message {
+func (x *UpdateProbeRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -216,39 +272,38 @@ func (x *RunProbeRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RunProbeRequest.ProtoReflect.Descriptor instead.
-func (*RunProbeRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use UpdateProbeRequest.ProtoReflect.Descriptor instead.
+func (*UpdateProbeRequest) Descriptor() ([]byte, []int) {
 	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *RunProbeRequest) GetProbeName() []string {
+func (x *UpdateProbeRequest) GetProbeConfig() *proto.ProbeDef {
 	if x != nil {
-		return x.ProbeName
+		return x.ProbeConfig
 	}
 	return nil
 }
 
-// TODO: Add metrics handling.
-type ResultMetric struct {
+type UpdateProbeResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ResultMetric) Reset() {
-	*x = ResultMetric{}
+func (x *UpdateProbeResponse) Reset() {
+	*x = UpdateProbeResponse{}
 	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ResultMetric) String() string {
+func (x *UpdateProbeResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResultMetric) ProtoMessage() {}
+func (*UpdateProbeResponse) ProtoMessage() {}
 
-func (x *ResultMetric) ProtoReflect() protoreflect.Message {
+func (x *UpdateProbeResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -260,42 +315,32 @@ func (x *ResultMetric) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResultMetric.ProtoReflect.Descriptor instead.
-func (*ResultMetric) Descriptor() ([]byte, []int) {
+// Deprecated: Use UpdateProbeResponse.ProtoReflect.Descriptor instead.
+func (*UpdateProbeResponse) Descriptor() ([]byte, []int) {
 	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{5}
 }
 
-// Results of a single probe run for a given target.
-type ProbeRunResult struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// Target that this result is associated with.
-	Target *proto1.Endpoint `protobuf:"bytes,1,req,name=target" json:"target,omitempty"`
-	// True if the probe run was successful.
-	Success *bool `protobuf:"varint,2,req,name=success" json:"success,omitempty"`
-	// Latency in microseconds. Set for successful probes.
-	LatencyUsec *int64 `protobuf:"varint,3,opt,name=latency_usec,json=latencyUsec" json:"latency_usec,omitempty"`
-	// Additional metrics from the probe run.
-	ResultMetrics []*ResultMetric `protobuf:"bytes,4,rep,name=result_metrics,json=resultMetrics" json:"result_metrics,omitempty"`
-	// Optional error message for failed probes.
-	Error         *string `protobuf:"bytes,5,opt,name=error" json:"error,omitempty"`
+type PauseProbeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProbeName     *string                `protobuf:"bytes,1,opt,name=probe_name,json=probeName" json:"probe_name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ProbeRunResult) Reset() {
-	*x = ProbeRunResult{}
+func (x *PauseProbeRequest) Reset() {
+	*x = PauseProbeRequest{}
 	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ProbeRunResult) String() string {
+func (x *PauseProbeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ProbeRunResult) ProtoMessage() {}
+func (*PauseProbeRequest) ProtoMessage() {}
 
-func (x *ProbeRunResult) ProtoReflect() protoreflect.Message {
+func (x *PauseProbeRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -307,67 +352,38 @@ func (x *ProbeRunResult) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ProbeRunResult.ProtoReflect.Descriptor instead.
-func (*ProbeRunResult) Descriptor() ([]byte, []int) {
+// Deprecated: Use PauseProbeRequest.ProtoReflect.Descriptor instead.
+func (*PauseProbeRequest) Descriptor() ([]byte, []int) {
 	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *ProbeRunResult) GetTarget() *proto1.Endpoint {
-	if x != nil {
-		return x.Target
-	}
-	return nil
-}
-
-func (x *ProbeRunResult) GetSuccess() bool {
-	if x != nil && x.Success != nil {
-		return *x.Success
-	}
-	return false
-}
-
-func (x *ProbeRunResult) GetLatencyUsec() int64 {
-	if x != nil && x.LatencyUsec != nil {
-		return *x.LatencyUsec
-	}
-	return 0
-}
-
-func (x *ProbeRunResult) GetResultMetrics() []*ResultMetric {
-	if x != nil {
-		return x.ResultMetrics
-	}
-	return nil
-}
-
-func (x *ProbeRunResult) GetError() string {
-	if x != nil && x.Error != nil {
-		return *x.Error
+func (x *PauseProbeRequest) GetProbeName() string {
+	if x != nil && x.ProbeName != nil {
+		return *x.ProbeName
 	}
 	return ""
 }
 
-type ProbeResults struct {
+type PauseProbeResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	RunResult     []*ProbeRunResult      `protobuf:"bytes,1,rep,name=run_result,json=runResult" json:"run_result,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ProbeResults) Reset() {
-	*x = ProbeResults{}
+func (x *PauseProbeResponse) Reset() {
+	*x = PauseProbeResponse{}
 	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ProbeResults) String() string {
+func (x *PauseProbeResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ProbeResults) ProtoMessage() {}
+func (*PauseProbeResponse) ProtoMessage() {}
 
-func (x *ProbeResults) ProtoReflect() protoreflect.Message {
+func (x *PauseProbeResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -379,39 +395,32 @@ func (x *ProbeResults) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ProbeResults.ProtoReflect.Descriptor instead.
-func (*ProbeResults) Descriptor() ([]byte, []int) {
+// Deprecated: Use PauseProbeResponse.ProtoReflect.Descriptor instead.
+func (*PauseProbeResponse) Descriptor() ([]byte, []int) {
 	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *ProbeResults) GetRunResult() []*ProbeRunResult {
-	if x != nil {
-		return x.RunResult
-	}
-	return nil
-}
-
-type RunProbeResponse struct {
-	state         protoimpl.MessageState   `protogen:"open.v1"`
-	Results       map[string]*ProbeResults `protobuf:"bytes,1,rep,name=results" json:"results,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+type ResumeProbeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProbeName     *string                `protobuf:"bytes,1,opt,name=probe_name,json=probeName" json:"probe_name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RunProbeResponse) Reset() {
-	*x = RunProbeResponse{}
+func (x *ResumeProbeRequest) Reset() {
+	*x = ResumeProbeRequest{}
 	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RunProbeResponse) String() string {
+func (x *ResumeProbeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RunProbeResponse) ProtoMessage() {}
+func (*ResumeProbeRequest) ProtoMessage() {}
 
-func (x *RunProbeResponse) ProtoReflect() protoreflect.Message {
+func (x *ResumeProbeRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -423,38 +432,38 @@ func (x *RunProbeResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RunProbeResponse.ProtoReflect.Descriptor instead.
-func (*RunProbeResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ResumeProbeRequest.ProtoReflect.Descriptor instead.
+func (*ResumeProbeRequest) Descriptor() ([]byte, []int) {
 	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *RunProbeResponse) GetResults() map[string]*ProbeResults {
-	if x != nil {
-		return x.Results
+func (x *ResumeProbeRequest) GetProbeName() string {
+	if x != nil && x.ProbeName != nil {
+		return *x.ProbeName
 	}
-	return nil
+	return ""
 }
 
-type ListProbesRequest struct {
+type ResumeProbeResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListProbesRequest) Reset() {
-	*x = ListProbesRequest{}
+func (x *ResumeProbeResponse) Reset() {
+	*x = ResumeProbeResponse{}
 	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListProbesRequest) String() string {
+func (x *ResumeProbeResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListProbesRequest) ProtoMessage() {}
+func (*ResumeProbeResponse) ProtoMessage() {}
 
-func (x *ListProbesRequest) ProtoReflect() protoreflect.Message {
+func (x *ResumeProbeResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -466,33 +475,37 @@ func (x *ListProbesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListProbesRequest.ProtoReflect.Descriptor instead.
-func (*ListProbesRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ResumeProbeResponse.ProtoReflect.Descriptor instead.
+func (*ResumeProbeResponse) Descriptor() ([]byte, []int) {
 	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{9}
 }
 
-type Probe struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          *string                `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
-	Config        *proto.ProbeDef        `protobuf:"bytes,2,opt,name=config" json:"config,omitempty"`
+type RunProbeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// If empty, all configured probes are run.
+	ProbeName []string `protobuf:"bytes,1,rep,name=probe_name,json=probeName" json:"probe_name,omitempty"`
+	// If set, probes are run against these targets instead of their
+	// configured targets. Targets are specified in the same format as
+	// host_names targets, e.g. "www.google.com", "10.1.1.1:8080".
+	Target        []string `protobuf:"bytes,2,rep,name=target" json:"target,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Probe) Reset() {
-	*x = Probe{}
+func (x *RunProbeRequest) Reset() {
+	*x = RunProbeRequest{}
 	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Probe) String() string {
+func (x *RunProbeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Probe) ProtoMessage() {}
+func (*RunProbeRequest) ProtoMessage() {}
 
-func (x *Probe) ProtoReflect() protoreflect.Message {
+func (x *RunProbeRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -504,46 +517,56 @@ func (x *Probe) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Probe.ProtoReflect.Descriptor instead.
-func (*Probe) Descriptor() ([]byte, []int) {
+// Deprecated: Use RunProbeRequest.ProtoReflect.Descriptor instead.
+func (*RunProbeRequest) Descriptor() ([]byte, []int) {
 	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *Probe) GetName() string {
-	if x != nil && x.Name != nil {
-		return *x.Name
+func (x *RunProbeRequest) GetProbeName() []string {
+	if x != nil {
+		return x.ProbeName
 	}
-	return ""
+	return nil
 }
 
-func (x *Probe) GetConfig() *proto.ProbeDef {
+func (x *RunProbeRequest) GetTarget() []string {
 	if x != nil {
-		return x.Config
+		return x.Target
 	}
 	return nil
 }
 
-type ListProbesResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Probe         []*Probe               `protobuf:"bytes,1,rep,name=probe" json:"probe,omitempty"`
+// A metric produced by the probe run.
+type ResultMetric struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  *string                `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// Metric value in cloudprober's text format, e.g. "23", "0.5" or
+	// "map:code,200:1". It can be parsed back using
+	// metrics.ParseValueFromString.
+	Value *string `protobuf:"bytes,2,opt,name=value" json:"value,omitempty"`
+	// Numeric value, set only for numeric (int and float) metrics.
+	NumValue *float64 `protobuf:"fixed64,3,opt,name=num_value,json=numValue" json:"num_value,omitempty"`
+	// Labels of the EventMetrics this metric was a part of, e.g. ptype. Note
+	// that the probe and target labels are not included.
+	Label         []*Label `protobuf:"bytes,4,rep,name=label" json:"label,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListProbesResponse) Reset() {
-	*x = ListProbesResponse{}
+func (x *ResultMetric) Reset() {
+	*x = ResultMetric{}
 	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListProbesResponse) String() string {
+func (x *ResultMetric) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListProbesResponse) ProtoMessage() {}
+func (*ResultMetric) ProtoMessage() {}
 
-func (x *ListProbesResponse) ProtoReflect() protoreflect.Message {
+func (x *ResultMetric) ProtoReflect() protoreflect.Message {
 	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -555,41 +578,70 @@ func (x *ListProbesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListProbesResponse.ProtoReflect.Descriptor instead.
-func (*ListProbesResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ResultMetric.ProtoReflect.Descriptor instead.
+func (*ResultMetric) Descriptor() ([]byte, []int) {
 	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *ListProbesResponse) GetProbe() []*Probe {
+func (x *ResultMetric) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *ResultMetric) GetValue() string {
+	if x != nil && x.Value != nil {
+		return *x.Value
+	}
+	return ""
+}
+
+func (x *ResultMetric) GetNumValue() float64 {
+	if x != nil && x.NumValue != nil {
+		return *x.NumValue
+	}
+	return 0
+}
+
+func (x *ResultMetric) GetLabel() []*Label {
 	if x != nil {
-		return x.Probe
+		return x.Label
 	}
 	return nil
 }
 
-type SaveProbesConfigRequest struct {
+// Results of a single probe run for a given target.
+type ProbeRunResult struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// File path to save probes config to. Default is configured through the
-	// command-line flag: --probes_config_save_path
-	FilePath      *string `protobuf:"bytes,1,opt,name=file_path,json=filePath" json:"file_path,omitempty"`
+	// Target that this result is associated with.
+	Target *proto1.Endpoint `protobuf:"bytes,1,req,name=target" json:"target,omitempty"`
+	// True if the probe run was successful.
+	Success *bool `protobuf:"varint,2,req,name=success" json:"success,omitempty"`
+	// Latency in microseconds. Set for successful probes.
+	LatencyUsec *int64 `protobuf:"varint,3,opt,name=latency_usec,json=latencyUsec" json:"latency_usec,omitempty"`
+	// Additional metrics from the probe run.
+	ResultMetrics []*ResultMetric `protobuf:"bytes,4,rep,name=result_metrics,json=resultMetrics" json:"result_metrics,omitempty"`
+	// Optional error message for failed probes.
+	Error         *string `protobuf:"bytes,5,opt,name=error" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SaveProbesConfigRequest) Reset() {
-	*x = SaveProbesConfigRequest{}
+func (x *ProbeRunResult) Reset() {
+	*x = ProbeRunResult{}
 	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SaveProbesConfigRequest) String() string {
+func (x *ProbeRunResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SaveProbesConfigRequest) ProtoMessage() {}
+func (*ProbeRunResult) ProtoMessage() {}
 
-func (x *SaveProbesConfigRequest) ProtoReflect() protoreflect.Message {
+func (x *ProbeRunResult) ProtoReflect() protoreflect.Message {
 	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -601,40 +653,680 @@ func (x *SaveProbesConfigRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SaveProbesConfigRequest.ProtoReflect.Descriptor instead.
-func (*SaveProbesConfigRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ProbeRunResult.ProtoReflect.Descriptor instead.
+func (*ProbeRunResult) Descriptor() ([]byte, []int) {
 	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *SaveProbesConfigRequest) GetFilePath() string {
-	if x != nil && x.FilePath != nil {
-		return *x.FilePath
+func (x *ProbeRunResult) GetTarget() *proto1.Endpoint {
+	if x != nil {
+		return x.Target
 	}
-	return ""
-}
-
-type SaveProbesConfigResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	FilePath      *string                `protobuf:"bytes,1,opt,name=file_path,json=filePath" json:"file_path,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	return nil
 }
 
-func (x *SaveProbesConfigResponse) Reset() {
-	*x = SaveProbesConfigResponse{}
+func (x *ProbeRunResult) GetSuccess() bool {
+	if x != nil && x.Success != nil {
+		return *x.Success
+	}
+	return false
+}
+
+func (x *ProbeRunResult) GetLatencyUsec() int64 {
+	if x != nil && x.LatencyUsec != nil {
+		return *x.LatencyUsec
+	}
+	return 0
+}
+
+func (x *ProbeRunResult) GetResultMetrics() []*ResultMetric {
+	if x != nil {
+		return x.ResultMetrics
+	}
+	return nil
+}
+
+func (x *ProbeRunResult) GetError() string {
+	if x != nil && x.Error != nil {
+		return *x.Error
+	}
+	return ""
+}
+
+type ProbeResults struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RunResult     []*ProbeRunResult      `protobuf:"bytes,1,rep,name=run_result,json=runResult" json:"run_result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProbeResults) Reset() {
+	*x = ProbeResults{}
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProbeResults) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProbeResults) ProtoMessage() {}
+
+func (x *ProbeResults) ProtoReflect() protoreflect.Message {
 	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProbeResults.ProtoReflect.Descriptor instead.
+func (*ProbeResults) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ProbeResults) GetRunResult() []*ProbeRunResult {
+	if x != nil {
+		return x.RunResult
+	}
+	return nil
+}
+
+type RunProbeResponse struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Results       map[string]*ProbeResults `protobuf:"bytes,1,rep,name=results" json:"results,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunProbeResponse) Reset() {
+	*x = RunProbeResponse{}
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunProbeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunProbeResponse) ProtoMessage() {}
+
+func (x *RunProbeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunProbeResponse.ProtoReflect.Descriptor instead.
+func (*RunProbeResponse) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RunProbeResponse) GetResults() map[string]*ProbeResults {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type ListProbesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProbesRequest) Reset() {
+	*x = ListProbesRequest{}
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProbesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProbesRequest) ProtoMessage() {}
+
+func (x *ListProbesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProbesRequest.ProtoReflect.Descriptor instead.
+func (*ListProbesRequest) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{15}
+}
+
+type Probe struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Name   *string                `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Config *proto.ProbeDef        `protobuf:"bytes,2,opt,name=config" json:"config,omitempty"`
+	// True if probe has been paused through the PauseProbe RPC.
+	Paused        *bool `protobuf:"varint,3,opt,name=paused" json:"paused,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Probe) Reset() {
+	*x = Probe{}
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Probe) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Probe) ProtoMessage() {}
+
+func (x *Probe) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Probe.ProtoReflect.Descriptor instead.
+func (*Probe) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Probe) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *Probe) GetConfig() *proto.ProbeDef {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *Probe) GetPaused() bool {
+	if x != nil && x.Paused != nil {
+		return *x.Paused
+	}
+	return false
+}
+
+type ListProbesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Probe         []*Probe               `protobuf:"bytes,1,rep,name=probe" json:"probe,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProbesResponse) Reset() {
+	*x = ListProbesResponse{}
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProbesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProbesResponse) ProtoMessage() {}
+
+func (x *ListProbesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProbesResponse.ProtoReflect.Descriptor instead.
+func (*ListProbesResponse) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListProbesResponse) GetProbe() []*Probe {
+	if x != nil {
+		return x.Probe
+	}
+	return nil
+}
+
+type SaveProbesConfigRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// File path to save probes config to. Default is configured through the
+	// command-line flag: --probes_config_save_path
+	FilePath      *string `protobuf:"bytes,1,opt,name=file_path,json=filePath" json:"file_path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SaveProbesConfigRequest) Reset() {
+	*x = SaveProbesConfigRequest{}
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SaveProbesConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SaveProbesConfigRequest) ProtoMessage() {}
+
+func (x *SaveProbesConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SaveProbesConfigRequest.ProtoReflect.Descriptor instead.
+func (*SaveProbesConfigRequest) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SaveProbesConfigRequest) GetFilePath() string {
+	if x != nil && x.FilePath != nil {
+		return *x.FilePath
+	}
+	return ""
+}
+
+type SaveProbesConfigResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FilePath      *string                `protobuf:"bytes,1,opt,name=file_path,json=filePath" json:"file_path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SaveProbesConfigResponse) Reset() {
+	*x = SaveProbesConfigResponse{}
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SaveProbesConfigResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SaveProbesConfigResponse) ProtoMessage() {}
+
+func (x *SaveProbesConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SaveProbesConfigResponse.ProtoReflect.Descriptor instead.
+func (*SaveProbesConfigResponse) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SaveProbesConfigResponse) GetFilePath() string {
+	if x != nil && x.FilePath != nil {
+		return *x.FilePath
+	}
+	return ""
+}
+
+type GetProbeStatusRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// If empty, status for all probes is returned.
+	ProbeName []string `protobuf:"bytes,1,rep,name=probe_name,json=probeName" json:"probe_name,omitempty"`
+	// Time window in minutes to compute aggregated stats for. Default is 10.
+	TimeWindowMinutes *int32 `protobuf:"varint,2,opt,name=time_window_minutes,json=timeWindowMinutes,def=10" json:"time_window_minutes,omitempty"`
+	// Unix epoch timestamp in seconds for the end of the window.
+	// If not provided, it defaults to the current time.
+	EndTimeSec    *int64 `protobuf:"varint,3,opt,name=end_time_sec,json=endTimeSec" json:"end_time_sec,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+// Default values for GetProbeStatusRequest fields.
+const (
+	Default_GetProbeStatusRequest_TimeWindowMinutes = int32(10)
+)
+
+func (x *GetProbeStatusRequest) Reset() {
+	*x = GetProbeStatusRequest{}
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProbeStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProbeStatusRequest) ProtoMessage() {}
+
+func (x *GetProbeStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProbeStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetProbeStatusRequest) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetProbeStatusRequest) GetProbeName() []string {
+	if x != nil {
+		return x.ProbeName
+	}
+	return nil
+}
+
+func (x *GetProbeStatusRequest) GetTimeWindowMinutes() int32 {
+	if x != nil && x.TimeWindowMinutes != nil {
+		return *x.TimeWindowMinutes
+	}
+	return Default_GetProbeStatusRequest_TimeWindowMinutes
+}
+
+func (x *GetProbeStatusRequest) GetEndTimeSec() int64 {
+	if x != nil && x.EndTimeSec != nil {
+		return *x.EndTimeSec
+	}
+	return 0
+}
+
+type GetProbeStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProbeStatus   []*ProbeStatus         `protobuf:"bytes,1,rep,name=probe_status,json=probeStatus" json:"probe_status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProbeStatusResponse) Reset() {
+	*x = GetProbeStatusResponse{}
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProbeStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProbeStatusResponse) ProtoMessage() {}
+
+func (x *GetProbeStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProbeStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetProbeStatusResponse) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetProbeStatusResponse) GetProbeStatus() []*ProbeStatus {
+	if x != nil {
+		return x.ProbeStatus
+	}
+	return nil
+}
+
+type ProbeStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          *string                `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	TargetStatus  []*TargetStatus        `protobuf:"bytes,2,rep,name=target_status,json=targetStatus" json:"target_status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProbeStatus) Reset() {
+	*x = ProbeStatus{}
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProbeStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProbeStatus) ProtoMessage() {}
+
+func (x *ProbeStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProbeStatus.ProtoReflect.Descriptor instead.
+func (*ProbeStatus) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ProbeStatus) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *ProbeStatus) GetTargetStatus() []*TargetStatus {
+	if x != nil {
+		return x.TargetStatus
+	}
+	return nil
+}
+
+type TargetStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TargetName    *string                `protobuf:"bytes,1,opt,name=target_name,json=targetName" json:"target_name,omitempty"`
+	Total         *int64                 `protobuf:"varint,2,opt,name=total" json:"total,omitempty"`
+	Success       *int64                 `protobuf:"varint,3,opt,name=success" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TargetStatus) Reset() {
+	*x = TargetStatus{}
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TargetStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TargetStatus) ProtoMessage() {}
+
+func (x *TargetStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TargetStatus.ProtoReflect.Descriptor instead.
+func (*TargetStatus) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *TargetStatus) GetTargetName() string {
+	if x != nil && x.TargetName != nil {
+		return *x.TargetName
+	}
+	return ""
+}
+
+func (x *TargetStatus) GetTotal() int64 {
+	if x != nil && x.Total != nil {
+		return *x.Total
+	}
+	return 0
+}
+
+func (x *TargetStatus) GetSuccess() int64 {
+	if x != nil && x.Success != nil {
+		return *x.Success
+	}
+	return 0
+}
+
+type WatchProbeResultsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only results for these probes are streamed. If empty, results for all
+	// probes are streamed.
+	ProbeName []string `protobuf:"bytes,1,rep,name=probe_name,json=probeName" json:"probe_name,omitempty"`
+	// Only results for these targets (dst label) are streamed. If empty,
+	// results for all targets are streamed.
+	Target []string `protobuf:"bytes,2,rep,name=target" json:"target,omitempty"`
+	// Only these metrics are included in the streamed results. Results that
+	// don't have any of these metrics are skipped. If empty, all metrics are
+	// included.
+	MetricName    []string `protobuf:"bytes,3,rep,name=metric_name,json=metricName" json:"metric_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchProbeResultsRequest) Reset() {
+	*x = WatchProbeResultsRequest{}
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchProbeResultsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchProbeResultsRequest) ProtoMessage() {}
+
+func (x *WatchProbeResultsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchProbeResultsRequest.ProtoReflect.Descriptor instead.
+func (*WatchProbeResultsRequest) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *WatchProbeResultsRequest) GetProbeName() []string {
+	if x != nil {
+		return x.ProbeName
+	}
+	return nil
+}
+
+func (x *WatchProbeResultsRequest) GetTarget() []string {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+func (x *WatchProbeResultsRequest) GetMetricName() []string {
+	if x != nil {
+		return x.MetricName
+	}
+	return nil
+}
+
+type Label struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           *string                `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	Value         *string                `protobuf:"bytes,2,opt,name=value" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Label) Reset() {
+	*x = Label{}
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SaveProbesConfigResponse) String() string {
+func (x *Label) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SaveProbesConfigResponse) ProtoMessage() {}
+func (*Label) ProtoMessage() {}
 
-func (x *SaveProbesConfigResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[13]
+func (x *Label) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -645,51 +1337,53 @@ func (x *SaveProbesConfigResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SaveProbesConfigResponse.ProtoReflect.Descriptor instead.
-func (*SaveProbesConfigResponse) Descriptor() ([]byte, []int) {
-	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use Label.ProtoReflect.Descriptor instead.
+func (*Label) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *SaveProbesConfigResponse) GetFilePath() string {
-	if x != nil && x.FilePath != nil {
-		return *x.FilePath
+func (x *Label) GetKey() string {
+	if x != nil && x.Key != nil {
+		return *x.Key
 	}
 	return ""
 }
 
-type GetProbeStatusRequest struct {
+func (x *Label) GetValue() string {
+	if x != nil && x.Value != nil {
+		return *x.Value
+	}
+	return ""
+}
+
+type Metric struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// If empty, status for all probes is returned.
-	ProbeName []string `protobuf:"bytes,1,rep,name=probe_name,json=probeName" json:"probe_name,omitempty"`
-	// Time window in minutes to compute aggregated stats for. Default is 10.
-	TimeWindowMinutes *int32 `protobuf:"varint,2,opt,name=time_window_minutes,json=timeWindowMinutes,def=10" json:"time_window_minutes,omitempty"`
-	// Unix epoch timestamp in seconds for the end of the window.
-	// If not provided, it defaults to the current time.
-	EndTimeSec    *int64 `protobuf:"varint,3,opt,name=end_time_sec,json=endTimeSec" json:"end_time_sec,omitempty"`
+	Name  *string                `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// Metric value in cloudprober's text format, e.g. "23", "0.5",
+	// "map:code,200:10,500:1" or "dist:sum:...". It can be parsed back using
+	// metrics.ParseValueFromString.
+	Value *string `protobuf:"bytes,2,opt,name=value" json:"value,omitempty"`
+	// Numeric value, set only for numeric (int and float) metrics.
+	NumValue      *float64 `protobuf:"fixed64,3,opt,name=num_value,json=numValue" json:"num_value,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-// Default values for GetProbeStatusRequest fields.
-const (
-	Default_GetProbeStatusRequest_TimeWindowMinutes = int32(10)
-)
-
-func (x *GetProbeStatusRequest) Reset() {
-	*x = GetProbeStatusRequest{}
-	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[14]
+func (x *Metric) Reset() {
+	*x = Metric{}
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetProbeStatusRequest) String() string {
+func (x *Metric) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetProbeStatusRequest) ProtoMessage() {}
+func (*Metric) ProtoMessage() {}
 
-func (x *GetProbeStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[14]
+func (x *Metric) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -700,54 +1394,59 @@ func (x *GetProbeStatusRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetProbeStatusRequest.ProtoReflect.Descriptor instead.
-func (*GetProbeStatusRequest) Descriptor() ([]byte, []int) {
-	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use Metric.ProtoReflect.Descriptor instead.
+func (*Metric) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{26}
 }
 
-func (x *GetProbeStatusRequest) GetProbeName() []string {
-	if x != nil {
-		return x.ProbeName
+func (x *Metric) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
 	}
-	return nil
+	return ""
 }
 
-func (x *GetProbeStatusRequest) GetTimeWindowMinutes() int32 {
-	if x != nil && x.TimeWindowMinutes != nil {
-		return *x.TimeWindowMinutes
+func (x *Metric) GetValue() string {
+	if x != nil && x.Value != nil {
+		return *x.Value
 	}
-	return Default_GetProbeStatusRequest_TimeWindowMinutes
+	return ""
 }
 
-func (x *GetProbeStatusRequest) GetEndTimeSec() int64 {
-	if x != nil && x.EndTimeSec != nil {
-		return *x.EndTimeSec
+func (x *Metric) GetNumValue() float64 {
+	if x != nil && x.NumValue != nil {
+		return *x.NumValue
 	}
 	return 0
 }
 
-type GetProbeStatusResponse struct {
+// EventMetrics is the proto representation of metrics.EventMetrics, the
+// unit of data that probes export.
+type EventMetrics struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProbeStatus   []*ProbeStatus         `protobuf:"bytes,1,rep,name=probe_status,json=probeStatus" json:"probe_status,omitempty"`
+	TimestampMsec *int64                 `protobuf:"varint,1,opt,name=timestamp_msec,json=timestampMsec" json:"timestamp_msec,omitempty"`
+	Kind          *EventMetrics_Kind     `protobuf:"varint,2,opt,name=kind,enum=cloudprober.EventMetrics_Kind" json:"kind,omitempty"`
+	Label         []*Label               `protobuf:"bytes,3,rep,name=label" json:"label,omitempty"`
+	Metric        []*Metric              `protobuf:"bytes,4,rep,name=metric" json:"metric,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetProbeStatusResponse) Reset() {
-	*x = GetProbeStatusResponse{}
-	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[15]
+func (x *EventMetrics) Reset() {
+	*x = EventMetrics{}
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetProbeStatusResponse) String() string {
+func (x *EventMetrics) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetProbeStatusResponse) ProtoMessage() {}
+func (*EventMetrics) ProtoMessage() {}
 
-func (x *GetProbeStatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[15]
+func (x *EventMetrics) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -758,41 +1457,64 @@ func (x *GetProbeStatusResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetProbeStatusResponse.ProtoReflect.Descriptor instead.
-func (*GetProbeStatusResponse) Descriptor() ([]byte, []int) {
-	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use EventMetrics.ProtoReflect.Descriptor instead.
+func (*EventMetrics) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{27}
 }
 
-func (x *GetProbeStatusResponse) GetProbeStatus() []*ProbeStatus {
+func (x *EventMetrics) GetTimestampMsec() int64 {
+	if x != nil && x.TimestampMsec != nil {
+		return *x.TimestampMsec
+	}
+	return 0
+}
+
+func (x *EventMetrics) GetKind() EventMetrics_Kind {
+	if x != nil && x.Kind != nil {
+		return *x.Kind
+	}
+	return EventMetrics_CUMULATIVE
+}
+
+func (x *EventMetrics) GetLabel() []*Label {
 	if x != nil {
-		return x.ProbeStatus
+		return x.Label
 	}
 	return nil
 }
 
-type ProbeStatus struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          *string                `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
-	TargetStatus  []*TargetStatus        `protobuf:"bytes,2,rep,name=target_status,json=targetStatus" json:"target_status,omitempty"`
+func (x *EventMetrics) GetMetric() []*Metric {
+	if x != nil {
+		return x.Metric
+	}
+	return nil
+}
+
+type WatchProbeResultsResponse struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	EventMetrics *EventMetrics          `protobuf:"bytes,1,opt,name=event_metrics,json=eventMetrics" json:"event_metrics,omitempty"`
+	// Number of results dropped for this subscriber since the last message,
+	// because subscriber was not reading fast enough.
+	DroppedCount  *int64 `protobuf:"varint,2,opt,name=dropped_count,json=droppedCount" json:"dropped_count,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ProbeStatus) Reset() {
-	*x = ProbeStatus{}
-	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[16]
+func (x *WatchProbeResultsResponse) Reset() {
+	*x = WatchProbeResultsResponse{}
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ProbeStatus) String() string {
+func (x *WatchProbeResultsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ProbeStatus) ProtoMessage() {}
+func (*WatchProbeResultsResponse) ProtoMessage() {}
 
-func (x *ProbeStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[16]
+func (x *WatchProbeResultsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -803,49 +1525,50 @@ func (x *ProbeStatus) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ProbeStatus.ProtoReflect.Descriptor instead.
-func (*ProbeStatus) Descriptor() ([]byte, []int) {
-	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use WatchProbeResultsResponse.ProtoReflect.Descriptor instead.
+func (*WatchProbeResultsResponse) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *ProbeStatus) GetName() string {
-	if x != nil && x.Name != nil {
-		return *x.Name
+func (x *WatchProbeResultsResponse) GetEventMetrics() *EventMetrics {
+	if x != nil {
+		return x.EventMetrics
 	}
-	return ""
+	return nil
 }
 
-func (x *ProbeStatus) GetTargetStatus() []*TargetStatus {
-	if x != nil {
-		return x.TargetStatus
+func (x *WatchProbeResultsResponse) GetDroppedCount() int64 {
+	if x != nil && x.DroppedCount != nil {
+		return *x.DroppedCount
 	}
-	return nil
+	return 0
 }
 
-type TargetStatus struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	TargetName    *string                `protobuf:"bytes,1,opt,name=target_name,json=targetName" json:"target_name,omitempty"`
-	Total         *int64                 `protobuf:"varint,2,opt,name=total" json:"total,omitempty"`
-	Success       *int64                 `protobuf:"varint,3,opt,name=success" json:"success,omitempty"`
+type GetFailedResponsesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// If empty, failed responses for all probes are returned.
+	ProbeName []string `protobuf:"bytes,1,rep,name=probe_name,json=probeName" json:"probe_name,omitempty"`
+	// If empty, failed responses for all targets are returned.
+	Target        []string `protobuf:"bytes,2,rep,name=target" json:"target,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *TargetStatus) Reset() {
-	*x = TargetStatus{}
-	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[17]
+func (x *GetFailedResponsesRequest) Reset() {
+	*x = GetFailedResponsesRequest{}
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TargetStatus) String() string {
+func (x *GetFailedResponsesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TargetStatus) ProtoMessage() {}
+func (*GetFailedResponsesRequest) ProtoMessage() {}
 
-func (x *TargetStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[17]
+func (x *GetFailedResponsesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -856,32 +1579,228 @@ func (x *TargetStatus) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TargetStatus.ProtoReflect.Descriptor instead.
-func (*TargetStatus) Descriptor() ([]byte, []int) {
-	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{17}
+// Deprecated: Use GetFailedResponsesRequest.ProtoReflect.Descriptor instead.
+func (*GetFailedResponsesRequest) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{29}
 }
 
-func (x *TargetStatus) GetTargetName() string {
-	if x != nil && x.TargetName != nil {
-		return *x.TargetName
+func (x *GetFailedResponsesRequest) GetProbeName() []string {
+	if x != nil {
+		return x.ProbeName
+	}
+	return nil
+}
+
+func (x *GetFailedResponsesRequest) GetTarget() []string {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+type FailedResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProbeName     *string                `protobuf:"bytes,1,opt,name=probe_name,json=probeName" json:"probe_name,omitempty"`
+	Target        *string                `protobuf:"bytes,2,opt,name=target" json:"target,omitempty"`
+	TimestampMsec *int64                 `protobuf:"varint,3,opt,name=timestamp_msec,json=timestampMsec" json:"timestamp_msec,omitempty"`
+	Url           *string                `protobuf:"bytes,4,opt,name=url" json:"url,omitempty"`
+	// HTTP status code. Not set if no response was received.
+	StatusCode *int32 `protobuf:"varint,5,opt,name=status_code,json=statusCode" json:"status_code,omitempty"`
+	// Response headers. Headers with multiple values are repeated.
+	Header        []*Label `protobuf:"bytes,6,rep,name=header" json:"header,omitempty"`
+	Body          []byte   `protobuf:"bytes,7,opt,name=body" json:"body,omitempty"`
+	BodyTruncated *bool    `protobuf:"varint,8,opt,name=body_truncated,json=bodyTruncated" json:"body_truncated,omitempty"`
+	Error         *string  `protobuf:"bytes,9,opt,name=error" json:"error,omitempty"`
+	// Total latency and latency breakdown by request stages, in microseconds.
+	LatencyUsec             *int64 `protobuf:"varint,10,opt,name=latency_usec,json=latencyUsec" json:"latency_usec,omitempty"`
+	DnsLatencyUsec          *int64 `protobuf:"varint,11,opt,name=dns_latency_usec,json=dnsLatencyUsec" json:"dns_latency_usec,omitempty"`
+	ConnectLatencyUsec      *int64 `protobuf:"varint,12,opt,name=connect_latency_usec,json=connectLatencyUsec" json:"connect_latency_usec,omitempty"`
+	TlsHandshakeLatencyUsec *int64 `protobuf:"varint,13,opt,name=tls_handshake_latency_usec,json=tlsHandshakeLatencyUsec" json:"tls_handshake_latency_usec,omitempty"`
+	ReqWriteLatencyUsec     *int64 `protobuf:"varint,14,opt,name=req_write_latency_usec,json=reqWriteLatencyUsec" json:"req_write_latency_usec,omitempty"`
+	FirstByteLatencyUsec    *int64 `protobuf:"varint,15,opt,name=first_byte_latency_usec,json=firstByteLatencyUsec" json:"first_byte_latency_usec,omitempty"`
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *FailedResponse) Reset() {
+	*x = FailedResponse{}
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FailedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FailedResponse) ProtoMessage() {}
+
+func (x *FailedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FailedResponse.ProtoReflect.Descriptor instead.
+func (*FailedResponse) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *FailedResponse) GetProbeName() string {
+	if x != nil && x.ProbeName != nil {
+		return *x.ProbeName
 	}
 	return ""
 }
 
-func (x *TargetStatus) GetTotal() int64 {
-	if x != nil && x.Total != nil {
-		return *x.Total
+func (x *FailedResponse) GetTarget() string {
+	if x != nil && x.Target != nil {
+		return *x.Target
+	}
+	return ""
+}
+
+func (x *FailedResponse) GetTimestampMsec() int64 {
+	if x != nil && x.TimestampMsec != nil {
+		return *x.TimestampMsec
 	}
 	return 0
 }
 
-func (x *TargetStatus) GetSuccess() int64 {
-	if x != nil && x.Success != nil {
-		return *x.Success
+func (x *FailedResponse) GetUrl() string {
+	if x != nil && x.Url != nil {
+		return *x.Url
+	}
+	return ""
+}
+
+func (x *FailedResponse) GetStatusCode() int32 {
+	if x != nil && x.StatusCode != nil {
+		return *x.StatusCode
+	}
+	return 0
+}
+
+func (x *FailedResponse) GetHeader() []*Label {
+	if x != nil {
+		return x.Header
+	}
+	return nil
+}
+
+func (x *FailedResponse) GetBody() []byte {
+	if x != nil {
+		return x.Body
+	}
+	return nil
+}
+
+func (x *FailedResponse) GetBodyTruncated() bool {
+	if x != nil && x.BodyTruncated != nil {
+		return *x.BodyTruncated
+	}
+	return false
+}
+
+func (x *FailedResponse) GetError() string {
+	if x != nil && x.Error != nil {
+		return *x.Error
+	}
+	return ""
+}
+
+func (x *FailedResponse) GetLatencyUsec() int64 {
+	if x != nil && x.LatencyUsec != nil {
+		return *x.LatencyUsec
+	}
+	return 0
+}
+
+func (x *FailedResponse) GetDnsLatencyUsec() int64 {
+	if x != nil && x.DnsLatencyUsec != nil {
+		return *x.DnsLatencyUsec
+	}
+	return 0
+}
+
+func (x *FailedResponse) GetConnectLatencyUsec() int64 {
+	if x != nil && x.ConnectLatencyUsec != nil {
+		return *x.ConnectLatencyUsec
+	}
+	return 0
+}
+
+func (x *FailedResponse) GetTlsHandshakeLatencyUsec() int64 {
+	if x != nil && x.TlsHandshakeLatencyUsec != nil {
+		return *x.TlsHandshakeLatencyUsec
+	}
+	return 0
+}
+
+func (x *FailedResponse) GetReqWriteLatencyUsec() int64 {
+	if x != nil && x.ReqWriteLatencyUsec != nil {
+		return *x.ReqWriteLatencyUsec
+	}
+	return 0
+}
+
+func (x *FailedResponse) GetFirstByteLatencyUsec() int64 {
+	if x != nil && x.FirstByteLatencyUsec != nil {
+		return *x.FirstByteLatencyUsec
 	}
 	return 0
 }
 
+type GetFailedResponsesResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	FailedResponse []*FailedResponse      `protobuf:"bytes,1,rep,name=failed_response,json=failedResponse" json:"failed_response,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetFailedResponsesResponse) Reset() {
+	*x = GetFailedResponsesResponse{}
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFailedResponsesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFailedResponsesResponse) ProtoMessage() {}
+
+func (x *GetFailedResponsesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFailedResponsesResponse.ProtoReflect.Descriptor instead.
+func (*GetFailedResponsesResponse) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *GetFailedResponsesResponse) GetFailedResponse() []*FailedResponse {
+	if x != nil {
+		return x.FailedResponse
+	}
+	return nil
+}
+
 var File_github_com_cloudprober_cloudprober_prober_proto_service_proto protoreflect.FileDescriptor
 
 const file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDesc = "" +
@@ -893,11 +1812,27 @@ const file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDesc
 	"\x12RemoveProbeRequest\x12\x1d\n" +
 	"\n" +
 	"probe_name\x18\x01 \x01(\tR\tprobeName\"\x15\n" +
-	"\x13RemoveProbeResponse\"0\n" +
+	"\x13RemoveProbeResponse\"U\n" +
+	"\x12UpdateProbeRequest\x12?\n" +
+	"\fprobe_config\x18\x01 \x01(\v2\x1c.cloudprober.probes.ProbeDefR\vprobeConfig\"\x15\n" +
+	"\x13UpdateProbeResponse\"2\n" +
+	"\x11PauseProbeRequest\x12\x1d\n" +
+	"\n" +
+	"probe_name\x18\x01 \x01(\tR\tprobeName\"\x14\n" +
+	"\x12PauseProbeResponse\"3\n" +
+	"\x12ResumeProbeRequest\x12\x1d\n" +
+	"\n" +
+	"probe_name\x18\x01 \x01(\tR\tprobeName\"\x15\n" +
+	"\x13ResumeProbeResponse\"H\n" +
 	"\x0fRunProbeRequest\x12\x1d\n" +
 	"\n" +
-	"probe_name\x18\x01 \x03(\tR\tprobeName\"\x0e\n" +
-	"\fResultMetric\"\xdc\x01\n" +
+	"probe_name\x18\x01 \x03(\tR\tprobeName\x12\x16\n" +
+	"\x06target\x18\x02 \x03(\tR\x06target\"\x7f\n" +
+	"\fResultMetric\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\x12\x1b\n" +
+	"\tnum_value\x18\x03 \x01(\x01R\bnumValue\x12(\n" +
+	"\x05label\x18\x04 \x03(\v2\x12.cloudprober.LabelR\x05label\"\xdc\x01\n" +
 	"\x0eProbeRunResult\x125\n" +
 	"\x06target\x18\x01 \x02(\v2\x1d.cloudprober.targets.EndpointR\x06target\x12\x18\n" +
 	"\asuccess\x18\x02 \x02(\bR\asuccess\x12!\n" +
@@ -912,10 +1847,11 @@ const file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDesc
 	"\fResultsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12/\n" +
 	"\x05value\x18\x02 \x01(\v2\x19.cloudprober.ProbeResultsR\x05value:\x028\x01\"\x13\n" +
-	"\x11ListProbesRequest\"Q\n" +
+	"\x11ListProbesRequest\"i\n" +
 	"\x05Probe\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x124\n" +
-	"\x06config\x18\x02 \x01(\v2\x1c.cloudprober.probes.ProbeDefR\x06config\">\n" +
+	"\x06config\x18\x02 \x01(\v2\x1c.cloudprober.probes.ProbeDefR\x06config\x12\x16\n" +
+	"\x06paused\x18\x03 \x01(\bR\x06paused\">\n" +
 	"\x12ListProbesResponse\x12(\n" +
 	"\x05probe\x18\x01 \x03(\v2\x12.cloudprober.ProbeR\x05probe\"6\n" +
 	"\x17SaveProbesConfigRequest\x12\x1b\n" +
@@ -937,15 +1873,71 @@ const file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDesc
 	"\vtarget_name\x18\x01 \x01(\tR\n" +
 	"targetName\x12\x14\n" +
 	"\x05total\x18\x02 \x01(\x03R\x05total\x12\x18\n" +
-	"\asuccess\x18\x03 \x01(\x03R\asuccess2\x88\x04\n" +
+	"\asuccess\x18\x03 \x01(\x03R\asuccess\"r\n" +
+	"\x18WatchProbeResultsRequest\x12\x1d\n" +
+	"\n" +
+	"probe_name\x18\x01 \x03(\tR\tprobeName\x12\x16\n" +
+	"\x06target\x18\x02 \x03(\tR\x06target\x12\x1f\n" +
+	"\vmetric_name\x18\x03 \x03(\tR\n" +
+	"metricName\"/\n" +
+	"\x05Label\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\"O\n" +
+	"\x06Metric\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\x12\x1b\n" +
+	"\tnum_value\x18\x03 \x01(\x01R\bnumValue\"\xe3\x01\n" +
+	"\fEventMetrics\x12%\n" +
+	"\x0etimestamp_msec\x18\x01 \x01(\x03R\rtimestampMsec\x122\n" +
+	"\x04kind\x18\x02 \x01(\x0e2\x1e.cloudprober.EventMetrics.KindR\x04kind\x12(\n" +
+	"\x05label\x18\x03 \x03(\v2\x12.cloudprober.LabelR\x05label\x12+\n" +
+	"\x06metric\x18\x04 \x03(\v2\x13.cloudprober.MetricR\x06metric\"!\n" +
+	"\x04Kind\x12\x0e\n" +
+	"\n" +
+	"CUMULATIVE\x10\x00\x12\t\n" +
+	"\x05GAUGE\x10\x01\"\x80\x01\n" +
+	"\x19WatchProbeResultsResponse\x12>\n" +
+	"\revent_metrics\x18\x01 \x01(\v2\x19.cloudprober.EventMetricsR\feventMetrics\x12#\n" +
+	"\rdropped_count\x18\x02 \x01(\x03R\fdroppedCount\"R\n" +
+	"\x19GetFailedResponsesRequest\x12\x1d\n" +
+	"\n" +
+	"probe_name\x18\x01 \x03(\tR\tprobeName\x12\x16\n" +
+	"\x06target\x18\x02 \x03(\tR\x06target\"\xc6\x04\n" +
+	"\x0eFailedResponse\x12\x1d\n" +
+	"\n" +
+	"probe_name\x18\x01 \x01(\tR\tprobeName\x12\x16\n" +
+	"\x06target\x18\x02 \x01(\tR\x06target\x12%\n" +
+	"\x0etimestamp_msec\x18\x03 \x01(\x03R\rtimestampMsec\x12\x10\n" +
+	"\x03url\x18\x04 \x01(\tR\x03url\x12\x1f\n" +
+	"\vstatus_code\x18\x05 \x01(\x05R\n" +
+	"statusCode\x12*\n" +
+	"\x06header\x18\x06 \x03(\v2\x12.cloudprober.LabelR\x06header\x12\x12\n" +
+	"\x04body\x18\a \x01(\fR\x04body\x12%\n" +
+	"\x0ebody_truncated\x18\b \x01(\bR\rbodyTruncated\x12\x14\n" +
+	"\x05error\x18\t \x01(\tR\x05error\x12!\n" +
+	"\flatency_usec\x18\n" +
+	" \x01(\x03R\vlatencyUsec\x12(\n" +
+	"\x10dns_latency_usec\x18\v \x01(\x03R\x0ednsLatencyUsec\x120\n" +
+	"\x14connect_latency_usec\x18\f \x01(\x03R\x12connectLatencyUsec\x12;\n" +
+	"\x1atls_handshake_latency_usec\x18\r \x01(\x03R\x17tlsHandshakeLatencyUsec\x123\n" +
+	"\x16req_write_latency_usec\x18\x0e \x01(\x03R\x13reqWriteLatencyUsec\x125\n" +
+	"\x17first_byte_latency_usec\x18\x0f \x01(\x03R\x14firstByteLatencyUsec\"b\n" +
+	"\x1aGetFailedResponsesResponse\x12D\n" +
+	"\x0ffailed_response\x18\x01 \x03(\v2\x1b.cloudprober.FailedResponseR\x0efailedResponse2\xd2\a\n" +
 	"\vCloudprober\x12I\n" +
 	"\bAddProbe\x12\x1c.cloudprober.AddProbeRequest\x1a\x1d.cloudprober.AddProbeResponse\"\x00\x12R\n" +
-	"\vRemoveProbe\x12\x1f.cloudprober.RemoveProbeRequest\x1a .cloudprober.RemoveProbeResponse\"\x00\x12I\n" +
+	"\vRemoveProbe\x12\x1f.cloudprober.RemoveProbeRequest\x1a .cloudprober.RemoveProbeResponse\"\x00\x12R\n" +
+	"\vUpdateProbe\x12\x1f.cloudprober.UpdateProbeRequest\x1a .cloudprober.UpdateProbeResponse\"\x00\x12O\n" +
+	"\n" +
+	"PauseProbe\x12\x1e.cloudprober.PauseProbeRequest\x1a\x1f.cloudprober.PauseProbeResponse\"\x00\x12R\n" +
+	"\vResumeProbe\x12\x1f.cloudprober.ResumeProbeRequest\x1a .cloudprober.ResumeProbeResponse\"\x00\x12I\n" +
 	"\bRunProbe\x12\x1c.cloudprober.RunProbeRequest\x1a\x1d.cloudprober.RunProbeResponse\"\x00\x12O\n" +
 	"\n" +
 	"ListProbes\x12\x1e.cloudprober.ListProbesRequest\x1a\x1f.cloudprober.ListProbesResponse\"\x00\x12a\n" +
 	"\x10SaveProbesConfig\x12$.cloudprober.SaveProbesConfigRequest\x1a%.cloudprober.SaveProbesConfigResponse\"\x00\x12[\n" +
-	"\x0eGetProbeStatus\x12\".cloudprober.GetProbeStatusRequest\x1a#.cloudprober.GetProbeStatusResponse\"\x00B1Z/github.com/cloudprober/cloudprober/prober/proto"
+	"\x0eGetProbeStatus\x12\".cloudprober.GetProbeStatusRequest\x1a#.cloudprober.GetProbeStatusResponse\"\x00\x12f\n" +
+	"\x11WatchProbeResults\x12%.cloudprober.WatchProbeResultsRequest\x1a&.cloudprober.WatchProbeResultsResponse\"\x000\x01\x12g\n" +
+	"\x12GetFailedResponses\x12&.cloudprober.GetFailedResponsesRequest\x1a'.cloudprober.GetFailedResponsesResponse\"\x00B1Z/github.com/cloudprober/cloudprober/prober/proto"
 
 var (
 	file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescOnce sync.Once
@@ -959,58 +1951,92 @@ func file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescG
 	return file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDescData
 }
 
-var file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_github_com_cloudprober_cloudprober_prober_proto_service_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes = make([]protoimpl.MessageInfo, 33)
 var file_github_com_cloudprober_cloudprober_prober_proto_service_proto_goTypes = []any{
-	(*AddProbeRequest)(nil),          // 0: cloudprober.AddProbeRequest
-	(*AddProbeResponse)(nil),         // 1: cloudprober.AddProbeResponse
-	(*RemoveProbeRequest)(nil),       // 2: cloudprober.RemoveProbeRequest
-	(*RemoveProbeResponse)(nil),      // 3: cloudprober.RemoveProbeResponse
-	(*RunProbeRequest)(nil),          // 4: cloudprober.RunProbeRequest
-	(*ResultMetric)(nil),             // 5: cloudprober.ResultMetric
-	(*ProbeRunResult)(nil),           // 6: cloudprober.ProbeRunResult
-	(*ProbeResults)(nil),             // 7: cloudprober.ProbeResults
-	(*RunProbeResponse)(nil),         // 8: cloudprober.RunProbeResponse
-	(*ListProbesRequest)(nil),        // 9: cloudprober.ListProbesRequest
-	(*Probe)(nil),                    // 10: cloudprober.Probe
-	(*ListProbesResponse)(nil),       // 11: cloudprober.ListProbesResponse
-	(*SaveProbesConfigRequest)(nil),  // 12: cloudprober.SaveProbesConfigRequest
-	(*SaveProbesConfigResponse)(nil), // 13: cloudprober.SaveProbesConfigResponse
-	(*GetProbeStatusRequest)(nil),    // 14: cloudprober.GetProbeStatusRequest
-	(*GetProbeStatusResponse)(nil),   // 15: cloudprober.GetProbeStatusResponse
-	(*ProbeStatus)(nil),              // 16: cloudprober.ProbeStatus
-	(*TargetStatus)(nil),             // 17: cloudprober.TargetStatus
-	nil,                              // 18: cloudprober.RunProbeResponse.ResultsEntry
-	(*proto.ProbeDef)(nil),           // 19: cloudprober.probes.ProbeDef
-	(*proto1.Endpoint)(nil),          // 20: cloudprober.targets.Endpoint
+	(EventMetrics_Kind)(0),             // 0: cloudprober.EventMetrics.Kind
+	(*AddProbeRequest)(nil),            // 1: cloudprober.AddProbeRequest
+	(*AddProbeResponse)(nil),           // 2: cloudprober.AddProbeResponse
+	(*RemoveProbeRequest)(nil),         // 3: cloudprober.RemoveProbeRequest
+	(*RemoveProbeResponse)(nil),        // 4: cloudprober.RemoveProbeResponse
+	(*UpdateProbeRequest)(nil),         // 5: cloudprober.UpdateProbeRequest
+	(*UpdateProbeResponse)(nil),        // 6: cloudprober.UpdateProbeResponse
+	(*PauseProbeRequest)(nil),          // 7: cloudprober.PauseProbeRequest
+	(*PauseProbeResponse)(nil),         // 8: cloudprober.PauseProbeResponse
+	(*ResumeProbeRequest)(nil),         // 9: cloudprober.ResumeProbeRequest
+	(*ResumeProbeResponse)(nil),        // 10: cloudprober.ResumeProbeResponse
+	(*RunProbeRequest)(nil),            // 11: cloudprober.RunProbeRequest
+	(*ResultMetric)(nil),               // 12: cloudprober.ResultMetric
+	(*ProbeRunResult)(nil),             // 13: cloudprober.ProbeRunResult
+	(*ProbeResults)(nil),               // 14: cloudprober.ProbeResults
+	(*RunProbeResponse)(nil),           // 15: cloudprober.RunProbeResponse
+	(*ListProbesRequest)(nil),          // 16: cloudprober.ListProbesRequest
+	(*Probe)(nil),                      // 17: cloudprober.Probe
+	(*ListProbesResponse)(nil),         // 18: cloudprober.ListProbesResponse
+	(*SaveProbesConfigRequest)(nil),    // 19: cloudprober.SaveProbesConfigRequest
+	(*SaveProbesConfigResponse)(nil),   // 20: cloudprober.SaveProbesConfigResponse
+	(*GetProbeStatusRequest)(nil),      // 21: cloudprober.GetProbeStatusRequest
+	(*GetProbeStatusResponse)(nil),     // 22: cloudprober.GetProbeStatusResponse
+	(*ProbeStatus)(nil),                // 23: cloudprober.ProbeStatus
+	(*TargetStatus)(nil),               // 24: cloudprober.TargetStatus
+	(*WatchProbeResultsRequest)(nil),   // 25: cloudprober.WatchProbeResultsRequest
+	(*Label)(nil),                      // 26: cloudprober.Label
+	(*Metric)(nil),                     // 27: cloudprober.Metric
+	(*EventMetrics)(nil),               // 28: cloudprober.EventMetrics
+	(*WatchProbeResultsResponse)(nil),  // 29: cloudprober.WatchProbeResultsResponse
+	(*GetFailedResponsesRequest)(nil),  // 30: cloudprober.GetFailedResponsesRequest
+	(*FailedResponse)(nil),             // 31: cloudprober.FailedResponse
+	(*GetFailedResponsesResponse)(nil), // 32: cloudprober.GetFailedResponsesResponse
+	nil,                                // 33: cloudprober.RunProbeResponse.ResultsEntry
+	(*proto.ProbeDef)(nil),             // 34: cloudprober.probes.ProbeDef
+	(*proto1.Endpoint)(nil),            // 35: cloudprober.targets.Endpoint
 }
 var file_github_com_cloudprober_cloudprober_prober_proto_service_proto_depIdxs = []int32{
-	19, // 0: cloudprober.AddProbeRequest.probe_config:type_name -> cloudprober.probes.ProbeDef
-	20, // 1: cloudprober.ProbeRunResult.target:type_name -> cloudprober.targets.Endpoint
-	5,  // 2: cloudprober.ProbeRunResult.result_metrics:type_name -> cloudprober.ResultMetric
-	6,  // 3: cloudprober.ProbeResults.run_result:type_name -> cloudprober.ProbeRunResult
-	18, // 4: cloudprober.RunProbeResponse.results:type_name -> cloudprober.RunProbeResponse.ResultsEntry
-	19, // 5: cloudprober.Probe.config:type_name -> cloudprober.probes.ProbeDef
-	10, // 6: cloudprober.ListProbesResponse.probe:type_name -> cloudprober.Probe
-	16, // 7: cloudprober.GetProbeStatusResponse.probe_status:type_name -> cloudprober.ProbeStatus
-	17, // 8: cloudprober.ProbeStatus.target_status:type_name -> cloudprober.TargetStatus
-	7,  // 9: cloudprober.RunProbeResponse.ResultsEntry.value:type_name -> cloudprober.ProbeResults
-	0,  // 10: cloudprober.Cloudprober.AddProbe:input_type -> cloudprober.AddProbeRequest
-	2,  // 11: cloudprober.Cloudprober.RemoveProbe:input_type -> cloudprober.RemoveProbeRequest
-	4,  // 12: cloudprober.Cloudprober.RunProbe:input_type -> cloudprober.RunProbeRequest
-	9,  // 13: cloudprober.Cloudprober.ListProbes:input_type -> cloudprober.ListProbesRequest
-	12, // 14: cloudprober.Cloudprober.SaveProbesConfig:input_type -> cloudprober.SaveProbesConfigRequest
-	14, // 15: cloudprober.Cloudprober.GetProbeStatus:input_type -> cloudprober.GetProbeStatusRequest
-	1,  // 16: cloudprober.Cloudprober.AddProbe:output_type -> cloudprober.AddProbeResponse
-	3,  // 17: cloudprober.Cloudprober.RemoveProbe:output_type -> cloudprober.RemoveProbeResponse
-	8,  // 18: cloudprober.Cloudprober.RunProbe:output_type -> cloudprober.RunProbeResponse
-	11, // 19: cloudprober.Cloudprober.ListProbes:output_type -> cloudprober.ListProbesResponse
-	13, // 20: cloudprober.Cloudprober.SaveProbesConfig:output_type -> cloudprober.SaveProbesConfigResponse
-	15, // 21: cloudprober.Cloudprober.GetProbeStatus:output_type -> cloudprober.GetProbeStatusResponse
-	16, // [16:22] is the sub-list for method output_type
-	10, // [10:16] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+	34, // 0: cloudprober.AddProbeRequest.probe_config:type_name -> cloudprober.probes.ProbeDef
+	34, // 1: cloudprober.UpdateProbeRequest.probe_config:type_name -> cloudprober.probes.ProbeDef
+	26, // 2: cloudprober.ResultMetric.label:type_name -> cloudprober.Label
+	35, // 3: cloudprober.ProbeRunResult.target:type_name -> cloudprober.targets.Endpoint
+	12, // 4: cloudprober.ProbeRunResult.result_metrics:type_name -> cloudprober.ResultMetric
+	13, // 5: cloudprober.ProbeResults.run_result:type_name -> cloudprober.ProbeRunResult
+	33, // 6: cloudprober.RunProbeResponse.results:type_name -> cloudprober.RunProbeResponse.ResultsEntry
+	34, // 7: cloudprober.Probe.config:type_name -> cloudprober.probes.ProbeDef
+	17, // 8: cloudprober.ListProbesResponse.probe:type_name -> cloudprober.Probe
+	23, // 9: cloudprober.GetProbeStatusResponse.probe_status:type_name -> cloudprober.ProbeStatus
+	24, // 10: cloudprober.ProbeStatus.target_status:type_name -> cloudprober.TargetStatus
+	0,  // 11: cloudprober.EventMetrics.kind:type_name -> cloudprober.EventMetrics.Kind
+	26, // 12: cloudprober.EventMetrics.label:type_name -> cloudprober.Label
+	27, // 13: cloudprober.EventMetrics.metric:type_name -> cloudprober.Metric
+	28, // 14: cloudprober.WatchProbeResultsResponse.event_metrics:type_name -> cloudprober.EventMetrics
+	26, // 15: cloudprober.FailedResponse.header:type_name -> cloudprober.Label
+	31, // 16: cloudprober.GetFailedResponsesResponse.failed_response:type_name -> cloudprober.FailedResponse
+	14, // 17: cloudprober.RunProbeResponse.ResultsEntry.value:type_name -> cloudprober.ProbeResults
+	1,  // 18: cloudprober.Cloudprober.AddProbe:input_type -> cloudprober.AddProbeRequest
+	3,  // 19: cloudprober.Cloudprober.RemoveProbe:input_type -> cloudprober.RemoveProbeRequest
+	5,  // 20: cloudprober.Cloudprober.UpdateProbe:input_type -> cloudprober.UpdateProbeRequest
+	7,  // 21: cloudprober.Cloudprober.PauseProbe:input_type -> cloudprober.PauseProbeRequest
+	9,  // 22: cloudprober.Cloudprober.ResumeProbe:input_type -> cloudprober.ResumeProbeRequest
+	11, // 23: cloudprober.Cloudprober.RunProbe:input_type -> cloudprober.RunProbeRequest
+	16, // 24: cloudprober.Cloudprober.ListProbes:input_type -> cloudprober.ListProbesRequest
+	19, // 25: cloudprober.Cloudprober.SaveProbesConfig:input_type -> cloudprober.SaveProbesConfigRequest
+	21, // 26: cloudprober.Cloudprober.GetProbeStatus:input_type -> cloudprober.GetProbeStatusRequest
+	25, // 27: cloudprober.Cloudprober.WatchProbeResults:input_type -> cloudprober.WatchProbeResultsRequest
+	30, // 28: cloudprober.Cloudprober.GetFailedResponses:input_type -> cloudprober.GetFailedResponsesRequest
+	2,  // 29: cloudprober.Cloudprober.AddProbe:output_type -> cloudprober.AddProbeResponse
+	4,  // 30: cloudprober.Cloudprober.RemoveProbe:output_type -> cloudprober.RemoveProbeResponse
+	6,  // 31: cloudprober.Cloudprober.UpdateProbe:output_type -> cloudprober.UpdateProbeResponse
+	8,  // 32: cloudprober.Cloudprober.PauseProbe:output_type -> cloudprober.PauseProbeResponse
+	10, // 33: cloudprober.Cloudprober.ResumeProbe:output_type -> cloudprober.ResumeProbeResponse
+	15, // 34: cloudprober.Cloudprober.RunProbe:output_type -> cloudprober.RunProbeResponse
+	18, // 35: cloudprober.Cloudprober.ListProbes:output_type -> cloudprober.ListProbesResponse
+	20, // 36: cloudprober.Cloudprober.SaveProbesConfig:output_type -> cloudprober.SaveProbesConfigResponse
+	22, // 37: cloudprober.Cloudprober.GetProbeStatus:output_type -> cloudprober.GetProbeStatusResponse
+	29, // 38: cloudprober.Cloudprober.WatchProbeResults:output_type -> cloudprober.WatchProbeResultsResponse
+	32, // 39: cloudprober.Cloudprober.GetFailedResponses:output_type -> cloudprober.GetFailedResponsesResponse
+	29, // [29:40] is the sub-list for method output_type
+	18, // [18:29] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
 }
 
 func init() { file_github_com_cloudprober_cloudprober_prober_proto_service_proto_init() }
@@ -1023,13 +2049,14 @@ func file_github_com_cloudprober_cloudprober_prober_proto_service_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDesc), len(file_github_com_cloudprober_cloudprober_prober_proto_service_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   19,
+			NumEnums:      1,
+			NumMessages:   33,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_github_com_cloudprober_cloudprober_prober_proto_service_proto_goTypes,
 		DependencyIndexes: file_github_com_cloudprober_cloudprober_prober_proto_service_proto_depIdxs,
+		EnumInfos:         file_github_com_cloudprober_cloudprober_prober_proto_service_proto_enumTypes,
 		MessageInfos:      file_github_com_cloudprober_cloudprober_prober_proto_service_proto_msgTypes,
 	}.Build()
 	File_github_com_cloudprober_cloudprober_prober_proto_service_proto = out.File
@@ -19,12 +19,17 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Cloudprober_AddProbe_FullMethodName         = "/cloudprober.Cloudprober/AddProbe"
-	Cloudprober_RemoveProbe_FullMethodName      = "/cloudprober.Cloudprober/RemoveProbe"
-	Cloudprober_RunProbe_FullMethodName         = "/cloudprober.Cloudprober/RunProbe"
-	Cloudprober_ListProbes_FullMethodName       = "/cloudprober.Cloudprober/ListProbes"
-	Cloudprober_SaveProbesConfig_FullMethodName = "/cloudprober.Cloudprober/SaveProbesConfig"
-	Cloudprober_GetProbeStatus_FullMethodName   = "/cloudprober.Cloudprober/GetProbeStatus"
+	Cloudprober_AddProbe_FullMethodName           = "/cloudprober.Cloudprober/AddProbe"
+	Cloudprober_RemoveProbe_FullMethodName        = "/cloudprober.Cloudprober/RemoveProbe"
+	Cloudprober_UpdateProbe_FullMethodName        = "/cloudprober.Cloudprober/UpdateProbe"
+	Cloudprober_PauseProbe_FullMethodName         = "/cloudprober.Cloudprober/PauseProbe"
+	Cloudprober_ResumeProbe_FullMethodName        = "/cloudprober.Cloudprober/ResumeProbe"
+	Cloudprober_RunProbe_FullMethodName           = "/cloudprober.Cloudprober/RunProbe"
+	Cloudprober_ListProbes_FullMethodName         = "/cloudprober.Cloudprober/ListProbes"
+	Cloudprober_SaveProbesConfig_FullMethodName   = "/cloudprober.Cloudprober/SaveProbesConfig"
+	Cloudprober_GetProbeStatus_FullMethodName     = "/cloudprober.Cloudprober/GetProbeStatus"
+	Cloudprober_WatchProbeResults_FullMethodName  = "/cloudprober.Cloudprober/WatchProbeResults"
+	Cloudprober_GetFailedResponses_FullMethodName = "/cloudprober.Cloudprober/GetFailedResponses"
 )
 
 // CloudproberClient is the client API for Cloudprober service.
@@ -36,6 +41,16 @@ type CloudproberClient interface {
 	AddProbe(ctx context.Context, in *AddProbeRequest, opts ...grpc.CallOption) (*AddProbeResponse, error)
 	// RemoveProbe stops the probe and removes it from the in-memory database.
 	RemoveProbe(ctx context.Context, in *RemoveProbeRequest, opts ...grpc.CallOption) (*RemoveProbeResponse, error)
+	// UpdateProbe replaces an existing probe's definition in place. Probe is
+	// restarted with the new definition, but it keeps its name and status
+	// history. A paused probe stays paused after the update. If there is an
+	// error in the new definition, the existing probe is left untouched.
+	UpdateProbe(ctx context.Context, in *UpdateProbeRequest, opts ...grpc.CallOption) (*UpdateProbeResponse, error)
+	// PauseProbe stops scheduling new runs of the probe, without removing it.
+	// Paused probes are reported as such by ListProbes.
+	PauseProbe(ctx context.Context, in *PauseProbeRequest, opts ...grpc.CallOption) (*PauseProbeResponse, error)
+	// ResumeProbe resumes a paused probe.
+	ResumeProbe(ctx context.Context, in *ResumeProbeRequest, opts ...grpc.CallOption) (*ResumeProbeResponse, error)
 	// EXPERIMENTAL. It's still in development. Implementation subject to change.
 	// RunProbe runs all or subset of probes this instance is configured with.
 	RunProbe(ctx context.Context, in *RunProbeRequest, opts ...grpc.CallOption) (*RunProbeResponse, error)
@@ -45,6 +60,16 @@ type CloudproberClient interface {
 	// GetProbeStatus returns the ongoing probe status data, including
 	// total/success counters per probe/target.
 	GetProbeStatus(ctx context.Context, in *GetProbeStatusRequest, opts ...grpc.CallOption) (*GetProbeStatusResponse, error)
+	// WatchProbeResults streams probe results (EventMetrics) as they are
+	// generated. Results can be filtered by probe name, target and metric name.
+	// Each subscriber gets a bounded buffer; if a subscriber can't keep up,
+	// results are dropped and the number of dropped results is reported in the
+	// next message.
+	WatchProbeResults(ctx context.Context, in *WatchProbeResultsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchProbeResultsResponse], error)
+	// GetFailedResponses returns the recent failed responses kept by the probes
+	// that are configured to keep them (see HTTP probe's keep_failed_responses).
+	// Responses are returned newest first for each probe and target.
+	GetFailedResponses(ctx context.Context, in *GetFailedResponsesRequest, opts ...grpc.CallOption) (*GetFailedResponsesResponse, error)
 }
 
 type cloudproberClient struct {
@@ -75,6 +100,36 @@ func (c *cloudproberClient) RemoveProbe(ctx context.Context, in *RemoveProbeRequ
 	return out, nil
 }
 
+func (c *cloudproberClient) UpdateProbe(ctx context.Context, in *UpdateProbeRequest, opts ...grpc.CallOption) (*UpdateProbeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateProbeResponse)
+	err := c.cc.Invoke(ctx, Cloudprober_UpdateProbe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cloudproberClient) PauseProbe(ctx context.Context, in *PauseProbeRequest, opts ...grpc.CallOption) (*PauseProbeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PauseProbeResponse)
+	err := c.cc.Invoke(ctx, Cloudprober_PauseProbe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cloudproberClient) ResumeProbe(ctx context.Context, in *ResumeProbeRequest, opts ...grpc.CallOption) (*ResumeProbeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResumeProbeResponse)
+	err := c.cc.Invoke(ctx, Cloudprober_ResumeProbe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *cloudproberClient) RunProbe(ctx context.Context, in *RunProbeRequest, opts ...grpc.CallOption) (*RunProbeResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(RunProbeResponse)
@@ -115,6 +170,35 @@ func (c *cloudproberClient) GetProbeStatus(ctx context.Context, in *GetProbeStat
 	return out, nil
 }
 
+func (c *cloudproberClient) WatchProbeResults(ctx context.Context, in *WatchProbeResultsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchProbeResultsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Cloudprober_ServiceDesc.Streams[0], Cloudprober_WatchProbeResults_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchProbeResultsRequest, WatchProbeResultsResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Cloudprober_WatchProbeResultsClient = grpc.ServerStreamingClient[WatchProbeResultsResponse]
+
+func (c *cloudproberClient) GetFailedResponses(ctx context.Context, in *GetFailedResponsesRequest, opts ...grpc.CallOption) (*GetFailedResponsesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetFailedResponsesResponse)
+	err := c.cc.Invoke(ctx, Cloudprober_GetFailedResponses_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CloudproberServer is the server API for Cloudprober service.
 // All implementations must embed UnimplementedCloudproberServer
 // for forward compatibility.
@@ -124,6 +208,16 @@ type CloudproberServer interface {
 	AddProbe(context.Context, *AddProbeRequest) (*AddProbeResponse, error)
 	// RemoveProbe stops the probe and removes it from the in-memory database.
 	RemoveProbe(context.Context, *RemoveProbeRequest) (*RemoveProbeResponse, error)
+	// UpdateProbe replaces an existing probe's definition in place. Probe is
+	// restarted with the new definition, but it keeps its name and status
+	// history. A paused probe stays paused after the update. If there is an
+	// error in the new definition, the existing probe is left untouched.
+	UpdateProbe(context.Context, *UpdateProbeRequest) (*UpdateProbeResponse, error)
+	// PauseProbe stops scheduling new runs of the probe, without removing it.
+	// Paused probes are reported as such by ListProbes.
+	PauseProbe(context.Context, *PauseProbeRequest) (*PauseProbeResponse, error)
+	// ResumeProbe resumes a paused probe.
+	ResumeProbe(context.Context, *ResumeProbeRequest) (*ResumeProbeResponse, error)
 	// EXPERIMENTAL. It's still in development. Implementation subject to change.
 	// RunProbe runs all or subset of probes this instance is configured with.
 	RunProbe(context.Context, *RunProbeRequest) (*RunProbeResponse, error)
@@ -133,6 +227,16 @@ type CloudproberServer interface {
 	// GetProbeStatus returns the ongoing probe status data, including
 	// total/success counters per probe/target.
 	GetProbeStatus(context.Context, *GetProbeStatusRequest) (*GetProbeStatusResponse, error)
+	// WatchProbeResults streams probe results (EventMetrics) as they are
+	// generated. Results can be filtered by probe name, target and metric name.
+	// Each subscriber gets a bounded buffer; if a subscriber can't keep up,
+	// results are dropped and the number of dropped results is reported in the
+	// next message.
+	WatchProbeResults(*WatchProbeResultsRequest, grpc.ServerStreamingServer[WatchProbeResultsResponse]) error
+	// GetFailedResponses returns the recent failed responses kept by the probes
+	// that are configured to keep them (see HTTP probe's keep_failed_responses).
+	// Responses are returned newest first for each probe and target.
+	GetFailedResponses(context.Context, *GetFailedResponsesRequest) (*GetFailedResponsesResponse, error)
 	mustEmbedUnimplementedCloudproberServer()
 }
 
@@ -149,6 +253,15 @@ func (UnimplementedCloudproberServer) AddProbe(context.Context, *AddProbeRequest
 func (UnimplementedCloudproberServer) RemoveProbe(context.Context, *RemoveProbeRequest) (*RemoveProbeResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method RemoveProbe not implemented")
 }
+func (UnimplementedCloudproberServer) UpdateProbe(context.Context, *UpdateProbeRequest) (*UpdateProbeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateProbe not implemented")
+}
+func (UnimplementedCloudproberServer) PauseProbe(context.Context, *PauseProbeRequest) (*PauseProbeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method PauseProbe not implemented")
+}
+func (UnimplementedCloudproberServer) ResumeProbe(context.Context, *ResumeProbeRequest) (*ResumeProbeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ResumeProbe not implemented")
+}
 func (UnimplementedCloudproberServer) RunProbe(context.Context, *RunProbeRequest) (*RunProbeResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method RunProbe not implemented")
 }
@@ -161,6 +274,12 @@ func (UnimplementedCloudproberServer) SaveProbesConfig(context.Context, *SavePro
 func (UnimplementedCloudproberServer) GetProbeStatus(context.Context, *GetProbeStatusRequest) (*GetProbeStatusResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetProbeStatus not implemented")
 }
+func (UnimplementedCloudproberServer) WatchProbeResults(*WatchProbeResultsRequest, grpc.ServerStreamingServer[WatchProbeResultsResponse]) error {
+	return status.Error(codes.Unimplemented, "method WatchProbeResults not implemented")
+}
+func (UnimplementedCloudproberServer) GetFailedResponses(context.Context, *GetFailedResponsesRequest) (*GetFailedResponsesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetFailedResponses not implemented")
+}
 func (UnimplementedCloudproberServer) mustEmbedUnimplementedCloudproberServer() {}
 func (UnimplementedCloudproberServer) testEmbeddedByValue()                     {}
 
@@ -218,6 +337,60 @@ func _Cloudprober_RemoveProbe_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Cloudprober_UpdateProbe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateProbeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudproberServer).UpdateProbe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cloudprober_UpdateProbe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudproberServer).UpdateProbe(ctx, req.(*UpdateProbeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cloudprober_PauseProbe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseProbeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudproberServer).PauseProbe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cloudprober_PauseProbe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudproberServer).PauseProbe(ctx, req.(*PauseProbeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cloudprober_ResumeProbe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeProbeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudproberServer).ResumeProbe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cloudprober_ResumeProbe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudproberServer).ResumeProbe(ctx, req.(*ResumeProbeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Cloudprober_RunProbe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RunProbeRequest)
 	if err := dec(in); err != nil {
@@ -290,6 +463,35 @@ func _Cloudprober_GetProbeStatus_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Cloudprober_WatchProbeResults_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchProbeResultsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CloudproberServer).WatchProbeResults(m, &grpc.GenericServerStream[WatchProbeResultsRequest, WatchProbeResultsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Cloudprober_WatchProbeResultsServer = grpc.ServerStreamingServer[WatchProbeResultsResponse]
+
+func _Cloudprober_GetFailedResponses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFailedResponsesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudproberServer).GetFailedResponses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cloudprober_GetFailedResponses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudproberServer).GetFailedResponses(ctx, req.(*GetFailedResponsesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Cloudprober_ServiceDesc is the grpc.ServiceDesc for Cloudprober service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -305,6 +507,18 @@ var Cloudprober_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RemoveProbe",
 			Handler:    _Cloudprober_RemoveProbe_Handler,
 		},
+		{
+			MethodName: "UpdateProbe",
+			Handler:    _Cloudprober_UpdateProbe_Handler,
+		},
+		{
+			MethodName: "PauseProbe",
+			Handler:    _Cloudprober_PauseProbe_Handler,
+		},
+		{
+			MethodName: "ResumeProbe",
+			Handler:    _Cloudprober_ResumeProbe_Handler,
+		},
 		{
 			MethodName: "RunProbe",
 			Handler:    _Cloudprober_RunProbe_Handler,
@@ -321,7 +535,17 @@ var Cloudprober_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetProbeStatus",
 			Handler:    _Cloudprober_GetProbeStatus_Handler,
 		},
+		{
+			MethodName: "GetFailedResponses",
+			Handler:    _Cloudprober_GetFailedResponses_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchProbeResults",
+			Handler:       _Cloudprober_WatchProbeResults_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "github.com/cloudprober/cloudprober/prober/proto/service.proto",
 }
@@ -0,0 +1,339 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prober
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	configpb "github.com/cloudprober/cloudprober/config/proto"
+	"github.com/cloudprober/cloudprober/internal/servers"
+	serverspb "github.com/cloudprober/cloudprober/internal/servers/proto"
+	"github.com/cloudprober/cloudprober/probes"
+	probes_configpb "github.com/cloudprober/cloudprober/probes/proto"
+	"github.com/cloudprober/cloudprober/surfacers"
+	"google.golang.org/protobuf/proto"
+)
+
+// serverStopTimeout is how long we wait for a server to stop on config
+// reload, before starting its replacement.
+const serverStopTimeout = 5 * time.Second
+
+// serverState keeps track of what we need to stop a server.
+type serverState struct {
+	initCancel  context.CancelFunc
+	startCancel context.CancelFunc
+	done        chan struct{}
+}
+
+// initServer initializes a server with its own context, so that it can be
+// stopped independently of the other servers.
+func (pr *Prober) initServer(ctx context.Context, serverDef *serverspb.ServerDef) (*servers.ServerInfo, error) {
+	initCtx, cancel := context.WithCancel(ctx)
+	sis, err := servers.Init(initCtx, []*serverspb.ServerDef{serverDef})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	pr.serverStates[sis[0]] = &serverState{initCancel: cancel}
+	return sis[0], nil
+}
+
+// startServer starts the given server in its own goroutine.
+func (pr *Prober) startServer(si *servers.ServerInfo) {
+	ss := pr.serverStates[si]
+	ctx, cancel := context.WithCancel(pr.startCtx)
+	ss.startCancel, ss.done = cancel, make(chan struct{})
+
+	go func() {
+		defer close(ss.done)
+		si.Start(ctx, pr.dataChan)
+	}()
+}
+
+// stopServer stops the given server and waits for it to exit, so that its
+// resources, e.g. listening port, can be reused by its replacement.
+func (pr *Prober) stopServer(si *servers.ServerInfo) {
+	ss := pr.serverStates[si]
+	delete(pr.serverStates, si)
+	if ss == nil {
+		return
+	}
+
+	ss.initCancel()
+	if ss.startCancel == nil {
+		return
+	}
+	ss.startCancel()
+
+	select {
+	case <-ss.done:
+	case <-time.After(serverStopTimeout):
+		pr.l.Warningf("Timed out waiting for the %s server to stop", si.Type)
+	}
+}
+
+// nonReloadableConfig returns the part of the config that can't be changed
+// without a restart.
+func nonReloadableConfig(cfg *configpb.ProberConfig) *configpb.ProberConfig {
+	cfg = proto.Clone(cfg).(*configpb.ProberConfig)
+	cfg.Probe, cfg.Surfacer, cfg.Server = nil, nil, nil
+	return cfg
+}
+
+func equalDefs[T proto.Message](a, b []T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !proto.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// probesUpdate holds the probe changes to be applied on config reload.
+type probesUpdate struct {
+	toRemove []string
+	// New and modified probes. A nil ProbeInfo means that the probe is not
+	// supposed to run on this host; we still need to remove its old
+	// instance, if any.
+	newProbes map[string]*probes.ProbeInfo
+}
+
+// prepareProbesUpdate creates new and modified probes for the new config. We
+// only touch probes that were defined in the old or new config; probes added
+// through the gRPC API are left alone, unless the new config defines a probe
+// with the same name. Running probes are not changed until the update is
+// applied using applyProbesUpdate.
+func (pr *Prober) prepareProbesUpdate(cfg *configpb.ProberConfig) (*probesUpdate, error) {
+	newDefs := make(map[string]*probes_configpb.ProbeDef)
+	for _, p := range cfg.GetProbe() {
+		if newDefs[p.GetName()] != nil {
+			return nil, fmt.Errorf("probe %s is defined more than once", p.GetName())
+		}
+		newDefs[p.GetName()] = p
+	}
+
+	pu := &probesUpdate{newProbes: make(map[string]*probes.ProbeInfo)}
+
+	pr.mu.RLock()
+	for _, p := range pr.c.GetProbe() {
+		if newDefs[p.GetName()] == nil && pr.Probes[p.GetName()] != nil {
+			pu.toRemove = append(pu.toRemove, p.GetName())
+		}
+	}
+
+	var toCreate []*probes_configpb.ProbeDef
+	for _, p := range cfg.GetProbe() {
+		if pi := pr.Probes[p.GetName()]; pi == nil || !proto.Equal(pi.ProbeDef, p) {
+			toCreate = append(toCreate, p)
+		}
+	}
+	pr.mu.RUnlock()
+
+	for _, p := range toCreate {
+		probeInfo, err := pr.createProbe(p)
+		if err != nil {
+			return nil, fmt.Errorf("error while creating probe '%s': %v", p.GetName(), err)
+		}
+		pu.newProbes[p.GetName()] = probeInfo
+	}
+	return pu, nil
+}
+
+// applyProbesUpdate stops the removed and modified probes, and starts the new
+// and modified probes.
+func (pr *Prober) applyProbesUpdate(pu *probesUpdate) {
+	pr.mu.Lock()
+	for _, name := range pu.toRemove {
+		pr.l.Infof("Config reload: removing probe %s", name)
+		pr.stopProbe(name)
+		delete(pr.Probes, name)
+	}
+
+	var toStart []string
+	for name, probeInfo := range pu.newProbes {
+		if oldProbe := pr.Probes[name]; oldProbe != nil {
+			pr.l.Infof("Config reload: stopping probe %s", name)
+			pr.stopProbe(name)
+			delete(pr.Probes, name)
+			// Paused probes stay paused across reloads.
+			if probeInfo != nil {
+				probeInfo.Options.SetPaused(oldProbe.Paused())
+			}
+		}
+		if probeInfo != nil {
+			pr.Probes[name] = probeInfo
+			toStart = append(toStart, name)
+		}
+	}
+	pr.mu.Unlock()
+
+	for _, name := range toStart {
+		pr.l.Infof("Config reload: starting probe %s", name)
+		pr.startProbe(name)
+	}
+}
+
+// reloadServers updates servers to match the new config. Servers don't have
+// names, so we match them by their definitions: servers with unchanged
+// definitions are kept running, rest are stopped and new servers are
+// started in their place.
+//
+// Servers acquire resources like listening ports during initialization, so
+// old servers have to be stopped before their replacements can be created.
+// If a new server fails to initialize, the stopped servers are re-created
+// from their definitions, so that we are back to where we started.
+func (pr *Prober) reloadServers(cfg *configpb.ProberConfig) error {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	if equalDefs(pr.c.GetServer(), cfg.GetServer()) {
+		return nil
+	}
+
+	kept := make(map[*servers.ServerInfo]bool)
+	var toCreate []*serverspb.ServerDef
+	var result []*servers.ServerInfo
+	for _, serverDef := range cfg.GetServer() {
+		var found *servers.ServerInfo
+		for _, si := range pr.Servers {
+			if !kept[si] && proto.Equal(si.ServerDef, serverDef) {
+				found = si
+				break
+			}
+		}
+		if found == nil {
+			toCreate = append(toCreate, serverDef)
+			continue
+		}
+		kept[found] = true
+		result = append(result, found)
+	}
+
+	var stopped []*serverspb.ServerDef
+	for _, si := range pr.Servers {
+		if !kept[si] {
+			pr.l.Infof("Config reload: stopping %s server", si.Type)
+			pr.stopServer(si)
+			stopped = append(stopped, si.ServerDef)
+		}
+	}
+
+	var created []*servers.ServerInfo
+	for _, serverDef := range toCreate {
+		si, err := pr.initServer(pr.startCtx, serverDef)
+		if err != nil {
+			for _, si := range created {
+				pr.stopServer(si)
+			}
+			pr.restoreServers(kept, stopped)
+			return fmt.Errorf("error while initializing servers: %v", err)
+		}
+		created = append(created, si)
+	}
+
+	for _, si := range created {
+		pr.l.Infof("Config reload: starting %s server", si.Type)
+		pr.startServer(si)
+	}
+	pr.Servers = append(result, created...)
+	return nil
+}
+
+// restoreServers re-creates the servers that were stopped during a failed
+// reload. It must be called with pr.mu held.
+func (pr *Prober) restoreServers(kept map[*servers.ServerInfo]bool, stopped []*serverspb.ServerDef) {
+	var restored []*servers.ServerInfo
+	for _, si := range pr.Servers {
+		if kept[si] {
+			restored = append(restored, si)
+		}
+	}
+	for _, serverDef := range stopped {
+		si, err := pr.initServer(pr.startCtx, serverDef)
+		if err != nil {
+			pr.l.Errorf("Config reload: error restoring %s server: %v", serverDef.GetType(), err)
+			continue
+		}
+		pr.l.Infof("Config reload: restarting %s server", si.Type)
+		pr.startServer(si)
+		restored = append(restored, si)
+	}
+	pr.Servers = restored
+}
+
+// Reload updates the running prober to match the new config. Only probes,
+// surfacers and servers that have changed are restarted; everything else
+// keeps running undisturbed. Changes to other parts of the config, e.g.
+// shared targets or RDS server, require a restart and are ignored with a
+// warning.
+//
+// Reload is all-or-nothing: new and modified surfacers and probes are created
+// before anything is changed, and if any of them, or a new server, fails to
+// initialize, the prober is left running with the old config.
+//
+// Reload must only be called after Start.
+func (pr *Prober) Reload(cfg *configpb.ProberConfig) error {
+	if pr.startCtx == nil {
+		return fmt.Errorf("prober not started")
+	}
+
+	if !proto.Equal(nonReloadableConfig(pr.c), nonReloadableConfig(cfg)) {
+		pr.l.Warning("Config reload: only changes to probes, surfacers and servers are applied. Restart cloudprober to apply other changes.")
+	}
+
+	pr.mu.RLock()
+	oldSurfacers := pr.Surfacers
+	surfacersChanged := !equalDefs(pr.c.GetSurfacer(), cfg.GetSurfacer())
+	pr.mu.RUnlock()
+
+	newSurfacers := oldSurfacers
+	if surfacersChanged {
+		var err error
+		newSurfacers, err = surfacers.Update(pr.startCtx, oldSurfacers, cfg.GetSurfacer())
+		if err != nil {
+			return fmt.Errorf("error while initializing surfacers: %v", err)
+		}
+	}
+
+	pu, err := pr.prepareProbesUpdate(cfg)
+	if err == nil {
+		err = pr.reloadServers(cfg)
+	}
+	if err != nil {
+		surfacers.StopUnused(newSurfacers, oldSurfacers)
+		return err
+	}
+
+	// We switch to the new surfacers before updating probes, so that metrics
+	// from the new probes are surfaced using the new surfacers.
+	pr.mu.Lock()
+	pr.Surfacers = newSurfacers
+	pr.setProbeStatusSurfacer()
+	pr.mu.Unlock()
+	surfacers.StopUnused(oldSurfacers, newSurfacers)
+
+	pr.applyProbesUpdate(pu)
+
+	pr.mu.Lock()
+	pr.c = cfg
+	pr.mu.Unlock()
+
+	return nil
+}
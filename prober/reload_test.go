@@ -0,0 +1,263 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prober
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	configpb "github.com/cloudprober/cloudprober/config/proto"
+	httpserverpb "github.com/cloudprober/cloudprober/internal/servers/http/proto"
+	serverspb "github.com/cloudprober/cloudprober/internal/servers/proto"
+	udpserverpb "github.com/cloudprober/cloudprober/internal/servers/udp/proto"
+	promconfigpb "github.com/cloudprober/cloudprober/internal/surfacers/prometheus/proto"
+	surfacerpb "github.com/cloudprober/cloudprober/internal/surfacers/proto"
+	"github.com/cloudprober/cloudprober/metrics"
+	probes_configpb "github.com/cloudprober/cloudprober/probes/proto"
+	"github.com/cloudprober/cloudprober/state"
+	targetspb "github.com/cloudprober/cloudprober/targets/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestReloadProbes(t *testing.T) {
+	pr, cancel := testProber(t, &configpb.ProberConfig{
+		Probe: []*probes_configpb.ProbeDef{
+			testProbeDef("p1"),
+			testProbeDef("p2"),
+			testProbeDef("p3"),
+		},
+		DisableJitter: proto.Bool(true),
+	})
+	defer cancel()
+
+	oldProbes := make(map[string]*testProbe)
+	for name, p := range pr.Probes {
+		oldProbes[name] = p.Probe.(*testProbe)
+		verifyProbeRunningStatus(t, oldProbes[name], true)
+	}
+
+	// Remove p1, modify p2, keep p3 as is, and add p4.
+	p2 := testProbeDef("p2")
+	p2.Interval = proto.String("10s")
+	newCfg := &configpb.ProberConfig{
+		Probe: []*probes_configpb.ProbeDef{
+			p2,
+			testProbeDef("p3"),
+			testProbeDef("p4"),
+		},
+		DisableJitter: proto.Bool(true),
+	}
+	assert.NoError(t, pr.Reload(newCfg))
+
+	var names []string
+	for name := range pr.Probes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{"p2", "p3", "p4"}, names)
+
+	// p1 and old p2 should be stopped.
+	verifyProbeRunningStatus(t, oldProbes["p1"], false)
+	verifyProbeRunningStatus(t, oldProbes["p2"], false)
+
+	// p3 should not have been touched.
+	assert.Same(t, oldProbes["p3"], pr.Probes["p3"].Probe.(*testProbe))
+
+	// New p2 and p4 should be running.
+	assert.NotSame(t, oldProbes["p2"], pr.Probes["p2"].Probe.(*testProbe))
+	assert.Equal(t, "10s", pr.Probes["p2"].ProbeDef.GetInterval())
+	verifyProbeRunningStatus(t, pr.Probes["p2"].Probe.(*testProbe), true)
+	verifyProbeRunningStatus(t, pr.Probes["p4"].Probe.(*testProbe), true)
+}
+
+func TestReloadProbesError(t *testing.T) {
+	pr, cancel := testProber(t, &configpb.ProberConfig{
+		Probe:         []*probes_configpb.ProbeDef{testProbeDef("p1")},
+		DisableJitter: proto.Bool(true),
+	})
+	defer cancel()
+
+	p1 := pr.Probes["p1"].Probe.(*testProbe)
+	verifyProbeRunningStatus(t, p1, true)
+
+	// An extension probe without the extension fails to initialize.
+	badProbe := &probes_configpb.ProbeDef{
+		Name: proto.String("p2"),
+		Type: probes_configpb.ProbeDef_EXTENSION.Enum(),
+		Targets: &targetspb.TargetsDef{
+			Type: &targetspb.TargetsDef_DummyTargets{},
+		},
+	}
+
+	err := pr.Reload(&configpb.ProberConfig{
+		Probe:         []*probes_configpb.ProbeDef{badProbe},
+		DisableJitter: proto.Bool(true),
+	})
+	assert.Error(t, err)
+
+	// Nothing should have changed.
+	assert.Len(t, pr.Probes, 1)
+	assert.Same(t, p1, pr.Probes["p1"].Probe.(*testProbe))
+	assert.Len(t, pr.c.GetProbe(), 1)
+}
+
+func TestReloadServers(t *testing.T) {
+	udpServer := func(port int32) *serverspb.ServerDef {
+		return &serverspb.ServerDef{
+			Type: serverspb.ServerDef_UDP.Enum(),
+			Server: &serverspb.ServerDef_UdpServer{
+				UdpServer: &udpserverpb.ServerConf{
+					Port: proto.Int32(port),
+					Type: udpserverpb.ServerConf_ECHO.Enum(),
+				},
+			},
+		}
+	}
+
+	pr, cancel := testProber(t, &configpb.ProberConfig{
+		Server: []*serverspb.ServerDef{udpServer(0)},
+	})
+	defer cancel()
+
+	assert.Len(t, pr.Servers, 1)
+	oldServer := pr.Servers[0]
+
+	// Same config, server should be kept.
+	assert.NoError(t, pr.Reload(&configpb.ProberConfig{
+		Server: []*serverspb.ServerDef{udpServer(0)},
+	}))
+	assert.Equal(t, oldServer, pr.Servers[0])
+
+	// Add another server.
+	assert.NoError(t, pr.Reload(&configpb.ProberConfig{
+		Server: []*serverspb.ServerDef{udpServer(0), udpServer(0)},
+	}))
+	assert.Len(t, pr.Servers, 2)
+	assert.Equal(t, oldServer, pr.Servers[0])
+	assert.Len(t, pr.serverStates, 2)
+
+	// A server that fails to initialize: stopped server should be restored,
+	// and probes should be left untouched.
+	badServer := &serverspb.ServerDef{
+		Type: serverspb.ServerDef_HTTP.Enum(),
+		Server: &serverspb.ServerDef_HttpServer{
+			HttpServer: &httpserverpb.ServerConf{
+				Port:     proto.Int32(0),
+				Protocol: httpserverpb.ServerConf_HTTPS.Enum(),
+			},
+		},
+	}
+	assert.Error(t, pr.Reload(&configpb.ProberConfig{
+		Probe:  []*probes_configpb.ProbeDef{testProbeDef("p1")},
+		Server: []*serverspb.ServerDef{udpServer(0), badServer},
+	}))
+	assert.Len(t, pr.Servers, 2)
+	assert.Equal(t, oldServer, pr.Servers[0])
+	assert.Len(t, pr.serverStates, 2)
+	assert.Empty(t, pr.Probes)
+	assert.Len(t, pr.c.GetServer(), 2)
+
+	// Remove all servers.
+	assert.NoError(t, pr.Reload(&configpb.ProberConfig{}))
+	assert.Len(t, pr.Servers, 0)
+	assert.Len(t, pr.serverStates, 0)
+}
+
+func TestReloadSurfacers(t *testing.T) {
+	// Unlike testProber, we keep the HTTP ServeMux around for the reloads.
+	mux := http.NewServeMux()
+	state.SetDefaultHTTPServeMux(mux)
+	defer state.SetDefaultHTTPServeMux(nil)
+
+	promSurfacer := func(prefix string) *surfacerpb.SurfacerDef {
+		return &surfacerpb.SurfacerDef{
+			Type: surfacerpb.Type_PROMETHEUS.Enum(),
+			Surfacer: &surfacerpb.SurfacerDef_PrometheusSurfacer{
+				PrometheusSurfacer: &promconfigpb.SurfacerConf{MetricsPrefix: proto.String(prefix)},
+			},
+		}
+	}
+	getMetrics := func() (int, string) {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+		return w.Code, w.Body.String()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pr, err := Init(ctx, &configpb.ProberConfig{
+		Probe:         []*probes_configpb.ProbeDef{testProbeDef("p1")},
+		Surfacer:      []*surfacerpb.SurfacerDef{promSurfacer("old_")},
+		DisableJitter: proto.Bool(true),
+	}, nil)
+	require.NoError(t, err)
+	pr.Start(ctx)
+
+	writeAndCheck := func(wantPrefix string) {
+		t.Helper()
+		pr.dataChan <- metrics.NewEventMetrics(time.Now()).AddMetric("total", metrics.NewInt(1)).AddLabel("probe", "p1")
+		assert.Eventually(t, func() bool {
+			code, body := getMetrics()
+			return code == http.StatusOK && strings.Contains(body, wantPrefix+"total{")
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+	writeAndCheck("old_")
+
+	// Invalid probe in the new config: nothing should change, including
+	// surfacers.
+	badProbe := &probes_configpb.ProbeDef{
+		Name: proto.String("p2"),
+		Type: probes_configpb.ProbeDef_EXTENSION.Enum(),
+		Targets: &targetspb.TargetsDef{
+			Type: &targetspb.TargetsDef_DummyTargets{},
+		},
+	}
+	oldSurfacers := pr.Surfacers
+	err = pr.Reload(&configpb.ProberConfig{
+		Probe:         []*probes_configpb.ProbeDef{testProbeDef("p1"), badProbe},
+		Surfacer:      []*surfacerpb.SurfacerDef{promSurfacer("new_")},
+		DisableJitter: proto.Bool(true),
+	})
+	assert.Error(t, err)
+	assert.Equal(t, oldSurfacers, pr.Surfacers)
+	assert.Equal(t, "old_", pr.c.GetSurfacer()[0].GetPrometheusSurfacer().GetMetricsPrefix())
+	writeAndCheck("old_")
+
+	// Modified prometheus surfacer.
+	require.NoError(t, pr.Reload(&configpb.ProberConfig{
+		Probe:         []*probes_configpb.ProbeDef{testProbeDef("p1")},
+		Surfacer:      []*surfacerpb.SurfacerDef{promSurfacer("new_")},
+		DisableJitter: proto.Bool(true),
+	}))
+	assert.NotEqual(t, oldSurfacers[0], pr.Surfacers[0])
+	writeAndCheck("new_")
+
+	// Removed prometheus surfacer: /metrics should go away instead of
+	// hanging.
+	require.NoError(t, pr.Reload(&configpb.ProberConfig{
+		Probe:         []*probes_configpb.ProbeDef{testProbeDef("p1")},
+		Surfacer:      []*surfacerpb.SurfacerDef{{Type: surfacerpb.Type_FILE.Enum()}},
+		DisableJitter: proto.Bool(true),
+	}))
+	code, _ := getMetrics()
+	assert.Equal(t, http.StatusNotFound, code)
+}
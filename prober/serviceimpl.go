@@ -19,12 +19,14 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"sort"
 	"time"
 
 	configpb "github.com/cloudprober/cloudprober/config/proto"
 	"github.com/cloudprober/cloudprober/metrics/singlerun"
 	pb "github.com/cloudprober/cloudprober/prober/proto"
+	"github.com/cloudprober/cloudprober/probes/common/failedresponses"
 	probes_configpb "github.com/cloudprober/cloudprober/probes/proto"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -95,7 +97,7 @@ func (pr *Prober) AddProbe(ctx context.Context, req *pb.AddProbeRequest) (*pb.Ad
 }
 
 func (pr *Prober) RunProbe(ctx context.Context, req *pb.RunProbeRequest) (*pb.RunProbeResponse, error) {
-	results, err := pr.Run(ctx, req.GetProbeName())
+	results, err := pr.RunWithTargets(ctx, req.GetProbeName(), req.GetTarget())
 	if err != nil {
 		return nil, err
 	}
@@ -115,7 +117,7 @@ func (pr *Prober) removeProbe(name string) error {
 		return fmt.Errorf("probe %s not found", name)
 	}
 
-	pr.probeCancelFunc[name]()
+	pr.stopProbe(name)
 	delete(pr.Probes, name)
 	return nil
 }
@@ -141,6 +143,112 @@ func (pr *Prober) RemoveProbe(ctx context.Context, req *pb.RemoveProbeRequest) (
 	return &pb.RemoveProbeResponse{}, nil
 }
 
+// updateProbe replaces an existing probe with a new probe built from the
+// given definition. New probe is created before the existing probe is
+// stopped, so that an error in the new definition leaves the existing probe
+// untouched.
+func (pr *Prober) updateProbe(p *probes_configpb.ProbeDef) error {
+	name := p.GetName()
+
+	pr.mu.RLock()
+	exists := pr.Probes[name] != nil
+	pr.mu.RUnlock()
+	if !exists {
+		return status.Errorf(codes.NotFound, "probe %s not found", name)
+	}
+
+	probeInfo, err := pr.createProbe(p)
+	if err != nil {
+		return err
+	}
+	if probeInfo == nil {
+		return status.Errorf(codes.FailedPrecondition, "updated probe %s is not configured to run on this host", name)
+	}
+
+	pr.mu.Lock()
+	oldProbe := pr.Probes[name]
+	if oldProbe == nil {
+		pr.mu.Unlock()
+		return status.Errorf(codes.NotFound, "probe %s not found", name)
+	}
+	probeInfo.Options.SetPaused(oldProbe.Paused())
+	pr.stopProbe(name)
+	pr.Probes[name] = probeInfo
+	pr.mu.Unlock()
+
+	pr.startProbe(name)
+	return nil
+}
+
+// UpdateProbe gRPC method replaces an existing probe's definition in place.
+// Since probe's name doesn't change, its status history is preserved.
+func (pr *Prober) UpdateProbe(ctx context.Context, req *pb.UpdateProbeRequest) (*pb.UpdateProbeResponse, error) {
+	p := req.GetProbeConfig()
+	if p == nil {
+		return &pb.UpdateProbeResponse{}, status.Errorf(codes.InvalidArgument, "probe config cannot be nil")
+	}
+	pr.l.Infof("UpdateProbe called for: %s", p.GetName())
+
+	if pr.startCtx == nil {
+		return &pb.UpdateProbeResponse{}, status.Errorf(codes.FailedPrecondition, "prober not started")
+	}
+
+	if err := pr.updateProbe(p); err != nil {
+		return &pb.UpdateProbeResponse{}, err
+	}
+
+	if *probesConfigSavePath != "" {
+		pr.saveProbesConfigToFile(*probesConfigSavePath)
+	}
+
+	return &pb.UpdateProbeResponse{}, nil
+}
+
+// setProbePaused pauses or resumes the probe with the given name.
+func (pr *Prober) setProbePaused(name string, paused bool) error {
+	if name == "" {
+		return status.Errorf(codes.InvalidArgument, "probe name cannot be empty")
+	}
+
+	pr.mu.RLock()
+	defer pr.mu.RUnlock()
+
+	p := pr.Probes[name]
+	if p == nil {
+		return status.Errorf(codes.NotFound, "probe %s not found", name)
+	}
+
+	// These probe types don't run on a schedule, and hence can't be paused.
+	switch p.ProbeDef.GetType() {
+	case probes_configpb.ProbeDef_SYSTEM, probes_configpb.ProbeDef_UDP_LISTENER:
+		return status.Errorf(codes.FailedPrecondition, "%s probes can't be paused", p.ProbeDef.GetType())
+	}
+
+	p.Options.SetPaused(paused)
+	return nil
+}
+
+// PauseProbe gRPC method stops scheduling new runs of the probe. Probe is not
+// removed and can be resumed using ResumeProbe.
+func (pr *Prober) PauseProbe(ctx context.Context, req *pb.PauseProbeRequest) (*pb.PauseProbeResponse, error) {
+	pr.l.Infof("PauseProbe called with: %s", req.GetProbeName())
+
+	if err := pr.setProbePaused(req.GetProbeName(), true); err != nil {
+		return &pb.PauseProbeResponse{}, err
+	}
+	return &pb.PauseProbeResponse{}, nil
+}
+
+// ResumeProbe gRPC method resumes a paused probe.
+func (pr *Prober) ResumeProbe(ctx context.Context, req *pb.ResumeProbeRequest) (*pb.ResumeProbeResponse, error) {
+	pr.l.Infof("ResumeProbe called with: %s", req.GetProbeName())
+
+	if err := pr.setProbePaused(req.GetProbeName(), false); err != nil {
+		return &pb.ResumeProbeResponse{}, err
+	}
+	return &pb.ResumeProbeResponse{}, nil
+}
+
 // GetProbeStatus returns the ongoing probe status data.
 func (pr *Prober) GetProbeStatus(ctx context.Context, req *pb.GetProbeStatusRequest) (*pb.GetProbeStatusResponse, error) {
 	if pr.probeStatusSurfacer == nil {
@@ -181,6 +289,61 @@ func (pr *Prober) GetProbeStatus(ctx context.Context, req *pb.GetProbeStatusRequ
 	return resp, nil
 }
 
+// GetFailedResponses gRPC method returns the recent failed responses kept by
+// the probes.
+func (pr *Prober) GetFailedResponses(ctx context.Context, req *pb.GetFailedResponsesRequest) (*pb.GetFailedResponsesResponse, error) {
+	resp := &pb.GetFailedResponsesResponse{}
+
+	for _, probeName := range failedresponses.Probes() {
+		if len(req.GetProbeName()) != 0 && !slices.Contains(req.GetProbeName(), probeName) {
+			continue
+		}
+		b := failedresponses.Get(probeName)
+		for _, target := range b.Targets() {
+			if len(req.GetTarget()) != 0 && !slices.Contains(req.GetTarget(), target) {
+				continue
+			}
+			for _, r := range b.List(target) {
+				resp.FailedResponse = append(resp.FailedResponse, failedResponseProto(probeName, target, r))
+			}
+		}
+	}
+	return resp, nil
+}
+
+func failedResponseProto(probeName, target string, r *failedresponses.Response) *pb.FailedResponse {
+	fr := &pb.FailedResponse{
+		ProbeName:               proto.String(probeName),
+		Target:                  proto.String(target),
+		TimestampMsec:           proto.Int64(r.Time.UnixMilli()),
+		Url:                     proto.String(r.URL),
+		Body:                    r.Body,
+		BodyTruncated:           proto.Bool(r.BodyTruncated),
+		Error:                   proto.String(r.Error),
+		LatencyUsec:             proto.Int64(r.Latency.Microseconds()),
+		DnsLatencyUsec:          proto.Int64(r.DNSLatency.Microseconds()),
+		ConnectLatencyUsec:      proto.Int64(r.ConnectLatency.Microseconds()),
+		TlsHandshakeLatencyUsec: proto.Int64(r.TLSHandshakeLatency.Microseconds()),
+		ReqWriteLatencyUsec:     proto.Int64(r.ReqWriteLatency.Microseconds()),
+		FirstByteLatencyUsec:    proto.Int64(r.FirstByteLatency.Microseconds()),
+	}
+	if r.StatusCode != 0 {
+		fr.StatusCode = proto.Int32(int32(r.StatusCode))
+	}
+
+	keys := make([]string, 0, len(r.Header))
+	for k := range r.Header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, v := range r.Header[k] {
+			fr.Header = append(fr.Header, &pb.Label{Key: proto.String(k), Value: proto.String(v)})
+		}
+	}
+	return fr
+}
+
 // ListProbes gRPC method returns the list of probes from the in-memory database.
 func (pr *Prober) ListProbes(ctx context.Context, req *pb.ListProbesRequest) (*pb.ListProbesResponse, error) {
 	pr.l.Info("ListProbes called")
@@ -193,6 +356,7 @@ func (pr *Prober) ListProbes(ctx context.Context, req *pb.ListProbesRequest) (*p
 		resp.Probe = append(resp.Probe, &pb.Probe{
 			Name:   proto.String(name),
 			Config: proto.Clone(p.ProbeDef).(*probes_configpb.ProbeDef),
+			Paused: proto.Bool(p.Paused()),
 		})
 	}
 
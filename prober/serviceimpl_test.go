@@ -16,6 +16,7 @@ package prober
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"os"
 	"reflect"
@@ -28,6 +29,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	pb "github.com/cloudprober/cloudprober/prober/proto"
+	"github.com/cloudprober/cloudprober/probes/common/failedresponses"
 	probes_configpb "github.com/cloudprober/cloudprober/probes/proto"
 	"github.com/cloudprober/cloudprober/state"
 	targetspb "github.com/cloudprober/cloudprober/targets/proto"
@@ -185,6 +187,86 @@ func TestRemoveProbes(t *testing.T) {
 	verifyProbeRunningStatus(t, p, false)
 }
 
+func TestUpdateProbe(t *testing.T) {
+	pr, cancel := testProber(t, &configpb.ProberConfig{})
+	defer cancel()
+
+	testProbeName := "test-probe-for-update"
+
+	// Update a non-existent probe, should result in error.
+	_, err := pr.UpdateProbe(context.Background(), &pb.UpdateProbeRequest{ProbeConfig: testProbeDef(testProbeName)})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = pr.AddProbe(context.Background(), &pb.AddProbeRequest{ProbeConfig: testProbeDef(testProbeName)})
+	assert.NoError(t, err)
+	oldProbe := pr.Probes[testProbeName].Probe.(*testProbe)
+	verifyProbeRunningStatus(t, oldProbe, true)
+
+	_, err = pr.PauseProbe(context.Background(), &pb.PauseProbeRequest{ProbeName: &testProbeName})
+	assert.NoError(t, err)
+
+	// Invalid probe definition should leave the existing probe untouched.
+	badDef := testProbeDef(testProbeName)
+	badDef.Interval = proto.String("invalid")
+	_, err = pr.UpdateProbe(context.Background(), &pb.UpdateProbeRequest{ProbeConfig: badDef})
+	assert.Error(t, err)
+	assert.Same(t, oldProbe, pr.Probes[testProbeName].Probe.(*testProbe))
+
+	newDef := testProbeDef(testProbeName)
+	newDef.Interval = proto.String("10s")
+	_, err = pr.UpdateProbe(context.Background(), &pb.UpdateProbeRequest{ProbeConfig: newDef})
+	assert.NoError(t, err)
+
+	// Old probe should be stopped and new probe started, still paused.
+	verifyProbeRunningStatus(t, oldProbe, false)
+	newProbe := pr.Probes[testProbeName].Probe.(*testProbe)
+	assert.NotSame(t, oldProbe, newProbe)
+	verifyProbeRunningStatus(t, newProbe, true)
+	assert.Equal(t, "10s", pr.Probes[testProbeName].ProbeDef.GetInterval())
+	assert.True(t, pr.Probes[testProbeName].Paused())
+}
+
+func TestPauseResumeProbe(t *testing.T) {
+	pr, cancel := testProber(t, &configpb.ProberConfig{})
+	defer cancel()
+
+	testProbeName := "test-probe-for-pause"
+
+	_, err := pr.PauseProbe(context.Background(), &pb.PauseProbeRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = pr.PauseProbe(context.Background(), &pb.PauseProbeRequest{ProbeName: &testProbeName})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = pr.AddProbe(context.Background(), &pb.AddProbeRequest{ProbeConfig: testProbeDef(testProbeName)})
+	assert.NoError(t, err)
+	p := pr.Probes[testProbeName]
+	verifyProbeRunningStatus(t, p.Probe.(*testProbe), true)
+
+	pausedState := func() bool {
+		t.Helper()
+		resp, err := pr.ListProbes(context.Background(), &pb.ListProbesRequest{})
+		assert.NoError(t, err)
+		assert.Len(t, resp.GetProbe(), 1)
+		return resp.GetProbe()[0].GetPaused()
+	}
+
+	assert.True(t, p.Options.IsScheduled())
+	assert.False(t, pausedState())
+
+	_, err = pr.PauseProbe(context.Background(), &pb.PauseProbeRequest{ProbeName: &testProbeName})
+	assert.NoError(t, err)
+	assert.False(t, p.Options.IsScheduled())
+	assert.True(t, pausedState())
+
+	// Probe is not torn down while paused.
+	assert.Same(t, p, pr.Probes[testProbeName])
+
+	_, err = pr.ResumeProbe(context.Background(), &pb.ResumeProbeRequest{ProbeName: &testProbeName})
+	assert.NoError(t, err)
+	assert.True(t, p.Options.IsScheduled())
+	assert.False(t, pausedState())
+}
+
 func TestGetProbeStatus(t *testing.T) {
 	pr, cancel := testProber(t, &configpb.ProberConfig{})
 	defer cancel()
@@ -243,6 +325,79 @@ func TestGetProbeStatusNoSurfacer(t *testing.T) {
 	assert.Contains(t, err.Error(), "probestatus surfacer is not available")
 }
 
+func TestGetFailedResponses(t *testing.T) {
+	b1 := failedresponses.NewBuffer(2, 10)
+	b1.Add("t1", &failedresponses.Response{
+		Time:       time.UnixMilli(1000),
+		StatusCode: 500,
+		Header:     http.Header{"X-B": {"2"}, "X-A": {"1", "3"}},
+		Body:       []byte("error"),
+		Error:      "failed validations: v1",
+		Latency:    2 * time.Millisecond,
+	})
+	b1.Add("t1", &failedresponses.Response{Time: time.UnixMilli(2000), Error: "timeout"})
+	b1.Add("t2", &failedresponses.Response{Time: time.UnixMilli(3000), Error: "timeout"})
+	b2 := failedresponses.NewBuffer(2, 10)
+	b2.Add("t1", &failedresponses.Response{Time: time.UnixMilli(4000), Error: "timeout"})
+
+	failedresponses.Register("p1", b1)
+	failedresponses.Register("p2", b2)
+	defer failedresponses.Unregister("p1", b1)
+	defer failedresponses.Unregister("p2", b2)
+
+	key := func(fr *pb.FailedResponse) string {
+		return fmt.Sprintf("%s/%s/%d", fr.GetProbeName(), fr.GetTarget(), fr.GetTimestampMsec())
+	}
+
+	tests := []struct {
+		name string
+		req  *pb.GetFailedResponsesRequest
+		want []string
+	}{
+		{
+			name: "all",
+			req:  &pb.GetFailedResponsesRequest{},
+			want: []string{"p1/t1/2000", "p1/t1/1000", "p1/t2/3000", "p2/t1/4000"},
+		},
+		{
+			name: "probe",
+			req:  &pb.GetFailedResponsesRequest{ProbeName: []string{"p1"}},
+			want: []string{"p1/t1/2000", "p1/t1/1000", "p1/t2/3000"},
+		},
+		{
+			name: "target",
+			req:  &pb.GetFailedResponsesRequest{Target: []string{"t1"}},
+			want: []string{"p1/t1/2000", "p1/t1/1000", "p2/t1/4000"},
+		},
+	}
+
+	pr := &Prober{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := pr.GetFailedResponses(context.Background(), tt.req)
+			assert.NoError(t, err)
+			var got []string
+			for _, fr := range resp.GetFailedResponse() {
+				got = append(got, key(fr))
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	resp, _ := pr.GetFailedResponses(context.Background(), &pb.GetFailedResponsesRequest{ProbeName: []string{"p1"}, Target: []string{"t1"}})
+	fr := resp.GetFailedResponse()[1]
+	assert.Equal(t, int32(500), fr.GetStatusCode())
+	assert.Equal(t, "error", string(fr.GetBody()))
+	assert.Equal(t, "failed validations: v1", fr.GetError())
+	assert.Equal(t, int64(2000), fr.GetLatencyUsec())
+	var headers []string
+	for _, h := range fr.GetHeader() {
+		headers = append(headers, h.GetKey()+"="+h.GetValue())
+	}
+	assert.Equal(t, []string{"X-A=1", "X-A=3", "X-B=2"}, headers)
+	assert.Nil(t, resp.GetFailedResponse()[0].StatusCode, "no status code for responses without response")
+}
+
 func TestSaveProbesConfig(t *testing.T) {
 	tmpFile := func() *os.File {
 		f, err := os.CreateTemp(t.TempDir(), "cloudprober_save.cfg")
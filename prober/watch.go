@@ -0,0 +1,187 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prober
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/cloudprober/cloudprober/metrics"
+	pb "github.com/cloudprober/cloudprober/prober/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// watcherBufferSize is the number of results buffered for each
+// WatchProbeResults subscriber. If subscriber's buffer is full, new results
+// are dropped for that subscriber.
+const watcherBufferSize = 1000
+
+// resultWatcher is a WatchProbeResults subscriber.
+type resultWatcher struct {
+	probes, targets, metricNames map[string]bool
+
+	ch      chan *metrics.EventMetrics
+	dropped atomic.Int64
+}
+
+// resultWatchers keeps track of the active WatchProbeResults subscribers.
+type resultWatchers struct {
+	mu       sync.RWMutex
+	watchers map[*resultWatcher]bool
+}
+
+func stringSet(ss []string) map[string]bool {
+	if len(ss) == 0 {
+		return nil
+	}
+	m := make(map[string]bool, len(ss))
+	for _, s := range ss {
+		m[s] = true
+	}
+	return m
+}
+
+func newResultWatcher(req *pb.WatchProbeResultsRequest) *resultWatcher {
+	return &resultWatcher{
+		probes:      stringSet(req.GetProbeName()),
+		targets:     stringSet(req.GetTarget()),
+		metricNames: stringSet(req.GetMetricName()),
+		ch:          make(chan *metrics.EventMetrics, watcherBufferSize),
+	}
+}
+
+// matches returns true if EventMetrics matches watcher's filters. Only probe
+// results, i.e. EventMetrics with a probe label, are matched.
+func (w *resultWatcher) matches(em *metrics.EventMetrics) bool {
+	probe := em.Label("probe")
+	if probe == "" {
+		return false
+	}
+	if w.probes != nil && !w.probes[probe] {
+		return false
+	}
+	if w.targets != nil && !w.targets[em.Label("dst")] {
+		return false
+	}
+	if w.metricNames != nil {
+		for _, name := range em.MetricsKeys() {
+			if w.metricNames[name] {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func (rw *resultWatchers) add(w *resultWatcher) {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	if rw.watchers == nil {
+		rw.watchers = make(map[*resultWatcher]bool)
+	}
+	rw.watchers[w] = true
+}
+
+func (rw *resultWatchers) remove(w *resultWatcher) {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	delete(rw.watchers, w)
+}
+
+// publish sends EventMetrics to all the matching watchers. It never blocks:
+// if a watcher's buffer is full, EventMetrics is dropped for that watcher.
+func (rw *resultWatchers) publish(em *metrics.EventMetrics) {
+	rw.mu.RLock()
+	defer rw.mu.RUnlock()
+
+	for w := range rw.watchers {
+		if !w.matches(em) {
+			continue
+		}
+		select {
+		case w.ch <- em:
+		default:
+			w.dropped.Add(1)
+		}
+	}
+}
+
+// eventMetricsToProto converts EventMetrics to its proto representation. If
+// metricNames is not nil, only the metrics in metricNames are included.
+func eventMetricsToProto(em *metrics.EventMetrics, metricNames map[string]bool) *pb.EventMetrics {
+	emProto := &pb.EventMetrics{
+		TimestampMsec: proto.Int64(em.Timestamp.UnixMilli()),
+		Kind:          pb.EventMetrics_CUMULATIVE.Enum(),
+	}
+	if em.Kind == metrics.GAUGE {
+		emProto.Kind = pb.EventMetrics_GAUGE.Enum()
+	}
+
+	for _, k := range em.LabelsKeys() {
+		emProto.Label = append(emProto.Label, &pb.Label{
+			Key:   proto.String(k),
+			Value: proto.String(em.Label(k)),
+		})
+	}
+
+	for _, name := range em.MetricsKeys() {
+		if metricNames != nil && !metricNames[name] {
+			continue
+		}
+		emProto.Metric = append(emProto.Metric, metricToProto(name, em.Metric(name)))
+	}
+	return emProto
+}
+
+func metricToProto(name string, val metrics.Value) *pb.Metric {
+	m := &pb.Metric{
+		Name:  proto.String(name),
+		Value: proto.String(val.String()),
+	}
+	if numVal, ok := val.(metrics.NumValue); ok {
+		m.NumValue = proto.Float64(numVal.Float64())
+	}
+	return m
+}
+
+// WatchProbeResults gRPC method streams probe results as they are generated.
+// Results are fed off the same path that feeds surfacers.
+func (pr *Prober) WatchProbeResults(req *pb.WatchProbeResultsRequest, stream grpc.ServerStreamingServer[pb.WatchProbeResultsResponse]) error {
+	pr.l.Infof("WatchProbeResults called with: %v", req)
+
+	w := newResultWatcher(req)
+	pr.watchers.add(w)
+	defer pr.watchers.remove(w)
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case em := <-w.ch:
+			resp := &pb.WatchProbeResultsResponse{
+				EventMetrics: eventMetricsToProto(em, w.metricNames),
+			}
+			if dropped := w.dropped.Swap(0); dropped > 0 {
+				resp.DroppedCount = proto.Int64(dropped)
+			}
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prober
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	configpb "github.com/cloudprober/cloudprober/config/proto"
+	"github.com/cloudprober/cloudprober/metrics"
+	pb "github.com/cloudprober/cloudprober/prober/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+type fakeWatchStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	respCh chan *pb.WatchProbeResultsResponse
+}
+
+func (s *fakeWatchStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeWatchStream) Send(resp *pb.WatchProbeResultsResponse) error {
+	s.respCh <- resp
+	return nil
+}
+
+func testEM(probe, target string, ts time.Time) *metrics.EventMetrics {
+	return metrics.NewEventMetrics(ts).
+		AddMetric("total", metrics.NewInt(10)).
+		AddMetric("success", metrics.NewInt(9)).
+		AddMetric("resp-code", metrics.NewMap("code").IncKeyBy("200", 9)).
+		AddLabel("ptype", "http").
+		AddLabel("probe", probe).
+		AddLabel("dst", target)
+}
+
+func TestEventMetricsToProto(t *testing.T) {
+	ts := time.Unix(1700000000, 0)
+	em := testEM("p1", "t1", ts)
+	em.Kind = metrics.GAUGE
+
+	want := &pb.EventMetrics{
+		TimestampMsec: proto.Int64(ts.UnixMilli()),
+		Kind:          pb.EventMetrics_GAUGE.Enum(),
+		Label: []*pb.Label{
+			{Key: proto.String("ptype"), Value: proto.String("http")},
+			{Key: proto.String("probe"), Value: proto.String("p1")},
+			{Key: proto.String("dst"), Value: proto.String("t1")},
+		},
+		Metric: []*pb.Metric{
+			{Name: proto.String("total"), Value: proto.String("10"), NumValue: proto.Float64(10)},
+			{Name: proto.String("success"), Value: proto.String("9"), NumValue: proto.Float64(9)},
+			{Name: proto.String("resp-code"), Value: proto.String("map:code,200:9")},
+		},
+	}
+	assert.True(t, proto.Equal(want, eventMetricsToProto(em, nil)), "got: %v", eventMetricsToProto(em, nil))
+
+	got := eventMetricsToProto(em, map[string]bool{"success": true})
+	assert.Len(t, got.GetMetric(), 1)
+	assert.Equal(t, "success", got.GetMetric()[0].GetName())
+}
+
+func TestResultWatcherMatches(t *testing.T) {
+	ts := time.Now()
+	tests := []struct {
+		name string
+		req  *pb.WatchProbeResultsRequest
+		em   *metrics.EventMetrics
+		want bool
+	}{
+		{
+			name: "no_filter",
+			req:  &pb.WatchProbeResultsRequest{},
+			em:   testEM("p1", "t1", ts),
+			want: true,
+		},
+		{
+			name: "not_a_probe_result",
+			req:  &pb.WatchProbeResultsRequest{},
+			em:   metrics.NewEventMetrics(ts).AddMetric("uptime", metrics.NewInt(1)),
+			want: false,
+		},
+		{
+			name: "probe_match",
+			req:  &pb.WatchProbeResultsRequest{ProbeName: []string{"p1", "p2"}},
+			em:   testEM("p1", "t1", ts),
+			want: true,
+		},
+		{
+			name: "probe_mismatch",
+			req:  &pb.WatchProbeResultsRequest{ProbeName: []string{"p2"}},
+			em:   testEM("p1", "t1", ts),
+			want: false,
+		},
+		{
+			name: "target_mismatch",
+			req:  &pb.WatchProbeResultsRequest{Target: []string{"t2"}},
+			em:   testEM("p1", "t1", ts),
+			want: false,
+		},
+		{
+			name: "metric_match",
+			req:  &pb.WatchProbeResultsRequest{MetricName: []string{"latency", "success"}},
+			em:   testEM("p1", "t1", ts),
+			want: true,
+		},
+		{
+			name: "metric_mismatch",
+			req:  &pb.WatchProbeResultsRequest{MetricName: []string{"latency"}},
+			em:   testEM("p1", "t1", ts),
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, newResultWatcher(tt.req).matches(tt.em))
+		})
+	}
+}
+
+func TestResultWatchersDrop(t *testing.T) {
+	var rw resultWatchers
+	w := newResultWatcher(&pb.WatchProbeResultsRequest{})
+	rw.add(w)
+
+	for i := 0; i < watcherBufferSize+5; i++ {
+		rw.publish(testEM("p1", "t1", time.Now()))
+	}
+	assert.Len(t, w.ch, watcherBufferSize)
+	assert.Equal(t, int64(5), w.dropped.Load())
+
+	rw.remove(w)
+	rw.publish(testEM("p1", "t1", time.Now()))
+	assert.Equal(t, int64(5), w.dropped.Load())
+}
+
+func TestWatchProbeResults(t *testing.T) {
+	pr, cancel := testProber(t, &configpb.ProberConfig{})
+	defer cancel()
+
+	ctx, cancelWatch := context.WithCancel(context.Background())
+	stream := &fakeWatchStream{
+		ctx:    ctx,
+		respCh: make(chan *pb.WatchProbeResultsResponse, 10),
+	}
+
+	errCh := make(chan error)
+	go func() {
+		errCh <- pr.WatchProbeResults(&pb.WatchProbeResultsRequest{
+			ProbeName:  []string{"p1"},
+			MetricName: []string{"success"},
+		}, stream)
+	}()
+
+	// Wait for the watcher to be registered.
+	assert.Eventually(t, func() bool {
+		pr.watchers.mu.RLock()
+		defer pr.watchers.mu.RUnlock()
+		return len(pr.watchers.watchers) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	pr.dataChan <- testEM("p2", "t1", time.Now())
+	pr.dataChan <- testEM("p1", "t1", time.Now())
+
+	select {
+	case resp := <-stream.respCh:
+		em := resp.GetEventMetrics()
+		assert.Equal(t, "p1", em.GetLabel()[1].GetValue())
+		assert.Len(t, em.GetMetric(), 1)
+		assert.Equal(t, "success", em.GetMetric()[0].GetName())
+		assert.Equal(t, 9.0, em.GetMetric()[0].GetNumValue())
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for probe results")
+	}
+
+	cancelWatch()
+	assert.NoError(t, <-errCh)
+	assert.Len(t, pr.watchers.watchers, 0)
+	assert.Len(t, stream.respCh, 0, "p2 results should have been filtered out")
+}
@@ -0,0 +1,209 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dependency implements probe dependencies. Tracker keeps track of
+// the recent state of all probes' targets, and Dependencies uses it to find
+// out if a probe's upstream (parent) probes are failing.
+package dependency
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cloudprober/cloudprober/metrics"
+	configpb "github.com/cloudprober/cloudprober/probes/proto"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+)
+
+// UpstreamFailedLabel is the label added to the results of the probes that
+// use the LABEL action.
+const UpstreamFailedLabel = "upstream_failed"
+
+type targetState struct {
+	lastTotal, lastSuccess int64
+	failing                bool
+	validUntil             time.Time
+}
+
+// Tracker tracks the state (failing or not) of the probes' targets. It's
+// shared by all the probes.
+type Tracker struct {
+	mu     sync.RWMutex
+	probes map[string]map[string]*targetState
+}
+
+// NewTracker returns a new Tracker.
+func NewTracker() *Tracker {
+	return &Tracker{
+		probes: make(map[string]map[string]*targetState),
+	}
+}
+
+func metricValue(em *metrics.EventMetrics, name string) (int64, bool) {
+	v, ok := em.Metric(name).(metrics.NumValue)
+	if !ok {
+		return 0, false
+	}
+	return v.Int64(), true
+}
+
+// Record updates the state of the probe's target using the "total" and
+// "success" metrics in the EventMetrics. A target is considered failing if
+// there were any failures since the last update. State is considered valid for
+// the validFor duration; a target that doesn't get updated within that time
+// is not considered failing anymore.
+func (t *Tracker) Record(probe, target string, em *metrics.EventMetrics, validFor time.Duration) {
+	if t == nil {
+		return
+	}
+
+	total, ok := metricValue(em, "total")
+	if !ok {
+		return
+	}
+	success, ok := metricValue(em, "success")
+	if !ok {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.probes[probe] == nil {
+		t.probes[probe] = make(map[string]*targetState)
+	}
+	ts := t.probes[probe][target]
+	if ts == nil {
+		ts = &targetState{}
+		t.probes[probe][target] = ts
+	}
+
+	// If total went down, probe must have been reset.
+	if total < ts.lastTotal {
+		ts.lastTotal, ts.lastSuccess = 0, 0
+	}
+
+	if total > ts.lastTotal {
+		ts.failing = (total - ts.lastTotal) > (success - ts.lastSuccess)
+	}
+	ts.lastTotal, ts.lastSuccess = total, success
+	ts.validUntil = time.Now().Add(validFor)
+}
+
+// Failing returns true if the probe's target is currently failing.
+func (t *Tracker) Failing(probe, target string) bool {
+	if t == nil {
+		return false
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	ts := t.probes[probe][target]
+	return ts != nil && ts.failing && time.Now().Before(ts.validUntil)
+}
+
+// RemoveProbe removes the probe's state from the tracker.
+func (t *Tracker) RemoveProbe(probe string) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.probes, probe)
+}
+
+// Dependencies represents a probe's dependencies.
+type Dependencies struct {
+	deps      []*configpb.Dependency
+	labelMode bool
+	skipMode  bool
+	tracker   *Tracker
+}
+
+// New returns Dependencies for the given probe, built from the probe's
+// depends_on config.
+func New(confs []*configpb.Dependency, probeName string) (*Dependencies, error) {
+	d := &Dependencies{
+		deps: confs,
+	}
+
+	for _, c := range confs {
+		if c.GetProbe() == "" {
+			return nil, fmt.Errorf("dependency probe name cannot be empty")
+		}
+		if c.GetProbe() == probeName {
+			return nil, fmt.Errorf("probe %s cannot depend on itself", probeName)
+		}
+		switch c.GetAction() {
+		case configpb.Dependency_LABEL:
+			d.labelMode = true
+		case configpb.Dependency_SKIP:
+			d.skipMode = true
+		}
+	}
+	return d, nil
+}
+
+// SetTracker sets the tracker used to look up the parent probes' state. It
+// should be called before the probe is started.
+func (d *Dependencies) SetTracker(t *Tracker) {
+	d.tracker = t
+}
+
+// HasSkipAction returns true if any of the dependencies uses the SKIP action.
+func (d *Dependencies) HasSkipAction() bool {
+	return d != nil && d.skipMode
+}
+
+// HasLabelAction returns true if any of the dependencies uses the LABEL
+// action.
+func (d *Dependencies) HasLabelAction() bool {
+	return d != nil && d.labelMode
+}
+
+func parentTarget(c *configpb.Dependency, ep endpoint.Endpoint) string {
+	switch c.GetParentTarget().(type) {
+	case *configpb.Dependency_Target:
+		return c.GetTarget()
+	case *configpb.Dependency_TargetLabel:
+		return ep.Labels[c.GetTargetLabel()]
+	default:
+		return ep.Name
+	}
+}
+
+// Check checks the probe's dependencies for the given target. It returns the
+// name of the failing parent probe (empty string if none is failing), and
+// whether probe runs should be skipped for the target.
+func (d *Dependencies) Check(ep endpoint.Endpoint) (failingParent string, skip bool) {
+	if d == nil {
+		return "", false
+	}
+
+	for _, c := range d.deps {
+		if !d.tracker.Failing(c.GetProbe(), parentTarget(c, ep)) {
+			continue
+		}
+		if failingParent == "" {
+			failingParent = c.GetProbe()
+		}
+		if c.GetAction() == configpb.Dependency_SKIP {
+			return c.GetProbe(), true
+		}
+	}
+	return failingParent, false
+}
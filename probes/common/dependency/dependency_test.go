@@ -0,0 +1,174 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dependency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cloudprober/cloudprober/metrics"
+	configpb "github.com/cloudprober/cloudprober/probes/proto"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func testEM(total, success int64) *metrics.EventMetrics {
+	return metrics.NewEventMetrics(time.Now()).
+		AddMetric("total", metrics.NewInt(total)).
+		AddMetric("success", metrics.NewInt(success))
+}
+
+func TestTracker(t *testing.T) {
+	tr := NewTracker()
+
+	assert.False(t, tr.Failing("p1", "t1"), "unknown target")
+
+	tr.Record("p1", "t1", testEM(10, 10), time.Minute)
+	assert.False(t, tr.Failing("p1", "t1"))
+
+	tr.Record("p1", "t1", testEM(20, 15), time.Minute)
+	assert.True(t, tr.Failing("p1", "t1"))
+	assert.False(t, tr.Failing("p1", "t2"))
+	assert.False(t, tr.Failing("p2", "t1"))
+
+	// No new runs, state stays the same.
+	tr.Record("p1", "t1", testEM(20, 15), time.Minute)
+	assert.True(t, tr.Failing("p1", "t1"))
+
+	tr.Record("p1", "t1", testEM(30, 25), time.Minute)
+	assert.False(t, tr.Failing("p1", "t1"))
+
+	// Probe reset.
+	tr.Record("p1", "t1", testEM(5, 0), time.Minute)
+	assert.True(t, tr.Failing("p1", "t1"))
+
+	// Stale state.
+	tr.Record("p1", "t1", testEM(10, 0), -time.Second)
+	assert.False(t, tr.Failing("p1", "t1"))
+
+	tr.Record("p1", "t1", testEM(20, 0), time.Minute)
+	tr.RemoveProbe("p1")
+	assert.False(t, tr.Failing("p1", "t1"))
+
+	// Nil tracker.
+	var nilTracker *Tracker
+	nilTracker.Record("p1", "t1", testEM(1, 0), time.Minute)
+	assert.False(t, nilTracker.Failing("p1", "t1"))
+}
+
+func TestNew(t *testing.T) {
+	_, err := New([]*configpb.Dependency{{Probe: proto.String("")}}, "p")
+	assert.Error(t, err)
+
+	_, err = New([]*configpb.Dependency{{Probe: proto.String("p")}}, "p")
+	assert.Error(t, err)
+
+	d, err := New([]*configpb.Dependency{{Probe: proto.String("parent")}}, "p")
+	assert.NoError(t, err)
+	assert.True(t, d.HasSkipAction())
+	assert.False(t, d.HasLabelAction())
+
+	d, err = New([]*configpb.Dependency{{Probe: proto.String("parent"), Action: configpb.Dependency_LABEL.Enum()}}, "p")
+	assert.NoError(t, err)
+	assert.False(t, d.HasSkipAction())
+	assert.True(t, d.HasLabelAction())
+
+	var nilDeps *Dependencies
+	assert.False(t, nilDeps.HasLabelAction())
+	parent, skip := nilDeps.Check(endpoint.Endpoint{Name: "t1"})
+	assert.Equal(t, "", parent)
+	assert.False(t, skip)
+}
+
+func TestDependenciesCheck(t *testing.T) {
+	tr := NewTracker()
+	tr.Record("dns", "8.8.8.8", testEM(1, 0), time.Minute)
+	tr.Record("lb", "lb1", testEM(1, 0), time.Minute)
+	tr.Record("lb", "lb2", testEM(1, 1), time.Minute)
+	tr.Record("ping", "host1", testEM(1, 0), time.Minute)
+
+	tests := []struct {
+		name       string
+		deps       []*configpb.Dependency
+		ep         endpoint.Endpoint
+		wantParent string
+		wantSkip   bool
+	}{
+		{
+			name:       "fixed_target_failing",
+			deps:       []*configpb.Dependency{{Probe: proto.String("dns"), ParentTarget: &configpb.Dependency_Target{Target: "8.8.8.8"}}},
+			ep:         endpoint.Endpoint{Name: "host2"},
+			wantParent: "dns",
+			wantSkip:   true,
+		},
+		{
+			name:       "same_target_failing",
+			deps:       []*configpb.Dependency{{Probe: proto.String("ping")}},
+			ep:         endpoint.Endpoint{Name: "host1"},
+			wantParent: "ping",
+			wantSkip:   true,
+		},
+		{
+			name: "same_target_not_failing",
+			deps: []*configpb.Dependency{{Probe: proto.String("ping")}},
+			ep:   endpoint.Endpoint{Name: "host2"},
+		},
+		{
+			name:       "label_target_failing",
+			deps:       []*configpb.Dependency{{Probe: proto.String("lb"), ParentTarget: &configpb.Dependency_TargetLabel{TargetLabel: "lb"}}},
+			ep:         endpoint.Endpoint{Name: "host1", Labels: map[string]string{"lb": "lb1"}},
+			wantParent: "lb",
+			wantSkip:   true,
+		},
+		{
+			name: "label_target_not_failing",
+			deps: []*configpb.Dependency{{Probe: proto.String("lb"), ParentTarget: &configpb.Dependency_TargetLabel{TargetLabel: "lb"}}},
+			ep:   endpoint.Endpoint{Name: "host1", Labels: map[string]string{"lb": "lb2"}},
+		},
+		{
+			name: "label_action_then_skip",
+			deps: []*configpb.Dependency{
+				{Probe: proto.String("ping"), Action: configpb.Dependency_LABEL.Enum()},
+				{Probe: proto.String("dns"), ParentTarget: &configpb.Dependency_Target{Target: "8.8.8.8"}},
+			},
+			ep:         endpoint.Endpoint{Name: "host1"},
+			wantParent: "dns",
+			wantSkip:   true,
+		},
+		{
+			name: "label_action",
+			deps: []*configpb.Dependency{
+				{Probe: proto.String("ping"), Action: configpb.Dependency_LABEL.Enum()},
+				{Probe: proto.String("lb"), ParentTarget: &configpb.Dependency_Target{Target: "lb2"}},
+			},
+			ep:         endpoint.Endpoint{Name: "host1"},
+			wantParent: "ping",
+			wantSkip:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := New(tt.deps, "http")
+			assert.NoError(t, err)
+			d.SetTracker(tr)
+
+			parent, skip := d.Check(tt.ep)
+			assert.Equal(t, tt.wantParent, parent)
+			assert.Equal(t, tt.wantSkip, skip)
+		})
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package failedresponses keeps the recent failed responses of the probes,
+// to help debug intermittent failures. Each probe that keeps failed responses
+// has a Buffer, with a bounded ring of responses per target. Buffers are
+// registered in a global registry, which is used by the web UI and the gRPC
+// API to look them up.
+package failedresponses
+
+import (
+	"bytes"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Response is a failed response.
+type Response struct {
+	Time          time.Time
+	URL           string
+	StatusCode    int // 0 if no response was received.
+	Header        http.Header
+	Body          []byte
+	BodyTruncated bool
+	Error         string
+	Latency       time.Duration
+
+	// Request timing breakdown. Stages that didn't happen, e.g. TLS
+	// handshake for plain HTTP requests, are left at 0.
+	DNSLatency          time.Duration
+	ConnectLatency      time.Duration
+	TLSHandshakeLatency time.Duration
+	ReqWriteLatency     time.Duration
+	FirstByteLatency    time.Duration
+}
+
+type ring struct {
+	responses []*Response
+	next      int
+}
+
+// Buffer keeps the last N failed responses per target.
+type Buffer struct {
+	mu          sync.RWMutex
+	size        int
+	maxBodySize int
+	targets     map[string]*ring
+}
+
+// NewBuffer returns a new Buffer that keeps up to size responses per target.
+// Response bodies longer than maxBodySize are truncated.
+func NewBuffer(size, maxBodySize int) *Buffer {
+	return &Buffer{
+		size:        size,
+		maxBodySize: maxBodySize,
+		targets:     make(map[string]*ring),
+	}
+}
+
+// Add adds a failed response for the target, overwriting the oldest response
+// if the target's ring is full.
+func (b *Buffer) Add(target string, r *Response) {
+	if b == nil || b.size <= 0 {
+		return
+	}
+
+	if len(r.Body) > b.maxBodySize {
+		// Clone the truncated body to not hold on to the full body.
+		r.Body, r.BodyTruncated = bytes.Clone(r.Body[:b.maxBodySize]), true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	rg := b.targets[target]
+	if rg == nil {
+		rg = &ring{}
+		b.targets[target] = rg
+	}
+
+	if len(rg.responses) < b.size {
+		rg.responses = append(rg.responses, r)
+		return
+	}
+	rg.responses[rg.next] = r
+	rg.next = (rg.next + 1) % b.size
+}
+
+// List returns the failed responses for the target, newest first.
+func (b *Buffer) List(target string) []*Response {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	rg := b.targets[target]
+	if rg == nil {
+		return nil
+	}
+
+	out := make([]*Response, 0, len(rg.responses))
+	for i := len(rg.responses) - 1; i >= 0; i-- {
+		out = append(out, rg.responses[(rg.next+i)%len(rg.responses)])
+	}
+	return out
+}
+
+// Targets returns the targets that have failed responses, in sorted order.
+func (b *Buffer) Targets() []string {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	targets := make([]string, 0, len(b.targets))
+	for t := range b.targets {
+		targets = append(targets, t)
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+var registry = struct {
+	mu      sync.RWMutex
+	buffers map[string]*Buffer
+}{
+	buffers: make(map[string]*Buffer),
+}
+
+// Register registers the probe's buffer in the global registry, replacing
+// any existing buffer for the probe.
+func Register(probeName string, b *Buffer) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.buffers[probeName] = b
+}
+
+// Unregister removes the probe's buffer from the global registry. It's a
+// no-op if the probe's registered buffer is not b, e.g. if the probe has
+// been updated and the new instance has registered its own buffer already.
+func Unregister(probeName string, b *Buffer) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if registry.buffers[probeName] == b {
+		delete(registry.buffers, probeName)
+	}
+}
+
+// Get returns the probe's buffer, or nil if the probe doesn't keep failed
+// responses.
+func Get(probeName string) *Buffer {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	return registry.buffers[probeName]
+}
+
+// Probes returns the names of the probes that keep failed responses, in
+// sorted order.
+func Probes() []string {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	probes := make([]string, 0, len(registry.buffers))
+	for p := range registry.buffers {
+		probes = append(probes, p)
+	}
+	sort.Strings(probes)
+	return probes
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package failedresponses
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func responseErrors(resps []*Response) []string {
+	var out []string
+	for _, r := range resps {
+		out = append(out, r.Error)
+	}
+	return out
+}
+
+func TestBuffer(t *testing.T) {
+	b := NewBuffer(3, 4)
+
+	assert.Nil(t, b.List("t1"))
+
+	b.Add("t1", &Response{Error: "e1", Body: []byte("body")})
+	b.Add("t1", &Response{Error: "e2", Body: []byte("long body")})
+	assert.Equal(t, []string{"e2", "e1"}, responseErrors(b.List("t1")))
+
+	resps := b.List("t1")
+	assert.Equal(t, "long", string(resps[0].Body))
+	assert.True(t, resps[0].BodyTruncated)
+	assert.Equal(t, "body", string(resps[1].Body))
+	assert.False(t, resps[1].BodyTruncated)
+
+	for _, e := range []string{"e3", "e4", "e5"} {
+		b.Add("t1", &Response{Error: e})
+	}
+	assert.Equal(t, []string{"e5", "e4", "e3"}, responseErrors(b.List("t1")))
+
+	b.Add("t1", &Response{Error: "e6"})
+	assert.Equal(t, []string{"e6", "e5", "e4"}, responseErrors(b.List("t1")))
+
+	b.Add("t0", &Response{Error: "e1"})
+	assert.Equal(t, []string{"e1"}, responseErrors(b.List("t0")))
+	assert.Equal(t, []string{"t0", "t1"}, b.Targets())
+
+	// Nil buffer.
+	var nilBuffer *Buffer
+	nilBuffer.Add("t1", &Response{Error: "e1"})
+	assert.Nil(t, nilBuffer.List("t1"))
+	assert.Nil(t, nilBuffer.Targets())
+}
+
+func TestRegistry(t *testing.T) {
+	b1, b2 := NewBuffer(1, 10), NewBuffer(1, 10)
+
+	Register("p1", b1)
+	Register("p2", b2)
+	assert.Equal(t, []string{"p1", "p2"}, Probes())
+	assert.Equal(t, b1, Get("p1"))
+
+	// Probe is updated, new instance registers a new buffer before the old
+	// instance unregisters.
+	b1New := NewBuffer(1, 10)
+	Register("p1", b1New)
+	Unregister("p1", b1)
+	assert.Equal(t, b1New, Get("p1"))
+
+	Unregister("p1", b1New)
+	Unregister("p2", b2)
+	assert.Nil(t, Get("p1"))
+	assert.Empty(t, Probes())
+}
+
+func TestStatusHTML(t *testing.T) {
+	b := NewBuffer(2, 100)
+	b.Add("t1", &Response{
+		Time:       time.Now(),
+		URL:        "http://t1/health",
+		StatusCode: 503,
+		Header:     http.Header{"Retry-After": {"10"}},
+		Body:       []byte("<b>unavailable</b>"),
+		Error:      "failed validations: status_code",
+	})
+	b.Add("t2", &Response{Time: time.Now(), URL: "http://t2/health", Error: "timeout"})
+	Register("p1", b)
+	defer Unregister("p1", b)
+
+	html, err := StatusHTML(nil, nil)
+	assert.NoError(t, err)
+	for _, s := range []string{"Probe: <a href=\"?probe=p1\">p1</a>", "http://t1/health", "503", "Retry-After: 10", "&lt;b&gt;unavailable&lt;/b&gt;", "http://t2/health", "timeout"} {
+		assert.Contains(t, html, s)
+	}
+
+	html, err = StatusHTML([]string{"p1"}, []string{"t2"})
+	assert.NoError(t, err)
+	assert.Contains(t, html, "http://t2/health")
+	assert.NotContains(t, html, "http://t1/health")
+
+	html, err = StatusHTML([]string{"p2"}, nil)
+	assert.NoError(t, err)
+	assert.NotContains(t, html, "p1")
+}
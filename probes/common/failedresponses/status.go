@@ -0,0 +1,109 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package failedresponses
+
+import (
+	"bytes"
+	"html/template"
+	"slices"
+)
+
+// URL is the URL of the failed responses page.
+const URL = "/failed-responses"
+
+var statusTmpl = template.Must(template.New("failedResponses").Parse(`
+{{ if not .Probes }}
+  <p>No probes are keeping failed responses. Set <code>keep_failed_responses</code> in the HTTP probe config to enable it.</p>
+{{ end }}
+{{ range .Probes }}
+<h3>Probe: <a href="?probe={{ .Name }}">{{ .Name }}</a></h3>
+{{ if not .Targets }}
+  <p>No failed responses.</p>
+{{ end }}
+{{ $probe := .Name }}
+{{ range .Targets }}
+<p><b>Target: <a href="?probe={{ $probe }}&target={{ .Name }}">{{ .Name }}</a></b></p>
+<table class="status-list">
+<tr>
+  <th>Time</th>
+  <th>URL</th>
+  <th>Status</th>
+  <th>Error</th>
+  <th>Latency</th>
+  <th>Timings</th>
+  <th>Headers</th>
+  <th>Body</th>
+</tr>
+{{ range .Responses }}
+<tr>
+  <td>{{ .Time.Format "2006-01-02 15:04:05.000 MST" }}</td>
+  <td>{{ .URL }}</td>
+  <td>{{ if .StatusCode }}{{ .StatusCode }}{{ end }}</td>
+  <td>{{ .Error }}</td>
+  <td>{{ .Latency }}</td>
+  <td style="white-space:nowrap">
+    {{- if .DNSLatency }}dns: {{ .DNSLatency }}<br>{{ end }}
+    {{- if .ConnectLatency }}connect: {{ .ConnectLatency }}<br>{{ end }}
+    {{- if .TLSHandshakeLatency }}tls: {{ .TLSHandshakeLatency }}<br>{{ end }}
+    {{- if .ReqWriteLatency }}req_write: {{ .ReqWriteLatency }}<br>{{ end }}
+    {{- if .FirstByteLatency }}first_byte: {{ .FirstByteLatency }}{{ end -}}
+  </td>
+  <td><pre>{{ range $k, $v := .Header }}{{ range $v }}{{ $k }}: {{ . }}
+{{ end }}{{ end }}</pre></td>
+  <td><pre>{{ printf "%s" .Body }}</pre>{{ if .BodyTruncated }}<i>(truncated)</i>{{ end }}</td>
+</tr>
+{{- end }}
+</table>
+{{ end }}
+{{ end }}
+`))
+
+type targetData struct {
+	Name      string
+	Responses []*Response
+}
+
+type probeData struct {
+	Name    string
+	Targets []targetData
+}
+
+// StatusHTML returns the failed responses page content. If probes or targets
+// are not empty, only the failed responses for those probes and targets are
+// included.
+func StatusHTML(probes, targets []string) (string, error) {
+	var data []probeData
+
+	for _, probe := range Probes() {
+		if len(probes) != 0 && !slices.Contains(probes, probe) {
+			continue
+		}
+		b := Get(probe)
+		pd := probeData{Name: probe}
+		for _, target := range b.Targets() {
+			if len(targets) != 0 && !slices.Contains(targets, target) {
+				continue
+			}
+			pd.Targets = append(pd.Targets, targetData{Name: target, Responses: b.List(target)})
+		}
+		data = append(data, pd)
+	}
+
+	var buf bytes.Buffer
+	if err := statusTmpl.Execute(&buf, struct{ Probes []probeData }{data}); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sched
+
+import (
+	"context"
+	"net"
+	"strconv"
+
+	"github.com/cloudprober/cloudprober/common/iputils"
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/probes/options"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+)
+
+// lookupIP is used to get all IPs of a target if dual_stack.all_ips is set.
+// It's a variable to allow overriding it in tests.
+var lookupIP = net.DefaultResolver.LookupIP
+
+// dualStackIPs returns the IPs to probe for a target in dual-stack mode: one
+// IP per address family, or all resolved IPs if opts.DualStackAllIPs is set.
+func dualStackIPs(ctx context.Context, opts *options.Options, target endpoint.Endpoint) []net.IP {
+	// Nothing to resolve if target comes with an IP.
+	if target.IP != nil {
+		return []net.IP{target.IP}
+	}
+
+	host := target.Host()
+
+	var ips []net.IP
+	for _, ipVer := range []int{4, 6} {
+		if opts.DualStackAllIPs {
+			lookupCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+			resolved, err := lookupIP(lookupCtx, "ip"+strconv.Itoa(ipVer), host)
+			cancel()
+			if err != nil {
+				opts.Logger.Warningf("dual_stack: error resolving IPv%d addresses for %s: %v", ipVer, host, err)
+				continue
+			}
+			ips = append(ips, resolved...)
+			continue
+		}
+
+		ip, err := target.Resolve(ipVer, opts.Targets, endpoint.WithNameOverride(host))
+		if err != nil {
+			opts.Logger.Warningf("dual_stack: error resolving IPv%d address for %s: %v", ipVer, host, err)
+			continue
+		}
+		ips = append(ips, ip)
+	}
+	return ips
+}
+
+// expandDualStack expands targets into one target per IP to probe, with the
+// target's IP set to that IP. Targets that don't resolve to any IP are kept
+// as they are, so that probes can report resolution failures for them.
+func expandDualStack(ctx context.Context, opts *options.Options, targets []endpoint.Endpoint) []endpoint.Endpoint {
+	var out []endpoint.Endpoint
+	for _, target := range targets {
+		ips := dualStackIPs(ctx, opts, target)
+		if len(ips) == 0 {
+			out = append(out, target)
+			continue
+		}
+		for _, ip := range ips {
+			ep := target.Clone()
+			ep.IP = ip
+			out = append(out, *ep)
+		}
+	}
+	return out
+}
+
+// addDualStackLabels adds the "ip_version" and "ip" labels to em, based on
+// the target's IP.
+func addDualStackLabels(em *metrics.EventMetrics, target endpoint.Endpoint) {
+	var ipVersion, ip string
+	if target.IP != nil {
+		ipVersion, ip = strconv.Itoa(iputils.IPVersion(target.IP)), target.IP.String()
+	}
+	em.AddLabel("ip_version", ipVersion).AddLabel("ip", ip)
+}
@@ -0,0 +1,178 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sched
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cloudprober/cloudprober/logger"
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/probes/options"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+	"github.com/stretchr/testify/assert"
+)
+
+var testDNS = map[string]map[string][]string{
+	"dual.example.com": {
+		"ip4": {"192.0.2.1", "192.0.2.2"},
+		"ip6": {"2001:db8::1", "2001:db8::2"},
+	},
+	"v4only.example.com": {
+		"ip4": {"192.0.2.10"},
+	},
+}
+
+func testLookup(_ context.Context, network, host string) ([]net.IP, error) {
+	var ips []net.IP
+	for _, s := range testDNS[host][network] {
+		ips = append(ips, net.ParseIP(s))
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("no %s address for %s", network, host)
+	}
+	return ips, nil
+}
+
+type testTargets struct {
+	eps []endpoint.Endpoint
+}
+
+func (tt *testTargets) ListEndpoints() []endpoint.Endpoint {
+	return tt.eps
+}
+
+func (tt *testTargets) Resolve(name string, ipVer int) (net.IP, error) {
+	ips, err := testLookup(context.Background(), fmt.Sprintf("ip%d", ipVer), name)
+	if err != nil {
+		return nil, err
+	}
+	return ips[0], nil
+}
+
+func TestExpandDualStack(t *testing.T) {
+	defer func(f func(context.Context, string, string) ([]net.IP, error)) { lookupIP = f }(lookupIP)
+	lookupIP = testLookup
+
+	eps := []endpoint.Endpoint{
+		{Name: "dual.example.com", Port: 443},
+		{Name: "https://dual.example.com/health", Labels: map[string]string{"__cp_host__": "dual.example.com"}},
+		{Name: "v4only.example.com"},
+		{Name: "static", IP: net.ParseIP("2001:db8::10")},
+		{Name: "unknown.example.com"},
+	}
+
+	ipsFor := func(eps []endpoint.Endpoint) map[string][]string {
+		m := make(map[string][]string)
+		for _, ep := range eps {
+			ip := ""
+			if ep.IP != nil {
+				ip = ep.IP.String()
+			}
+			m[ep.Name] = append(m[ep.Name], ip)
+		}
+		return m
+	}
+
+	tests := []struct {
+		name   string
+		allIPs bool
+		want   map[string][]string
+	}{
+		{
+			name: "per_family",
+			want: map[string][]string{
+				"dual.example.com":                {"192.0.2.1", "2001:db8::1"},
+				"https://dual.example.com/health": {"192.0.2.1", "2001:db8::1"},
+				"v4only.example.com":              {"192.0.2.10"},
+				"static":                          {"2001:db8::10"},
+				"unknown.example.com":             {""},
+			},
+		},
+		{
+			name:   "all_ips",
+			allIPs: true,
+			want: map[string][]string{
+				"dual.example.com":                {"192.0.2.1", "192.0.2.2", "2001:db8::1", "2001:db8::2"},
+				"https://dual.example.com/health": {"192.0.2.1", "192.0.2.2", "2001:db8::1", "2001:db8::2"},
+				"v4only.example.com":              {"192.0.2.10"},
+				"static":                          {"2001:db8::10"},
+				"unknown.example.com":             {""},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &options.Options{
+				Targets:         &testTargets{eps: eps},
+				Timeout:         time.Second,
+				Logger:          &logger.Logger{},
+				DualStack:       true,
+				DualStackAllIPs: tt.allIPs,
+			}
+			got := expandDualStack(context.Background(), opts, eps)
+			assert.Equal(t, tt.want, ipsFor(got))
+
+			// Other fields should be retained.
+			assert.Equal(t, 443, got[0].Port)
+
+			// Expanded targets should have unique keys.
+			keys := make(map[string]bool)
+			for _, ep := range got {
+				keys[ep.Key()] = true
+			}
+			assert.Len(t, keys, len(got))
+		})
+	}
+}
+
+func TestAddDualStackLabels(t *testing.T) {
+	for _, tt := range []struct {
+		ip            string
+		wantIPVersion string
+	}{
+		{ip: "192.0.2.1", wantIPVersion: "4"},
+		{ip: "2001:db8::1", wantIPVersion: "6"},
+		{ip: "", wantIPVersion: ""},
+	} {
+		em := metrics.NewEventMetrics(time.Now())
+		addDualStackLabels(em, endpoint.Endpoint{Name: "t", IP: net.ParseIP(tt.ip)})
+		assert.Equal(t, tt.wantIPVersion, em.Label("ip_version"))
+		assert.Equal(t, tt.ip, em.Label("ip"))
+	}
+}
+
+func TestRunOnceDualStack(t *testing.T) {
+	opts := &options.Options{
+		Targets:   &testTargets{eps: []endpoint.Endpoint{{Name: "dual.example.com"}}},
+		Timeout:   time.Second,
+		Logger:    &logger.Logger{},
+		DualStack: true,
+	}
+
+	results := RunOnce(context.Background(), opts, func(_ context.Context, runReq *RunProbeForTargetRequest) {
+		runReq.LastRun.Success = runReq.Target.IP.To4() != nil
+	})
+
+	assert.Len(t, results, 2)
+	assert.Equal(t, "192.0.2.1", results[0].Target.IP.String())
+	assert.True(t, results[0].Success)
+	assert.Equal(t, "2001:db8::1", results[1].Target.IP.String())
+	assert.False(t, results[1].Success)
+}
@@ -175,7 +175,7 @@ func (s *Scheduler) startForTarget(ctx context.Context, target endpoint.Endpoint
 		if CtxDone(ctx) {
 			return
 		}
-		if !s.Opts.IsScheduled() {
+		if !s.Opts.IsScheduled() || s.Opts.SkipForUpstreamFailure(target) {
 			continue
 		}
 
@@ -196,6 +196,9 @@ func (s *Scheduler) startForTarget(ctx context.Context, target endpoint.Endpoint
 				}
 				em.AddLabel("probe", s.ProbeName).
 					AddLabel("dst", target.Dst())
+				if s.Opts.DualStack {
+					addDualStackLabels(em, target)
+				}
 				s.Opts.RecordMetrics(target, em, s.DataChan)
 			}
 		}
@@ -217,6 +220,9 @@ func (s *Scheduler) refreshTargets(ctx context.Context) {
 	} else {
 		newTargets = s.Opts.Targets.ListEndpoints()
 	}
+	if s.Opts.DualStack {
+		newTargets = expandDualStack(ctx, s.Opts, newTargets)
+	}
 
 	s.Opts.Logger.Debugf("Probe(%s) got %d targets", s.ProbeName, len(s.targets))
 
@@ -288,8 +294,13 @@ func RunOnce(ctx context.Context, opts *options.Options, run func(context.Contex
 	var out []*singlerun.ProbeRunResult
 	var outMu sync.Mutex
 
+	targets := opts.Targets.ListEndpoints()
+	if opts.DualStack {
+		targets = expandDualStack(ctx, opts, targets)
+	}
+
 	var wg sync.WaitGroup
-	for _, target := range opts.Targets.ListEndpoints() {
+	for _, target := range targets {
 		wg.Add(1)
 		go func(target endpoint.Endpoint) {
 			defer wg.Done()
@@ -322,7 +333,11 @@ func RunOnce(ctx context.Context, opts *options.Options, run func(context.Contex
 	wg.Wait()
 
 	sort.Slice(out, func(i, j int) bool {
-		return out[i].Target.Name < out[j].Target.Name
+		if out[i].Target.Name != out[j].Target.Name {
+			return out[i].Target.Name < out[j].Target.Name
+		}
+		// Same target can appear multiple times in dual-stack mode.
+		return out[i].Target.IP.String() < out[j].Target.IP.String()
 	})
 
 	return out
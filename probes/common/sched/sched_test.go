@@ -18,15 +18,18 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/cloudprober/cloudprober/logger"
 	"github.com/cloudprober/cloudprober/metrics"
 	"github.com/cloudprober/cloudprober/metrics/testutils"
+	"github.com/cloudprober/cloudprober/probes/common/dependency"
 	dnsconfigpb "github.com/cloudprober/cloudprober/probes/dns/proto"
 	httpconfigpb "github.com/cloudprober/cloudprober/probes/http/proto"
 	"github.com/cloudprober/cloudprober/probes/options"
+	configpb "github.com/cloudprober/cloudprober/probes/proto"
 	tcpconfigpb "github.com/cloudprober/cloudprober/probes/tcp/proto"
 	"github.com/cloudprober/cloudprober/targets"
 	"github.com/cloudprober/cloudprober/targets/endpoint"
@@ -386,3 +389,48 @@ func TestSchedulerGapBetweenTargets(t *testing.T) {
 		})
 	}
 }
+
+func TestSkipForUpstreamFailure(t *testing.T) {
+	opts := &options.Options{
+		Name:                "child",
+		Targets:             targets.StaticTargets("test1.com,test2.com"),
+		Interval:            10 * time.Millisecond,
+		Timeout:             5 * time.Millisecond,
+		StatsExportInterval: 10 * time.Millisecond,
+		Logger:              &logger.Logger{},
+	}
+	var err error
+	opts.Dependencies, err = dependency.New([]*configpb.Dependency{{Probe: proto.String("parent")}}, "child")
+	assert.NoError(t, err)
+
+	tracker := dependency.NewTracker()
+	opts.SetDependencyTracker(tracker)
+	tracker.Record("parent", "test1.com", metrics.NewEventMetrics(time.Now()).
+		AddMetric("total", metrics.NewInt(1)).
+		AddMetric("success", metrics.NewInt(0)), time.Minute)
+
+	var mu sync.Mutex
+	runs := make(map[string]int)
+	s := &Scheduler{
+		Opts:      opts,
+		DataChan:  make(chan *metrics.EventMetrics, 100),
+		NewResult: func(_ *endpoint.Endpoint) ProbeResult { return &testProbeResult{} },
+		RunProbeForTarget: func(ctx context.Context, runReq *RunProbeForTargetRequest) {
+			mu.Lock()
+			defer mu.Unlock()
+			runs[runReq.Target.Name]++
+		},
+	}
+	s.init()
+
+	ctx, cancelF := context.WithCancel(context.Background())
+	s.refreshTargets(ctx)
+	time.Sleep(100 * time.Millisecond)
+	cancelF()
+	s.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 0, runs["test1.com"], "test1.com should be skipped")
+	assert.Greater(t, runs["test2.com"], 0, "test2.com should run")
+}
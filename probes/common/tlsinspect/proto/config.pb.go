@@ -0,0 +1,151 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.5
+// source: github.com/cloudprober/cloudprober/probes/common/tlsinspect/proto/config.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// TLS inspection config. If configured, probes inspect the TLS connection
+// and server certificates, and export the following GAUGE metrics:
+//
+//	tls_san_match:     1 if the leaf certificate is valid for the server name.
+//	tls_chain_valid:   1 if the certificate chain verifies against the CA
+//	                   bundle (system roots if ca_bundle_file is not set).
+//	tls_ocsp_stapled:  1 if server stapled an OCSP response.
+//
+// These metrics carry the following labels: tls_version, tls_cipher,
+// cert_subject and cert_issuer (leaf certificate's subject and issuer).
+//
+// Additionally, "tls_cert_days_to_expiry" metric is exported for every
+// certificate in the chain presented by the server, with labels cert_index
+// (0 for the leaf certificate), cert_subject and cert_issuer.
+type TLSInspectConf struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// CA bundle (PEM) to verify the server's certificate chain against. If not
+	// set, system roots are used.
+	CaBundleFile *string `protobuf:"bytes,1,opt,name=ca_bundle_file,json=caBundleFile" json:"ca_bundle_file,omitempty"`
+	// Server name to check the leaf certificate's SANs against. By default,
+	// TLS server name (SNI) used for the connection is used.
+	ExpectedServerName *string `protobuf:"bytes,2,opt,name=expected_server_name,json=expectedServerName" json:"expected_server_name,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *TLSInspectConf) Reset() {
+	*x = TLSInspectConf{}
+	mi := &file_github_com_cloudprober_cloudprober_probes_common_tlsinspect_proto_config_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TLSInspectConf) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TLSInspectConf) ProtoMessage() {}
+
+func (x *TLSInspectConf) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_probes_common_tlsinspect_proto_config_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TLSInspectConf.ProtoReflect.Descriptor instead.
+func (*TLSInspectConf) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_probes_common_tlsinspect_proto_config_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TLSInspectConf) GetCaBundleFile() string {
+	if x != nil && x.CaBundleFile != nil {
+		return *x.CaBundleFile
+	}
+	return ""
+}
+
+func (x *TLSInspectConf) GetExpectedServerName() string {
+	if x != nil && x.ExpectedServerName != nil {
+		return *x.ExpectedServerName
+	}
+	return ""
+}
+
+var File_github_com_cloudprober_cloudprober_probes_common_tlsinspect_proto_config_proto protoreflect.FileDescriptor
+
+const file_github_com_cloudprober_cloudprober_probes_common_tlsinspect_proto_config_proto_rawDesc = "" +
+	"\n" +
+	"Ngithub.com/cloudprober/cloudprober/probes/common/tlsinspect/proto/config.proto\x12\x1dcloudprober.probes.tlsinspect\"h\n" +
+	"\x0eTLSInspectConf\x12$\n" +
+	"\x0eca_bundle_file\x18\x01 \x01(\tR\fcaBundleFile\x120\n" +
+	"\x14expected_server_name\x18\x02 \x01(\tR\x12expectedServerNameBCZAgithub.com/cloudprober/cloudprober/probes/common/tlsinspect/proto"
+
+var (
+	file_github_com_cloudprober_cloudprober_probes_common_tlsinspect_proto_config_proto_rawDescOnce sync.Once
+	file_github_com_cloudprober_cloudprober_probes_common_tlsinspect_proto_config_proto_rawDescData []byte
+)
+
+func file_github_com_cloudprober_cloudprober_probes_common_tlsinspect_proto_config_proto_rawDescGZIP() []byte {
+	file_github_com_cloudprober_cloudprober_probes_common_tlsinspect_proto_config_proto_rawDescOnce.Do(func() {
+		file_github_com_cloudprober_cloudprober_probes_common_tlsinspect_proto_config_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_github_com_cloudprober_cloudprober_probes_common_tlsinspect_proto_config_proto_rawDesc), len(file_github_com_cloudprober_cloudprober_probes_common_tlsinspect_proto_config_proto_rawDesc)))
+	})
+	return file_github_com_cloudprober_cloudprober_probes_common_tlsinspect_proto_config_proto_rawDescData
+}
+
+var file_github_com_cloudprober_cloudprober_probes_common_tlsinspect_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_github_com_cloudprober_cloudprober_probes_common_tlsinspect_proto_config_proto_goTypes = []any{
+	(*TLSInspectConf)(nil), // 0: cloudprober.probes.tlsinspect.TLSInspectConf
+}
+var file_github_com_cloudprober_cloudprober_probes_common_tlsinspect_proto_config_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() {
+	file_github_com_cloudprober_cloudprober_probes_common_tlsinspect_proto_config_proto_init()
+}
+func file_github_com_cloudprober_cloudprober_probes_common_tlsinspect_proto_config_proto_init() {
+	if File_github_com_cloudprober_cloudprober_probes_common_tlsinspect_proto_config_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_github_com_cloudprober_cloudprober_probes_common_tlsinspect_proto_config_proto_rawDesc), len(file_github_com_cloudprober_cloudprober_probes_common_tlsinspect_proto_config_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_github_com_cloudprober_cloudprober_probes_common_tlsinspect_proto_config_proto_goTypes,
+		DependencyIndexes: file_github_com_cloudprober_cloudprober_probes_common_tlsinspect_proto_config_proto_depIdxs,
+		MessageInfos:      file_github_com_cloudprober_cloudprober_probes_common_tlsinspect_proto_config_proto_msgTypes,
+	}.Build()
+	File_github_com_cloudprober_cloudprober_probes_common_tlsinspect_proto_config_proto = out.File
+	file_github_com_cloudprober_cloudprober_probes_common_tlsinspect_proto_config_proto_goTypes = nil
+	file_github_com_cloudprober_cloudprober_probes_common_tlsinspect_proto_config_proto_depIdxs = nil
+}
@@ -0,0 +1,174 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tlsinspect implements TLS connection and certificate inspection,
+// shared by the probes that establish TLS connections (HTTP and TCP).
+package tlsinspect
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/cloudprober/cloudprober/metrics"
+	configpb "github.com/cloudprober/cloudprober/probes/common/tlsinspect/proto"
+)
+
+// Inspector inspects TLS connections.
+type Inspector struct {
+	roots      *x509.CertPool // nil means system roots.
+	serverName string
+}
+
+// New returns a new Inspector for the given config.
+func New(c *configpb.TLSInspectConf) (*Inspector, error) {
+	in := &Inspector{
+		serverName: c.GetExpectedServerName(),
+	}
+
+	if c.GetCaBundleFile() != "" {
+		b, err := os.ReadFile(c.GetCaBundleFile())
+		if err != nil {
+			return nil, fmt.Errorf("error reading CA bundle file: %v", err)
+		}
+		in.roots = x509.NewCertPool()
+		if !in.roots.AppendCertsFromPEM(b) {
+			return nil, fmt.Errorf("no certificates found in CA bundle file: %s", c.GetCaBundleFile())
+		}
+	}
+
+	return in, nil
+}
+
+// CertInfo is the information about a certificate in the chain.
+type CertInfo struct {
+	Subject, Issuer string
+	DaysToExpiry    int64
+}
+
+// Result is the result of a TLS connection inspection.
+type Result struct {
+	Version     string
+	CipherSuite string
+	SANMatch    bool
+	ChainValid  bool
+	OCSPStapled bool
+
+	// Certificates presented by the server, leaf first.
+	Certs []CertInfo
+}
+
+func nameString(n pkix.Name) string {
+	if n.CommonName != "" {
+		return n.CommonName
+	}
+	return n.String()
+}
+
+// Inspect inspects the TLS connection state. serverName is used to check
+// leaf certificate's SANs, if it's not configured explicitly and connection
+// state doesn't have it.
+func (in *Inspector) Inspect(cs *tls.ConnectionState, serverName string, now time.Time) *Result {
+	r := &Result{
+		Version:     tls.VersionName(cs.Version),
+		CipherSuite: tls.CipherSuiteName(cs.CipherSuite),
+		OCSPStapled: len(cs.OCSPResponse) > 0,
+	}
+
+	if len(cs.PeerCertificates) == 0 {
+		return r
+	}
+
+	for _, cert := range cs.PeerCertificates {
+		r.Certs = append(r.Certs, CertInfo{
+			Subject:      nameString(cert.Subject),
+			Issuer:       nameString(cert.Issuer),
+			DaysToExpiry: int64(math.Floor(cert.NotAfter.Sub(now).Hours() / 24)),
+		})
+	}
+
+	switch {
+	case in.serverName != "":
+		serverName = in.serverName
+	case cs.ServerName != "":
+		serverName = cs.ServerName
+	}
+
+	leaf := cs.PeerCertificates[0]
+	r.SANMatch = leaf.VerifyHostname(serverName) == nil
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range cs.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         in.roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+	})
+	r.ChainValid = err == nil
+
+	return r
+}
+
+func boolMetric(b bool) *metrics.Int {
+	if b {
+		return metrics.NewInt(1)
+	}
+	return metrics.NewInt(0)
+}
+
+// EventMetrics returns the inspection result as GAUGE EventMetrics. Probe
+// and target labels are expected to be added by the caller (usually by the
+// scheduler).
+func (r *Result) EventMetrics(ts time.Time, ptype string) []*metrics.EventMetrics {
+	if r == nil {
+		return nil
+	}
+
+	em := metrics.NewEventMetrics(ts).
+		AddMetric("tls_san_match", boolMetric(r.SANMatch)).
+		AddMetric("tls_chain_valid", boolMetric(r.ChainValid)).
+		AddMetric("tls_ocsp_stapled", boolMetric(r.OCSPStapled)).
+		AddLabel("ptype", ptype).
+		AddLabel("tls_version", r.Version).
+		AddLabel("tls_cipher", r.CipherSuite)
+	if len(r.Certs) > 0 {
+		em.AddLabel("cert_subject", r.Certs[0].Subject).
+			AddLabel("cert_issuer", r.Certs[0].Issuer)
+	}
+	em.Kind = metrics.GAUGE
+	em.SetNotForAlerting()
+
+	ems := []*metrics.EventMetrics{em}
+
+	for i, cert := range r.Certs {
+		em := metrics.NewEventMetrics(ts).
+			AddMetric("tls_cert_days_to_expiry", metrics.NewInt(cert.DaysToExpiry)).
+			AddLabel("ptype", ptype).
+			AddLabel("cert_index", strconv.Itoa(i)).
+			AddLabel("cert_subject", cert.Subject).
+			AddLabel("cert_issuer", cert.Issuer)
+		em.Kind = metrics.GAUGE
+		em.SetNotForAlerting()
+		ems = append(ems, em)
+	}
+
+	return ems
+}
@@ -0,0 +1,239 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tlsinspect
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cloudprober/cloudprober/metrics"
+	configpb "github.com/cloudprober/cloudprober/probes/common/tlsinspect/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func testCert(t *testing.T, tmpl *x509.Certificate, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	if parent == nil {
+		parent, parentKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
+
+// testChain returns a leaf certificate for "test.example.com" issued by an
+// intermediate CA, and the root CA.
+func testChain(t *testing.T, now time.Time) (leaf, intermediate, root *x509.Certificate) {
+	t.Helper()
+
+	root, rootKey := testCert(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test Root CA"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(365 * 24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil, nil)
+
+	intermediate, intKey := testCert(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(2),
+		Subject:               pkix.Name{CommonName: "Test Intermediate CA"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(100*24*time.Hour + time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, root, rootKey)
+
+	leaf, _ = testCert(t, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "test.example.com"},
+		DNSNames:     []string{"test.example.com"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(30*24*time.Hour + time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, intermediate, intKey)
+
+	return leaf, intermediate, root
+}
+
+func writeCABundle(t *testing.T, cert *x509.Certificate) string {
+	t.Helper()
+	f := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(f, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), 0644))
+	return f
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(&configpb.TLSInspectConf{CaBundleFile: proto.String("/does/not/exist")})
+	assert.Error(t, err)
+
+	f := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(f, []byte("not a cert"), 0644))
+	_, err = New(&configpb.TLSInspectConf{CaBundleFile: proto.String(f)})
+	assert.Error(t, err)
+}
+
+func TestInspect(t *testing.T) {
+	now := time.Now()
+	leaf, intermediate, root := testChain(t, now)
+	caBundle := writeCABundle(t, root)
+
+	cs := &tls.ConnectionState{
+		Version:          tls.VersionTLS13,
+		CipherSuite:      tls.TLS_AES_128_GCM_SHA256,
+		PeerCertificates: []*x509.Certificate{leaf, intermediate},
+		ServerName:       "test.example.com",
+	}
+
+	tests := []struct {
+		name           string
+		conf           *configpb.TLSInspectConf
+		cs             *tls.ConnectionState
+		serverName     string
+		wantSANMatch   bool
+		wantChainValid bool
+	}{
+		{
+			name:           "valid",
+			conf:           &configpb.TLSInspectConf{CaBundleFile: proto.String(caBundle)},
+			cs:             cs,
+			wantSANMatch:   true,
+			wantChainValid: true,
+		},
+		{
+			name:         "system_roots",
+			conf:         &configpb.TLSInspectConf{},
+			cs:           cs,
+			wantSANMatch: true,
+		},
+		{
+			name:           "expected_server_name_mismatch",
+			conf:           &configpb.TLSInspectConf{CaBundleFile: proto.String(caBundle), ExpectedServerName: proto.String("other.example.com")},
+			cs:             cs,
+			wantChainValid: true,
+		},
+		{
+			name: "server_name_from_caller",
+			conf: &configpb.TLSInspectConf{CaBundleFile: proto.String(caBundle)},
+			cs: &tls.ConnectionState{
+				Version:          tls.VersionTLS12,
+				PeerCertificates: []*x509.Certificate{leaf, intermediate},
+			},
+			serverName:     "test.example.com",
+			wantSANMatch:   true,
+			wantChainValid: true,
+		},
+		{
+			name: "missing_intermediate",
+			conf: &configpb.TLSInspectConf{CaBundleFile: proto.String(caBundle)},
+			cs: &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{leaf},
+				ServerName:       "test.example.com",
+			},
+			wantSANMatch: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in, err := New(tt.conf)
+			require.NoError(t, err)
+
+			r := in.Inspect(tt.cs, tt.serverName, now)
+			assert.Equal(t, tt.wantSANMatch, r.SANMatch, "SAN match")
+			assert.Equal(t, tt.wantChainValid, r.ChainValid, "chain valid")
+		})
+	}
+
+	in, _ := New(&configpb.TLSInspectConf{})
+	r := in.Inspect(&tls.ConnectionState{
+		Version:          tls.VersionTLS13,
+		CipherSuite:      tls.TLS_AES_128_GCM_SHA256,
+		PeerCertificates: []*x509.Certificate{leaf, intermediate},
+		OCSPResponse:     []byte("ocsp"),
+	}, "test.example.com", now)
+	assert.Equal(t, "TLS 1.3", r.Version)
+	assert.Equal(t, "TLS_AES_128_GCM_SHA256", r.CipherSuite)
+	assert.True(t, r.OCSPStapled)
+	assert.Equal(t, []CertInfo{
+		{Subject: "test.example.com", Issuer: "Test Intermediate CA", DaysToExpiry: 30},
+		{Subject: "Test Intermediate CA", Issuer: "Test Root CA", DaysToExpiry: 100},
+	}, r.Certs)
+
+	// Expired certificate.
+	r = in.Inspect(cs, "", now.Add(31*24*time.Hour))
+	assert.Equal(t, int64(-1), r.Certs[0].DaysToExpiry)
+}
+
+func TestEventMetrics(t *testing.T) {
+	var nilResult *Result
+	assert.Nil(t, nilResult.EventMetrics(time.Now(), "http"))
+
+	r := &Result{
+		Version:     "TLS 1.3",
+		CipherSuite: "TLS_AES_128_GCM_SHA256",
+		SANMatch:    true,
+		OCSPStapled: true,
+		Certs: []CertInfo{
+			{Subject: "leaf", Issuer: "ca", DaysToExpiry: 30},
+			{Subject: "ca", Issuer: "root", DaysToExpiry: 100},
+		},
+	}
+
+	ems := r.EventMetrics(time.Now(), "tcp")
+	require.Len(t, ems, 3)
+	for _, em := range ems {
+		assert.Equal(t, metrics.Kind(metrics.GAUGE), em.Kind)
+		assert.Equal(t, "tcp", em.Label("ptype"))
+	}
+
+	em := ems[0]
+	assert.Equal(t, "TLS 1.3", em.Label("tls_version"))
+	assert.Equal(t, "TLS_AES_128_GCM_SHA256", em.Label("tls_cipher"))
+	assert.Equal(t, "leaf", em.Label("cert_subject"))
+	assert.Equal(t, "ca", em.Label("cert_issuer"))
+	assert.Equal(t, int64(1), em.Metric("tls_san_match").(metrics.NumValue).Int64())
+	assert.Equal(t, int64(0), em.Metric("tls_chain_valid").(metrics.NumValue).Int64())
+	assert.Equal(t, int64(1), em.Metric("tls_ocsp_stapled").(metrics.NumValue).Int64())
+
+	for i, want := range []struct {
+		index, subject string
+		days           int64
+	}{{"0", "leaf", 30}, {"1", "ca", 100}} {
+		em := ems[i+1]
+		assert.Equal(t, want.index, em.Label("cert_index"))
+		assert.Equal(t, want.subject, em.Label("cert_subject"))
+		assert.Equal(t, want.days, em.Metric("tls_cert_days_to_expiry").(metrics.NumValue).Int64())
+	}
+}
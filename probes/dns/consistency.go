@@ -0,0 +1,75 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dns
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// targetAnswers records the latest answers received from a target.
+type targetAnswers struct {
+	key string
+	ts  time.Time
+}
+
+// answersKey returns a string that identifies the response's answers: rcode
+// and the sorted record data of the answer records. Signatures are ignored
+// as they may differ between servers even for the same data.
+func answersKey(resp *dns.Msg) string {
+	var rdata []string
+	for _, rr := range resp.Answer {
+		if rr == nil || rr.Header().Rrtype == dns.TypeRRSIG {
+			continue
+		}
+		h := rr.Header()
+		rdata = append(rdata, strings.ToLower(h.Name+" "+dns.TypeToString[h.Rrtype]+strings.TrimPrefix(rr.String(), h.String())))
+	}
+	sort.Strings(rdata)
+	return dns.RcodeToString[resp.Rcode] + "|" + strings.Join(slices.Compact(rdata), "|")
+}
+
+// checkConsistency records the answers from the target and compares them with
+// the latest answers from the other targets. It returns the targets whose
+// answers differ. Answers older than two probe intervals are not considered,
+// to skip the targets that are gone or are not responding anymore.
+func (p *Probe) checkConsistency(target string, resp *dns.Msg) []string {
+	key, now := answersKey(resp), time.Now()
+
+	p.answersMu.Lock()
+	defer p.answersMu.Unlock()
+
+	p.answers[target] = &targetAnswers{key: key, ts: now}
+
+	var mismatched []string
+	for t, ta := range p.answers {
+		if t == target {
+			continue
+		}
+		if now.Sub(ta.ts) > 2*p.opts.Interval {
+			delete(p.answers, t)
+			continue
+		}
+		if ta.key != key {
+			mismatched = append(mismatched, t)
+		}
+	}
+	sort.Strings(mismatched)
+	return mismatched
+}
@@ -0,0 +1,123 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dns
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/probes/common/sched"
+	configpb "github.com/cloudprober/cloudprober/probes/dns/proto"
+	"github.com/cloudprober/cloudprober/probes/options"
+	"github.com/cloudprober/cloudprober/targets"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// answersClient returns the configured answers for each target.
+type answersClient struct {
+	answers map[string][]string // Keyed by target (host:port).
+}
+
+func (c *answersClient) ExchangeContext(_ context.Context, in *dns.Msg, target string) (*dns.Msg, time.Duration, error) {
+	out := new(dns.Msg)
+	out.SetReply(in)
+	for _, a := range c.answers[target] {
+		rr, _ := dns.NewRR(in.Question[0].Name + " 300 IN A " + a)
+		out.Answer = append(out.Answer, rr)
+	}
+	return out, time.Millisecond, nil
+}
+func (*answersClient) setTimeout(time.Duration)      {}
+func (*answersClient) setSourceIP(net.IP)            {}
+func (*answersClient) setDNSProto(configpb.DNSProto) {}
+
+func TestAnswersKey(t *testing.T) {
+	msg := func(rcode int, answers ...string) *dns.Msg {
+		m := &dns.Msg{}
+		m.Rcode = rcode
+		for _, a := range answers {
+			rr, _ := dns.NewRR(a)
+			m.Answer = append(m.Answer, rr)
+		}
+		return m
+	}
+
+	a1 := "www.example.com. 300 IN A 192.168.1.1"
+	a2 := "www.example.com. 60 IN A 192.168.1.2"
+	sig := "www.example.com. 300 IN RRSIG A 13 3 300 20300101000000 20200101000000 1234 example.com. AAAA"
+
+	assert.Equal(t, answersKey(msg(0, a1, a2)), answersKey(msg(0, a2, a1, sig)), "order, TTL and signatures should not matter")
+	assert.NotEqual(t, answersKey(msg(0, a1)), answersKey(msg(0, a1, a2)))
+	assert.NotEqual(t, answersKey(msg(dns.RcodeSuccess)), answersKey(msg(dns.RcodeNameError)))
+}
+
+func TestCheckConsistency(t *testing.T) {
+	client := &answersClient{answers: map[string][]string{
+		"10.0.0.1:53": {"192.168.1.1", "192.168.1.2"},
+		"10.0.0.2:53": {"192.168.1.2", "192.168.1.1"},
+		"10.0.0.3:53": {"192.168.1.1"},
+	}}
+
+	p := &Probe{}
+	opts := &options.Options{
+		Targets:  targets.StaticTargets("10.0.0.1,10.0.0.2,10.0.0.3"),
+		Interval: 2 * time.Second,
+		Timeout:  time.Second,
+		ProbeConf: &configpb.ProbeConf{
+			ResolvedDomain:   proto.String("www.example.com"),
+			QueryType:        configpb.QueryType_A.Enum(),
+			CheckConsistency: proto.Bool(true),
+		},
+	}
+	require.NoError(t, p.Init("dns_consistency_test", opts))
+	p.client = client
+
+	runProbe := func(target string) *metrics.EventMetrics {
+		t.Helper()
+		runReq := &sched.RunProbeForTargetRequest{Target: endpoint.Endpoint{Name: target}, LastRun: &sched.LastRunResult{}}
+		p.runProbe(context.Background(), runReq)
+		assert.True(t, runReq.LastRun.Success, "mismatch shouldn't fail the probe")
+		return runReq.Result.Metrics(time.Now(), 0, p.opts)[0]
+	}
+	mismatch := func(em *metrics.EventMetrics) int64 {
+		return em.Metric("answer_mismatch").(metrics.NumValue).Int64()
+	}
+
+	assert.Equal(t, int64(0), mismatch(runProbe("10.0.0.1")), "first target")
+	assert.Equal(t, int64(0), mismatch(runProbe("10.0.0.2")), "same answers, different order")
+	assert.Equal(t, int64(1), mismatch(runProbe("10.0.0.3")), "different answers")
+	assert.Equal(t, []string{"10.0.0.3:53"}, p.checkConsistency("10.0.0.1:53", queryAnswers(t, p, client, "10.0.0.1:53")))
+
+	// Stale answers are not considered.
+	p.answers["10.0.0.3:53"].ts = time.Now().Add(-time.Minute)
+	assert.Empty(t, p.checkConsistency("10.0.0.1:53", queryAnswers(t, p, client, "10.0.0.1:53")))
+	assert.NotContains(t, p.answers, "10.0.0.3:53")
+}
+
+func queryAnswers(t *testing.T, p *Probe, c *answersClient, target string) *dns.Msg {
+	t.Helper()
+	msg := new(dns.Msg)
+	msg.SetQuestion(p.fqdn, p.queryType)
+	resp, _, err := c.ExchangeContext(context.Background(), msg, target)
+	require.NoError(t, err)
+	return resp
+}
@@ -25,6 +25,7 @@ package dns
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -34,6 +35,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cloudprober/cloudprober/common/tlsconfig"
 	"github.com/cloudprober/cloudprober/internal/validators"
 	"github.com/cloudprober/cloudprober/logger"
 	"github.com/cloudprober/cloudprober/metrics"
@@ -99,6 +101,14 @@ type Probe struct {
 	queryClass uint16
 	fqdn       string
 	client     Client
+	dnssec     *dnssecValidator
+
+	// Set if a validator checks the rcode itself.
+	validatorsCheckRcode bool
+
+	// Latest answers from all targets, used for the consistency check.
+	answersMu sync.Mutex
+	answers   map[string]*targetAnswers
 }
 
 // probeRunResult captures the results of a single probe run. The way we work with
@@ -111,6 +121,13 @@ type probeRunResult struct {
 	latency           metrics.LatencyValue
 	timeouts          metrics.Int
 	validationFailure *metrics.Map[int64]
+
+	// DNSSEC validation results, only if DNSSEC validation is enabled.
+	dnssecStatus       *metrics.Map[int64]
+	dnssecSigExpirySec int64
+
+	// Set only if consistency check is enabled.
+	answerMismatch *metrics.Int
 }
 
 func (p *Probe) newResult() sched.ProbeResult {
@@ -120,6 +137,18 @@ func (p *Probe) newResult() sched.ProbeResult {
 		result.validationFailure = validators.ValidationFailureMap(p.opts.Validators)
 	}
 
+	if p.c.GetCheckConsistency() {
+		result.answerMismatch = metrics.NewInt(0)
+	}
+
+	result.dnssecSigExpirySec = -1
+	if p.dnssec != nil {
+		result.dnssecStatus = metrics.NewMap("status")
+		for _, status := range []string{dnssecSecure, dnssecInsecure, dnssecBogus, dnssecIndeterminate} {
+			result.dnssecStatus.IncKeyBy(status, 0)
+		}
+	}
+
 	if p.opts.LatencyDist != nil {
 		result.latency = p.opts.LatencyDist.CloneDist()
 	} else {
@@ -142,7 +171,28 @@ func (prr probeRunResult) Metrics(ts time.Time, _ int64, opts *options.Options)
 		em.AddMetric("validation_failure", prr.validationFailure)
 	}
 
-	return []*metrics.EventMetrics{em}
+	if prr.answerMismatch != nil {
+		em.AddMetric("answer_mismatch", prr.answerMismatch.Clone())
+	}
+
+	if prr.dnssecStatus != nil {
+		em.AddMetric("dnssec_validation", prr.dnssecStatus.Clone())
+	}
+
+	ems := []*metrics.EventMetrics{em}
+
+	// Earliest RRSIG expiry is exported in an independent EM as it's a GAUGE
+	// metric.
+	if prr.dnssecSigExpirySec >= 0 {
+		em := metrics.NewEventMetrics(ts).
+			AddMetric("dnssec_earliest_sig_expiry_sec", metrics.NewInt(prr.dnssecSigExpirySec))
+		em.Kind = metrics.GAUGE
+		em.SetNotForAlerting()
+		em.AddLabel("ptype", "dns")
+		ems = append(ems, em)
+	}
+
+	return ems
 }
 
 // Init initializes the probe with the given params.
@@ -173,6 +223,15 @@ func (p *Probe) Init(name string, opts *options.Options) error {
 
 	p.targets = p.opts.Targets.ListEndpoints()
 
+	for _, v := range p.opts.Validators {
+		if v.ChecksStatus {
+			p.validatorsCheckRcode = true
+		}
+	}
+	if p.c.GetCheckConsistency() {
+		p.answers = make(map[string]*targetAnswers)
+	}
+
 	queryType := p.c.GetQueryType()
 	if queryType == configpb.QueryType_NONE || int32(queryType) >= int32(dns.TypeReserved) {
 		return fmt.Errorf("dns_probe(%v): invalid query type %v", name, queryType)
@@ -181,11 +240,26 @@ func (p *Probe) Init(name string, opts *options.Options) error {
 	p.queryClass = uint16(p.c.GetQueryClass())
 	p.fqdn = dns.Fqdn(p.c.GetResolvedDomain())
 
+	var tlsConfig *tls.Config
+	if p.c.GetTlsConfig() != nil {
+		tlsConfig = &tls.Config{}
+		if err := tlsconfig.UpdateTLSConfig(tlsConfig, p.c.GetTlsConfig()); err != nil {
+			return fmt.Errorf("tls_config error: %v", err)
+		}
+	}
+
 	// I believe the client is safe for concurrent use by multiple goroutines
 	// (although the documentation doesn't explicitly say so). It uses locks
 	// internally and the underlying net.Conn declares that multiple goroutines
 	// may invoke methods on a net.Conn simultaneously.
-	p.client = new(clientImpl)
+	switch p.c.GetDnsProto() {
+	case configpb.DNSProto_DOQ:
+		p.client = newDoQClient(tlsConfig)
+	case configpb.DNSProto_DOH:
+		p.client = newDoHClient(p.c, tlsConfig)
+	default:
+		p.client = &clientImpl{Client: dns.Client{TLSConfig: tlsConfig}}
+	}
 	if p.opts.SourceIP != nil {
 		p.client.setSourceIP(p.opts.SourceIP)
 	}
@@ -198,20 +272,33 @@ func (p *Probe) Init(name string, opts *options.Options) error {
 	// Set DNS Protocol to use
 	p.client.setDNSProto(p.c.GetDnsProto())
 
+	if p.c.GetDnssec() != nil {
+		v, err := newDNSSECValidator(p.c.GetDnssec(), p.client)
+		if err != nil {
+			return fmt.Errorf("dnssec config error: %v", err)
+		}
+		p.dnssec = v
+	}
+
 	return nil
 }
 
 // Return true if the underlying error indicates a dns.Client timeout.
 // In our case, we're using the ReadTimeout- time until response is read.
 func isClientTimeout(err error) bool {
-	e, ok := err.(*net.OpError)
-	return ok && e != nil && e.Timeout()
+	if e, ok := err.(*net.OpError); ok {
+		return e != nil && e.Timeout()
+	}
+	// DoH client returns *url.Error, and DoQ client returns QUIC errors, both
+	// implement net.Error.
+	var e net.Error
+	return errors.As(err, &e) && e.Timeout()
 }
 
 // validateResponse checks status code and answer section for correctness.
 // In case of validation failures, it also updates the result structure.
 func (p *Probe) validateResponse(resp *dns.Msg, result *probeRunResult, l *logger.Logger) error {
-	if resp == nil || resp.Rcode != dns.RcodeSuccess {
+	if resp == nil || (resp.Rcode != dns.RcodeSuccess && !p.validatorsCheckRcode) {
 		return fmt.Errorf("error in response %v", resp.String())
 	}
 
@@ -231,7 +318,7 @@ func (p *Probe) validateResponse(resp *dns.Msg, result *probeRunResult, l *logge
 		}
 		respBytes := []byte(strings.Join(answers, "\n"))
 
-		failedValidations := validators.RunValidators(p.opts.Validators, &validators.Input{ResponseBody: respBytes}, result.validationFailure, l)
+		failedValidations := validators.RunValidators(p.opts.Validators, &validators.Input{Response: resp, ResponseBody: respBytes}, result.validationFailure, l)
 		if len(failedValidations) > 0 {
 			return fmt.Errorf("failed validations: %s", strings.Join(failedValidations, ","))
 		}
@@ -247,9 +334,18 @@ func (p *Probe) doDNSRequest(ctx context.Context, target string, result *probeRu
 	msg := new(dns.Msg)
 	msg.SetQuestion(p.fqdn, p.queryType)
 	msg.Question[0].Qclass = p.queryClass
+	if p.dnssec != nil {
+		prepareQuery(msg)
+	}
 
 	resp, latency, err := p.client.ExchangeContext(ctx, msg, target)
 
+	// DNSSEC validation sends more queries, we do it before locking the result.
+	var dnssecRes *dnssecResult
+	if err == nil && p.dnssec != nil && resp != nil && resp.Rcode == dns.RcodeSuccess {
+		dnssecRes = p.dnssec.validate(ctx, resp, target)
+	}
+
 	if resultMu != nil {
 		resultMu.Lock()
 		defer resultMu.Unlock()
@@ -265,11 +361,29 @@ func (p *Probe) doDNSRequest(ctx context.Context, target string, result *probeRu
 		return err
 	}
 
+	if result.answerMismatch != nil {
+		if mismatched := p.checkConsistency(target, resp); len(mismatched) > 0 {
+			l.Warningf("Answers differ from the answers from: %s", strings.Join(mismatched, ", "))
+			result.answerMismatch.Inc()
+		}
+	}
+
 	if err := p.validateResponse(resp, result, l); err != nil {
 		l.Error(err.Error())
 		return err
 	}
 
+	if dnssecRes != nil {
+		result.dnssecStatus.IncKey(dnssecRes.status)
+		if !dnssecRes.earliestExpiry.IsZero() {
+			result.dnssecSigExpirySec = max(0, int64(time.Until(dnssecRes.earliestExpiry).Seconds()))
+		}
+		if dnssecRes.err != nil {
+			l.Error(dnssecRes.err.Error())
+			return dnssecRes.err
+		}
+	}
+
 	result.success.Inc()
 	result.latency.AddFloat64(latency.Seconds() / p.opts.LatencyUnit.Seconds())
 	return nil
@@ -283,6 +397,12 @@ func (p *Probe) runProbe(ctx context.Context, runReq *sched.RunProbeForTargetReq
 	startSuccess := result.success.Int64()
 
 	port := defaultPort
+	switch p.c.GetDnsProto() {
+	case configpb.DNSProto_DOH:
+		port = defaultDoHPort
+	case configpb.DNSProto_DOQ:
+		port = defaultDoQPort
+	}
 	if target.Port != 0 {
 		port = target.Port
 	}
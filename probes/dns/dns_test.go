@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/cloudprober/cloudprober/internal/validators"
+	dnsvalidatorpb "github.com/cloudprober/cloudprober/internal/validators/dns/proto"
 	validatorpb "github.com/cloudprober/cloudprober/internal/validators/proto"
 	"github.com/cloudprober/cloudprober/logger"
 	"github.com/cloudprober/cloudprober/probes/common/sched"
@@ -328,3 +329,46 @@ func TestValidator(t *testing.T) {
 		runProbeAndVerify(t, tst.name, p, 1, tst.successCt)
 	}
 }
+
+func TestDNSValidatorRcode(t *testing.T) {
+	for _, tst := range []struct {
+		name      string
+		domain    string
+		rcode     string
+		successCt int64
+	}{
+		{"nxdomain_expected", questionBadDomain, "NXDOMAIN", 1},
+		{"noerror_unexpected", "test.com", "NXDOMAIN", 0},
+		{"nxdomain_without_rcode", questionBadDomain, "", 0},
+	} {
+		t.Run(tst.name, func(t *testing.T) {
+			dnsValidator := &dnsvalidatorpb.Validator{Rcode: tst.rcode}
+			if tst.rcode == "" {
+				dnsValidator.MaxAnswers = proto.Int32(1)
+			}
+			vs, err := validators.Init([]*validatorpb.Validator{
+				{
+					Name: tst.name,
+					Type: &validatorpb.Validator_DnsValidator{DnsValidator: dnsValidator},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Error initializing validator: %v", err)
+			}
+			p := &Probe{}
+			opts := &options.Options{
+				Targets:  targets.StaticTargets("8.8.8.8"),
+				Interval: 2 * time.Second,
+				Timeout:  time.Second,
+				ProbeConf: &configpb.ProbeConf{
+					ResolvedDomain: proto.String(tst.domain),
+				},
+				Validators: vs,
+			}
+			if err := p.Init("dns_probe_rcode_"+tst.name, opts); err != nil {
+				t.Fatalf("Error creating probe: %v", err)
+			}
+			runProbeAndVerify(t, tst.name, p, 1, tst.successCt)
+		})
+	}
+}
@@ -0,0 +1,464 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	configpb "github.com/cloudprober/cloudprober/probes/dns/proto"
+	"github.com/miekg/dns"
+)
+
+// DNSSEC validation statuses (RFC 4033, section 5).
+const (
+	dnssecSecure        = "secure"
+	dnssecInsecure      = "insecure"
+	dnssecBogus         = "bogus"
+	dnssecIndeterminate = "indeterminate"
+)
+
+// Root zone trust anchors (KSK-2017 and KSK-2024), as published by IANA at
+// https://data.iana.org/root-anchors/root-anchors.xml.
+var rootTrustAnchors = []string{
+	". IN DS 20326 8 2 E06D44B80B8F1D39A95C0B0D7C65D08458E880409BBB683457104237C7F8EC8D",
+	". IN DS 38696 8 2 683D2D0ACB8C9B712A1948B27F741219298D0A450D612C483AF444A4C0FB2B16",
+}
+
+// maxChainZones limits the number of zones we look at while validating an
+// answer.
+const maxChainZones = 16
+
+const ednsBufSize = 4096
+
+// Errors returned while walking the chain of trust are classified by these
+// sentinel errors. Any other error means that the answer is bogus.
+var (
+	errInsecure      = errors.New("insecure")
+	errIndeterminate = errors.New("indeterminate")
+)
+
+type dnssecValidator struct {
+	client         Client
+	anchors        map[string][]*dns.DS // Keyed by lower-cased zone name.
+	allowInsecure  bool
+	minSigValidity time.Duration
+
+	now func() time.Time
+}
+
+// dnssecResult is the result of a DNSSEC validation.
+type dnssecResult struct {
+	status         string
+	earliestExpiry time.Time
+	err            error
+}
+
+func newDNSSECValidator(c *configpb.DNSSECConfig, client Client) (*dnssecValidator, error) {
+	v := &dnssecValidator{
+		client:         client,
+		anchors:        make(map[string][]*dns.DS),
+		allowInsecure:  c.GetAllowInsecure(),
+		minSigValidity: time.Duration(c.GetMinSigValiditySec()) * time.Second,
+		now:            time.Now,
+	}
+
+	anchors := c.GetTrustAnchor()
+	if len(anchors) == 0 {
+		anchors = rootTrustAnchors
+	}
+	for _, s := range anchors {
+		rr, err := dns.NewRR(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trust anchor %q: %v", s, err)
+		}
+		var ds *dns.DS
+		switch rr := rr.(type) {
+		case *dns.DS:
+			ds = rr
+		case *dns.DNSKEY:
+			ds = rr.ToDS(dns.SHA256)
+		default:
+			return nil, fmt.Errorf("invalid trust anchor %q: not a DS or DNSKEY record", s)
+		}
+		if ds == nil {
+			return nil, fmt.Errorf("invalid trust anchor %q", s)
+		}
+		zone := strings.ToLower(ds.Header().Name)
+		v.anchors[zone] = append(v.anchors[zone], ds)
+	}
+
+	return v, nil
+}
+
+// prepareQuery sets the DO and CD bits in the query. We set the CD bit to
+// get the answer even if the server fails to validate it, so that we can
+// report bogus answers ourselves.
+func prepareQuery(msg *dns.Msg) {
+	msg.SetEdns0(ednsBufSize, true)
+	msg.CheckingDisabled = true
+}
+
+// rrsets groups the given records by owner name and type. Signatures are
+// returned separately, grouped by owner name and the type they cover.
+func rrsets(rrs []dns.RR) (sets map[string][]dns.RR, sigs map[string][]*dns.RRSIG, keys []string) {
+	sets, sigs = make(map[string][]dns.RR), make(map[string][]*dns.RRSIG)
+	for _, rr := range rrs {
+		name := strings.ToLower(rr.Header().Name)
+		if sig, ok := rr.(*dns.RRSIG); ok {
+			k := name + "/" + dns.TypeToString[sig.TypeCovered]
+			sigs[k] = append(sigs[k], sig)
+			continue
+		}
+		k := name + "/" + dns.TypeToString[rr.Header().Rrtype]
+		if sets[k] == nil {
+			keys = append(keys, k)
+		}
+		sets[k] = append(sets[k], rr)
+	}
+	return sets, sigs, keys
+}
+
+// chain tracks the state of a single validation.
+type chain struct {
+	target         string
+	zoneKeys       map[string][]*dns.DNSKEY // Validated keys, by zone.
+	earliestExpiry time.Time
+}
+
+func (v *dnssecValidator) validate(ctx context.Context, resp *dns.Msg, target string) *dnssecResult {
+	ch := &chain{target: target, zoneKeys: make(map[string][]*dns.DNSKEY)}
+	err := v.validateAnswer(ctx, resp, ch)
+
+	res := &dnssecResult{status: dnssecSecure, earliestExpiry: ch.earliestExpiry}
+	switch {
+	case err == nil:
+		if v.minSigValidity > 0 && ch.earliestExpiry.Sub(v.now()) < v.minSigValidity {
+			res.err = fmt.Errorf("dnssec: signature expires at %s, sooner than min_sig_validity_sec (%s)", ch.earliestExpiry.UTC().Format(time.RFC3339), v.minSigValidity)
+		}
+	case errors.Is(err, errInsecure):
+		res.status = dnssecInsecure
+		if !v.allowInsecure {
+			res.err = fmt.Errorf("dnssec: %v", err)
+		}
+	case errors.Is(err, errIndeterminate):
+		res.status, res.err = dnssecIndeterminate, fmt.Errorf("dnssec: %v", err)
+	default:
+		res.status, res.err = dnssecBogus, fmt.Errorf("dnssec: bogus: %v", err)
+	}
+	return res
+}
+
+func (v *dnssecValidator) validateAnswer(ctx context.Context, resp *dns.Msg, ch *chain) error {
+	sets, sigs, keys := rrsets(resp.Answer)
+	if len(keys) == 0 {
+		return fmt.Errorf("%w: no answer records to validate", errIndeterminate)
+	}
+	for _, k := range keys {
+		if err := v.validateRRSet(ctx, sets[k], sigs[k], ch); err != nil {
+			return fmt.Errorf("%s: %w", k, err)
+		}
+	}
+	return nil
+}
+
+// validateRRSet verifies that at least one of the given signatures over the
+// rrset is valid and is made by a trusted key.
+func (v *dnssecValidator) validateRRSet(ctx context.Context, rrset []dns.RR, sigs []*dns.RRSIG, ch *chain) error {
+	if len(sigs) == 0 {
+		// Unsigned rrset is insecure only if its zone is provably insecure.
+		zone, err := v.zoneCut(ctx, rrset[0].Header().Name, ch)
+		if err != nil {
+			return err
+		}
+		if err := v.insecureZone(ctx, zone, ch); err != nil {
+			return fmt.Errorf("no signatures: %w", err)
+		}
+		return fmt.Errorf("no signatures, but zone %s is signed", zone)
+	}
+
+	signer := sigs[0].SignerName
+	for _, sig := range sigs[1:] {
+		if !strings.EqualFold(sig.SignerName, signer) {
+			return fmt.Errorf("signatures from multiple signers: %s, %s", signer, sig.SignerName)
+		}
+	}
+	if !dns.IsSubDomain(signer, rrset[0].Header().Name) {
+		return fmt.Errorf("signer %s is not authoritative for %s", signer, rrset[0].Header().Name)
+	}
+
+	keys, err := v.zoneKeys(ctx, signer, ch)
+	if err != nil {
+		return err
+	}
+	return v.verify(rrset, sigs, keys, ch)
+}
+
+// verify verifies rrset signatures using the given keys. It's enough for one
+// signature to verify.
+func (v *dnssecValidator) verify(rrset []dns.RR, sigs []*dns.RRSIG, keys []*dns.DNSKEY, ch *chain) error {
+	var errs []error
+	for _, sig := range sigs {
+		if !sig.ValidityPeriod(v.now()) {
+			errs = append(errs, fmt.Errorf("signature (key tag %d) is outside its validity period", sig.KeyTag))
+			continue
+		}
+		for _, key := range keys {
+			if key.KeyTag() != sig.KeyTag || key.Algorithm != sig.Algorithm {
+				continue
+			}
+			if err := sig.Verify(key, rrset); err != nil {
+				errs = append(errs, fmt.Errorf("signature (key tag %d) verification failed: %v", sig.KeyTag, err))
+				continue
+			}
+			expiry := time.Unix(int64(sig.Expiration), 0)
+			if ch.earliestExpiry.IsZero() || expiry.Before(ch.earliestExpiry) {
+				ch.earliestExpiry = expiry
+			}
+			return nil
+		}
+	}
+	if len(errs) == 0 {
+		return errors.New("no signature made by a trusted key")
+	}
+	return errors.Join(errs...)
+}
+
+func (v *dnssecValidator) exchange(ctx context.Context, name string, qtype uint16, ch *chain) (*dns.Msg, error) {
+	msg := new(dns.Msg)
+	msg.SetQuestion(name, qtype)
+	prepareQuery(msg)
+
+	resp, _, err := v.client.ExchangeContext(ctx, msg, ch.target)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error querying %s %s: %v", errIndeterminate, name, dns.TypeToString[qtype], err)
+	}
+	if resp.Rcode != dns.RcodeSuccess {
+		return nil, fmt.Errorf("%w: error querying %s %s: rcode %s", errIndeterminate, name, dns.TypeToString[qtype], dns.RcodeToString[resp.Rcode])
+	}
+	return resp, nil
+}
+
+func (v *dnssecValidator) query(ctx context.Context, name string, qtype uint16, ch *chain) (sets map[string][]dns.RR, sigs map[string][]*dns.RRSIG, err error) {
+	resp, err := v.exchange(ctx, name, qtype, ch)
+	if err != nil {
+		return nil, nil, err
+	}
+	sets, sigs, _ = rrsets(resp.Answer)
+	return sets, sigs, nil
+}
+
+// zoneCut returns the apex of the zone that the name belongs to. We find it
+// using the SOA record, which is returned in the answer section if name is
+// the zone apex, and in the authority section otherwise.
+func (v *dnssecValidator) zoneCut(ctx context.Context, name string, ch *chain) (string, error) {
+	resp, err := v.exchange(ctx, dns.Fqdn(name), dns.TypeSOA, ch)
+	if err != nil {
+		return "", err
+	}
+	for _, rr := range append(resp.Answer, resp.Ns...) {
+		if soa, ok := rr.(*dns.SOA); ok && dns.IsSubDomain(soa.Hdr.Name, name) {
+			return strings.ToLower(dns.Fqdn(soa.Hdr.Name)), nil
+		}
+	}
+	return "", fmt.Errorf("%w: no SOA record for %s", errIndeterminate, name)
+}
+
+// insecureZone returns an errInsecure error if the zone is provably
+// insecure, i.e. there is no chain of trust to it. It returns a nil error if
+// zone is signed, i.e. it has a trust anchor or validated DS records.
+func (v *dnssecValidator) insecureZone(ctx context.Context, zone string, ch *chain) error {
+	if _, ok := v.anchors[zone]; ok {
+		return nil
+	}
+	_, err := v.parentDS(ctx, zone, ch)
+	return err
+}
+
+// zoneKeys returns the validated DNSKEY records of the given zone. Zone's
+// keys are validated using the trust anchor, if zone has one, or using the
+// DS records from the parent zone, which are validated recursively.
+func (v *dnssecValidator) zoneKeys(ctx context.Context, zone string, ch *chain) ([]*dns.DNSKEY, error) {
+	zone = strings.ToLower(dns.Fqdn(zone))
+	if keys, ok := ch.zoneKeys[zone]; ok {
+		return keys, nil
+	}
+	if len(ch.zoneKeys) >= maxChainZones {
+		return nil, fmt.Errorf("too many zones in the chain of trust (> %d)", maxChainZones)
+	}
+
+	sets, sigs, err := v.query(ctx, zone, dns.TypeDNSKEY, ch)
+	if err != nil {
+		return nil, err
+	}
+	k := zone + "/DNSKEY"
+	var keys []*dns.DNSKEY
+	for _, rr := range sets[k] {
+		if key, ok := rr.(*dns.DNSKEY); ok && key.Flags&dns.ZONE != 0 {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no DNSKEY records for signer zone %s", zone)
+	}
+
+	dsSet, ok := v.anchors[zone]
+	if !ok {
+		if dsSet, err = v.parentDS(ctx, zone, ch); err != nil {
+			return nil, err
+		}
+	}
+
+	// Keys that match the DS records are used to validate the DNSKEY rrset.
+	var trusted []*dns.DNSKEY
+	for _, key := range keys {
+		for _, ds := range dsSet {
+			if key.KeyTag() != ds.KeyTag || key.Algorithm != ds.Algorithm {
+				continue
+			}
+			if kds := key.ToDS(ds.DigestType); kds != nil && strings.EqualFold(kds.Digest, ds.Digest) {
+				trusted = append(trusted, key)
+				break
+			}
+		}
+	}
+	if len(trusted) == 0 {
+		return nil, fmt.Errorf("no DNSKEY for zone %s matches its DS records", zone)
+	}
+
+	if err := v.verify(sets[k], sigs[k], trusted, ch); err != nil {
+		return nil, fmt.Errorf("DNSKEY rrset for zone %s: %w", zone, err)
+	}
+	ch.zoneKeys[zone] = keys
+	return keys, nil
+}
+
+// parentDS returns the validated DS records for the zone. If there are no DS
+// records, it returns an errInsecure error if their absence is proven (see
+// noDS), and a bogus error otherwise.
+func (v *dnssecValidator) parentDS(ctx context.Context, zone string, ch *chain) ([]*dns.DS, error) {
+	if zone == "." {
+		return nil, errors.New("no trust anchor for the root zone")
+	}
+
+	resp, err := v.exchange(ctx, zone, dns.TypeDS, ch)
+	if err != nil {
+		return nil, err
+	}
+	sets, sigs, _ := rrsets(resp.Answer)
+	k := zone + "/DS"
+	if len(sets[k]) == 0 {
+		return nil, v.noDS(ctx, zone, resp, ch)
+	}
+	if len(sigs[k]) == 0 {
+		// Parent zone is signed (we got here through its signatures), so
+		// unsigned DS records mean bogus, not insecure.
+		return nil, fmt.Errorf("DS rrset for zone %s: no signatures", zone)
+	}
+	if err := v.validateRRSet(ctx, sets[k], sigs[k], ch); err != nil {
+		return nil, fmt.Errorf("DS rrset for zone %s: %w", zone, err)
+	}
+
+	var dsSet []*dns.DS
+	for _, rr := range sets[k] {
+		if ds, ok := rr.(*dns.DS); ok {
+			dsSet = append(dsSet, ds)
+		}
+	}
+	return dsSet, nil
+}
+
+// noDS checks the proof of absence of DS records for the zone, in the DS
+// query response. Absence of DS records is proven if:
+//   - Parent zone returns validated NSEC or NSEC3 records showing that zone is
+//     a delegation point without DS records, or that it's covered by an
+//     opt-out NSEC3 record (RFC 5155, section 6), or
+//   - Parent zone is unsigned, and it's provably insecure itself.
+//
+// It returns an errInsecure error if absence is proven, and a bogus error
+// otherwise.
+func (v *dnssecValidator) noDS(ctx context.Context, zone string, resp *dns.Msg, ch *chain) error {
+	sets, sigs, keys := rrsets(resp.Ns)
+	signedDenial := false
+	for _, k := range keys {
+		rrset := sets[k]
+		if t := rrset[0].Header().Rrtype; t != dns.TypeNSEC && t != dns.TypeNSEC3 {
+			continue
+		}
+		// Unsigned NSEC records don't prove anything.
+		if len(sigs[k]) == 0 {
+			continue
+		}
+		if err := v.validateRRSet(ctx, rrset, sigs[k], ch); err != nil {
+			return fmt.Errorf("%s: %w", k, err)
+		}
+		signedDenial = true
+		for _, rr := range rrset {
+			if provesNoDS(rr, zone) {
+				return fmt.Errorf("%w: no DS records for zone %s", errInsecure, zone)
+			}
+		}
+	}
+	if signedDenial {
+		return fmt.Errorf("no DS records for zone %s, and NSEC records don't prove their absence", zone)
+	}
+
+	// No signed denial of existence, that's fine only if the parent zone is
+	// unsigned.
+	off, end := dns.NextLabel(zone, 0)
+	if end {
+		return fmt.Errorf("no DS records for zone %s, and no proof of their absence", zone)
+	}
+	parent, err := v.zoneCut(ctx, zone[off:], ch)
+	if err != nil {
+		return err
+	}
+	if err := v.insecureZone(ctx, parent, ch); err != nil {
+		if errors.Is(err, errInsecure) {
+			return fmt.Errorf("%w: no DS records for zone %s, and parent zone %s is insecure", errInsecure, zone, parent)
+		}
+		return err
+	}
+	return fmt.Errorf("no DS records for zone %s, and no proof of their absence from the signed parent zone %s", zone, parent)
+}
+
+// provesNoDS returns true if the NSEC or NSEC3 record proves that there are
+// no DS records for the zone.
+func provesNoDS(rr dns.RR, zone string) bool {
+	var types []uint16
+	switch rr := rr.(type) {
+	case *dns.NSEC:
+		if !strings.EqualFold(rr.Hdr.Name, zone) {
+			return false
+		}
+		types = rr.TypeBitMap
+	case *dns.NSEC3:
+		if !rr.Match(zone) {
+			// Opt-out NSEC3 covering the zone means that the zone may be an
+			// unsigned delegation.
+			return rr.Flags&1 == 1 && rr.Cover(zone)
+		}
+		types = rr.TypeBitMap
+	default:
+		return false
+	}
+	// Zone should be a delegation point (NS without SOA) without DS records.
+	// SOA would mean that the record comes from the child zone.
+	return slices.Contains(types, dns.TypeNS) && !slices.Contains(types, dns.TypeSOA) && !slices.Contains(types, dns.TypeDS)
+}
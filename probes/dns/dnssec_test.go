@@ -0,0 +1,433 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dns
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/probes/common/sched"
+	configpb "github.com/cloudprober/cloudprober/probes/dns/proto"
+	"github.com/cloudprober/cloudprober/probes/options"
+	"github.com/cloudprober/cloudprober/targets"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+type testZoneKey struct {
+	key  *dns.DNSKEY
+	priv crypto.Signer
+}
+
+func newTestZoneKey(t *testing.T, zone string) *testZoneKey {
+	t.Helper()
+	key := &dns.DNSKEY{
+		Hdr:       dns.RR_Header{Name: zone, Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET, Ttl: 3600},
+		Flags:     dns.ZONE | dns.SEP,
+		Protocol:  3,
+		Algorithm: dns.ECDSAP256SHA256,
+	}
+	priv, err := key.Generate(256)
+	require.NoError(t, err)
+	return &testZoneKey{key: key, priv: priv.(crypto.Signer)}
+}
+
+func (zk *testZoneKey) sign(t *testing.T, rrset []dns.RR, expiration time.Time) *dns.RRSIG {
+	t.Helper()
+	sig := &dns.RRSIG{
+		Hdr:        dns.RR_Header{Name: rrset[0].Header().Name, Rrtype: dns.TypeRRSIG, Class: dns.ClassINET, Ttl: 3600},
+		KeyTag:     zk.key.KeyTag(),
+		SignerName: zk.key.Hdr.Name,
+		Algorithm:  zk.key.Algorithm,
+		Inception:  uint32(time.Now().Add(-time.Hour).Unix()),
+		Expiration: uint32(expiration.Unix()),
+	}
+	require.NoError(t, sig.Sign(zk.priv, rrset))
+	return sig
+}
+
+// dnssecTestServer serves records from a test hierarchy: signed zones ".",
+// "com." and "example.com.", and unsigned zones "insecure.com." and
+// "sub.insecure.com.".
+type dnssecTestServer struct {
+	records   map[string][]dns.RR // Keyed by "name/TYPE".
+	authority map[string][]dns.RR // Authority records for empty answers.
+	fail      map[string]bool
+}
+
+func (s *dnssecTestServer) ExchangeContext(_ context.Context, in *dns.Msg, _ string) (*dns.Msg, time.Duration, error) {
+	if opt := in.IsEdns0(); opt == nil || !opt.Do() || !in.CheckingDisabled {
+		return nil, 0, errors.New("DO or CD bit not set")
+	}
+	k := in.Question[0].Name + "/" + dns.TypeToString[in.Question[0].Qtype]
+	if s.fail[k] {
+		return nil, 0, errors.New("query failed")
+	}
+	out := new(dns.Msg)
+	out.SetReply(in)
+	out.Answer = s.records[k]
+	if len(out.Answer) > 0 {
+		return out, time.Millisecond, nil
+	}
+
+	// Empty answers come with the SOA of the enclosing zone. DS records are
+	// served by the parent zone.
+	out.Ns = s.authority[k]
+	name := in.Question[0].Name
+	if in.Question[0].Qtype == dns.TypeDS {
+		off, _ := dns.NextLabel(name, 0)
+		name = name[off:]
+	}
+	for {
+		if soa := s.records[name+"/SOA"]; soa != nil {
+			out.Ns = append(out.Ns, soa...)
+			break
+		}
+		off, end := dns.NextLabel(name, 0)
+		if end {
+			break
+		}
+		name = name[off:]
+	}
+	return out, time.Millisecond, nil
+}
+func (*dnssecTestServer) setTimeout(time.Duration)      {}
+func (*dnssecTestServer) setSourceIP(net.IP)            {}
+func (*dnssecTestServer) setDNSProto(configpb.DNSProto) {}
+
+type testHierarchy struct {
+	keys       map[string]*testZoneKey
+	expiration map[string]time.Time // Signature expiration, by "name/TYPE".
+	aRecord    string               // Override signed A record.
+	unsigned   map[string]bool      // Records to not sign.
+	// How "com." denies the DS records for "insecure.com.": "nsec" (default),
+	// "nsec3", "nsec3_optout", "nsec_no_ns" (doesn't prove anything), or
+	// "none".
+	denial string
+}
+
+func (th *testHierarchy) server(t *testing.T) *dnssecTestServer {
+	t.Helper()
+	s := &dnssecTestServer{records: make(map[string][]dns.RR), authority: make(map[string][]dns.RR), fail: make(map[string]bool)}
+
+	signed := func(signer string, rrs []dns.RR) []dns.RR {
+		k := rrs[0].Header().Name + "/" + dns.TypeToString[rrs[0].Header().Rrtype]
+		if signer == "" || th.unsigned[k] {
+			return rrs
+		}
+		exp, ok := th.expiration[k]
+		if !ok {
+			exp = time.Now().Add(7 * 24 * time.Hour)
+		}
+		return append(rrs, th.keys[signer].sign(t, rrs, exp))
+	}
+	add := func(signer string, rrs ...dns.RR) {
+		k := rrs[0].Header().Name + "/" + dns.TypeToString[rrs[0].Header().Rrtype]
+		s.records[k] = signed(signer, rrs)
+	}
+	newRR := func(str string) dns.RR {
+		rr, err := dns.NewRR(str)
+		require.NoError(t, err)
+		return rr
+	}
+
+	parent := map[string]string{"com.": ".", "example.com.": "com."}
+	for _, zone := range []string{".", "com.", "example.com."} {
+		add(zone, th.keys[zone].key)
+		add(zone, newRR(zone+" 3600 IN SOA ns.test. admin.test. 1 3600 600 86400 300"))
+		if p := parent[zone]; p != "" {
+			add(p, th.keys[zone].key.ToDS(dns.SHA256))
+		}
+	}
+	add("example.com.", newRR("www.example.com. 300 IN A 192.168.0.1"))
+	if th.aRecord != "" {
+		s.records["www.example.com./A"][0] = newRR(th.aRecord)
+	}
+
+	// Unsigned zones.
+	for _, zone := range []string{"insecure.com.", "sub.insecure.com."} {
+		add("", newRR(zone+" 3600 IN SOA ns.test. admin.test. 1 3600 600 86400 300"))
+		add("", newRR("www."+zone+" 300 IN A 192.168.0.2"))
+	}
+	hash := dns.HashName("insecure.com.", dns.SHA1, 0, "")
+	var denial dns.RR
+	switch th.denial {
+	case "", "nsec":
+		denial = newRR("insecure.com. 300 IN NSEC www.example.com. NS RRSIG NSEC")
+	case "nsec_no_ns":
+		denial = newRR("insecure.com. 300 IN NSEC www.example.com. A RRSIG NSEC")
+	case "nsec3":
+		denial = newRR(hash + ".com. 300 IN NSEC3 1 0 0 - " + hash + " NS")
+	case "nsec3_optout":
+		denial = newRR("00000000000000000000000000000000.com. 300 IN NSEC3 1 1 0 - VVVVVVVVVVVVVVVVVVVVVVVVVVVVVVVV")
+	}
+	if denial != nil {
+		s.authority["insecure.com./DS"] = signed("com.", []dns.RR{denial})
+	}
+	return s
+}
+
+func newTestHierarchy(t *testing.T) *testHierarchy {
+	return &testHierarchy{
+		keys: map[string]*testZoneKey{
+			".":            newTestZoneKey(t, "."),
+			"com.":         newTestZoneKey(t, "com."),
+			"example.com.": newTestZoneKey(t, "example.com."),
+		},
+		expiration: make(map[string]time.Time),
+		unsigned:   make(map[string]bool),
+	}
+}
+
+func TestDNSSECValidate(t *testing.T) {
+	th := newTestHierarchy(t)
+	rootAnchor := th.keys["."].key.ToDS(dns.SHA256).String()
+
+	soon := time.Now().Add(time.Hour).Truncate(time.Second)
+	expired := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name          string
+		conf          *configpb.DNSSECConfig
+		modify        func(th *testHierarchy)
+		answer        string
+		fail          string
+		wantStatus    string
+		wantErr       bool
+		wantEarliest  time.Time
+		wantErrString string
+	}{
+		{
+			name:       "secure",
+			wantStatus: dnssecSecure,
+		},
+		{
+			name:         "secure_earliest_expiry",
+			modify:       func(th *testHierarchy) { th.expiration["com./DS"] = soon },
+			wantStatus:   dnssecSecure,
+			wantEarliest: soon,
+		},
+		{
+			name:          "min_sig_validity",
+			conf:          &configpb.DNSSECConfig{MinSigValiditySec: proto.Int32(86400)},
+			modify:        func(th *testHierarchy) { th.expiration["example.com./DNSKEY"] = soon },
+			wantStatus:    dnssecSecure,
+			wantErr:       true,
+			wantEarliest:  soon,
+			wantErrString: "min_sig_validity_sec",
+		},
+		{
+			name:          "bogus_tampered",
+			modify:        func(th *testHierarchy) { th.aRecord = "www.example.com. 300 IN A 10.0.0.1" },
+			wantStatus:    dnssecBogus,
+			wantErr:       true,
+			wantErrString: "verification failed",
+		},
+		{
+			name:          "bogus_expired",
+			modify:        func(th *testHierarchy) { th.expiration["www.example.com./A"] = expired },
+			wantStatus:    dnssecBogus,
+			wantErr:       true,
+			wantErrString: "validity period",
+		},
+		{
+			name:          "bogus_unsigned_ds",
+			modify:        func(th *testHierarchy) { th.unsigned["example.com./DS"] = true },
+			wantStatus:    dnssecBogus,
+			wantErr:       true,
+			wantErrString: "DS rrset for zone example.com.",
+		},
+		{
+			name:          "bogus_wrong_anchor",
+			conf:          &configpb.DNSSECConfig{TrustAnchor: []string{newTestZoneKey(t, ".").key.String()}},
+			wantStatus:    dnssecBogus,
+			wantErr:       true,
+			wantErrString: "matches its DS records",
+		},
+		{
+			// Zone has DS records, so unsigned answer is not insecure.
+			name:          "bogus_unsigned_answer",
+			modify:        func(th *testHierarchy) { th.unsigned["www.example.com./A"] = true },
+			wantStatus:    dnssecBogus,
+			wantErr:       true,
+			wantErrString: "zone example.com. is signed",
+		},
+		{
+			// Signed parent zone doesn't prove that there are no DS records.
+			name:          "bogus_unproven_no_ds",
+			modify:        func(th *testHierarchy) { th.denial = "none" },
+			answer:        "www.insecure.com./A",
+			wantStatus:    dnssecBogus,
+			wantErr:       true,
+			wantErrString: "no proof of their absence",
+		},
+		{
+			name:          "bogus_nsec_no_proof",
+			modify:        func(th *testHierarchy) { th.denial = "nsec_no_ns" },
+			answer:        "www.insecure.com./A",
+			wantStatus:    dnssecBogus,
+			wantErr:       true,
+			wantErrString: "don't prove their absence",
+		},
+		{
+			name:          "bogus_unsigned_nsec",
+			modify:        func(th *testHierarchy) { th.unsigned["insecure.com./NSEC"] = true },
+			answer:        "www.insecure.com./A",
+			wantStatus:    dnssecBogus,
+			wantErr:       true,
+			wantErrString: "no proof of their absence",
+		},
+		{
+			name:       "insecure",
+			answer:     "www.insecure.com./A",
+			wantStatus: dnssecInsecure,
+			wantErr:    true,
+		},
+		{
+			name:       "insecure_allowed",
+			conf:       &configpb.DNSSECConfig{AllowInsecure: proto.Bool(true)},
+			answer:     "www.insecure.com./A",
+			wantStatus: dnssecInsecure,
+		},
+		{
+			name:       "insecure_nsec3",
+			modify:     func(th *testHierarchy) { th.denial = "nsec3" },
+			answer:     "www.insecure.com./A",
+			wantStatus: dnssecInsecure,
+			wantErr:    true,
+		},
+		{
+			name:       "insecure_nsec3_optout",
+			modify:     func(th *testHierarchy) { th.denial = "nsec3_optout" },
+			answer:     "www.insecure.com./A",
+			wantStatus: dnssecInsecure,
+			wantErr:    true,
+		},
+		{
+			// Parent zone is unsigned, and provably insecure.
+			name:       "insecure_parent",
+			answer:     "www.sub.insecure.com./A",
+			wantStatus: dnssecInsecure,
+			wantErr:    true,
+		},
+		{
+			name:       "indeterminate",
+			fail:       "com./DNSKEY",
+			wantStatus: dnssecIndeterminate,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			th := &testHierarchy{keys: th.keys, expiration: make(map[string]time.Time), unsigned: make(map[string]bool)}
+			if tt.modify != nil {
+				tt.modify(th)
+			}
+			s := th.server(t)
+			if tt.fail != "" {
+				s.fail[tt.fail] = true
+			}
+
+			conf := tt.conf
+			if conf == nil {
+				conf = &configpb.DNSSECConfig{}
+			}
+			if conf.TrustAnchor == nil {
+				conf.TrustAnchor = []string{rootAnchor}
+			}
+			v, err := newDNSSECValidator(conf, s)
+			require.NoError(t, err)
+
+			if tt.answer == "" {
+				tt.answer = "www.example.com./A"
+			}
+			resp := &dns.Msg{Answer: s.records[tt.answer]}
+			res := v.validate(context.Background(), resp, "ns:53")
+
+			assert.Equal(t, tt.wantStatus, res.status, "error: %v", res.err)
+			assert.Equal(t, tt.wantErr, res.err != nil, "error: %v", res.err)
+			if tt.wantErrString != "" && res.err != nil {
+				assert.Contains(t, res.err.Error(), tt.wantErrString)
+			}
+			if !tt.wantEarliest.IsZero() {
+				assert.Equal(t, tt.wantEarliest.Unix(), res.earliestExpiry.Unix())
+			}
+		})
+	}
+}
+
+func TestNewDNSSECValidator(t *testing.T) {
+	v, err := newDNSSECValidator(&configpb.DNSSECConfig{}, nil)
+	require.NoError(t, err)
+	assert.Len(t, v.anchors["."], 2, "default root anchors")
+
+	for _, anchor := range []string{"not a record", "example.com. IN A 1.2.3.4"} {
+		_, err := newDNSSECValidator(&configpb.DNSSECConfig{TrustAnchor: []string{anchor}}, nil)
+		assert.Error(t, err, anchor)
+	}
+}
+
+func TestDNSSECProbe(t *testing.T) {
+	th := newTestHierarchy(t)
+	s := th.server(t)
+
+	p := &Probe{}
+	opts := &options.Options{
+		Targets:  targets.StaticTargets("8.8.8.8"),
+		Interval: 2 * time.Second,
+		Timeout:  time.Second,
+		ProbeConf: &configpb.ProbeConf{
+			ResolvedDomain: proto.String("www.example.com"),
+			QueryType:      configpb.QueryType_A.Enum(),
+			Dnssec: &configpb.DNSSECConfig{
+				TrustAnchor: []string{th.keys["."].key.ToDS(dns.SHA256).String()},
+			},
+		},
+		LatencyUnit: time.Millisecond,
+	}
+	require.NoError(t, p.Init("dnssec_test", opts))
+	p.client, p.dnssec.client = s, s
+
+	runReq := &sched.RunProbeForTargetRequest{Target: opts.Targets.ListEndpoints()[0], LastRun: &sched.LastRunResult{}}
+	p.runProbe(context.Background(), runReq)
+	assert.True(t, runReq.LastRun.Success, "last run error: %v", runReq.LastRun.Error)
+
+	ems := runReq.Result.Metrics(time.Now(), 0, p.opts)
+	require.Len(t, ems, 2)
+	status := ems[0].Metric("dnssec_validation").(*metrics.Map[int64])
+	assert.Equal(t, int64(1), status.GetKey(dnssecSecure))
+	assert.Equal(t, int64(0), status.GetKey(dnssecBogus))
+
+	assert.Equal(t, metrics.Kind(metrics.GAUGE), ems[1].Kind)
+	expirySec := ems[1].Metric("dnssec_earliest_sig_expiry_sec").(metrics.NumValue).Int64()
+	assert.InDelta(t, 7*24*3600, expirySec, 10)
+
+	// Bogus answer fails the probe.
+	s.records["www.example.com./A"][0], _ = dns.NewRR("www.example.com. 300 IN A 10.0.0.1")
+	p.runProbe(context.Background(), runReq)
+	assert.False(t, runReq.LastRun.Success)
+	assert.True(t, strings.Contains(runReq.LastRun.Error.Error(), "bogus"), runReq.LastRun.Error.Error())
+	status = runReq.Result.Metrics(time.Now(), 0, p.opts)[0].Metric("dnssec_validation").(*metrics.Map[int64])
+	assert.Equal(t, int64(1), status.GetKey(dnssecBogus))
+}
@@ -0,0 +1,132 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dns
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	configpb "github.com/cloudprober/cloudprober/probes/dns/proto"
+	"github.com/miekg/dns"
+)
+
+const (
+	defaultDoHPort  = 443
+	dohContentType  = "application/dns-message"
+	maxDNSMsgLength = 65535
+)
+
+// dohClient implements the Client interface for DNS-over-HTTPS (RFC 8484).
+type dohClient struct {
+	httpClient *http.Client
+	dialer     *net.Dialer
+	method     configpb.ProbeConf_DoHMethod
+	path       string
+}
+
+func newDoHClient(c *configpb.ProbeConf, tlsConfig *tls.Config) *dohClient {
+	dialer := &net.Dialer{}
+	transport := &http.Transport{
+		DialContext:       dialer.DialContext,
+		TLSClientConfig:   tlsConfig,
+		ForceAttemptHTTP2: true,
+	}
+	return &dohClient{
+		httpClient: &http.Client{Transport: transport},
+		dialer:     dialer,
+		method:     c.GetDohMethod(),
+		path:       c.GetDohPath(),
+	}
+}
+
+func (c *dohClient) setTimeout(d time.Duration) {
+	c.httpClient.Timeout = d
+}
+
+func (c *dohClient) setSourceIP(ip net.IP) {
+	c.dialer.LocalAddr = &net.TCPAddr{IP: ip}
+}
+
+func (c *dohClient) setDNSProto(configpb.DNSProto) {}
+
+func (c *dohClient) newRequest(ctx context.Context, msg []byte, target string) (*http.Request, error) {
+	u := &url.URL{Scheme: "https", Host: target, Path: c.path}
+
+	if c.method == configpb.ProbeConf_GET {
+		u.RawQuery = url.Values{"dns": {base64.RawURLEncoding.EncodeToString(msg)}}.Encode()
+		return http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(msg))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", dohContentType)
+	return req, nil
+}
+
+// ExchangeContext sends the DNS query to the DoH server at target (host:port)
+// and returns the response along with the round-trip time.
+func (c *dohClient) ExchangeContext(ctx context.Context, in *dns.Msg, target string) (*dns.Msg, time.Duration, error) {
+	// RFC 8484 recommends using 0 as the message ID, to make responses more
+	// cache friendly.
+	msg := in.Copy()
+	msg.Id = 0
+	b, err := msg.Pack()
+	if err != nil {
+		return nil, 0, fmt.Errorf("error packing DNS message: %v", err)
+	}
+
+	req, err := c.newRequest(ctx, b, target)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Accept", dohContentType)
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxDNSMsgLength))
+	rtt := time.Since(start)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error reading DoH response: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("unexpected DoH response status: %s", resp.Status)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != dohContentType {
+		return nil, 0, fmt.Errorf("unexpected DoH response content-type: %s", ct)
+	}
+
+	out := new(dns.Msg)
+	if err := out.Unpack(body); err != nil {
+		return nil, 0, fmt.Errorf("error unpacking DoH response: %v", err)
+	}
+	out.Id = in.Id
+	return out, rtt, nil
+}
@@ -0,0 +1,183 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dns
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	tlsconfigpb "github.com/cloudprober/cloudprober/common/tlsconfig/proto"
+	"github.com/cloudprober/cloudprober/probes/common/sched"
+	configpb "github.com/cloudprober/cloudprober/probes/dns/proto"
+	"github.com/cloudprober/cloudprober/probes/options"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// dohHandler implements a DoH server that answers every query with an A
+// record. Queries for questionBadDomain get a NXDOMAIN response.
+func dohHandler(t *testing.T, contentType string, status int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var b []byte
+		var err error
+		switch r.Method {
+		case http.MethodGet:
+			b, err = base64.RawURLEncoding.DecodeString(r.URL.Query().Get("dns"))
+		case http.MethodPost:
+			if r.Header.Get("Content-Type") != dohContentType {
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				return
+			}
+			b, err = io.ReadAll(r.Body)
+		}
+		if r.URL.Path != "/dns-query" || err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		in := new(dns.Msg)
+		if err := in.Unpack(b); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if in.Id != 0 {
+			t.Errorf("DoH query ID=%d, want 0", in.Id)
+		}
+
+		out := new(dns.Msg)
+		out.SetReply(in)
+		if in.Question[0].Name == questionBadDomain+"." {
+			out.Rcode = dns.RcodeNameError
+		} else {
+			a, _ := dns.NewRR(in.Question[0].Name + answerContent)
+			out.Answer = []dns.RR{a}
+		}
+		resp, _ := out.Pack()
+
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(status)
+		w.Write(resp)
+	}
+}
+
+func TestDoHRunProbe(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      configpb.ProbeConf_DoHMethod
+		domain      string
+		contentType string
+		status      int
+		wantSuccess bool
+	}{
+		{
+			name:        "post",
+			method:      configpb.ProbeConf_POST,
+			wantSuccess: true,
+		},
+		{
+			name:        "get",
+			method:      configpb.ProbeConf_GET,
+			wantSuccess: true,
+		},
+		{
+			name:   "nxdomain",
+			method: configpb.ProbeConf_GET,
+			domain: questionBadDomain,
+		},
+		{
+			name:   "bad_status",
+			method: configpb.ProbeConf_POST,
+			status: http.StatusInternalServerError,
+		},
+		{
+			name:        "bad_content_type",
+			method:      configpb.ProbeConf_POST,
+			contentType: "text/plain",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.contentType == "" {
+				tt.contentType = dohContentType
+			}
+			if tt.status == 0 {
+				tt.status = http.StatusOK
+			}
+			if tt.domain == "" {
+				tt.domain = "test.com"
+			}
+
+			ts := httptest.NewTLSServer(dohHandler(t, tt.contentType, tt.status))
+			defer ts.Close()
+			host, port, err := net.SplitHostPort(ts.Listener.Addr().String())
+			require.NoError(t, err)
+			portNum, _ := strconv.Atoi(port)
+
+			p := &Probe{}
+			opts := options.DefaultOptions()
+			opts.Timeout = 2 * time.Second
+			opts.ProbeConf = &configpb.ProbeConf{
+				ResolvedDomain: proto.String(tt.domain),
+				DnsProto:       configpb.DNSProto_DOH.Enum(),
+				DohMethod:      tt.method.Enum(),
+				TlsConfig:      &tlsconfigpb.TLSConfig{DisableCertValidation: proto.Bool(true)},
+			}
+			require.NoError(t, p.Init("dns_doh_test", opts))
+
+			runReq := &sched.RunProbeForTargetRequest{
+				Target:  endpoint.Endpoint{Name: host, Port: portNum},
+				LastRun: &sched.LastRunResult{},
+			}
+			p.runProbe(context.Background(), runReq)
+
+			result := runReq.Result.(*probeRunResult)
+			assert.Equal(t, int64(1), result.total.Int64())
+			assert.Equal(t, tt.wantSuccess, result.success.Int64() == 1, "last run error: %v", runReq.LastRun.Error)
+		})
+	}
+}
+
+func TestDoHRequest(t *testing.T) {
+	msg := new(dns.Msg)
+	msg.SetQuestion("test.com.", dns.TypeA)
+	b, _ := msg.Pack()
+
+	for _, method := range []configpb.ProbeConf_DoHMethod{configpb.ProbeConf_GET, configpb.ProbeConf_POST} {
+		c := newDoHClient(&configpb.ProbeConf{DohMethod: method.Enum(), DohPath: proto.String("/resolve")}, nil)
+		req, err := c.newRequest(context.Background(), b, "dns.example.com:443")
+		require.NoError(t, err)
+
+		assert.Equal(t, "dns.example.com:443", req.URL.Host)
+		assert.Equal(t, "/resolve", req.URL.Path)
+		if method == configpb.ProbeConf_GET {
+			assert.Equal(t, http.MethodGet, req.Method)
+			assert.Equal(t, base64.RawURLEncoding.EncodeToString(b), req.URL.Query().Get("dns"))
+		} else {
+			assert.Equal(t, http.MethodPost, req.Method)
+			assert.Equal(t, dohContentType, req.Header.Get("Content-Type"))
+		}
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dns
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	configpb "github.com/cloudprober/cloudprober/probes/dns/proto"
+	"github.com/miekg/dns"
+	"github.com/quic-go/quic-go"
+)
+
+const (
+	defaultDoQPort = 853
+	doqALPN        = "doq"
+
+	// DOQ_NO_ERROR error code (RFC 9250, section 4.3).
+	doqNoError = 0
+)
+
+// doqClient implements the Client interface for DNS-over-QUIC (RFC 9250).
+// Like the TCP and TLS clients, it uses a new connection for each query, and
+// the query is sent on the connection's first (and only) stream. Returned
+// round-trip time doesn't include the QUIC handshake.
+type doqClient struct {
+	tlsConfig *tls.Config
+	timeout   time.Duration
+	sourceIP  net.IP
+}
+
+func newDoQClient(tlsConfig *tls.Config) *doqClient {
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	} else {
+		tlsConfig = tlsConfig.Clone()
+	}
+	tlsConfig.NextProtos = []string{doqALPN}
+	return &doqClient{tlsConfig: tlsConfig}
+}
+
+func (c *doqClient) setTimeout(d time.Duration) {
+	c.timeout = d
+}
+
+func (c *doqClient) setSourceIP(ip net.IP) {
+	c.sourceIP = ip
+}
+
+func (c *doqClient) setDNSProto(configpb.DNSProto) {}
+
+// dial establishes a QUIC connection to target (host:port). Returned
+// connection owns the UDP socket, it's closed with the connection.
+func (c *doqClient) dial(ctx context.Context, target string) (*quic.Conn, error) {
+	host, _, err := net.SplitHostPort(target)
+	if err != nil {
+		return nil, err
+	}
+	raddr, err := net.ResolveUDPAddr("udp", target)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := c.tlsConfig.Clone()
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = host
+	}
+
+	udpConn, err := net.ListenUDP("udp", &net.UDPAddr{IP: c.sourceIP})
+	if err != nil {
+		return nil, err
+	}
+	conn, err := quic.Dial(ctx, udpConn, raddr, tlsConfig, &quic.Config{})
+	if err != nil {
+		udpConn.Close()
+		return nil, err
+	}
+	go func() {
+		<-conn.Context().Done()
+		udpConn.Close()
+	}()
+	return conn, nil
+}
+
+// ExchangeContext sends the DNS query to the DoQ server at target (host:port)
+// and returns the response along with the round-trip time.
+func (c *doqClient) ExchangeContext(ctx context.Context, in *dns.Msg, target string) (*dns.Msg, time.Duration, error) {
+	// RFC 9250 requires the message ID to be 0.
+	msg := in.Copy()
+	msg.Id = 0
+	b, err := msg.Pack()
+	if err != nil {
+		return nil, 0, fmt.Errorf("error packing DNS message: %v", err)
+	}
+
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	conn, err := c.dial(ctx, target)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer conn.CloseWithError(doqNoError, "")
+
+	start := time.Now()
+	stream, err := conn.OpenStreamSync(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		stream.SetDeadline(deadline)
+	}
+
+	// Messages are prefixed with a 2-byte length field, and client indicates
+	// the end of the query with a STREAM FIN (RFC 9250, section 4.2).
+	query := binary.BigEndian.AppendUint16(make([]byte, 0, 2+len(b)), uint16(len(b)))
+	if _, err := stream.Write(append(query, b...)); err != nil {
+		return nil, 0, err
+	}
+	if err := stream.Close(); err != nil {
+		return nil, 0, err
+	}
+
+	var respLen [2]byte
+	if _, err := io.ReadFull(stream, respLen[:]); err != nil {
+		return nil, 0, err
+	}
+	resp := make([]byte, binary.BigEndian.Uint16(respLen[:]))
+	if _, err := io.ReadFull(stream, resp); err != nil {
+		return nil, 0, err
+	}
+	rtt := time.Since(start)
+
+	out := new(dns.Msg)
+	if err := out.Unpack(resp); err != nil {
+		return nil, 0, fmt.Errorf("error unpacking DoQ response: %v", err)
+	}
+	out.Id = in.Id
+	return out, rtt, nil
+}
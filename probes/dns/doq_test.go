@@ -0,0 +1,232 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dns
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tlsconfigpb "github.com/cloudprober/cloudprober/common/tlsconfig/proto"
+	"github.com/cloudprober/cloudprober/probes/common/sched"
+	configpb "github.com/cloudprober/cloudprober/probes/dns/proto"
+	"github.com/cloudprober/cloudprober/probes/options"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+	"github.com/miekg/dns"
+	"github.com/quic-go/quic-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+type doqServer struct {
+	port       int
+	numQueries atomic.Int32
+	badQueries atomic.Int32
+}
+
+// startDoQServer starts a DoQ server on localhost that answers every query
+// with an A record. Queries for questionBadDomain get a NXDOMAIN response. If
+// reply is false, server reads the queries but never answers them.
+func startDoQServer(t *testing.T, alpn string, reply bool) *doqServer {
+	t.Helper()
+
+	// Borrow test certificate from the httptest server.
+	ts := httptest.NewTLSServer(nil)
+	certs := ts.TLS.Certificates
+	ts.Close()
+
+	ln, err := quic.ListenAddr("127.0.0.1:0", &tls.Config{Certificates: certs, NextProtos: []string{alpn}}, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	s := &doqServer{port: ln.Addr().(*net.UDPAddr).Port}
+
+	handleStream := func(stream *quic.Stream) {
+		// Query should be terminated with a STREAM FIN, so we can read all of it.
+		b, err := io.ReadAll(stream)
+		if err != nil || len(b) < 2 || int(binary.BigEndian.Uint16(b)) != len(b)-2 {
+			s.badQueries.Add(1)
+			return
+		}
+		in := new(dns.Msg)
+		if err := in.Unpack(b[2:]); err != nil || in.Id != 0 {
+			s.badQueries.Add(1)
+			return
+		}
+		s.numQueries.Add(1)
+		if !reply {
+			return
+		}
+
+		out := new(dns.Msg)
+		out.SetReply(in)
+		if in.Question[0].Name == questionBadDomain+"." {
+			out.Rcode = dns.RcodeNameError
+		} else {
+			a, _ := dns.NewRR(in.Question[0].Name + answerContent)
+			out.Answer = []dns.RR{a}
+		}
+		resp, _ := out.Pack()
+		stream.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(resp))), resp...))
+		stream.Close()
+	}
+
+	go func() {
+		for {
+			conn, err := ln.Accept(context.Background())
+			if err != nil {
+				return
+			}
+			go func() {
+				for {
+					stream, err := conn.AcceptStream(context.Background())
+					if err != nil {
+						return
+					}
+					go handleStream(stream)
+				}
+			}()
+		}
+	}()
+
+	return s
+}
+
+func TestDoQRunProbe(t *testing.T) {
+	tests := []struct {
+		name         string
+		alpn         string
+		noReply      bool
+		domain       string
+		requests     int32
+		wantSuccess  int64
+		wantTimeouts int64
+		wantQueries  int32
+	}{
+		{
+			name:        "success",
+			wantSuccess: 1,
+			wantQueries: 1,
+		},
+		{
+			name:        "multiple_requests",
+			requests:    3,
+			wantSuccess: 3,
+			wantQueries: 3,
+		},
+		{
+			name:        "nxdomain",
+			domain:      questionBadDomain,
+			wantQueries: 1,
+		},
+		{
+			name: "wrong_alpn",
+			alpn: "dot",
+		},
+		{
+			name:         "no_reply",
+			noReply:      true,
+			wantTimeouts: 1,
+			wantQueries:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.alpn == "" {
+				tt.alpn = doqALPN
+			}
+			if tt.domain == "" {
+				tt.domain = "test.com"
+			}
+			if tt.requests == 0 {
+				tt.requests = 1
+			}
+
+			s := startDoQServer(t, tt.alpn, !tt.noReply)
+
+			p := &Probe{}
+			opts := options.DefaultOptions()
+			opts.Timeout = time.Second
+			opts.ProbeConf = &configpb.ProbeConf{
+				ResolvedDomain:   proto.String(tt.domain),
+				DnsProto:         configpb.DNSProto_DOQ.Enum(),
+				RequestsPerProbe: proto.Int32(tt.requests),
+				TlsConfig: &tlsconfigpb.TLSConfig{
+					DisableCertValidation: proto.Bool(true),
+				},
+			}
+			require.NoError(t, p.Init("dns_doq_test", opts))
+
+			runReq := &sched.RunProbeForTargetRequest{
+				Target:  endpoint.Endpoint{Name: "127.0.0.1", Port: s.port},
+				LastRun: &sched.LastRunResult{},
+			}
+			p.runProbe(context.Background(), runReq)
+
+			result := runReq.Result.(*probeRunResult)
+			assert.Equal(t, int64(tt.requests), result.total.Int64())
+			assert.Equal(t, tt.wantSuccess, result.success.Int64(), "last run error: %v", runReq.LastRun.Error)
+			assert.Equal(t, tt.wantTimeouts, result.timeouts.Int64())
+			assert.Equal(t, tt.wantQueries, s.numQueries.Load(), "queries received by server")
+			assert.Zero(t, s.badQueries.Load(), "malformed queries received by server")
+		})
+	}
+}
+
+func TestDoQServerName(t *testing.T) {
+	s := startDoQServer(t, doqALPN, true)
+	target := net.JoinHostPort("127.0.0.1", strconv.Itoa(s.port))
+
+	// httptest certificate is valid for example.com and 127.0.0.1.
+	ts := httptest.NewTLSServer(nil)
+	roots := x509.NewCertPool()
+	roots.AddCert(ts.Certificate())
+	ts.Close()
+
+	msg := new(dns.Msg)
+	msg.SetQuestion("test.com.", dns.TypeA)
+
+	for _, tt := range []struct {
+		serverName string
+		wantErr    bool
+	}{
+		{serverName: ""}, // Defaults to target's host.
+		{serverName: "example.com"},
+		{serverName: "other.test", wantErr: true},
+	} {
+		c := newDoQClient(&tls.Config{RootCAs: roots, ServerName: tt.serverName})
+		c.setTimeout(time.Second)
+
+		resp, _, err := c.ExchangeContext(context.Background(), msg, target)
+		if tt.wantErr {
+			assert.Error(t, err, "server name: %s", tt.serverName)
+			continue
+		}
+		require.NoError(t, err, "server name: %s", tt.serverName)
+		assert.Equal(t, msg.Id, resp.Id)
+		assert.Len(t, resp.Answer, 1)
+		assert.Equal(t, tt.serverName, c.tlsConfig.ServerName, "client's TLS config should not be modified")
+	}
+}
@@ -7,6 +7,7 @@
 package proto
 
 import (
+	proto "github.com/cloudprober/cloudprober/common/tlsconfig/proto"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -255,6 +256,12 @@ const (
 	DNSProto_UDP     DNSProto = 0
 	DNSProto_TCP     DNSProto = 1
 	DNSProto_TCP_TLS DNSProto = 2
+	// DNS-over-HTTPS (RFC 8484). Default port is 443.
+	DNSProto_DOH DNSProto = 3
+	// DNS-over-QUIC (RFC 9250). Default port is 853. Like TCP and TCP_TLS, a
+	// new connection is used for each query, and latency doesn't include the
+	// connection setup (QUIC handshake).
+	DNSProto_DOQ DNSProto = 4
 )
 
 // Enum value maps for DNSProto.
@@ -263,11 +270,15 @@ var (
 		0: "UDP",
 		1: "TCP",
 		2: "TCP_TLS",
+		3: "DOH",
+		4: "DOQ",
 	}
 	DNSProto_value = map[string]int32{
 		"UDP":     0,
 		"TCP":     1,
 		"TCP_TLS": 2,
+		"DOH":     3,
+		"DOQ":     4,
 	}
 )
 
@@ -308,6 +319,155 @@ func (DNSProto) EnumDescriptor() ([]byte, []int) {
 	return file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_rawDescGZIP(), []int{2}
 }
 
+// HTTP method to use for DNS-over-HTTPS. Both methods use the DNS wire
+// format, GET sends the query in the "dns" URL parameter.
+type ProbeConf_DoHMethod int32
+
+const (
+	ProbeConf_POST ProbeConf_DoHMethod = 0
+	ProbeConf_GET  ProbeConf_DoHMethod = 1
+)
+
+// Enum value maps for ProbeConf_DoHMethod.
+var (
+	ProbeConf_DoHMethod_name = map[int32]string{
+		0: "POST",
+		1: "GET",
+	}
+	ProbeConf_DoHMethod_value = map[string]int32{
+		"POST": 0,
+		"GET":  1,
+	}
+)
+
+func (x ProbeConf_DoHMethod) Enum() *ProbeConf_DoHMethod {
+	p := new(ProbeConf_DoHMethod)
+	*p = x
+	return p
+}
+
+func (x ProbeConf_DoHMethod) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProbeConf_DoHMethod) Descriptor() protoreflect.EnumDescriptor {
+	return file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_enumTypes[3].Descriptor()
+}
+
+func (ProbeConf_DoHMethod) Type() protoreflect.EnumType {
+	return &file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_enumTypes[3]
+}
+
+func (x ProbeConf_DoHMethod) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *ProbeConf_DoHMethod) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = ProbeConf_DoHMethod(num)
+	return nil
+}
+
+// Deprecated: Use ProbeConf_DoHMethod.Descriptor instead.
+func (ProbeConf_DoHMethod) EnumDescriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_rawDescGZIP(), []int{1, 0}
+}
+
+// DNSSEC validation config. When enabled, probe sets the DO (DNSSEC OK) and
+// CD (checking disabled) bits in queries and validates the answer's chain of
+// trust itself, by fetching DNSKEY and DS records from the same server, up to
+// a configured trust anchor.
+//
+// Following metrics are exported:
+//
+//	dnssec_validation: map of validation results, keyed by "status", which
+//	  can be one of: secure, insecure, bogus, indeterminate.
+//	dnssec_earliest_sig_expiry_sec: (GAUGE) seconds until the earliest
+//	  expiring RRSIG in the chain of trust expires.
+//
+// Note: negative responses (NXDOMAIN, NODATA) are not validated.
+type DNSSECConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Trust anchors, as DS or DNSKEY records in presentation format, e.g.:
+	//
+	//	". IN DS 20326 8 2 E06D44B80B8F1D39A95C0B0D7C65D08458E880409BBB683457104237C7F8EC8D"
+	//
+	// If not specified, root zone KSKs published by IANA (KSK-2017 and
+	// KSK-2024) are used.
+	TrustAnchor []string `protobuf:"bytes,1,rep,name=trust_anchor,json=trustAnchor" json:"trust_anchor,omitempty"`
+	// By default, an answer from a provably insecure zone fails the probe. Zone
+	// is provably insecure if its parent zone proves, using validated NSEC or
+	// NSEC3 records, that there are no DS records for it. Unsigned answers
+	// from signed zones are always bogus. Set this to true to only fail on
+	// bogus answers.
+	AllowInsecure *bool `protobuf:"varint,2,opt,name=allow_insecure,json=allowInsecure,def=0" json:"allow_insecure,omitempty"`
+	// Fail the probe if any RRSIG in the chain of trust expires within this
+	// many seconds. This helps catch signatures that are not being refreshed.
+	MinSigValiditySec *int32 `protobuf:"varint,3,opt,name=min_sig_validity_sec,json=minSigValiditySec" json:"min_sig_validity_sec,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+// Default values for DNSSECConfig fields.
+const (
+	Default_DNSSECConfig_AllowInsecure = bool(false)
+)
+
+func (x *DNSSECConfig) Reset() {
+	*x = DNSSECConfig{}
+	mi := &file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DNSSECConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DNSSECConfig) ProtoMessage() {}
+
+func (x *DNSSECConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DNSSECConfig.ProtoReflect.Descriptor instead.
+func (*DNSSECConfig) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DNSSECConfig) GetTrustAnchor() []string {
+	if x != nil {
+		return x.TrustAnchor
+	}
+	return nil
+}
+
+func (x *DNSSECConfig) GetAllowInsecure() bool {
+	if x != nil && x.AllowInsecure != nil {
+		return *x.AllowInsecure
+	}
+	return Default_DNSSECConfig_AllowInsecure
+}
+
+func (x *DNSSECConfig) GetMinSigValiditySec() int32 {
+	if x != nil && x.MinSigValiditySec != nil {
+		return *x.MinSigValiditySec
+	}
+	return 0
+}
+
 type ProbeConf struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Domain to use when making DNS queries
@@ -323,6 +483,24 @@ type ProbeConf struct {
 	// default we resolve first if it's a discovered resource, e.g., a k8s
 	// endpoint.
 	ResolveFirst *bool `protobuf:"varint,5,opt,name=resolve_first,json=resolveFirst" json:"resolve_first,omitempty"`
+	// TLS config, used by the TCP_TLS, DOH and DOQ protocols. If you probe
+	// DNS servers by IP address, use tls_config.server_name to set the server
+	// name for the TLS handshake.
+	TlsConfig *proto.TLSConfig     `protobuf:"bytes,6,opt,name=tls_config,json=tlsConfig" json:"tls_config,omitempty"`
+	DohMethod *ProbeConf_DoHMethod `protobuf:"varint,7,opt,name=doh_method,json=dohMethod,enum=cloudprober.probes.dns.ProbeConf_DoHMethod,def=0" json:"doh_method,omitempty"`
+	// URL path for DNS-over-HTTPS, e.g. "/dns-query" (default) or
+	// "/resolve".
+	DohPath *string `protobuf:"bytes,8,opt,name=doh_path,json=dohPath,def=/dns-query" json:"doh_path,omitempty"`
+	// Validate DNSSEC signatures. See DNSSECConfig above for details.
+	Dnssec *DNSSECConfig `protobuf:"bytes,9,opt,name=dnssec" json:"dnssec,omitempty"`
+	// Check answer consistency across targets, e.g. to catch split-brain
+	// between the authoritative servers of a zone. If enabled, answers from
+	// each target are compared with the latest answers from all other targets
+	// and the "answer_mismatch" counter is incremented for the target if they
+	// differ from any of them. Answers are compared as sets of record data,
+	// i.e. order and TTLs are ignored. Note that a mismatch doesn't fail the
+	// probe.
+	CheckConsistency *bool `protobuf:"varint,10,opt,name=check_consistency,json=checkConsistency" json:"check_consistency,omitempty"`
 	// DNS Query QueryClass
 	QueryClass *QueryClass `protobuf:"varint,96,opt,name=query_class,json=queryClass,enum=cloudprober.probes.dns.QueryClass,def=1" json:"query_class,omitempty"`
 	// Which DNS protocol is used for resolution.
@@ -350,6 +528,8 @@ const (
 	Default_ProbeConf_ResolvedDomain       = string("www.google.com.")
 	Default_ProbeConf_QueryType            = QueryType_MX
 	Default_ProbeConf_MinAnswers           = uint32(0)
+	Default_ProbeConf_DohMethod            = ProbeConf_POST
+	Default_ProbeConf_DohPath              = string("/dns-query")
 	Default_ProbeConf_QueryClass           = QueryClass_IN
 	Default_ProbeConf_DnsProto             = DNSProto_UDP
 	Default_ProbeConf_RequestsPerProbe     = int32(1)
@@ -358,7 +538,7 @@ const (
 
 func (x *ProbeConf) Reset() {
 	*x = ProbeConf{}
-	mi := &file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_msgTypes[0]
+	mi := &file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -370,7 +550,7 @@ func (x *ProbeConf) String() string {
 func (*ProbeConf) ProtoMessage() {}
 
 func (x *ProbeConf) ProtoReflect() protoreflect.Message {
-	mi := &file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_msgTypes[0]
+	mi := &file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -383,7 +563,7 @@ func (x *ProbeConf) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProbeConf.ProtoReflect.Descriptor instead.
 func (*ProbeConf) Descriptor() ([]byte, []int) {
-	return file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_rawDescGZIP(), []int{0}
+	return file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *ProbeConf) GetResolvedDomain() string {
@@ -414,6 +594,41 @@ func (x *ProbeConf) GetResolveFirst() bool {
 	return false
 }
 
+func (x *ProbeConf) GetTlsConfig() *proto.TLSConfig {
+	if x != nil {
+		return x.TlsConfig
+	}
+	return nil
+}
+
+func (x *ProbeConf) GetDohMethod() ProbeConf_DoHMethod {
+	if x != nil && x.DohMethod != nil {
+		return *x.DohMethod
+	}
+	return Default_ProbeConf_DohMethod
+}
+
+func (x *ProbeConf) GetDohPath() string {
+	if x != nil && x.DohPath != nil {
+		return *x.DohPath
+	}
+	return Default_ProbeConf_DohPath
+}
+
+func (x *ProbeConf) GetDnssec() *DNSSECConfig {
+	if x != nil {
+		return x.Dnssec
+	}
+	return nil
+}
+
+func (x *ProbeConf) GetCheckConsistency() bool {
+	if x != nil && x.CheckConsistency != nil {
+		return *x.CheckConsistency
+	}
+	return false
+}
+
 func (x *ProbeConf) GetQueryClass() QueryClass {
 	if x != nil && x.QueryClass != nil {
 		return *x.QueryClass
@@ -446,19 +661,35 @@ var File_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto protor
 
 const file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_rawDesc = "" +
 	"\n" +
-	"@github.com/cloudprober/cloudprober/probes/dns/proto/config.proto\x12\x16cloudprober.probes.dns\"\xcb\x03\n" +
+	"@github.com/cloudprober/cloudprober/probes/dns/proto/config.proto\x12\x16cloudprober.probes.dns\x1aFgithub.com/cloudprober/cloudprober/common/tlsconfig/proto/config.proto\"\x90\x01\n" +
+	"\fDNSSECConfig\x12!\n" +
+	"\ftrust_anchor\x18\x01 \x03(\tR\vtrustAnchor\x12,\n" +
+	"\x0eallow_insecure\x18\x02 \x01(\b:\x05falseR\rallowInsecure\x12/\n" +
+	"\x14min_sig_validity_sec\x18\x03 \x01(\x05R\x11minSigValiditySec\"\x90\x06\n" +
 	"\tProbeConf\x128\n" +
 	"\x0fresolved_domain\x18\x01 \x01(\t:\x0fwww.google.com.R\x0eresolvedDomain\x12D\n" +
 	"\n" +
 	"query_type\x18\x03 \x01(\x0e2!.cloudprober.probes.dns.QueryType:\x02MXR\tqueryType\x12\"\n" +
 	"\vmin_answers\x18\x04 \x01(\r:\x010R\n" +
 	"minAnswers\x12#\n" +
-	"\rresolve_first\x18\x05 \x01(\bR\fresolveFirst\x12G\n" +
+	"\rresolve_first\x18\x05 \x01(\bR\fresolveFirst\x12?\n" +
+	"\n" +
+	"tls_config\x18\x06 \x01(\v2 .cloudprober.tlsconfig.TLSConfigR\ttlsConfig\x12P\n" +
+	"\n" +
+	"doh_method\x18\a \x01(\x0e2+.cloudprober.probes.dns.ProbeConf.DoHMethod:\x04POSTR\tdohMethod\x12%\n" +
+	"\bdoh_path\x18\b \x01(\t:\n" +
+	"/dns-queryR\adohPath\x12<\n" +
+	"\x06dnssec\x18\t \x01(\v2$.cloudprober.probes.dns.DNSSECConfigR\x06dnssec\x12+\n" +
+	"\x11check_consistency\x18\n" +
+	" \x01(\bR\x10checkConsistency\x12G\n" +
 	"\vquery_class\x18` \x01(\x0e2\".cloudprober.probes.dns.QueryClass:\x02INR\n" +
 	"queryClass\x12B\n" +
 	"\tdns_proto\x18a \x01(\x0e2 .cloudprober.probes.dns.DNSProto:\x03UDPR\bdnsProto\x12/\n" +
 	"\x12requests_per_probe\x18b \x01(\x05:\x011R\x10requestsPerProbe\x127\n" +
-	"\x16requests_interval_msec\x18c \x01(\x05:\x010R\x14requestsIntervalMsec*\xa4\x03\n" +
+	"\x16requests_interval_msec\x18c \x01(\x05:\x010R\x14requestsIntervalMsec\"\x1e\n" +
+	"\tDoHMethod\x12\b\n" +
+	"\x04POST\x10\x00\x12\a\n" +
+	"\x03GET\x10\x01*\xa4\x03\n" +
 	"\tQueryType\x12\b\n" +
 	"\x04NONE\x10\x00\x12\x05\n" +
 	"\x01A\x10\x01\x12\x06\n" +
@@ -506,11 +737,13 @@ const file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_rawD
 	"\n" +
 	"QueryClass\x12\x06\n" +
 	"\x02IN\x10\x01\x12\x06\n" +
-	"\x02CH\x10\x03*)\n" +
+	"\x02CH\x10\x03*;\n" +
 	"\bDNSProto\x12\a\n" +
 	"\x03UDP\x10\x00\x12\a\n" +
 	"\x03TCP\x10\x01\x12\v\n" +
-	"\aTCP_TLS\x10\x02B5Z3github.com/cloudprober/cloudprober/probes/dns/proto"
+	"\aTCP_TLS\x10\x02\x12\a\n" +
+	"\x03DOH\x10\x03\x12\a\n" +
+	"\x03DOQ\x10\x04B5Z3github.com/cloudprober/cloudprober/probes/dns/proto"
 
 var (
 	file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_rawDescOnce sync.Once
@@ -524,23 +757,29 @@ func file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_rawDe
 	return file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_rawDescData
 }
 
-var file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_goTypes = []any{
-	(QueryType)(0),    // 0: cloudprober.probes.dns.QueryType
-	(QueryClass)(0),   // 1: cloudprober.probes.dns.QueryClass
-	(DNSProto)(0),     // 2: cloudprober.probes.dns.DNSProto
-	(*ProbeConf)(nil), // 3: cloudprober.probes.dns.ProbeConf
+	(QueryType)(0),           // 0: cloudprober.probes.dns.QueryType
+	(QueryClass)(0),          // 1: cloudprober.probes.dns.QueryClass
+	(DNSProto)(0),            // 2: cloudprober.probes.dns.DNSProto
+	(ProbeConf_DoHMethod)(0), // 3: cloudprober.probes.dns.ProbeConf.DoHMethod
+	(*DNSSECConfig)(nil),     // 4: cloudprober.probes.dns.DNSSECConfig
+	(*ProbeConf)(nil),        // 5: cloudprober.probes.dns.ProbeConf
+	(*proto.TLSConfig)(nil),  // 6: cloudprober.tlsconfig.TLSConfig
 }
 var file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_depIdxs = []int32{
 	0, // 0: cloudprober.probes.dns.ProbeConf.query_type:type_name -> cloudprober.probes.dns.QueryType
-	1, // 1: cloudprober.probes.dns.ProbeConf.query_class:type_name -> cloudprober.probes.dns.QueryClass
-	2, // 2: cloudprober.probes.dns.ProbeConf.dns_proto:type_name -> cloudprober.probes.dns.DNSProto
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	6, // 1: cloudprober.probes.dns.ProbeConf.tls_config:type_name -> cloudprober.tlsconfig.TLSConfig
+	3, // 2: cloudprober.probes.dns.ProbeConf.doh_method:type_name -> cloudprober.probes.dns.ProbeConf.DoHMethod
+	4, // 3: cloudprober.probes.dns.ProbeConf.dnssec:type_name -> cloudprober.probes.dns.DNSSECConfig
+	1, // 4: cloudprober.probes.dns.ProbeConf.query_class:type_name -> cloudprober.probes.dns.QueryClass
+	2, // 5: cloudprober.probes.dns.ProbeConf.dns_proto:type_name -> cloudprober.probes.dns.DNSProto
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_init() }
@@ -553,8 +792,8 @@ func file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_init(
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_rawDesc), len(file_github_com_cloudprober_cloudprober_probes_dns_proto_config_proto_rawDesc)),
-			NumEnums:      3,
-			NumMessages:   1,
+			NumEnums:      4,
+			NumMessages:   2,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
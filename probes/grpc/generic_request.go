@@ -1,4 +1,4 @@
-// Copyright 2020-2023 The Cloudprober Authors.
+// Copyright 2020-2026 The Cloudprober Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -20,13 +20,19 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	configpb "github.com/cloudprober/cloudprober/probes/grpc/proto"
 	"github.com/fullstorydev/grpcurl"
+	"github.com/jhump/protoreflect/desc"
 	"github.com/jhump/protoreflect/grpcreflect"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/runtime/protoiface"
 )
 
 func (p *Probe) initDescriptorSource() error {
@@ -61,37 +67,153 @@ func (r response) String() string {
 	return string(r)
 }
 
-func (p *Probe) callServiceMethod(ctx context.Context, req *configpb.GenericRequest, descSrc grpcurl.DescriptorSource, conn *grpc.ClientConn) (response, error) {
+// streamStats captures the stats of a streaming call.
+type streamStats struct {
+	firstMsgLatency time.Duration // Zero if no message was received.
+	numMessages     int64
+	duration        time.Duration
+}
+
+// streamHandler wraps grpcurl's default event handler to record the timing of
+// the response messages, and to stop the stream after max messages or after
+// the configured duration.
+type streamHandler struct {
+	*grpcurl.DefaultEventHandler
+	maxMsgs  int
+	duration time.Duration
+	timer    *time.Timer
+	stop     func()
+	stopped  *atomic.Bool
+
+	serverStreaming, clientStreaming bool
+	start, firstMsg                  time.Time
+}
+
+func (h *streamHandler) OnResolveMethod(md *desc.MethodDescriptor) {
+	h.serverStreaming, h.clientStreaming = md.IsServerStreaming(), md.IsClientStreaming()
+	h.DefaultEventHandler.OnResolveMethod(md)
+}
+
+func (h *streamHandler) OnSendHeaders(md metadata.MD) {
+	h.start = time.Now()
+	if h.duration > 0 {
+		h.timer = time.AfterFunc(h.duration, h.stop)
+	}
+	h.DefaultEventHandler.OnSendHeaders(md)
+}
+
+func (h *streamHandler) OnReceiveResponse(resp protoiface.MessageV1) {
+	// Ignore messages that may arrive after we've stopped the stream.
+	if h.stopped.Load() {
+		return
+	}
+	if h.NumResponses == 0 {
+		h.firstMsg = time.Now()
+	}
+	h.DefaultEventHandler.OnReceiveResponse(resp)
+	if h.maxMsgs > 0 && h.NumResponses >= h.maxMsgs {
+		h.stop()
+	}
+}
+
+func (h *streamHandler) stats() *streamStats {
+	if !h.serverStreaming && !h.clientStreaming {
+		return nil
+	}
+	ss := &streamStats{
+		numMessages: int64(h.NumResponses),
+		duration:    time.Since(h.start),
+	}
+	if !h.firstMsg.IsZero() {
+		ss.firstMsgLatency = h.firstMsg.Sub(h.start)
+	}
+	return ss
+}
+
+// compactMessages compacts the response messages and puts them in a JSON
+// array.
+func compactMessages(out []byte) (string, error) {
+	var msgs []string
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for {
+		var msg json.RawMessage
+		if err := dec.Decode(&msg); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return "", fmt.Errorf("error parsing response JSON (%s): %v", string(out), err)
+		}
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, msg); err != nil {
+			return "", fmt.Errorf("error compacting response JSON (%s): %v", string(msg), err)
+		}
+		msgs = append(msgs, buf.String())
+	}
+	return "[" + strings.Join(msgs, ",") + "]", nil
+}
+
+func (p *Probe) callServiceMethod(ctx context.Context, req *configpb.GenericRequest, descSrc grpcurl.DescriptorSource, conn *grpc.ClientConn) (response, *streamStats, error) {
 	in := strings.NewReader(req.GetBody())
 	rf, formatter, err := grpcurl.RequestParserAndFormatter(grpcurl.FormatJSON, descSrc, in, grpcurl.FormatOptions{})
 	if err != nil {
-		return "", fmt.Errorf("failed to construct parser and formatter: %v", err)
+		return "", nil, fmt.Errorf("failed to construct parser and formatter: %v", err)
 	}
 
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var stopped atomic.Bool
+	stop := func() {
+		stopped.Store(true)
+		cancel()
+	}
+	streamOpts := req.GetStreamOptions()
+
 	var out bytes.Buffer
-	h := &grpcurl.DefaultEventHandler{Out: &out, Formatter: formatter}
+	h := &streamHandler{
+		DefaultEventHandler: &grpcurl.DefaultEventHandler{Out: &out, Formatter: formatter},
+		maxMsgs:             int(streamOpts.GetMaxMessages()),
+		duration:            time.Duration(streamOpts.GetDurationMsec()) * time.Millisecond,
+		stop:                stop,
+		stopped:             &stopped,
+	}
+
+	err = grpcurl.InvokeRPC(ctx, descSrc, conn, req.GetCallServiceMethod(), nil, h, rf.Next)
+	if h.timer != nil {
+		h.timer.Stop()
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("error invoking gRPC: %v", err)
+	}
+	stats := h.stats()
 
-	if err := grpcurl.InvokeRPC(ctx, descSrc, conn, req.GetCallServiceMethod(), nil, h, rf.Next); err != nil {
-		return "", fmt.Errorf("error invoking gRPC: %v", err)
+	// If we stopped the stream ourselves, cancellation is expected.
+	if code := h.Status.Code(); code != codes.OK && !(stats != nil && stopped.Load() && code == codes.Canceled) {
+		return "", stats, fmt.Errorf("gRPC call failed: %s", h.Status.Message())
 	}
 
-	if h.Status.Code() != codes.OK {
-		return "", fmt.Errorf("gRPC call failed: %s", h.Status.Message())
+	if stats != nil && stats.numMessages < int64(streamOpts.GetMinMessages()) {
+		return "", stats, fmt.Errorf("received %d messages, min_messages: %d", stats.numMessages, streamOpts.GetMinMessages())
 	}
 
 	respBytes := out.Bytes()
+	if h.serverStreaming {
+		resp, err := compactMessages(respBytes)
+		return response(resp), stats, err
+	}
+
 	if len(respBytes) == 0 {
-		return "", nil
+		return "", stats, nil
 	}
 
 	var buf bytes.Buffer
 	if err := json.Compact(&buf, respBytes); err != nil {
-		return "", fmt.Errorf("error compacting response JSON (%s): %v", out.String(), err)
+		return "", stats, fmt.Errorf("error compacting response JSON (%s): %v", out.String(), err)
 	}
-	return response(buf.String()), nil
+	return response(buf.String()), stats, nil
 }
 
-func (p *Probe) genericRequest(ctx context.Context, conn *grpc.ClientConn, req *configpb.GenericRequest) (response, error) {
+func (p *Probe) genericRequest(ctx context.Context, conn *grpc.ClientConn, req *configpb.GenericRequest) (response, *streamStats, error) {
 	// If we didn't load protoset from a file, we'll get it everytime
 	// from the server.
 	descSrc := p.descSrc
@@ -103,24 +225,24 @@ func (p *Probe) genericRequest(ctx context.Context, conn *grpc.ClientConn, req *
 	case *configpb.GenericRequest_ListServices:
 		services, err := grpcurl.ListServices(descSrc)
 		if err != nil {
-			return "", fmt.Errorf("error listing services: %v", err)
+			return "", nil, fmt.Errorf("error listing services: %v", err)
 		}
-		return response(strings.Join(services, ",")), nil
+		return response(strings.Join(services, ",")), nil, nil
 	case *configpb.GenericRequest_ListServiceMethods:
 		methods, err := grpcurl.ListMethods(descSrc, req.GetListServiceMethods())
 		if err != nil {
-			return "", fmt.Errorf("error listing service (%s) methods: %v", req.GetListServiceMethods(), err)
+			return "", nil, fmt.Errorf("error listing service (%s) methods: %v", req.GetListServiceMethods(), err)
 		}
-		return response(strings.Join(methods, ",")), nil
+		return response(strings.Join(methods, ",")), nil, nil
 	case *configpb.GenericRequest_DescribeServiceMethod:
 		d, err := descSrc.FindSymbol(req.GetDescribeServiceMethod())
 		if err != nil {
-			return "", fmt.Errorf("error describing method(%s): %v", req.GetDescribeServiceMethod(), err)
+			return "", nil, fmt.Errorf("error describing method(%s): %v", req.GetDescribeServiceMethod(), err)
 		}
-		return response(strings.ReplaceAll(d.AsProto().String(), "  ", " ")), nil
+		return response(strings.ReplaceAll(d.AsProto().String(), "  ", " ")), nil, nil
 	case *configpb.GenericRequest_CallServiceMethod:
 		return p.callServiceMethod(ctx, req, descSrc, conn)
 	}
 
-	return "", fmt.Errorf("invalid request type: %v", req)
+	return "", nil, fmt.Errorf("invalid request type: %v", req)
 }
@@ -1,4 +1,4 @@
-// Copyright 2023 The Cloudprober Authors.
+// Copyright 2023-2026 The Cloudprober Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -16,16 +16,30 @@ package grpc
 
 import (
 	"context"
+	"fmt"
+	"net"
 	"os"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/cloudprober/cloudprober/internal/validators"
+	jsonvalidatorpb "github.com/cloudprober/cloudprober/internal/validators/json/proto"
+	validators_configpb "github.com/cloudprober/cloudprober/internal/validators/proto"
+	"github.com/cloudprober/cloudprober/metrics"
+	payloadpb "github.com/cloudprober/cloudprober/metrics/payload/proto"
+	"github.com/cloudprober/cloudprober/probes/common/sched"
 	configpb "github.com/cloudprober/cloudprober/probes/grpc/proto"
 	"github.com/cloudprober/cloudprober/probes/options"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/interop"
+	testgrpc "google.golang.org/grpc/interop/grpc_testing"
+	"google.golang.org/grpc/reflection"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -160,7 +174,7 @@ func TestGenericRequest(t *testing.T) {
 				return
 			}
 
-			resp, err := p.genericRequest(context.Background(), conn, p.c.GetRequest())
+			resp, _, err := p.genericRequest(context.Background(), conn, p.c.GetRequest())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Probe.genericRequest() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -235,3 +249,266 @@ func TestInitDescriptorSource(t *testing.T) {
 		})
 	}
 }
+
+// streamingTestServer starts a gRPC server implementing the gRPC interop test
+// service, which provides server-streaming, client-streaming and bidi
+// methods.
+func streamingTestServer(t *testing.T) *grpc.ClientConn {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+
+	srv := grpc.NewServer()
+	reflection.Register(srv)
+	testgrpc.RegisterTestServiceServer(srv, interop.NewTestServer())
+	go srv.Serve(ln)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient(ln.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// responseParams returns the response_parameters for n responses, each
+// delayed by the given interval.
+func responseParams(n int, interval time.Duration) string {
+	var params []string
+	for i := 0; i < n; i++ {
+		params = append(params, fmt.Sprintf(`{"size": 1, "intervalUs": %d}`, interval.Microseconds()))
+	}
+	return `"responseParameters": [` + strings.Join(params, ",") + `]`
+}
+
+func TestGenericRequestStreaming(t *testing.T) {
+	conn := streamingTestServer(t)
+
+	const (
+		serverStreaming = "grpc.testing.TestService.StreamingOutputCall"
+		clientStreaming = "grpc.testing.TestService.StreamingInputCall"
+		bidi            = "grpc.testing.TestService.FullDuplexCall"
+	)
+	msg := `{"payload":{"body":"AA=="}}`
+
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		streamOpts *configpb.GenericRequest_StreamOptions
+		timeout    time.Duration
+		wantResp   string
+		wantMsgs   int64
+		minDur     time.Duration
+		wantErr    bool
+	}{
+		{
+			name:     "server_streaming",
+			method:   serverStreaming,
+			body:     "{" + responseParams(3, 10*time.Millisecond) + "}",
+			wantResp: "[" + strings.Repeat(msg+",", 2) + msg + "]",
+			wantMsgs: 3,
+			minDur:   30 * time.Millisecond,
+		},
+		{
+			name:       "server_streaming_max_messages",
+			method:     serverStreaming,
+			body:       "{" + responseParams(5, 10*time.Millisecond) + "}",
+			streamOpts: &configpb.GenericRequest_StreamOptions{MaxMessages: proto.Int32(2)},
+			wantResp:   "[" + msg + "," + msg + "]",
+			wantMsgs:   2,
+			minDur:     20 * time.Millisecond,
+		},
+		{
+			name:       "server_streaming_duration",
+			method:     serverStreaming,
+			body:       "{" + responseParams(10, 100*time.Millisecond) + "}",
+			streamOpts: &configpb.GenericRequest_StreamOptions{DurationMsec: proto.Int32(250)},
+			wantResp:   "[" + msg + "," + msg + "]",
+			wantMsgs:   2,
+			minDur:     250 * time.Millisecond,
+		},
+		{
+			name:    "server_streaming_timeout",
+			method:  serverStreaming,
+			body:    "{" + responseParams(10, 50*time.Millisecond) + "}",
+			timeout: 120 * time.Millisecond,
+			wantErr: true,
+		},
+		{
+			name:       "server_streaming_min_messages",
+			method:     serverStreaming,
+			body:       "{" + responseParams(1, 0) + "}",
+			streamOpts: &configpb.GenericRequest_StreamOptions{MinMessages: proto.Int32(2)},
+			wantErr:    true,
+		},
+		{
+			name:     "client_streaming",
+			method:   clientStreaming,
+			body:     `{"payload":{"body":"AAA="}} {"payload":{"body":"AAAAAA=="}}`,
+			wantResp: `{"aggregatedPayloadSize":6}`,
+			wantMsgs: 1,
+		},
+		{
+			name:     "bidi",
+			method:   bidi,
+			body:     "{" + responseParams(1, 0) + "} {" + responseParams(2, 0) + "}",
+			wantResp: "[" + msg + "," + msg + "," + msg + "]",
+			wantMsgs: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Probe{}
+			opts := options.DefaultOptions()
+			opts.ProbeConf = &configpb.ProbeConf{
+				Method: configpb.ProbeConf_GENERIC.Enum(),
+				Request: &configpb.GenericRequest{
+					RequestType: &configpb.GenericRequest_CallServiceMethod{
+						CallServiceMethod: tt.method,
+					},
+					Body:          proto.String(tt.body),
+					StreamOptions: tt.streamOpts,
+				},
+			}
+			require.NoError(t, p.Init("test", opts))
+
+			ctx := context.Background()
+			if tt.timeout != 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			resp, stats, err := p.genericRequest(ctx, conn, p.c.GetRequest())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Probe.genericRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			require.NotNil(t, stats, "stream stats")
+			if tt.wantErr {
+				return
+			}
+
+			assert.Equal(t, response(tt.wantResp), resp)
+			assert.Equal(t, tt.wantMsgs, stats.numMessages)
+			assert.Greater(t, stats.firstMsgLatency, time.Duration(0))
+			assert.GreaterOrEqual(t, stats.duration, tt.minDur)
+			assert.GreaterOrEqual(t, stats.duration, stats.firstMsgLatency)
+		})
+	}
+}
+
+func TestStreamStatsMetrics(t *testing.T) {
+	p := &Probe{opts: options.DefaultOptions()}
+	p.opts.LatencyUnit = time.Millisecond
+
+	prr := &probeRunResult{latency: p.newLatencyValue()}
+	em := prr.Metrics(time.Now(), 1, p.opts)[0]
+	assert.Nil(t, em.Metric("stream_messages"), "stream metrics for non-streaming calls")
+
+	prr.addStreamStats(p, &streamStats{firstMsgLatency: 5 * time.Millisecond, numMessages: 3, duration: 20 * time.Millisecond})
+	prr.addStreamStats(p, &streamStats{duration: 10 * time.Millisecond})
+
+	em = prr.Metrics(time.Now(), 2, p.opts)[0]
+	assert.Equal(t, 5.0, em.Metric("first_message_latency").(metrics.NumValue).Float64())
+	assert.Equal(t, int64(3), em.Metric("stream_messages").(metrics.NumValue).Int64())
+	assert.Equal(t, 30.0, em.Metric("stream_duration").(metrics.NumValue).Float64())
+}
+
+func TestGenericRequestValidatorsAndMetrics(t *testing.T) {
+	addr, err := globalGRPCServer(0)
+	require.NoError(t, err, "Error starting global gRPC server")
+	host, portStr, _ := net.SplitHostPort(addr)
+	port, _ := strconv.Atoi(portStr)
+
+	tests := []struct {
+		name        string
+		jqFilter    string
+		wantSuccess bool
+		wantMetrics bool
+	}{
+		{
+			name:        "validation_success",
+			jqFilter:    `.blob == "test"`,
+			wantSuccess: true,
+			wantMetrics: true,
+		},
+		{
+			name:     "validation_failure",
+			jqFilter: `.blob == "test2"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := options.DefaultOptions()
+			opts.LatencyUnit = time.Millisecond
+			opts.ProbeConf = &configpb.ProbeConf{
+				Method:            configpb.ProbeConf_GENERIC.Enum(),
+				InsecureTransport: proto.Bool(true),
+				Request: &configpb.GenericRequest{
+					RequestType: &configpb.GenericRequest_CallServiceMethod{
+						CallServiceMethod: "cloudprober.servers.grpc.Prober.Echo",
+					},
+					Body: proto.String(`{"blob": "test"}`),
+				},
+				ResponseMetricsOptions: &payloadpb.OutputMetricsOptions{
+					JsonMetric: []*payloadpb.JSONMetric{{JqFilter: proto.String(`{"blob_ok": (.blob == "test")}`)}},
+				},
+			}
+			opts.Validators, err = validators.Init([]*validators_configpb.Validator{{
+				Name: "json",
+				Type: &validators_configpb.Validator_JsonValidator{JsonValidator: &jsonvalidatorpb.Validator{JqFilter: tt.jqFilter}},
+			}})
+			require.NoError(t, err)
+
+			p := &Probe{}
+			require.NoError(t, p.Init("test_generic", opts))
+
+			runReq := &sched.RunProbeForTargetRequest{
+				Target:  endpoint.Endpoint{Name: host, Port: port},
+				LastRun: &sched.LastRunResult{},
+			}
+			p.runProbeForTargetAndConnIndex(context.Background(), runReq)
+			assert.Equal(t, tt.wantSuccess, runReq.LastRun.Success, "last run error: %v", runReq.LastRun.Error)
+
+			ems := runReq.Result.Metrics(time.Now(), 1, p.opts)
+			wantFailures := int64(1)
+			if tt.wantSuccess {
+				wantFailures = 0
+			}
+			assert.Equal(t, wantFailures, ems[0].Metric("validation_failure").(*metrics.Map[int64]).GetKey("json"))
+
+			if !tt.wantMetrics {
+				assert.Len(t, ems, 1)
+				return
+			}
+			require.Len(t, ems, 2)
+			assert.Equal(t, "grpc", ems[1].Label("ptype"))
+			assert.Equal(t, int64(1), ems[1].Metric("blob_ok").(metrics.NumValue).Int64())
+		})
+	}
+}
+
+func TestInitResponseMetricsOptions(t *testing.T) {
+	for _, c := range []*configpb.ProbeConf{
+		{
+			Method:                 configpb.ProbeConf_ECHO.Enum(),
+			ResponseMetricsOptions: &payloadpb.OutputMetricsOptions{},
+		},
+		{
+			Method: configpb.ProbeConf_GENERIC.Enum(),
+			Request: &configpb.GenericRequest{
+				RequestType: &configpb.GenericRequest_ListServices{ListServices: true},
+			},
+			ResponseMetricsOptions: &payloadpb.OutputMetricsOptions{
+				HeaderMetric: []*payloadpb.HeaderMetric{{HeaderName: proto.String("date")}},
+			},
+		},
+	} {
+		opts := options.DefaultOptions()
+		opts.ProbeConf = c
+		assert.Error(t, (&Probe{}).Init("test", opts), "config: %v", c)
+	}
+}
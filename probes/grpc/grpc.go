@@ -42,6 +42,7 @@ import (
 	"github.com/cloudprober/cloudprober/internal/validators"
 	"github.com/cloudprober/cloudprober/logger"
 	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/metrics/payload"
 	"github.com/cloudprober/cloudprober/metrics/singlerun"
 	"github.com/cloudprober/cloudprober/probes/common/sched"
 	configpb "github.com/cloudprober/cloudprober/probes/grpc/proto"
@@ -91,6 +92,8 @@ type Probe struct {
 	creds    credentials.TransportCredentials
 	descSrc  grpcurl.DescriptorSource
 
+	responseParser *payload.Parser
+
 	targets []endpoint.Endpoint
 
 	// Results by target.
@@ -112,6 +115,35 @@ type probeRunResult struct {
 	connectErrors     metrics.Int
 	validationFailure *metrics.Map[int64]
 	lastRunID         int64
+
+	// Streaming call metrics, set only for the streaming GENERIC calls.
+	firstMsgLatency metrics.LatencyValue
+	streamMessages  metrics.Int
+	streamDuration  metrics.LatencyValue
+
+	// Metrics parsed from the GENERIC responses.
+	payloadMetrics []*metrics.EventMetrics
+}
+
+func (p *Probe) newLatencyValue() metrics.LatencyValue {
+	if p.opts.LatencyDist != nil {
+		return p.opts.LatencyDist.CloneDist()
+	}
+	return metrics.NewFloat(0)
+}
+
+// addStreamStats adds the stats of a streaming call to the result. Result
+// should be locked by the caller.
+func (prr *probeRunResult) addStreamStats(p *Probe, ss *streamStats) {
+	if prr.streamDuration == nil {
+		prr.firstMsgLatency = p.newLatencyValue()
+		prr.streamDuration = p.newLatencyValue()
+	}
+	if ss.numMessages > 0 {
+		prr.firstMsgLatency.AddFloat64(ss.firstMsgLatency.Seconds() / p.opts.LatencyUnit.Seconds())
+	}
+	prr.streamMessages.IncBy(ss.numMessages)
+	prr.streamDuration.AddFloat64(ss.duration.Seconds() / p.opts.LatencyUnit.Seconds())
 }
 
 func (p *Probe) newResult(target *endpoint.Endpoint) sched.ProbeResult {
@@ -123,15 +155,8 @@ func (p *Probe) newResult(target *endpoint.Endpoint) sched.ProbeResult {
 
 	result, ok := p.results[key]
 	if !ok {
-		var latencyValue metrics.LatencyValue
-		if p.opts.LatencyDist != nil {
-			latencyValue = p.opts.LatencyDist.CloneDist()
-		} else {
-			latencyValue = metrics.NewFloat(0)
-		}
-
 		result = &probeRunResult{
-			latency:           latencyValue,
+			latency:           p.newLatencyValue(),
 			validationFailure: validators.ValidationFailureMap(p.opts.Validators),
 		}
 
@@ -163,7 +188,16 @@ func (prr *probeRunResult) Metrics(ts time.Time, runID int64, opts *options.Opti
 		em.AddMetric("validation_failure", prr.validationFailure)
 	}
 
-	return []*metrics.EventMetrics{em}
+	if prr.streamDuration != nil {
+		em.AddMetric("first_message_latency", prr.firstMsgLatency.Clone()).
+			AddMetric("stream_messages", prr.streamMessages.Clone()).
+			AddMetric("stream_duration", prr.streamDuration.Clone())
+	}
+
+	ems := append([]*metrics.EventMetrics{em}, prr.payloadMetrics...)
+	prr.payloadMetrics = nil
+
+	return ems
 }
 
 func (p *Probe) transportCredentials() (credentials.TransportCredentials, error) {
@@ -310,6 +344,19 @@ func (p *Probe) Init(name string, opts *options.Options) error {
 		}
 	}
 
+	if p.c.GetResponseMetricsOptions() != nil {
+		if p.c.GetMethod() != configpb.ProbeConf_GENERIC {
+			return errors.New("response_metrics_options is supported only for the GENERIC method")
+		}
+		if len(p.c.GetResponseMetricsOptions().GetHeaderMetric()) > 0 {
+			return errors.New("header_metric is not supported for gRPC responses")
+		}
+		var err error
+		if p.responseParser, err = payload.NewParser(p.c.GetResponseMetricsOptions(), p.l); err != nil {
+			return fmt.Errorf("error initializing response metrics parser: %v", err)
+		}
+	}
+
 	// Initialize maps
 	p.results = make(map[string]*probeRunResult)
 	p.conns = make(map[string]*grpc.ClientConn)
@@ -463,6 +510,7 @@ func (p *Probe) runProbeForTargetAndConnIndex(ctx context.Context, runReq *sched
 
 	var success bool
 	var r fmt.Stringer
+	var stats *streamStats
 
 	getPaylod := func() []byte {
 		msg := make([]byte, p.c.GetBlobSize())
@@ -480,7 +528,7 @@ func (p *Probe) runProbeForTargetAndConnIndex(ctx context.Context, runReq *sched
 	case configpb.ProbeConf_HEALTH_CHECK:
 		r, err = p.healthCheckProbe(reqCtx, conn, l)
 	case configpb.ProbeConf_GENERIC:
-		r, err = p.genericRequest(reqCtx, conn, p.c.GetRequest())
+		r, stats, err = p.genericRequest(reqCtx, conn, p.c.GetRequest())
 	default:
 		p.l.Criticalf("Method %v not implemented", p.c.GetMethod())
 	}
@@ -507,12 +555,23 @@ func (p *Probe) runProbeForTargetAndConnIndex(ctx context.Context, runReq *sched
 		}
 	}
 
+	var payloadMetrics []*metrics.EventMetrics
+	if success && p.responseParser != nil {
+		payloadMetrics = p.responseParser.PayloadMetrics(&payload.Input{Text: []byte(r.String())}, runReq.Target.Dst())
+	}
+
 	result.Lock()
 	result.total.Inc()
 	if success {
 		result.success.Inc()
 	}
+	for _, em := range payloadMetrics {
+		result.payloadMetrics = append(result.payloadMetrics, em.AddLabel("ptype", "grpc"))
+	}
 	result.latency.AddFloat64(delta.Seconds() / p.opts.LatencyUnit.Seconds())
+	if stats != nil {
+		result.addStreamStats(p, stats)
+	}
 	result.Unlock()
 
 	runReq.LastRun.Set(success, time.Since(start), err)
@@ -9,6 +9,7 @@ package proto
 import (
 	proto "github.com/cloudprober/cloudprober/common/oauth/proto"
 	proto1 "github.com/cloudprober/cloudprober/common/tlsconfig/proto"
+	proto2 "github.com/cloudprober/cloudprober/metrics/payload/proto"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -105,14 +106,17 @@ type GenericRequest struct {
 	//	*GenericRequest_DescribeServiceMethod
 	//	*GenericRequest_CallServiceMethod
 	RequestType isGenericRequest_RequestType `protobuf_oneof:"request_type"`
-	// Request data (in JSON format) for the call_service_method request.
+	// Request data (in JSON format) for the call_service_method request. For
+	// client-streaming and bidi methods, body can contain multiple JSON
+	// messages, e.g. {"id": 1} {"id": 2}. They are sent in order.
 	Body *string `protobuf:"bytes,6,opt,name=body" json:"body,omitempty"`
 	// Request body from file. This field is similar to the body field above, but
 	// value is read from a file.
 	BodyFile *string `protobuf:"bytes,7,opt,name=body_file,json=bodyFile" json:"body_file,omitempty"`
 	// Substitute env variables in body file. It will expand environment
 	// variables if you refer to them as ${VARIABLE} or $VARIABLE in the file.
-	BodyFileSubstituteEnv *bool `protobuf:"varint,8,opt,name=body_file_substitute_env,json=bodyFileSubstituteEnv" json:"body_file_substitute_env,omitempty"`
+	BodyFileSubstituteEnv *bool                         `protobuf:"varint,8,opt,name=body_file_substitute_env,json=bodyFileSubstituteEnv" json:"body_file_substitute_env,omitempty"`
+	StreamOptions         *GenericRequest_StreamOptions `protobuf:"bytes,9,opt,name=stream_options,json=streamOptions" json:"stream_options,omitempty"`
 	unknownFields         protoimpl.UnknownFields
 	sizeCache             protoimpl.SizeCache
 }
@@ -218,6 +222,13 @@ func (x *GenericRequest) GetBodyFileSubstituteEnv() bool {
 	return false
 }
 
+func (x *GenericRequest) GetStreamOptions() *GenericRequest_StreamOptions {
+	if x != nil {
+		return x.StreamOptions
+	}
+	return nil
+}
+
 type isGenericRequest_RequestType interface {
 	isGenericRequest_RequestType()
 }
@@ -252,7 +263,7 @@ func (*GenericRequest_DescribeServiceMethod) isGenericRequest_RequestType() {}
 
 func (*GenericRequest_CallServiceMethod) isGenericRequest_RequestType() {}
 
-// Next tag: 14
+// Next tag: 18
 type ProbeConf struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Port for gRPC requests (Corresponding target field: port)
@@ -309,10 +320,22 @@ type ProbeConf struct {
 	// URI scheme allows gRPC to use different resolvers
 	// Example URI scheme: "google-c2p:///"
 	// See https://github.com/grpc/grpc/blob/master/doc/naming.md for more details
-	UriScheme     *string             `protobuf:"bytes,8,opt,name=uri_scheme,json=uriScheme,def=dns:///" json:"uri_scheme,omitempty"`
-	Headers       []*ProbeConf_Header `protobuf:"bytes,13,rep,name=headers" json:"headers,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	UriScheme *string             `protobuf:"bytes,8,opt,name=uri_scheme,json=uriScheme,def=dns:///" json:"uri_scheme,omitempty"`
+	Headers   []*ProbeConf_Header `protobuf:"bytes,13,rep,name=headers" json:"headers,omitempty"`
+	// Parse the GENERIC method's response as additional metrics. Response
+	// messages are in JSON format, so json_metric is the natural fit here, e.g.
+	// for a response like {"stats": {"queue_depth": 12}}:
+	//
+	//	response_metrics_options {
+	//	  json_metric {
+	//	    jq_filter: "{\"queue_depth\": .stats.queue_depth}"
+	//	  }
+	//	}
+	//
+	// Note that header_metric is not supported for gRPC.
+	ResponseMetricsOptions *proto2.OutputMetricsOptions `protobuf:"bytes,17,opt,name=response_metrics_options,json=responseMetricsOptions" json:"response_metrics_options,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
 }
 
 // Default values for ProbeConf fields.
@@ -464,6 +487,90 @@ func (x *ProbeConf) GetHeaders() []*ProbeConf_Header {
 	return nil
 }
 
+func (x *ProbeConf) GetResponseMetricsOptions() *proto2.OutputMetricsOptions {
+	if x != nil {
+		return x.ResponseMetricsOptions
+	}
+	return nil
+}
+
+// Options for the streaming methods (server-streaming, client-streaming and
+// bidi). Type of the method is determined from its descriptor. For these
+// methods, probe exports the following additional metrics:
+//
+//	first_message_latency: time to the first response message.
+//	stream_messages: number of response messages received.
+//	stream_duration: time from the start of the call to the end of stream.
+//
+// For server-streaming and bidi methods, response messages are put in a
+// JSON array before running validators, e.g. [{"id":1},{"id":2}].
+type GenericRequest_StreamOptions struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Stop reading the stream after receiving these many messages. By
+	// default, we read until the server closes the stream.
+	MaxMessages *int32 `protobuf:"varint,1,opt,name=max_messages,json=maxMessages" json:"max_messages,omitempty"`
+	// Stop reading the stream after this duration. This is useful for the
+	// watch/subscribe APIs where the server never closes the stream. Note
+	// that it should be less than the probe timeout, otherwise the probe
+	// will time out before the stream is stopped.
+	DurationMsec *int32 `protobuf:"varint,2,opt,name=duration_msec,json=durationMsec" json:"duration_msec,omitempty"`
+	// Minimum number of response messages required for success.
+	MinMessages   *int32 `protobuf:"varint,3,opt,name=min_messages,json=minMessages" json:"min_messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenericRequest_StreamOptions) Reset() {
+	*x = GenericRequest_StreamOptions{}
+	mi := &file_github_com_cloudprober_cloudprober_probes_grpc_proto_config_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenericRequest_StreamOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenericRequest_StreamOptions) ProtoMessage() {}
+
+func (x *GenericRequest_StreamOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_probes_grpc_proto_config_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenericRequest_StreamOptions.ProtoReflect.Descriptor instead.
+func (*GenericRequest_StreamOptions) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_probes_grpc_proto_config_proto_rawDescGZIP(), []int{0, 0}
+}
+
+func (x *GenericRequest_StreamOptions) GetMaxMessages() int32 {
+	if x != nil && x.MaxMessages != nil {
+		return *x.MaxMessages
+	}
+	return 0
+}
+
+func (x *GenericRequest_StreamOptions) GetDurationMsec() int32 {
+	if x != nil && x.DurationMsec != nil {
+		return *x.DurationMsec
+	}
+	return 0
+}
+
+func (x *GenericRequest_StreamOptions) GetMinMessages() int32 {
+	if x != nil && x.MinMessages != nil {
+		return *x.MinMessages
+	}
+	return 0
+}
+
 // ALTS is a gRPC security method supported by some Google services.
 // If enabled, peers, with the help of a handshaker service (e.g. metadata
 // server of GCE instances), use credentials attached to the service accounts
@@ -484,7 +591,7 @@ type ProbeConf_ALTSConfig struct {
 
 func (x *ProbeConf_ALTSConfig) Reset() {
 	*x = ProbeConf_ALTSConfig{}
-	mi := &file_github_com_cloudprober_cloudprober_probes_grpc_proto_config_proto_msgTypes[2]
+	mi := &file_github_com_cloudprober_cloudprober_probes_grpc_proto_config_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -496,7 +603,7 @@ func (x *ProbeConf_ALTSConfig) String() string {
 func (*ProbeConf_ALTSConfig) ProtoMessage() {}
 
 func (x *ProbeConf_ALTSConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_github_com_cloudprober_cloudprober_probes_grpc_proto_config_proto_msgTypes[2]
+	mi := &file_github_com_cloudprober_cloudprober_probes_grpc_proto_config_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -536,7 +643,7 @@ type ProbeConf_Header struct {
 
 func (x *ProbeConf_Header) Reset() {
 	*x = ProbeConf_Header{}
-	mi := &file_github_com_cloudprober_cloudprober_probes_grpc_proto_config_proto_msgTypes[3]
+	mi := &file_github_com_cloudprober_cloudprober_probes_grpc_proto_config_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -548,7 +655,7 @@ func (x *ProbeConf_Header) String() string {
 func (*ProbeConf_Header) ProtoMessage() {}
 
 func (x *ProbeConf_Header) ProtoReflect() protoreflect.Message {
-	mi := &file_github_com_cloudprober_cloudprober_probes_grpc_proto_config_proto_msgTypes[3]
+	mi := &file_github_com_cloudprober_cloudprober_probes_grpc_proto_config_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -582,7 +689,7 @@ var File_github_com_cloudprober_cloudprober_probes_grpc_proto_config_proto proto
 
 const file_github_com_cloudprober_cloudprober_probes_grpc_proto_config_proto_rawDesc = "" +
 	"\n" +
-	"Agithub.com/cloudprober/cloudprober/probes/grpc/proto/config.proto\x12\x17cloudprober.probes.grpc\x1aBgithub.com/cloudprober/cloudprober/common/oauth/proto/config.proto\x1aFgithub.com/cloudprober/cloudprober/common/tlsconfig/proto/config.proto\"\xf6\x02\n" +
+	"Agithub.com/cloudprober/cloudprober/probes/grpc/proto/config.proto\x12\x17cloudprober.probes.grpc\x1aBgithub.com/cloudprober/cloudprober/common/oauth/proto/config.proto\x1aFgithub.com/cloudprober/cloudprober/common/tlsconfig/proto/config.proto\x1aEgithub.com/cloudprober/cloudprober/metrics/payload/proto/config.proto\"\xd0\x04\n" +
 	"\x0eGenericRequest\x12#\n" +
 	"\rprotoset_file\x18\x01 \x01(\tR\fprotosetFile\x12%\n" +
 	"\rlist_services\x18\x02 \x01(\bH\x00R\flistServices\x122\n" +
@@ -591,8 +698,13 @@ const file_github_com_cloudprober_cloudprober_probes_grpc_proto_config_proto_raw
 	"\x13call_service_method\x18\x05 \x01(\tH\x00R\x11callServiceMethod\x12\x12\n" +
 	"\x04body\x18\x06 \x01(\tR\x04body\x12\x1b\n" +
 	"\tbody_file\x18\a \x01(\tR\bbodyFile\x127\n" +
-	"\x18body_file_substitute_env\x18\b \x01(\bR\x15bodyFileSubstituteEnvB\x0e\n" +
-	"\frequest_type\"\xd8\b\n" +
+	"\x18body_file_substitute_env\x18\b \x01(\bR\x15bodyFileSubstituteEnv\x12\\\n" +
+	"\x0estream_options\x18\t \x01(\v25.cloudprober.probes.grpc.GenericRequest.StreamOptionsR\rstreamOptions\x1az\n" +
+	"\rStreamOptions\x12!\n" +
+	"\fmax_messages\x18\x01 \x01(\x05R\vmaxMessages\x12#\n" +
+	"\rduration_msec\x18\x02 \x01(\x05R\fdurationMsec\x12!\n" +
+	"\fmin_messages\x18\x03 \x01(\x05R\vminMessagesB\x0e\n" +
+	"\frequest_type\"\xc5\t\n" +
 	"\tProbeConf\x12\x12\n" +
 	"\x04port\x18\x06 \x01(\x05R\x04port\x12<\n" +
 	"\foauth_config\x18\x01 \x01(\v2\x19.cloudprober.oauth.ConfigR\voauthConfig\x12N\n" +
@@ -613,7 +725,8 @@ const file_github_com_cloudprober_cloudprober_probes_grpc_proto_config_proto_raw
 	"\x14connect_timeout_msec\x18\a \x01(\x05R\x12connectTimeoutMsec\x12&\n" +
 	"\n" +
 	"uri_scheme\x18\b \x01(\t:\adns:///R\turiScheme\x12C\n" +
-	"\aheaders\x18\r \x03(\v2).cloudprober.probes.grpc.ProbeConf.HeaderR\aheaders\x1a\x80\x01\n" +
+	"\aheaders\x18\r \x03(\v2).cloudprober.probes.grpc.ProbeConf.HeaderR\aheaders\x12k\n" +
+	"\x18response_metrics_options\x18\x11 \x01(\v21.cloudprober.metrics.payload.OutputMetricsOptionsR\x16responseMetricsOptions\x1a\x80\x01\n" +
 	"\n" +
 	"ALTSConfig\x124\n" +
 	"\x16target_service_account\x18\x01 \x03(\tR\x14targetServiceAccount\x12<\n" +
@@ -642,28 +755,32 @@ func file_github_com_cloudprober_cloudprober_probes_grpc_proto_config_proto_rawD
 }
 
 var file_github_com_cloudprober_cloudprober_probes_grpc_proto_config_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_github_com_cloudprober_cloudprober_probes_grpc_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_github_com_cloudprober_cloudprober_probes_grpc_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_github_com_cloudprober_cloudprober_probes_grpc_proto_config_proto_goTypes = []any{
-	(ProbeConf_MethodType)(0),    // 0: cloudprober.probes.grpc.ProbeConf.MethodType
-	(*GenericRequest)(nil),       // 1: cloudprober.probes.grpc.GenericRequest
-	(*ProbeConf)(nil),            // 2: cloudprober.probes.grpc.ProbeConf
-	(*ProbeConf_ALTSConfig)(nil), // 3: cloudprober.probes.grpc.ProbeConf.ALTSConfig
-	(*ProbeConf_Header)(nil),     // 4: cloudprober.probes.grpc.ProbeConf.Header
-	(*proto.Config)(nil),         // 5: cloudprober.oauth.Config
-	(*proto1.TLSConfig)(nil),     // 6: cloudprober.tlsconfig.TLSConfig
+	(ProbeConf_MethodType)(0),            // 0: cloudprober.probes.grpc.ProbeConf.MethodType
+	(*GenericRequest)(nil),               // 1: cloudprober.probes.grpc.GenericRequest
+	(*ProbeConf)(nil),                    // 2: cloudprober.probes.grpc.ProbeConf
+	(*GenericRequest_StreamOptions)(nil), // 3: cloudprober.probes.grpc.GenericRequest.StreamOptions
+	(*ProbeConf_ALTSConfig)(nil),         // 4: cloudprober.probes.grpc.ProbeConf.ALTSConfig
+	(*ProbeConf_Header)(nil),             // 5: cloudprober.probes.grpc.ProbeConf.Header
+	(*proto.Config)(nil),                 // 6: cloudprober.oauth.Config
+	(*proto1.TLSConfig)(nil),             // 7: cloudprober.tlsconfig.TLSConfig
+	(*proto2.OutputMetricsOptions)(nil),  // 8: cloudprober.metrics.payload.OutputMetricsOptions
 }
 var file_github_com_cloudprober_cloudprober_probes_grpc_proto_config_proto_depIdxs = []int32{
-	5, // 0: cloudprober.probes.grpc.ProbeConf.oauth_config:type_name -> cloudprober.oauth.Config
-	3, // 1: cloudprober.probes.grpc.ProbeConf.alts_config:type_name -> cloudprober.probes.grpc.ProbeConf.ALTSConfig
-	6, // 2: cloudprober.probes.grpc.ProbeConf.tls_config:type_name -> cloudprober.tlsconfig.TLSConfig
-	0, // 3: cloudprober.probes.grpc.ProbeConf.method:type_name -> cloudprober.probes.grpc.ProbeConf.MethodType
-	1, // 4: cloudprober.probes.grpc.ProbeConf.request:type_name -> cloudprober.probes.grpc.GenericRequest
-	4, // 5: cloudprober.probes.grpc.ProbeConf.headers:type_name -> cloudprober.probes.grpc.ProbeConf.Header
-	6, // [6:6] is the sub-list for method output_type
-	6, // [6:6] is the sub-list for method input_type
-	6, // [6:6] is the sub-list for extension type_name
-	6, // [6:6] is the sub-list for extension extendee
-	0, // [0:6] is the sub-list for field type_name
+	3, // 0: cloudprober.probes.grpc.GenericRequest.stream_options:type_name -> cloudprober.probes.grpc.GenericRequest.StreamOptions
+	6, // 1: cloudprober.probes.grpc.ProbeConf.oauth_config:type_name -> cloudprober.oauth.Config
+	4, // 2: cloudprober.probes.grpc.ProbeConf.alts_config:type_name -> cloudprober.probes.grpc.ProbeConf.ALTSConfig
+	7, // 3: cloudprober.probes.grpc.ProbeConf.tls_config:type_name -> cloudprober.tlsconfig.TLSConfig
+	0, // 4: cloudprober.probes.grpc.ProbeConf.method:type_name -> cloudprober.probes.grpc.ProbeConf.MethodType
+	1, // 5: cloudprober.probes.grpc.ProbeConf.request:type_name -> cloudprober.probes.grpc.GenericRequest
+	5, // 6: cloudprober.probes.grpc.ProbeConf.headers:type_name -> cloudprober.probes.grpc.ProbeConf.Header
+	8, // 7: cloudprober.probes.grpc.ProbeConf.response_metrics_options:type_name -> cloudprober.metrics.payload.OutputMetricsOptions
+	8, // [8:8] is the sub-list for method output_type
+	8, // [8:8] is the sub-list for method input_type
+	8, // [8:8] is the sub-list for extension type_name
+	8, // [8:8] is the sub-list for extension extendee
+	0, // [0:8] is the sub-list for field type_name
 }
 
 func init() { file_github_com_cloudprober_cloudprober_probes_grpc_proto_config_proto_init() }
@@ -683,7 +800,7 @@ func file_github_com_cloudprober_cloudprober_probes_grpc_proto_config_proto_init
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_github_com_cloudprober_cloudprober_probes_grpc_proto_config_proto_rawDesc), len(file_github_com_cloudprober_cloudprober_probes_grpc_proto_config_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   4,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,137 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudprober/cloudprober/probes/common/failedresponses"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+)
+
+// requestTimings records a request's timing breakdown, to be kept along with
+// the failed responses. Unlike latencyDetails, it's per request and not
+// aggregated.
+type requestTimings struct {
+	mu                                                     sync.Mutex
+	dnsStart, connectStart, tlsStart, writeStart, connTime time.Time
+	dns, connect, tlsHandshake, reqWrite, firstByte        time.Duration
+}
+
+func (rt *requestTimings) start(t *time.Time) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	*t = time.Now()
+}
+
+func (rt *requestTimings) done(d *time.Duration, start *time.Time) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	if !start.IsZero() {
+		*d = time.Since(*start)
+	}
+}
+
+func (rt *requestTimings) trace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart:             func(_ httptrace.DNSStartInfo) { rt.start(&rt.dnsStart) },
+		DNSDone:              func(_ httptrace.DNSDoneInfo) { rt.done(&rt.dns, &rt.dnsStart) },
+		ConnectStart:         func(_, _ string) { rt.start(&rt.connectStart) },
+		ConnectDone:          func(_, _ string, _ error) { rt.done(&rt.connect, &rt.connectStart) },
+		TLSHandshakeStart:    func() { rt.start(&rt.tlsStart) },
+		TLSHandshakeDone:     func(_ tls.ConnectionState, _ error) { rt.done(&rt.tlsHandshake, &rt.tlsStart) },
+		WroteHeaders:         func() { rt.start(&rt.writeStart) },
+		WroteRequest:         func(_ httptrace.WroteRequestInfo) { rt.done(&rt.reqWrite, &rt.writeStart) },
+		GotConn:              func(_ httptrace.GotConnInfo) { rt.start(&rt.connTime) },
+		GotFirstResponseByte: func() { rt.done(&rt.firstByte, &rt.connTime) },
+	}
+}
+
+// redactedValue replaces the sensitive values in the failed responses, as
+// these responses are served on the status pages.
+const redactedValue = "REDACTED"
+
+// defaultRedactHeaders are the response headers that are always redacted in
+// the failed responses.
+var defaultRedactHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// redactURL returns the URL string with query parameter values and password
+// redacted, as these often carry credentials.
+func redactURL(u *url.URL) string {
+	if u.RawQuery == "" && u.User == nil {
+		return u.String()
+	}
+	ru := *u
+	if _, ok := u.User.Password(); ok {
+		ru.User = url.UserPassword(u.User.Username(), redactedValue)
+	}
+	if u.RawQuery != "" {
+		q := u.Query()
+		for k := range q {
+			q[k] = []string{redactedValue}
+		}
+		ru.RawQuery = q.Encode()
+	}
+	return ru.String()
+}
+
+// redactHeader returns a copy of the header with the values of the headers
+// in p.redactHeaders redacted.
+func (p *Probe) redactHeader(h http.Header) http.Header {
+	h = h.Clone()
+	for k := range h {
+		if p.redactHeaders[k] {
+			h[k] = []string{redactedValue}
+		}
+	}
+	return h
+}
+
+// recordFailedResponse adds a failed response to the probe's failed responses
+// buffer. resp and body can be nil if request failed before getting a
+// response.
+func (p *Probe) recordFailedResponse(target endpoint.Endpoint, req *http.Request, resp *http.Response, body []byte, err error, latency time.Duration, rt *requestTimings) {
+	if p.failedResponses == nil {
+		return
+	}
+
+	// Transport errors include the request URL, redact it there as well.
+	u := redactURL(req.URL)
+	r := &failedresponses.Response{
+		Time:    time.Now(),
+		URL:     u,
+		Body:    body,
+		Error:   strings.ReplaceAll(err.Error(), req.URL.String(), u),
+		Latency: latency,
+	}
+	if resp != nil {
+		r.StatusCode = resp.StatusCode
+		r.Header = p.redactHeader(resp.Header)
+	}
+	if rt != nil {
+		rt.mu.Lock()
+		r.DNSLatency, r.ConnectLatency, r.TLSHandshakeLatency = rt.dns, rt.connect, rt.tlsHandshake
+		r.ReqWriteLatency, r.FirstByteLatency = rt.reqWrite, rt.firstByte
+		rt.mu.Unlock()
+	}
+
+	p.failedResponses.Add(target.Dst(), r)
+}
@@ -40,8 +40,11 @@ import (
 	"github.com/cloudprober/cloudprober/metrics"
 	"github.com/cloudprober/cloudprober/metrics/payload"
 	"github.com/cloudprober/cloudprober/metrics/singlerun"
+	"github.com/cloudprober/cloudprober/probes/common/failedresponses"
 	"github.com/cloudprober/cloudprober/probes/common/sched"
+	"github.com/cloudprober/cloudprober/probes/common/tlsinspect"
 	configpb "github.com/cloudprober/cloudprober/probes/http/proto"
+	"github.com/cloudprober/cloudprober/probes/http/signer"
 	"github.com/cloudprober/cloudprober/probes/options"
 	"github.com/cloudprober/cloudprober/targets/endpoint"
 	"golang.org/x/oauth2"
@@ -66,10 +69,26 @@ type Probe struct {
 	method  string
 	url     string
 	oauthTS oauth2.TokenSource
+	signer  signer.Signer
 
 	responseParser *payload.Parser
 
 	requestBody *httpreq.RequestBody
+
+	// bodyTemplate is set if request body uses placeholders, in which case
+	// request body is computed for each target.
+	bodyTemplate []string
+	// useRequestVars is true if request uses request level placeholders,
+	// e.g. @uuid@.
+	useRequestVars bool
+
+	// failedResponses keeps the recent failed responses, if enabled.
+	failedResponses *failedresponses.Buffer
+	// redactHeaders are the canonical names of the response headers that are
+	// redacted in failed responses.
+	redactHeaders map[string]bool
+
+	tlsInspector *tlsinspect.Inspector
 }
 
 type latencyDetails struct {
@@ -85,6 +104,9 @@ type probeResult struct {
 	validationFailure            *metrics.Map[int64]
 	latencyBreakdown             *latencyDetails
 	sslEarliestExpirationSeconds int64
+	altSvcChecked                bool
+	http3Advertised              bool
+	tlsInspection                *tlsinspect.Result
 	payloadMetrics               []*metrics.EventMetrics
 }
 
@@ -150,7 +172,18 @@ func (p *Probe) getTransport() (*http.Transport, error) {
 		}
 	}
 
-	if p.c.GetDisableHttp2() {
+	switch p.c.GetHttpVersion() {
+	case configpb.ProbeConf_HTTP_1_1:
+		transport.Protocols = new(http.Protocols)
+		transport.Protocols.SetHTTP1(true)
+	case configpb.ProbeConf_HTTP_2:
+		transport.Protocols = new(http.Protocols)
+		transport.Protocols.SetHTTP2(true)
+		transport.Protocols.SetUnencryptedHTTP2(true)
+	}
+
+	// http_version, if set, takes precedence over disable_http2.
+	if p.c.GetDisableHttp2() && p.c.GetHttpVersion() == configpb.ProbeConf_AUTO {
 		// HTTP/2 is enabled by default if server supports it. Setting
 		// TLSNextProto to an empty dict is the only way to disable it.
 		// This only works if transport hasn't been previously cloned.
@@ -186,6 +219,11 @@ func (p *Probe) Init(name string, opts *options.Options) error {
 			totalDuration, p.opts.Interval)
 	}
 
+	// In dual-stack mode, targets come with the IP to probe.
+	if p.opts.DualStack && p.c.ResolveFirst != nil && !p.c.GetResolveFirst() {
+		return fmt.Errorf("resolve_first cannot be false with dual_stack")
+	}
+
 	p.method = p.c.GetMethod().String()
 
 	p.url = p.c.GetRelativeUrl()
@@ -202,6 +240,18 @@ func (p *Probe) Init(name string, opts *options.Options) error {
 		body = []string{string(b)}
 	}
 	p.requestBody = httpreq.NewRequestBody(body...)
+	if hasPlaceholders(body...) {
+		p.bodyTemplate = body
+	}
+
+	templatedFields := append([]string{p.url}, body...)
+	for _, v := range p.c.GetHeader() {
+		templatedFields = append(templatedFields, v)
+	}
+	for _, h := range p.c.GetHeaders() {
+		templatedFields = append(templatedFields, h.GetValue())
+	}
+	p.useRequestVars = hasRequestVars(templatedFields...)
 
 	if p.c.GetOauthConfig() != nil {
 		oauthTS, err := oauth.TokenSourceFromConfig(p.c.GetOauthConfig(), p.l)
@@ -211,13 +261,24 @@ func (p *Probe) Init(name string, opts *options.Options) error {
 		p.oauthTS = oauthTS
 	}
 
-	transport, err := p.getTransport()
+	if p.c.GetRequestSigner() != nil {
+		s, err := signer.New(context.Background(), p.c.GetRequestSigner())
+		if err != nil {
+			return fmt.Errorf("request_signer config error: %v", err)
+		}
+		p.signer = s
+	}
+
+	var err error
+	if p.c.GetHttpVersion() == configpb.ProbeConf_HTTP_3 {
+		p.baseTransport, err = p.getHTTP3Transport()
+	} else {
+		p.baseTransport, err = p.getTransport()
+	}
 	if err != nil {
 		return err
 	}
 
-	p.baseTransport = transport
-
 	if p.c.MaxRedirects != nil {
 		p.redirectFunc = func(req *http.Request, via []*http.Request) error {
 			if len(via) > int(p.c.GetMaxRedirects()) {
@@ -234,6 +295,21 @@ func (p *Probe) Init(name string, opts *options.Options) error {
 		}
 	}
 
+	if p.c.GetTlsInspect() != nil {
+		p.tlsInspector, err = tlsinspect.New(p.c.GetTlsInspect())
+		if err != nil {
+			return fmt.Errorf("tls_inspect config error: %v", err)
+		}
+	}
+
+	if p.c.GetKeepFailedResponses() > 0 {
+		p.failedResponses = failedresponses.NewBuffer(int(p.c.GetKeepFailedResponses()), int(p.c.GetFailedResponseMaxBodyBytes()))
+		p.redactHeaders = make(map[string]bool)
+		for _, h := range append(defaultRedactHeaders, p.c.GetFailedResponseRedactHeaders()...) {
+			p.redactHeaders[http.CanonicalHeaderKey(h)] = true
+		}
+	}
+
 	p.targets = p.opts.Targets.ListEndpoints()
 
 	return nil
@@ -317,6 +393,12 @@ func (p *Probe) doHTTPRequest(req *http.Request, client *http.Client, target end
 		req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
 	}
 
+	var timings *requestTimings
+	if p.failedResponses != nil {
+		timings = &requestTimings{}
+		req = req.WithContext(httptrace.WithClientTrace(req.Context(), timings.trace()))
+	}
+
 	resp, err := client.Do(req)
 	latency := time.Since(start)
 
@@ -337,18 +419,22 @@ func (p *Probe) doHTTPRequest(req *http.Request, client *http.Client, target end
 			return nil
 		}
 		l.Warning(err.Error())
+		p.recordFailedResponse(target, req, nil, nil, err, latency, timings)
 		return err
 	}
 
 	if p.opts.NegativeTest {
 		resp.Body.Close()
 		l.Error("Negative test, but HTTP request succeeded for: ", req.URL.String())
-		return errors.New("negative test: request succeeded unexpectedly")
+		err := errors.New("negative test: request succeeded unexpectedly")
+		p.recordFailedResponse(target, req, resp, nil, err, latency, timings)
+		return err
 	}
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
 		l.Warning(err.Error())
+		p.recordFailedResponse(target, req, resp, respBody, err, latency, timings)
 		return err
 	}
 
@@ -371,6 +457,15 @@ func (p *Probe) doHTTPRequest(req *http.Request, client *http.Client, target end
 		result.sslEarliestExpirationSeconds = int64(minExpirySeconds)
 	}
 
+	if p.tlsInspector != nil && resp.TLS != nil {
+		result.tlsInspection = p.tlsInspector.Inspect(resp.TLS, req.URL.Hostname(), time.Now())
+	}
+
+	if p.c.GetAltSvcDiscovery() {
+		result.altSvcChecked = true
+		result.http3Advertised = advertisesHTTP3(resp.Header.Values("Alt-Svc"))
+	}
+
 	if p.opts.Validators != nil {
 		failedValidations := validators.RunValidators(p.opts.Validators, &validators.Input{Response: resp, ResponseBody: respBody}, result.validationFailure, l)
 
@@ -379,7 +474,9 @@ func (p *Probe) doHTTPRequest(req *http.Request, client *http.Client, target end
 		if len(failedValidations) > 0 {
 			msg := fmt.Sprintf("failed validations: %s", strings.Join(failedValidations, ","))
 			l.Error(msg)
-			return errors.New(msg)
+			err := errors.New(msg)
+			p.recordFailedResponse(target, req, resp, respBody, err, latency, timings)
+			return err
 		}
 	}
 
@@ -398,6 +495,20 @@ func (p *Probe) doHTTPRequest(req *http.Request, client *http.Client, target end
 	return nil
 }
 
+// advertisesHTTP3 returns true if the Alt-Svc header values advertise HTTP/3,
+// e.g.: Alt-Svc: h3=":443"; ma=86400, h3-29=":443"
+func advertisesHTTP3(altSvc []string) bool {
+	for _, v := range altSvc {
+		for _, alt := range strings.Split(v, ",") {
+			protocolID, _, _ := strings.Cut(strings.TrimSpace(alt), "=")
+			if protocolID == "h3" || strings.HasPrefix(protocolID, "h3-") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (p *Probe) parseLatencyBreakdown(baseLatencyValue metrics.LatencyValue) *latencyDetails {
 	if len(p.c.GetLatencyBreakdown()) == 0 {
 		return nil
@@ -511,6 +622,23 @@ func (result *probeResult) Metrics(ts time.Time, runID int64, opts *options.Opti
 		ems = append(ems, em)
 	}
 
+	// HTTP/3 advertisement (through Alt-Svc header) is a GAUGE metric as well.
+	if result.altSvcChecked {
+		var advertised int64
+		if result.http3Advertised {
+			advertised = 1
+		}
+		em := metrics.NewEventMetrics(ts).
+			AddMetric("http3_advertised", metrics.NewInt(advertised))
+		em.Kind = metrics.GAUGE
+		em.SetNotForAlerting()
+		em.AddLabel("ptype", "http")
+		ems = append(ems, em)
+	}
+
+	// TLS inspection metrics are GAUGE metrics too.
+	ems = append(ems, result.tlsInspection.EventMetrics(ts, "http")...)
+
 	// Append any payload metrics and reset.
 	// If there is only one timestamp, use the same timestamp for all metrics.
 	timestamps := map[time.Time]bool{}
@@ -544,12 +672,21 @@ func (p *Probe) httpClient(target endpoint.Endpoint) *http.Client {
 				t.TLSClientConfig = &tls.Config{}
 			}
 			if t.TLSClientConfig.ServerName == "" {
-				t.TLSClientConfig.ServerName = hostForTarget(target)
+				t.TLSClientConfig.ServerName = target.Host()
 			}
 		}
 
 		return &http.Client{Transport: t, CheckRedirect: p.redirectFunc}
 	}
+
+	if h3t, ok := p.baseTransport.(*http3Transport); ok {
+		t := h3t.clone()
+		if p.resolveFirst(target) && t.TLSClientConfig.ServerName == "" {
+			t.TLSClientConfig.ServerName = target.Host()
+		}
+		return &http.Client{Transport: t, CheckRedirect: p.redirectFunc}
+	}
+
 	return &http.Client{Transport: p.baseTransport, CheckRedirect: p.redirectFunc}
 }
 
@@ -568,6 +705,16 @@ func (p *Probe) clientsForTarget(target endpoint.Endpoint) []*http.Client {
 	return clients
 }
 
+// requestForRun returns the request to use for a probe run. If request uses
+// request level placeholders, it returns a copy of the request with these
+// placeholders substituted.
+func (p *Probe) requestForRun(req *http.Request, target endpoint.Endpoint, runID int64) (*http.Request, error) {
+	if !p.useRequestVars {
+		return req, nil
+	}
+	return p.withRequestVars(req, target, runID)
+}
+
 type targetState struct {
 	req     *http.Request
 	clients []*http.Client
@@ -609,7 +756,14 @@ func (p *Probe) runProbe(ctx context.Context, runReq *sched.RunProbeForTargetReq
 	startSuccess := result.success
 
 	if p.c.GetRequestsPerProbe() == 1 {
-		err := p.doHTTPRequest(tgtState.req.WithContext(ctx), tgtState.clients[0], target, result, nil)
+		req, err := p.requestForRun(tgtState.req, target, tgtState.runCnt)
+		if err != nil {
+			p.l.Error("Error creating HTTP request for target: ", target.Name, ", err: ", err.Error())
+			result.total++
+			runReq.LastRun.Set(false, 0, err)
+			return
+		}
+		err = p.doHTTPRequest(req.WithContext(ctx), tgtState.clients[0], target, result, nil)
 		runReq.LastRun.Set(result.success > startSuccess, time.Since(start), err)
 		return
 	}
@@ -627,6 +781,14 @@ func (p *Probe) runProbe(ctx context.Context, runReq *sched.RunProbeForTargetReq
 			defer wg.Done()
 
 			time.Sleep(time.Duration(numReq*int(p.c.GetRequestsIntervalMsec())) * time.Millisecond)
+			req, err := p.requestForRun(req, target, tgtState.runCnt)
+			if err != nil {
+				p.l.Error("Error creating HTTP request for target: ", target.Name, ", err: ", err.Error())
+				resultMu.Lock()
+				result.total++
+				resultMu.Unlock()
+				return
+			}
 			// Ignore the error returned by doHTTPRequest, as it's already logged.
 			_ = p.doHTTPRequest(req.WithContext(ctx), tgtState.clients[numReq], target, result, &resultMu)
 		}(tgtState.req, numReq, target, result)
@@ -651,6 +813,11 @@ func (p *Probe) RunOnce(ctx context.Context) []*singlerun.ProbeRunResult {
 
 // Start starts and runs the probe indefinitely.
 func (p *Probe) Start(ctx context.Context, dataChan chan *metrics.EventMetrics) {
+	if p.failedResponses != nil {
+		failedresponses.Register(p.name, p.failedResponses)
+		defer failedresponses.Unregister(p.name, p.failedResponses)
+	}
+
 	s := &sched.Scheduler{
 		ProbeName:         p.name,
 		DataChan:          dataChan,
@@ -0,0 +1,199 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+
+	"github.com/cloudprober/cloudprober/common/tlsconfig"
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+)
+
+// http3Transport is the HTTP/3 (over QUIC) round tripper used when
+// http_version is HTTP_3. If keep-alive is not enabled, QUIC connections are
+// closed after each request, just like HTTP/1.1 and HTTP/2 connections.
+type http3Transport struct {
+	*http3.Transport
+	keepAlive bool
+}
+
+func (p *Probe) getHTTP3Transport() (*http3Transport, error) {
+	if p.c.GetProxyUrl() != "" {
+		return nil, errors.New("proxy_url is not supported with http_version HTTP_3")
+	}
+
+	tlsConfig := &tls.Config{}
+	if p.c.GetDisableCertValidation() {
+		tlsConfig.InsecureSkipVerify = true
+	}
+	if p.c.GetTlsConfig() != nil {
+		if err := tlsconfig.UpdateTLSConfig(tlsConfig, p.c.GetTlsConfig()); err != nil {
+			return nil, err
+		}
+	}
+
+	quicConfig := &quic.Config{HandshakeIdleTimeout: p.opts.Timeout}
+	if p.c.GetKeepAlive() {
+		// If it's been more than 2 probe intervals since connection was used,
+		// close it.
+		quicConfig.MaxIdleTimeout = 2 * p.opts.Interval
+	}
+
+	return &http3Transport{
+		Transport: &http3.Transport{
+			TLSClientConfig: tlsConfig,
+			QUICConfig:      quicConfig,
+			Dial:            p.dialQUIC,
+		},
+		keepAlive: p.c.GetKeepAlive(),
+	}, nil
+}
+
+// clone returns a new transport with the same configuration as t, but
+// without any of its connections.
+func (t *http3Transport) clone() *http3Transport {
+	return &http3Transport{
+		Transport: &http3.Transport{
+			TLSClientConfig: t.TLSClientConfig.Clone(),
+			QUICConfig:      t.QUICConfig.Clone(),
+			Dial:            t.Dial,
+		},
+		keepAlive: t.keepAlive,
+	}
+}
+
+func (t *http3Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.Transport.RoundTrip(req)
+	if t.keepAlive {
+		return resp, err
+	}
+	if err != nil {
+		t.CloseIdleConnections()
+		return nil, err
+	}
+	resp.Body = &closeConnBody{ReadCloser: resp.Body, t: t.Transport}
+	return resp, nil
+}
+
+// closeConnBody closes transport's connections once response body is closed.
+type closeConnBody struct {
+	io.ReadCloser
+	t *http3.Transport
+}
+
+func (b *closeConnBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.t.CloseIdleConnections()
+	return err
+}
+
+// dialQUIC establishes QUIC connections for the HTTP/3 transport. In contrast
+// to http3.Transport's default dialer, it binds to the configured source IP,
+// reports DNS resolution to the client trace, and waits for the handshake to
+// complete (no 0-RTT). As QUIC combines transport and TLS handshakes, both
+// connect and TLS handshake latencies measure the QUIC handshake.
+func (p *Probe) dialQUIC(ctx context.Context, addr string, tlsConf *tls.Config, quicConf *quic.Config) (*quic.Conn, error) {
+	trace := httptrace.ContextClientTrace(ctx)
+	if trace == nil {
+		trace = &httptrace.ClientTrace{}
+	}
+
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	port, err := net.LookupPort("udp", portStr)
+	if err != nil {
+		return nil, err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		ipNetwork := "ip"
+		switch p.opts.IPVersion {
+		case 4:
+			ipNetwork = "ip4"
+		case 6:
+			ipNetwork = "ip6"
+		}
+
+		if trace.DNSStart != nil {
+			trace.DNSStart(httptrace.DNSStartInfo{Host: host})
+		}
+		ips, err := net.DefaultResolver.LookupIP(ctx, ipNetwork, host)
+		if trace.DNSDone != nil {
+			addrs := make([]net.IPAddr, len(ips))
+			for i := range ips {
+				addrs[i] = net.IPAddr{IP: ips[i]}
+			}
+			trace.DNSDone(httptrace.DNSDoneInfo{Addrs: addrs, Err: err})
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(ips) == 0 {
+			return nil, fmt.Errorf("no IP address found for %s", host)
+		}
+		ip = ips[0]
+	}
+	raddr := &net.UDPAddr{IP: ip, Port: port}
+
+	udpConn, err := net.ListenUDP("udp", &net.UDPAddr{IP: p.opts.SourceIP})
+	if err != nil {
+		return nil, err
+	}
+
+	if trace.ConnectStart != nil {
+		trace.ConnectStart("udp", raddr.String())
+	}
+	if trace.TLSHandshakeStart != nil {
+		trace.TLSHandshakeStart()
+	}
+
+	conn, err := quic.Dial(ctx, udpConn, raddr, tlsConf, quicConf)
+
+	var tlsState tls.ConnectionState
+	if err == nil {
+		tlsState = conn.ConnectionState().TLS
+	}
+	if trace.TLSHandshakeDone != nil {
+		trace.TLSHandshakeDone(tlsState, err)
+	}
+	if trace.ConnectDone != nil {
+		trace.ConnectDone("udp", raddr.String(), err)
+	}
+
+	if err != nil {
+		udpConn.Close()
+		return nil, err
+	}
+
+	// quic.Dial doesn't take ownership of the UDP socket, close it once the
+	// QUIC connection is closed.
+	go func() {
+		<-conn.Context().Done()
+		udpConn.Close()
+	}()
+
+	return conn, nil
+}
@@ -0,0 +1,160 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/probes/common/sched"
+	tlsinspectpb "github.com/cloudprober/cloudprober/probes/common/tlsinspect/proto"
+	configpb "github.com/cloudprober/cloudprober/probes/http/proto"
+	"github.com/cloudprober/cloudprober/probes/options"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// startHTTP3Server starts an HTTP/3 server on localhost, using httptest's
+// certificate, and returns its port and a counter of QUIC connections.
+func startHTTP3Server(t *testing.T) (int, *atomic.Int32) {
+	t.Helper()
+
+	ts := httptest.NewTLSServer(nil)
+	certs := ts.TLS.Certificates
+	ts.Close()
+
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	var numConns atomic.Int32
+	srv := &http3.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, "Hello, %s", r.Proto)
+		}),
+		TLSConfig: http3.ConfigureTLSConfig(&tls.Config{Certificates: certs}),
+		ConnContext: func(ctx context.Context, _ *quic.Conn) context.Context {
+			numConns.Add(1)
+			return ctx
+		},
+	}
+	go srv.Serve(conn)
+	t.Cleanup(func() {
+		srv.Close()
+		conn.Close()
+	})
+
+	return conn.LocalAddr().(*net.UDPAddr).Port, &numConns
+}
+
+func TestHTTP3(t *testing.T) {
+	port, numConns := startHTTP3Server(t)
+
+	for _, keepAlive := range []bool{false, true} {
+		t.Run(fmt.Sprintf("keep_alive_%v", keepAlive), func(t *testing.T) {
+			numConns.Store(0)
+
+			opts := options.DefaultOptions()
+			opts.IPVersion = 4
+			opts.ProbeConf = &configpb.ProbeConf{
+				SchemeType:              &configpb.ProbeConf_Scheme_{Scheme: configpb.ProbeConf_HTTPS},
+				HttpVersion:             configpb.ProbeConf_HTTP_3.Enum(),
+				KeepAlive:               proto.Bool(keepAlive),
+				ExportResponseAsMetrics: proto.Bool(true),
+				DisableCertValidation:   proto.Bool(true),
+				LatencyBreakdown:        []configpb.ProbeConf_LatencyBreakdown{configpb.ProbeConf_ALL_STAGES},
+				TlsInspect:              &tlsinspectpb.TLSInspectConf{ExpectedServerName: proto.String("example.com")},
+			}
+			p := &Probe{}
+			require.NoError(t, p.Init("http3_test", opts))
+
+			runReq := &sched.RunProbeForTargetRequest{
+				Target: endpoint.Endpoint{Name: "localhost", Port: port},
+			}
+			for i := 0; i < 3; i++ {
+				p.runProbe(context.Background(), runReq)
+			}
+
+			result := runReq.Result.(*probeResult)
+			assert.Equal(t, int64(3), result.total)
+			assert.Equal(t, int64(3), result.success)
+			assert.Equal(t, int64(3), result.respBodies.GetKey("Hello, HTTP/3.0"))
+
+			lb := result.latencyBreakdown
+			for name, v := range map[string]metrics.Value{
+				"dns":           lb.dnsLatency,
+				"connect":       lb.connectLatency,
+				"tls_handshake": lb.tlsLatency,
+				"first_byte":    lb.firstByteLatency,
+			} {
+				assert.Greater(t, v.(metrics.NumValue).Float64(), float64(0), "%s latency", name)
+			}
+			assert.Greater(t, result.sslEarliestExpirationSeconds, int64(0))
+			if assert.NotNil(t, result.tlsInspection) {
+				assert.True(t, result.tlsInspection.SANMatch)
+			}
+
+			if keepAlive {
+				assert.Equal(t, int32(1), numConns.Load(), "connections with keep-alive")
+				assert.Equal(t, int64(1), result.connEvent.Int64())
+			} else {
+				assert.Equal(t, int32(3), numConns.Load(), "connections without keep-alive")
+			}
+		})
+	}
+
+	t.Run("server_down", func(t *testing.T) {
+		conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+		require.NoError(t, err)
+		conn.Close()
+
+		opts := options.DefaultOptions()
+		opts.Timeout = 500 * time.Millisecond
+		opts.ProbeConf = &configpb.ProbeConf{
+			SchemeType:  &configpb.ProbeConf_Scheme_{Scheme: configpb.ProbeConf_HTTPS},
+			HttpVersion: configpb.ProbeConf_HTTP_3.Enum(),
+		}
+		p := &Probe{}
+		require.NoError(t, p.Init("http3_test", opts))
+
+		runReq := &sched.RunProbeForTargetRequest{
+			Target: endpoint.Endpoint{Name: "127.0.0.1", Port: conn.LocalAddr().(*net.UDPAddr).Port},
+		}
+		p.runProbe(context.Background(), runReq)
+		result := runReq.Result.(*probeResult)
+		assert.Equal(t, int64(1), result.total)
+		assert.Equal(t, int64(0), result.success)
+	})
+}
+
+func TestHTTP3InitErrors(t *testing.T) {
+	opts := options.DefaultOptions()
+	opts.ProbeConf = &configpb.ProbeConf{
+		HttpVersion: configpb.ProbeConf_HTTP_3.Enum(),
+		ProxyUrl:    proto.String("http://proxy.example.com:3128"),
+	}
+	assert.Error(t, (&Probe{}).Init("http3_test", opts))
+}
@@ -34,10 +34,14 @@ import (
 	"testing"
 	"time"
 
+	tlsconfigpb "github.com/cloudprober/cloudprober/common/tlsconfig/proto"
+	"github.com/cloudprober/cloudprober/internal/validators"
+	validatorpb "github.com/cloudprober/cloudprober/internal/validators/proto"
 	"github.com/cloudprober/cloudprober/logger"
 	"github.com/cloudprober/cloudprober/metrics"
 	"github.com/cloudprober/cloudprober/metrics/testutils"
 	"github.com/cloudprober/cloudprober/probes/common/sched"
+	tlsinspectpb "github.com/cloudprober/cloudprober/probes/common/tlsinspect/proto"
 	configpb "github.com/cloudprober/cloudprober/probes/http/proto"
 	"github.com/cloudprober/cloudprober/probes/options"
 	"github.com/cloudprober/cloudprober/targets"
@@ -198,6 +202,17 @@ func TestProbeInitError(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			desc: "dual_stack_without_resolve_first",
+			opts: &options.Options{
+				Targets:   targets.StaticTargets("test.com"),
+				Interval:  2 * time.Second,
+				Timeout:   1 * time.Second,
+				DualStack: true,
+				ProbeConf: &configpb.ProbeConf{ResolveFirst: proto.Bool(false)},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, test := range tests {
@@ -1373,6 +1388,145 @@ func TestDisableHTTP2(t *testing.T) {
 	assert.Equal(t, "Hello, HTTP/1.1", string(greeting))
 }
 
+func TestHTTPVersion(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "Hello, %s", r.Proto)
+	})
+
+	tlsServer := httptest.NewUnstartedServer(handler)
+	tlsServer.EnableHTTP2 = true
+	tlsServer.StartTLS()
+	defer tlsServer.Close()
+
+	// Cleartext server that supports HTTP/2 with prior knowledge as well.
+	h2cServer := httptest.NewUnstartedServer(handler)
+	h2cServer.Config.Protocols = new(http.Protocols)
+	h2cServer.Config.Protocols.SetHTTP1(true)
+	h2cServer.Config.Protocols.SetUnencryptedHTTP2(true)
+	h2cServer.Start()
+	defer h2cServer.Close()
+
+	tests := []struct {
+		version      configpb.ProbeConf_HTTPVersion
+		disableHTTP2 bool
+		wantTLS      string
+		wantHTTP     string
+	}{
+		{
+			version:  configpb.ProbeConf_AUTO,
+			wantTLS:  "HTTP/2.0",
+			wantHTTP: "HTTP/1.1",
+		},
+		{
+			version:      configpb.ProbeConf_AUTO,
+			disableHTTP2: true,
+			wantTLS:      "HTTP/1.1",
+			wantHTTP:     "HTTP/1.1",
+		},
+		{
+			version:  configpb.ProbeConf_HTTP_1_1,
+			wantTLS:  "HTTP/1.1",
+			wantHTTP: "HTTP/1.1",
+		},
+		{
+			version:      configpb.ProbeConf_HTTP_2,
+			disableHTTP2: true, // http_version takes precedence.
+			wantTLS:      "HTTP/2.0",
+			wantHTTP:     "HTTP/2.0",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s_disable_http2_%v", test.version, test.disableHTTP2), func(t *testing.T) {
+			p := &Probe{
+				opts: options.DefaultOptions(),
+				c: &configpb.ProbeConf{
+					HttpVersion:  test.version.Enum(),
+					DisableHttp2: proto.Bool(test.disableHTTP2),
+					TlsConfig: &tlsconfigpb.TLSConfig{
+						DisableCertValidation: proto.Bool(true),
+					},
+				},
+			}
+
+			transport, err := p.getTransport()
+			assert.NoError(t, err)
+
+			for _, tt := range []struct {
+				url, want string
+			}{
+				{tlsServer.URL, test.wantTLS},
+				{h2cServer.URL, test.wantHTTP},
+			} {
+				res, err := (&http.Client{Transport: transport}).Get(tt.url)
+				if err != nil {
+					t.Fatal(err)
+				}
+				greeting, err := io.ReadAll(res.Body)
+				res.Body.Close()
+				if err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t, "Hello, "+tt.want, string(greeting), "url: %s", tt.url)
+			}
+		})
+	}
+}
+
+func TestAltSvcDiscovery(t *testing.T) {
+	for _, test := range []struct {
+		altSvc []string
+		want   bool
+	}{
+		{altSvc: nil, want: false},
+		{altSvc: []string{"clear"}, want: false},
+		{altSvc: []string{`h2="alt.example.com:443"`}, want: false},
+		{altSvc: []string{`h3=":443"; ma=86400`}, want: true},
+		{altSvc: []string{`h2=":443", h3-29=":443"; ma=3600`}, want: true},
+		{altSvc: []string{`h2=":443"`, ` h3=":8443"`}, want: true},
+	} {
+		assert.Equal(t, test.want, advertisesHTTP3(test.altSvc), "Alt-Svc: %v", test.altSvc)
+	}
+
+	altSvc := ""
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if altSvc != "" {
+			w.Header().Set("Alt-Svc", altSvc)
+		}
+	}))
+	defer ts.Close()
+
+	opts := options.DefaultOptions()
+	opts.ProbeConf = &configpb.ProbeConf{AltSvcDiscovery: proto.Bool(true)}
+	p := &Probe{}
+	assert.NoError(t, p.Init("test", opts))
+
+	gaugeValue := func(result *probeResult) int64 {
+		t.Helper()
+		for _, em := range result.Metrics(time.Now(), 0, p.opts) {
+			if m := em.Metric("http3_advertised"); m != nil {
+				assert.Equal(t, metrics.Kind(metrics.GAUGE), em.Kind)
+				return m.(metrics.NumValue).Int64()
+			}
+		}
+		return -1
+	}
+
+	result := p.newResult()
+	assert.Equal(t, int64(-1), gaugeValue(result), "no responses yet")
+
+	req, _ := http.NewRequest("GET", ts.URL, nil)
+	target := endpoint.Endpoint{Name: "test"}
+
+	altSvc = `h3=":443"; ma=86400`
+	assert.NoError(t, p.doHTTPRequest(req, ts.Client(), target, result, nil))
+	assert.Equal(t, int64(1), gaugeValue(result))
+
+	altSvc = ""
+	assert.NoError(t, p.doHTTPRequest(req, ts.Client(), target, result, nil))
+	assert.Equal(t, int64(0), gaugeValue(result))
+}
+
 func TestNegativeTest(t *testing.T) {
 	ctx, cancelF := context.WithCancel(context.Background())
 	defer cancelF()
@@ -1432,3 +1586,114 @@ func TestNegativeTest(t *testing.T) {
 		}
 	}
 }
+
+func TestFailedResponses(t *testing.T) {
+	var reqCount int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqCount++
+		if reqCount == 2 {
+			w.Write([]byte("ok"))
+			return
+		}
+		w.Header().Set("X-Request-Num", strconv.Itoa(reqCount))
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Header().Set("X-Api-Key", "secret")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf("error-%d", reqCount)))
+	}))
+
+	opts := options.DefaultOptions()
+	opts.ProbeConf = &configpb.ProbeConf{
+		KeepFailedResponses:         proto.Int32(2),
+		FailedResponseMaxBodyBytes:  proto.Int32(5),
+		FailedResponseRedactHeaders: []string{"x-api-key"},
+	}
+	var err error
+	opts.Validators, err = validators.Init([]*validatorpb.Validator{
+		{
+			Name: "body_ok",
+			Type: &validatorpb.Validator_Regex{Regex: "^ok$"},
+		},
+	})
+	assert.NoError(t, err)
+
+	p := &Probe{}
+	assert.NoError(t, p.Init("test", opts))
+
+	result := p.newResult()
+	req, _ := http.NewRequest("GET", ts.URL+"/?token=secret&user=u1", nil)
+	target := endpoint.Endpoint{Name: "test"}
+
+	for i := 0; i < 4; i++ {
+		p.doHTTPRequest(req, ts.Client(), target, result, nil)
+	}
+	assert.Equal(t, int64(1), result.success)
+
+	// Only last 2 failed responses are kept, newest first.
+	resps := p.failedResponses.List(target.Dst())
+	assert.Len(t, resps, 2)
+	for i, want := range []int{4, 3} {
+		r := resps[i]
+		assert.Equal(t, http.StatusInternalServerError, r.StatusCode)
+		assert.Equal(t, strconv.Itoa(want), r.Header.Get("X-Request-Num"))
+		assert.Equal(t, "error", string(r.Body))
+		assert.True(t, r.BodyTruncated)
+		assert.Contains(t, r.Error, "failed validations: body_ok")
+		assert.Equal(t, ts.URL+"/?token=REDACTED&user=REDACTED", r.URL)
+		assert.Equal(t, "REDACTED", r.Header.Get("Set-Cookie"))
+		assert.Equal(t, "REDACTED", r.Header.Get("X-Api-Key"))
+		assert.NotZero(t, r.Latency)
+		assert.NotZero(t, r.FirstByteLatency)
+	}
+
+	// Request that doesn't get a response.
+	ts.Close()
+	assert.Error(t, p.doHTTPRequest(req, ts.Client(), target, result, nil))
+	resps = p.failedResponses.List(target.Dst())
+	assert.Len(t, resps, 2)
+	assert.Equal(t, 0, resps[0].StatusCode)
+	assert.Nil(t, resps[0].Header)
+	assert.NotEmpty(t, resps[0].Error)
+	assert.NotContains(t, resps[0].Error, "secret")
+}
+
+func TestTLSInspect(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	opts := options.DefaultOptions()
+	opts.ProbeConf = &configpb.ProbeConf{
+		TlsInspect: &tlsinspectpb.TLSInspectConf{CaBundleFile: proto.String("/does/not/exist")},
+	}
+	assert.Error(t, (&Probe{}).Init("test", opts))
+
+	opts.ProbeConf = &configpb.ProbeConf{
+		TlsInspect: &tlsinspectpb.TLSInspectConf{ExpectedServerName: proto.String("example.com")},
+	}
+	p := &Probe{}
+	assert.NoError(t, p.Init("test", opts))
+
+	result := p.newResult()
+	req, _ := http.NewRequest("GET", ts.URL, nil)
+	assert.NoError(t, p.doHTTPRequest(req, ts.Client(), endpoint.Endpoint{Name: "test"}, result, nil))
+
+	var tlsEM, expiryEM *metrics.EventMetrics
+	for _, em := range result.Metrics(time.Now(), 0, p.opts) {
+		if em.Metric("tls_san_match") != nil {
+			tlsEM = em
+		}
+		if em.Metric("tls_cert_days_to_expiry") != nil {
+			expiryEM = em
+		}
+	}
+	if assert.NotNil(t, tlsEM) {
+		assert.Equal(t, "http", tlsEM.Label("ptype"))
+		assert.Equal(t, "TLS 1.3", tlsEM.Label("tls_version"))
+		assert.Equal(t, int64(1), tlsEM.Metric("tls_san_match").(metrics.NumValue).Int64())
+		// httptest server's certificate is not signed by a system root.
+		assert.Equal(t, int64(0), tlsEM.Metric("tls_chain_valid").(metrics.NumValue).Int64())
+	}
+	if assert.NotNil(t, expiryEM) {
+		assert.Equal(t, "0", expiryEM.Label("cert_index"))
+	}
+}
@@ -8,8 +8,10 @@ package proto
 
 import (
 	proto "github.com/cloudprober/cloudprober/common/oauth/proto"
-	proto1 "github.com/cloudprober/cloudprober/common/tlsconfig/proto"
-	proto2 "github.com/cloudprober/cloudprober/metrics/payload/proto"
+	proto2 "github.com/cloudprober/cloudprober/common/tlsconfig/proto"
+	proto4 "github.com/cloudprober/cloudprober/metrics/payload/proto"
+	proto3 "github.com/cloudprober/cloudprober/probes/common/tlsinspect/proto"
+	proto1 "github.com/cloudprober/cloudprober/probes/http/signer/proto"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -151,6 +153,78 @@ func (ProbeConf_Method) EnumDescriptor() ([]byte, []int) {
 	return file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_rawDescGZIP(), []int{0, 1}
 }
 
+type ProbeConf_HTTPVersion int32
+
+const (
+	// Let the client negotiate the version with the server: HTTP/2 if server
+	// supports it (over TLS), HTTP/1.1 otherwise.
+	ProbeConf_AUTO ProbeConf_HTTPVersion = 0
+	// Use only HTTP/1.1.
+	ProbeConf_HTTP_1_1 ProbeConf_HTTPVersion = 1
+	// Use only HTTP/2. For "http" scheme, this means HTTP/2 with prior
+	// knowledge (h2c). Requests fail if server doesn't support HTTP/2.
+	ProbeConf_HTTP_2 ProbeConf_HTTPVersion = 2
+	// Use HTTP/3 (over QUIC). Requires "https" scheme, and is not supported
+	// with proxy_url. As QUIC combines the transport and TLS handshakes, both
+	// connect and TLS handshake latencies (see latency_breakdown) measure the
+	// QUIC handshake. Use alt_svc_discovery to check if your servers advertise
+	// HTTP/3.
+	ProbeConf_HTTP_3 ProbeConf_HTTPVersion = 3
+)
+
+// Enum value maps for ProbeConf_HTTPVersion.
+var (
+	ProbeConf_HTTPVersion_name = map[int32]string{
+		0: "AUTO",
+		1: "HTTP_1_1",
+		2: "HTTP_2",
+		3: "HTTP_3",
+	}
+	ProbeConf_HTTPVersion_value = map[string]int32{
+		"AUTO":     0,
+		"HTTP_1_1": 1,
+		"HTTP_2":   2,
+		"HTTP_3":   3,
+	}
+)
+
+func (x ProbeConf_HTTPVersion) Enum() *ProbeConf_HTTPVersion {
+	p := new(ProbeConf_HTTPVersion)
+	*p = x
+	return p
+}
+
+func (x ProbeConf_HTTPVersion) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProbeConf_HTTPVersion) Descriptor() protoreflect.EnumDescriptor {
+	return file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_enumTypes[2].Descriptor()
+}
+
+func (ProbeConf_HTTPVersion) Type() protoreflect.EnumType {
+	return &file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_enumTypes[2]
+}
+
+func (x ProbeConf_HTTPVersion) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *ProbeConf_HTTPVersion) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = ProbeConf_HTTPVersion(num)
+	return nil
+}
+
+// Deprecated: Use ProbeConf_HTTPVersion.Descriptor instead.
+func (ProbeConf_HTTPVersion) EnumDescriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_rawDescGZIP(), []int{0, 2}
+}
+
 type ProbeConf_LatencyBreakdown int32
 
 const (
@@ -196,11 +270,11 @@ func (x ProbeConf_LatencyBreakdown) String() string {
 }
 
 func (ProbeConf_LatencyBreakdown) Descriptor() protoreflect.EnumDescriptor {
-	return file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_enumTypes[2].Descriptor()
+	return file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_enumTypes[3].Descriptor()
 }
 
 func (ProbeConf_LatencyBreakdown) Type() protoreflect.EnumType {
-	return &file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_enumTypes[2]
+	return &file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_enumTypes[3]
 }
 
 func (x ProbeConf_LatencyBreakdown) Number() protoreflect.EnumNumber {
@@ -219,9 +293,37 @@ func (x *ProbeConf_LatencyBreakdown) UnmarshalJSON(b []byte) error {
 
 // Deprecated: Use ProbeConf_LatencyBreakdown.Descriptor instead.
 func (ProbeConf_LatencyBreakdown) EnumDescriptor() ([]byte, []int) {
-	return file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_rawDescGZIP(), []int{0, 2}
-}
-
+	return file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_rawDescGZIP(), []int{0, 3}
+}
+
+// HTTP probe configuration.
+//
+// Fields relative_url, header, headers, body and body_file support the
+// following placeholders, substituted for each target:
+//
+//	@probe@                Probe name.
+//	@target@               Target name (same as @target.name@).
+//	@target.port@          Target port.
+//	@target.ip@            Target IP, if available.
+//	@target.label.<key>@   Target label value.
+//
+// and the following ones, substituted for each request:
+//
+//	@timestamp@            Current time in seconds since the Unix epoch.
+//	@timestamp_ms@         Current time in milliseconds since the Unix epoch.
+//	@run_id@               Per-target probe run count.
+//	@uuid@                 A random UUID.
+//
+// Example:
+//
+//	header {
+//	  key: "X-Tenant-ID"
+//	  value: "@target.label.tenant@"
+//	}
+//	body: "{\"tenant\": \"@target.label.tenant@\", \"id\": \"@uuid@\"}"
+//
+// Fields that don't use any of these placeholders are used as is. In the
+// fields that do, "@@" is replaced by a single "@".
 // Next tag: 21
 type ProbeConf struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -294,10 +396,41 @@ type ProbeConf struct {
 	KeepAlive *bool `protobuf:"varint,10,opt,name=keep_alive,json=keepAlive" json:"keep_alive,omitempty"`
 	// OAuth Config
 	OauthConfig *proto.Config `protobuf:"bytes,11,opt,name=oauth_config,json=oauthConfig" json:"oauth_config,omitempty"`
+	// Request signer config. Use it for the APIs that require signed requests,
+	// e.g. AWS Signature Version 4 or HMAC signatures over the request.
+	// Example:
+	//
+	//	request_signer {
+	//	  aws_sigv4 {
+	//	    region: "us-east-1"
+	//	    service: "s3"
+	//	  }
+	//	}
+	RequestSigner *proto1.SignerConfig `protobuf:"bytes,30,opt,name=request_signer,json=requestSigner" json:"request_signer,omitempty"`
 	// Disable HTTP2
 	// Golang HTTP client automatically enables HTTP/2 if server supports it. This
 	// option disables that behavior to enforce HTTP/1.1 for testing purpose.
 	DisableHttp2 *bool `protobuf:"varint,13,opt,name=disable_http2,json=disableHttp2" json:"disable_http2,omitempty"`
+	// HTTP protocol version to use. If set to something other than AUTO, it
+	// takes precedence over disable_http2.
+	HttpVersion *ProbeConf_HTTPVersion `protobuf:"varint,25,opt,name=http_version,json=httpVersion,enum=cloudprober.probes.http.ProbeConf_HTTPVersion,def=0" json:"http_version,omitempty"`
+	// Check if servers advertise HTTP/3 support through the Alt-Svc response
+	// header. If enabled, probe exports a gauge metric "http3_advertised", which
+	// is set to 1 if the last response advertised HTTP/3 ("h3"), 0 otherwise.
+	AltSvcDiscovery *bool `protobuf:"varint,26,opt,name=alt_svc_discovery,json=altSvcDiscovery" json:"alt_svc_discovery,omitempty"`
+	// Number of recent failed responses to keep per target, for debugging.
+	// Failed responses (status, headers, body, error and timing breakdown) are
+	// available on the /failed-responses page, linked from the status page,
+	// and through the GetFailedResponses gRPC method. Responses are kept only
+	// in memory. Default is 0, i.e. failed responses are not kept.
+	KeepFailedResponses *int32 `protobuf:"varint,27,opt,name=keep_failed_responses,json=keepFailedResponses" json:"keep_failed_responses,omitempty"`
+	// Failed responses' bodies longer than this are truncated.
+	FailedResponseMaxBodyBytes *int32 `protobuf:"varint,28,opt,name=failed_response_max_body_bytes,json=failedResponseMaxBodyBytes,def=4096" json:"failed_response_max_body_bytes,omitempty"`
+	// Failed responses are stored with the URL's query parameter values and
+	// the values of sensitive response headers (Authorization,
+	// Proxy-Authorization, Cookie and Set-Cookie) redacted. Use this field to
+	// redact more response headers.
+	FailedResponseRedactHeaders []string `protobuf:"bytes,31,rep,name=failed_response_redact_headers,json=failedResponseRedactHeaders" json:"failed_response_redact_headers,omitempty"`
 	// Disable TLS certificate validation. If set to true, any certificate
 	// presented by the server for any host name will be accepted
 	// Deprecation: This option is now subsumed by the tls_config below. To
@@ -308,7 +441,11 @@ type ProbeConf struct {
 	//	}
 	DisableCertValidation *bool `protobuf:"varint,14,opt,name=disable_cert_validation,json=disableCertValidation" json:"disable_cert_validation,omitempty"`
 	// TLS config
-	TlsConfig *proto1.TLSConfig `protobuf:"bytes,15,opt,name=tls_config,json=tlsConfig" json:"tls_config,omitempty"`
+	TlsConfig *proto2.TLSConfig `protobuf:"bytes,15,opt,name=tls_config,json=tlsConfig" json:"tls_config,omitempty"`
+	// Inspect the TLS connection and server certificates, and export TLS
+	// version, cipher, SAN match, chain validity, OCSP stapling and per
+	// certificate expiry metrics. See TLSInspectConf for the details.
+	TlsInspect *proto3.TLSInspectConf `protobuf:"bytes,29,opt,name=tls_inspect,json=tlsInspect" json:"tls_inspect,omitempty"`
 	// Proxy URL, e.g. http://myproxy:3128
 	ProxyUrl *string `protobuf:"bytes,16,opt,name=proxy_url,json=proxyUrl" json:"proxy_url,omitempty"`
 	// HTTP proxy connect headers. These headers are passed on to the CONNECT
@@ -335,7 +472,7 @@ type ProbeConf struct {
 	// Parse HTTP response as additional metrics. If configured, Cloudprober
 	// will try to extract metrics from HTTP response and export them along with
 	// the default success/total/latency metrics.
-	ResponseMetricsOptions *proto2.OutputMetricsOptions `protobuf:"bytes,96,opt,name=response_metrics_options,json=responseMetricsOptions" json:"response_metrics_options,omitempty"`
+	ResponseMetricsOptions *proto4.OutputMetricsOptions `protobuf:"bytes,96,opt,name=response_metrics_options,json=responseMetricsOptions" json:"response_metrics_options,omitempty"`
 	// Interval between targets.
 	IntervalBetweenTargetsMsec *int32 `protobuf:"varint,97,opt,name=interval_between_targets_msec,json=intervalBetweenTargetsMsec,def=10" json:"interval_between_targets_msec,omitempty"`
 	// Requests per probe.
@@ -362,6 +499,8 @@ const (
 	Default_ProbeConf_Scheme                     = ProbeConf_HTTP
 	Default_ProbeConf_ExportResponseAsMetrics    = bool(false)
 	Default_ProbeConf_Method                     = ProbeConf_GET
+	Default_ProbeConf_HttpVersion                = ProbeConf_AUTO
+	Default_ProbeConf_FailedResponseMaxBodyBytes = int32(4096)
 	Default_ProbeConf_MaxIdleConns               = int32(256)
 	Default_ProbeConf_IntervalBetweenTargetsMsec = int32(10)
 	Default_ProbeConf_RequestsPerProbe           = int32(1)
@@ -500,6 +639,13 @@ func (x *ProbeConf) GetOauthConfig() *proto.Config {
 	return nil
 }
 
+func (x *ProbeConf) GetRequestSigner() *proto1.SignerConfig {
+	if x != nil {
+		return x.RequestSigner
+	}
+	return nil
+}
+
 func (x *ProbeConf) GetDisableHttp2() bool {
 	if x != nil && x.DisableHttp2 != nil {
 		return *x.DisableHttp2
@@ -507,6 +653,41 @@ func (x *ProbeConf) GetDisableHttp2() bool {
 	return false
 }
 
+func (x *ProbeConf) GetHttpVersion() ProbeConf_HTTPVersion {
+	if x != nil && x.HttpVersion != nil {
+		return *x.HttpVersion
+	}
+	return Default_ProbeConf_HttpVersion
+}
+
+func (x *ProbeConf) GetAltSvcDiscovery() bool {
+	if x != nil && x.AltSvcDiscovery != nil {
+		return *x.AltSvcDiscovery
+	}
+	return false
+}
+
+func (x *ProbeConf) GetKeepFailedResponses() int32 {
+	if x != nil && x.KeepFailedResponses != nil {
+		return *x.KeepFailedResponses
+	}
+	return 0
+}
+
+func (x *ProbeConf) GetFailedResponseMaxBodyBytes() int32 {
+	if x != nil && x.FailedResponseMaxBodyBytes != nil {
+		return *x.FailedResponseMaxBodyBytes
+	}
+	return Default_ProbeConf_FailedResponseMaxBodyBytes
+}
+
+func (x *ProbeConf) GetFailedResponseRedactHeaders() []string {
+	if x != nil {
+		return x.FailedResponseRedactHeaders
+	}
+	return nil
+}
+
 func (x *ProbeConf) GetDisableCertValidation() bool {
 	if x != nil && x.DisableCertValidation != nil {
 		return *x.DisableCertValidation
@@ -514,13 +695,20 @@ func (x *ProbeConf) GetDisableCertValidation() bool {
 	return false
 }
 
-func (x *ProbeConf) GetTlsConfig() *proto1.TLSConfig {
+func (x *ProbeConf) GetTlsConfig() *proto2.TLSConfig {
 	if x != nil {
 		return x.TlsConfig
 	}
 	return nil
 }
 
+func (x *ProbeConf) GetTlsInspect() *proto3.TLSInspectConf {
+	if x != nil {
+		return x.TlsInspect
+	}
+	return nil
+}
+
 func (x *ProbeConf) GetProxyUrl() string {
 	if x != nil && x.ProxyUrl != nil {
 		return *x.ProxyUrl
@@ -563,7 +751,7 @@ func (x *ProbeConf) GetLatencyBreakdown() []ProbeConf_LatencyBreakdown {
 	return nil
 }
 
-func (x *ProbeConf) GetResponseMetricsOptions() *proto2.OutputMetricsOptions {
+func (x *ProbeConf) GetResponseMetricsOptions() *proto4.OutputMetricsOptions {
 	if x != nil {
 		return x.ResponseMetricsOptions
 	}
@@ -663,7 +851,7 @@ var File_github_com_cloudprober_cloudprober_probes_http_proto_config_proto proto
 
 const file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_rawDesc = "" +
 	"\n" +
-	"Agithub.com/cloudprober/cloudprober/probes/http/proto/config.proto\x12\x17cloudprober.probes.http\x1aBgithub.com/cloudprober/cloudprober/common/oauth/proto/config.proto\x1aFgithub.com/cloudprober/cloudprober/common/tlsconfig/proto/config.proto\x1aEgithub.com/cloudprober/cloudprober/metrics/payload/proto/config.proto\"\x9f\x0f\n" +
+	"Agithub.com/cloudprober/cloudprober/probes/http/proto/config.proto\x12\x17cloudprober.probes.http\x1aBgithub.com/cloudprober/cloudprober/common/oauth/proto/config.proto\x1aFgithub.com/cloudprober/cloudprober/common/tlsconfig/proto/config.proto\x1aNgithub.com/cloudprober/cloudprober/probes/common/tlsinspect/proto/config.proto\x1aHgithub.com/cloudprober/cloudprober/probes/http/signer/proto/config.proto\x1aEgithub.com/cloudprober/cloudprober/metrics/payload/proto/config.proto\"\xcb\x13\n" +
 	"\tProbeConf\x12M\n" +
 	"\bprotocol\x18\x01 \x01(\x0e2).cloudprober.probes.http.ProbeConf.Scheme:\x04HTTPH\x00R\bprotocol\x12I\n" +
 	"\x06scheme\x18\x15 \x01(\x0e2).cloudprober.probes.http.ProbeConf.Scheme:\x04HTTPH\x00R\x06scheme\x12!\n" +
@@ -679,11 +867,19 @@ const file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_raw
 	"\n" +
 	"keep_alive\x18\n" +
 	" \x01(\bR\tkeepAlive\x12<\n" +
-	"\foauth_config\x18\v \x01(\v2\x19.cloudprober.oauth.ConfigR\voauthConfig\x12#\n" +
-	"\rdisable_http2\x18\r \x01(\bR\fdisableHttp2\x126\n" +
+	"\foauth_config\x18\v \x01(\v2\x19.cloudprober.oauth.ConfigR\voauthConfig\x12S\n" +
+	"\x0erequest_signer\x18\x1e \x01(\v2,.cloudprober.probes.http.signer.SignerConfigR\rrequestSigner\x12#\n" +
+	"\rdisable_http2\x18\r \x01(\bR\fdisableHttp2\x12W\n" +
+	"\fhttp_version\x18\x19 \x01(\x0e2..cloudprober.probes.http.ProbeConf.HTTPVersion:\x04AUTOR\vhttpVersion\x12*\n" +
+	"\x11alt_svc_discovery\x18\x1a \x01(\bR\x0faltSvcDiscovery\x122\n" +
+	"\x15keep_failed_responses\x18\x1b \x01(\x05R\x13keepFailedResponses\x12H\n" +
+	"\x1efailed_response_max_body_bytes\x18\x1c \x01(\x05:\x044096R\x1afailedResponseMaxBodyBytes\x12C\n" +
+	"\x1efailed_response_redact_headers\x18\x1f \x03(\tR\x1bfailedResponseRedactHeaders\x126\n" +
 	"\x17disable_cert_validation\x18\x0e \x01(\bR\x15disableCertValidation\x12?\n" +
 	"\n" +
-	"tls_config\x18\x0f \x01(\v2 .cloudprober.tlsconfig.TLSConfigR\ttlsConfig\x12\x1b\n" +
+	"tls_config\x18\x0f \x01(\v2 .cloudprober.tlsconfig.TLSConfigR\ttlsConfig\x12N\n" +
+	"\vtls_inspect\x18\x1d \x01(\v2-.cloudprober.probes.tlsinspect.TLSInspectConfR\n" +
+	"tlsInspect\x12\x1b\n" +
 	"\tproxy_url\x18\x10 \x01(\tR\bproxyUrl\x12l\n" +
 	"\x14proxy_connect_header\x18\x17 \x03(\v2:.cloudprober.probes.http.ProbeConf.ProxyConnectHeaderEntryR\x12proxyConnectHeader\x12\x1d\n" +
 	"\n" +
@@ -715,7 +911,14 @@ const file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_raw
 	"\n" +
 	"\x06DELETE\x10\x04\x12\t\n" +
 	"\x05PATCH\x10\x05\x12\v\n" +
-	"\aOPTIONS\x10\x06\"\xa4\x01\n" +
+	"\aOPTIONS\x10\x06\"=\n" +
+	"\vHTTPVersion\x12\b\n" +
+	"\x04AUTO\x10\x00\x12\f\n" +
+	"\bHTTP_1_1\x10\x01\x12\n" +
+	"\n" +
+	"\x06HTTP_2\x10\x02\x12\n" +
+	"\n" +
+	"\x06HTTP_3\x10\x03\"\xa4\x01\n" +
 	"\x10LatencyBreakdown\x12\x10\n" +
 	"\fNO_BREAKDOWN\x10\x00\x12\x0e\n" +
 	"\n" +
@@ -739,36 +942,42 @@ func file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_rawD
 	return file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_rawDescData
 }
 
-var file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
 var file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_goTypes = []any{
 	(ProbeConf_Scheme)(0),               // 0: cloudprober.probes.http.ProbeConf.Scheme
 	(ProbeConf_Method)(0),               // 1: cloudprober.probes.http.ProbeConf.Method
-	(ProbeConf_LatencyBreakdown)(0),     // 2: cloudprober.probes.http.ProbeConf.LatencyBreakdown
-	(*ProbeConf)(nil),                   // 3: cloudprober.probes.http.ProbeConf
-	(*ProbeConf_Header)(nil),            // 4: cloudprober.probes.http.ProbeConf.Header
-	nil,                                 // 5: cloudprober.probes.http.ProbeConf.HeaderEntry
-	nil,                                 // 6: cloudprober.probes.http.ProbeConf.ProxyConnectHeaderEntry
-	(*proto.Config)(nil),                // 7: cloudprober.oauth.Config
-	(*proto1.TLSConfig)(nil),            // 8: cloudprober.tlsconfig.TLSConfig
-	(*proto2.OutputMetricsOptions)(nil), // 9: cloudprober.metrics.payload.OutputMetricsOptions
+	(ProbeConf_HTTPVersion)(0),          // 2: cloudprober.probes.http.ProbeConf.HTTPVersion
+	(ProbeConf_LatencyBreakdown)(0),     // 3: cloudprober.probes.http.ProbeConf.LatencyBreakdown
+	(*ProbeConf)(nil),                   // 4: cloudprober.probes.http.ProbeConf
+	(*ProbeConf_Header)(nil),            // 5: cloudprober.probes.http.ProbeConf.Header
+	nil,                                 // 6: cloudprober.probes.http.ProbeConf.HeaderEntry
+	nil,                                 // 7: cloudprober.probes.http.ProbeConf.ProxyConnectHeaderEntry
+	(*proto.Config)(nil),                // 8: cloudprober.oauth.Config
+	(*proto1.SignerConfig)(nil),         // 9: cloudprober.probes.http.signer.SignerConfig
+	(*proto2.TLSConfig)(nil),            // 10: cloudprober.tlsconfig.TLSConfig
+	(*proto3.TLSInspectConf)(nil),       // 11: cloudprober.probes.tlsinspect.TLSInspectConf
+	(*proto4.OutputMetricsOptions)(nil), // 12: cloudprober.metrics.payload.OutputMetricsOptions
 }
 var file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_depIdxs = []int32{
 	0,  // 0: cloudprober.probes.http.ProbeConf.protocol:type_name -> cloudprober.probes.http.ProbeConf.Scheme
 	0,  // 1: cloudprober.probes.http.ProbeConf.scheme:type_name -> cloudprober.probes.http.ProbeConf.Scheme
 	1,  // 2: cloudprober.probes.http.ProbeConf.method:type_name -> cloudprober.probes.http.ProbeConf.Method
-	4,  // 3: cloudprober.probes.http.ProbeConf.headers:type_name -> cloudprober.probes.http.ProbeConf.Header
-	5,  // 4: cloudprober.probes.http.ProbeConf.header:type_name -> cloudprober.probes.http.ProbeConf.HeaderEntry
-	7,  // 5: cloudprober.probes.http.ProbeConf.oauth_config:type_name -> cloudprober.oauth.Config
-	8,  // 6: cloudprober.probes.http.ProbeConf.tls_config:type_name -> cloudprober.tlsconfig.TLSConfig
-	6,  // 7: cloudprober.probes.http.ProbeConf.proxy_connect_header:type_name -> cloudprober.probes.http.ProbeConf.ProxyConnectHeaderEntry
-	2,  // 8: cloudprober.probes.http.ProbeConf.latency_breakdown:type_name -> cloudprober.probes.http.ProbeConf.LatencyBreakdown
-	9,  // 9: cloudprober.probes.http.ProbeConf.response_metrics_options:type_name -> cloudprober.metrics.payload.OutputMetricsOptions
-	10, // [10:10] is the sub-list for method output_type
-	10, // [10:10] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+	5,  // 3: cloudprober.probes.http.ProbeConf.headers:type_name -> cloudprober.probes.http.ProbeConf.Header
+	6,  // 4: cloudprober.probes.http.ProbeConf.header:type_name -> cloudprober.probes.http.ProbeConf.HeaderEntry
+	8,  // 5: cloudprober.probes.http.ProbeConf.oauth_config:type_name -> cloudprober.oauth.Config
+	9,  // 6: cloudprober.probes.http.ProbeConf.request_signer:type_name -> cloudprober.probes.http.signer.SignerConfig
+	2,  // 7: cloudprober.probes.http.ProbeConf.http_version:type_name -> cloudprober.probes.http.ProbeConf.HTTPVersion
+	10, // 8: cloudprober.probes.http.ProbeConf.tls_config:type_name -> cloudprober.tlsconfig.TLSConfig
+	11, // 9: cloudprober.probes.http.ProbeConf.tls_inspect:type_name -> cloudprober.probes.tlsinspect.TLSInspectConf
+	7,  // 10: cloudprober.probes.http.ProbeConf.proxy_connect_header:type_name -> cloudprober.probes.http.ProbeConf.ProxyConnectHeaderEntry
+	3,  // 11: cloudprober.probes.http.ProbeConf.latency_breakdown:type_name -> cloudprober.probes.http.ProbeConf.LatencyBreakdown
+	12, // 12: cloudprober.probes.http.ProbeConf.response_metrics_options:type_name -> cloudprober.metrics.payload.OutputMetricsOptions
+	13, // [13:13] is the sub-list for method output_type
+	13, // [13:13] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
 }
 
 func init() { file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_init() }
@@ -785,7 +994,7 @@ func file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_init
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_rawDesc), len(file_github_com_cloudprober_cloudprober_probes_http_proto_config_proto_rawDesc)),
-			NumEnums:      3,
+			NumEnums:      4,
 			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   0,
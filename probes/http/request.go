@@ -16,21 +16,85 @@ package http
 
 import (
 	"fmt"
+	"io"
 	"net"
 	"net/http"
+	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cloudprober/cloudprober/common/iputils"
+	"github.com/cloudprober/cloudprober/common/oauth"
+	"github.com/cloudprober/cloudprober/common/strtemplate"
 	"github.com/cloudprober/cloudprober/internal/httpreq"
-	"github.com/cloudprober/cloudprober/logger"
 	configpb "github.com/cloudprober/cloudprober/probes/http/proto"
 	"github.com/cloudprober/cloudprober/targets/endpoint"
-	"golang.org/x/oauth2"
+	"github.com/google/uuid"
 )
 
 const relURLLabel = "relative_url"
 
+// placeholderRe matches the placeholders supported in the request fields.
+// Placeholders in requestVars are substituted for each request, all others
+// once per target.
+var placeholderRe = regexp.MustCompile(`@(probe|target|target\.(name|port|ip|label\.[^@]+)|timestamp|timestamp_ms|run_id|uuid)@`)
+
+// requestVars are the placeholders that are substituted for each request.
+var requestVars = []string{"timestamp", "timestamp_ms", "run_id", "uuid"}
+
+// hasPlaceholders returns true if any of the given strings use one of the
+// supported placeholders.
+func hasPlaceholders(in ...string) bool {
+	for _, s := range in {
+		if placeholderRe.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasRequestVars returns true if any of the given strings use request level
+// placeholders.
+func hasRequestVars(in ...string) bool {
+	for _, s := range in {
+		for _, v := range requestVars {
+			if strings.Contains(s, "@"+v+"@") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// targetVars returns the values for the target level placeholders.
+func (p *Probe) targetVars(target endpoint.Endpoint) map[string]string {
+	vars := map[string]string{
+		"probe":       p.name,
+		"target":      target.Name,
+		"target.name": target.Name,
+		"target.port": strconv.Itoa(target.Port),
+	}
+	if target.IP != nil {
+		vars["target.ip"] = target.IP.String()
+	}
+	for k, v := range target.Labels {
+		vars["target.label."+k] = v
+	}
+	return vars
+}
+
+// substitute substitutes the placeholders in the given string. Strings that
+// don't use any of the supported placeholders are returned as is.
+func substitute(in string, vars map[string]string) string {
+	if !hasPlaceholders(in) {
+		return in
+	}
+	out, _ := strtemplate.SubstituteLabels(in, vars)
+	return out
+}
+
 func hostWithPort(host string, port int) string {
 	if port == 0 {
 		return host
@@ -67,16 +131,6 @@ func (p *Probe) schemeForTarget(target endpoint.Endpoint) string {
 	return "http"
 }
 
-func hostForTarget(target endpoint.Endpoint) string {
-	for _, label := range []string{"fqdn", "__cp_host__"} {
-		if target.Labels[label] != "" {
-			return handleIPv6(target.Labels[label])
-		}
-	}
-
-	return handleIPv6(target.Name)
-}
-
 func pathForTarget(target endpoint.Endpoint, probeURL string) string {
 	if probeURL != "" {
 		return probeURL
@@ -104,28 +158,31 @@ func (p *Probe) resolveFirst(target endpoint.Endpoint) bool {
 // setHeaders computes setHeaders for a target. Host header is computed slightly
 // differently than other setHeaders.
 //   - If host header is set in the probe, it overrides everything else.
-//   - Otherwise we use target's host (computed elsewhere) along with port.
-func (p *Probe) setHeaders(req *http.Request, host string, port int) {
+//   - Otherwise we use defaultHost, target's host (computed elsewhere) along
+//     with port.
+//
+// Placeholders in the header values are substituted using vars.
+func (p *Probe) setHeaders(req *http.Request, defaultHost string, vars map[string]string) {
 	var hostHeader string
 
 	for _, h := range p.c.GetHeaders() {
 		if h.GetName() == "Host" {
-			hostHeader = h.GetValue()
+			hostHeader = substitute(h.GetValue(), vars)
 			continue
 		}
-		req.Header.Set(h.GetName(), h.GetValue())
+		req.Header.Set(h.GetName(), substitute(h.GetValue(), vars))
 	}
 
 	for k, v := range p.c.GetHeader() {
 		if k == "Host" {
-			hostHeader = v
+			hostHeader = substitute(v, vars)
 			continue
 		}
-		req.Header.Set(k, v)
+		req.Header.Set(k, substitute(v, vars))
 	}
 
 	if hostHeader == "" {
-		hostHeader = hostWithPort(host, port)
+		hostHeader = defaultHost
 	}
 	req.Host = hostHeader
 }
@@ -153,7 +210,7 @@ func (p *Probe) httpRequestForTarget(target endpoint.Endpoint) (*http.Request, e
 		port = target.Port
 	}
 
-	host := hostForTarget(target)
+	host := handleIPv6(target.Host())
 
 	urlHost, ipForLabel, err := p.urlHostAndIPLabel(target, host)
 	// Make sure we update additional labels even if there is an error.
@@ -164,14 +221,15 @@ func (p *Probe) httpRequestForTarget(target endpoint.Endpoint) (*http.Request, e
 		return nil, err
 	}
 
-	url := fmt.Sprintf("%s://%s%s", p.schemeForTarget(target), hostWithPort(urlHost, port), pathForTarget(target, p.url))
+	vars := p.targetVars(target)
+	url := fmt.Sprintf("%s://%s%s", p.schemeForTarget(target), hostWithPort(urlHost, port), substitute(pathForTarget(target, p.url), vars))
 
-	req, err := httpreq.NewRequest(p.method, url, p.requestBody)
+	req, err := httpreq.NewRequest(p.method, url, p.requestBodyForVars(vars))
 	if err != nil {
 		return nil, err
 	}
 
-	p.setHeaders(req, host, port)
+	p.setHeaders(req, hostWithPort(host, port), vars)
 	if p.c.GetUserAgent() != "" {
 		req.Header.Set("User-Agent", p.c.GetUserAgent())
 	}
@@ -179,23 +237,17 @@ func (p *Probe) httpRequestForTarget(target endpoint.Endpoint) (*http.Request, e
 	return req, nil
 }
 
-func getToken(ts oauth2.TokenSource, l *logger.Logger) (string, error) {
-	tok, err := ts.Token()
-	if err != nil {
-		return "", err
-	}
-	l.Debug("Got OAuth token, len: ", strconv.FormatInt(int64(len(tok.AccessToken)), 10), ", expirationTime: ", tok.Expiry.String())
-
-	if tok.AccessToken != "" {
-		return tok.AccessToken, nil
+// requestBodyForVars returns the request body, with placeholders substituted
+// using vars if body uses them.
+func (p *Probe) requestBodyForVars(vars map[string]string) *httpreq.RequestBody {
+	if p.bodyTemplate == nil {
+		return p.requestBody
 	}
-
-	idToken, ok := tok.Extra("id_token").(string)
-	if ok {
-		return idToken, nil
+	body := make([]string, len(p.bodyTemplate))
+	for i, b := range p.bodyTemplate {
+		body[i] = substitute(b, vars)
 	}
-
-	return "", fmt.Errorf("got unknown token: %v", tok)
+	return httpreq.NewRequestBody(body...)
 }
 
 func (p *Probe) prepareRequest(req *http.Request) *http.Request {
@@ -205,25 +257,63 @@ func (p *Probe) prepareRequest(req *http.Request) *http.Request {
 	//      share it across multiple requests.
 	//   -- if OAuth token is used, each request gets its own Authorization
 	//      header.
-	if p.oauthTS == nil && p.requestBody.Len() == 0 {
+	//   -- if request signer is used, each request gets its own signature.
+	if p.oauthTS == nil && p.signer == nil && req.GetBody == nil {
 		return req
 	}
 
 	req = req.Clone(req.Context())
 
 	if p.oauthTS != nil {
-		tok, err := getToken(p.oauthTS, p.l)
-		// Note: We don't terminate the request if there is an error in getting
-		// token. That is to avoid complicating the flow, and to make sure that
-		// OAuth refresh failures show in probe failures.
-		if err != nil {
-			p.l.Error("Error getting OAuth token: ", err.Error())
-			tok = "<token-missing>"
-		}
-		req.Header.Set("Authorization", fmt.Sprintf(p.c.GetOauthConfig().GetTokenTypeFormat(), tok))
+		req.Header.Set("Authorization", oauth.AuthorizationHeader(p.oauthTS, p.c.GetOauthConfig(), p.l))
+	}
+
+	if req.GetBody != nil {
+		req.Body, _ = req.GetBody()
 	}
 
-	req.Body = p.requestBody.Reader()
+	// Signing should be the last step as signature covers the headers.
+	if p.signer != nil {
+		// Similar to OAuth, we don't terminate the request on signing errors,
+		// unsigned request will show up in probe failures.
+		if err := p.signer.Sign(req); err != nil {
+			p.l.Error("Error signing request: ", err.Error())
+		}
+	}
 
 	return req
 }
+
+// withRequestVars returns a copy of the request with the URL path, headers
+// and body rebuilt from the configured templates, substituting target and
+// request level placeholders (see requestVars) in a single pass.
+func (p *Probe) withRequestVars(req *http.Request, target endpoint.Endpoint, runID int64) (*http.Request, error) {
+	now := time.Now()
+	vars := p.targetVars(target)
+	vars["timestamp"] = strconv.FormatInt(now.Unix(), 10)
+	vars["timestamp_ms"] = strconv.FormatInt(now.UnixMilli(), 10)
+	vars["run_id"] = strconv.FormatInt(runID, 10)
+	vars["uuid"] = uuid.NewString()
+
+	newReq := req.Clone(req.Context())
+
+	u, err := url.Parse(fmt.Sprintf("%s://%s%s", req.URL.Scheme, req.URL.Host, substitute(pathForTarget(target, p.url), vars)))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing URL after substitution: %v", err)
+	}
+	newReq.URL = u
+
+	// req.Host is either the configured Host header, which gets overridden
+	// here, or the default one.
+	p.setHeaders(newReq, req.Host, vars)
+
+	if req.GetBody != nil {
+		reqBody := p.requestBodyForVars(vars)
+		newReq.Body, newReq.ContentLength = reqBody.Reader(), reqBody.Len()
+		newReq.GetBody = func() (io.ReadCloser, error) {
+			return reqBody.Reader(), nil
+		}
+	}
+
+	return newReq, nil
+}
@@ -15,9 +15,15 @@
 package http
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strconv"
 	"testing"
 	"time"
@@ -25,12 +31,16 @@ import (
 	oauthpb "github.com/cloudprober/cloudprober/common/oauth/proto"
 	"github.com/cloudprober/cloudprober/internal/httpreq"
 	configpb "github.com/cloudprober/cloudprober/probes/http/proto"
+	"github.com/cloudprober/cloudprober/probes/http/signer"
+	signerpb "github.com/cloudprober/cloudprober/probes/http/signer/proto"
 	probesconfigpb "github.com/cloudprober/cloudprober/probes/proto"
 
 	"github.com/cloudprober/cloudprober/probes/options"
 	"github.com/cloudprober/cloudprober/targets"
 	"github.com/cloudprober/cloudprober/targets/endpoint"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/oauth2"
 	"google.golang.org/protobuf/proto"
 )
@@ -127,7 +137,7 @@ func TestURLHostAndHeader(t *testing.T) {
 				Labels: map[string]string{"fqdn": test.fqdn},
 			}
 
-			urlHost := hostForTarget(target)
+			urlHost := handleIPv6(target.Host())
 			if urlHost != test.wantURLHost {
 				t.Errorf("Got URL host: %s, want URL host: %s", urlHost, test.wantURLHost)
 			}
@@ -142,7 +152,7 @@ func TestURLHostAndHeader(t *testing.T) {
 			}
 
 			req, _ := http.NewRequest("GET", "http://cloudprober.org", nil)
-			p.setHeaders(req, urlHost, test.port)
+			p.setHeaders(req, hostWithPort(urlHost, test.port), nil)
 			assert.Equal(t, test.wantHostHeader, req.Host, "host header mismatch")
 			assert.Equal(t, "probe1", req.Header.Get("X-Probe-Name"), "probe name header mismatch")
 		})
@@ -480,6 +490,45 @@ func TestPrepareRequest(t *testing.T) {
 	}
 }
 
+func TestPrepareRequestWithSigner(t *testing.T) {
+	opts := options.DefaultOptions()
+	opts.ProbeConf = &configpb.ProbeConf{
+		Method: configpb.ProbeConf_POST.Enum(),
+		Body:   []string{`{"id":1}`},
+		Header: map[string]string{"X-Client": "cloudprober"},
+		RequestSigner: &signerpb.SignerConfig{
+			Signer: &signerpb.SignerConfig_Hmac{
+				Hmac: &signerpb.HMAC{
+					KeySource:     &signerpb.HMAC_Key{Key: "secret"},
+					SignedHeaders: []string{"X-Client"},
+				},
+			},
+		},
+	}
+	p := &Probe{}
+	require.NoError(t, p.Init("test", opts))
+
+	inReq, err := p.httpRequestForTarget(endpoint.Endpoint{Name: "api.example.com"})
+	require.NoError(t, err)
+	got := p.prepareRequest(inReq)
+	assert.NotEqual(t, inReq, got, "request should be cloned")
+	assert.Empty(t, inReq.Header.Get("X-Signature"), "original request shouldn't be signed")
+
+	sts, err := signer.StringToSign(got, []string{"X-Client"})
+	require.NoError(t, err)
+	assert.Contains(t, sts, "x-client:cloudprober")
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte(sts))
+	assert.Equal(t, hex.EncodeToString(mac.Sum(nil)), got.Header.Get("X-Signature"))
+
+	// Body is not consumed by signing.
+	body, _ := io.ReadAll(got.Body)
+	assert.Equal(t, `{"id":1}`, string(body))
+
+	opts.ProbeConf.(*configpb.ProbeConf).RequestSigner = &signerpb.SignerConfig{}
+	assert.Error(t, (&Probe{}).Init("test", opts))
+}
+
 func TestRequestHasConfiguredHeaders(t *testing.T) {
 	p := &Probe{}
 
@@ -568,3 +617,141 @@ func TestResolveFirst(t *testing.T) {
 		})
 	}
 }
+
+func TestRequestTemplating(t *testing.T) {
+	bodyFile := filepath.Join(t.TempDir(), "body.json")
+	require.NoError(t, os.WriteFile(bodyFile, []byte(`{"tenant": "@target.label.tenant@", "run": @run_id@}`), 0644))
+
+	tests := []struct {
+		name           string
+		conf           *configpb.ProbeConf
+		tenant         string
+		wantReqVars    bool
+		wantURL        string
+		wantHeaders    map[string]string
+		wantHost       string
+		wantBody       string
+		wantFinalBody  string
+		wantFinalURLRe string
+	}{
+		{
+			name: "static",
+			conf: &configpb.ProbeConf{
+				RelativeUrl: proto.String("/status"),
+				Header:      map[string]string{"X-Static": "v1"},
+				Body:        []string{"user=u@example.com"},
+			},
+			wantURL:       "http://t1.example.com:8080/status",
+			wantHeaders:   map[string]string{"X-Static": "v1"},
+			wantBody:      "user=u@example.com",
+			wantFinalBody: "user=u@example.com",
+		},
+		{
+			// Fields that don't use any placeholders are used as is.
+			name: "literal_at",
+			conf: &configpb.ProbeConf{
+				RelativeUrl: proto.String("/users/@@me"),
+				Header:      map[string]string{"X-Handle": "@@user@"},
+				Body:        []string{`{"handle": "@@user", "tag": "@target_name@"}`},
+			},
+			wantURL:       "http://t1.example.com:8080/users/@@me",
+			wantHeaders:   map[string]string{"X-Handle": "@@user@"},
+			wantBody:      `{"handle": "@@user", "tag": "@target_name@"}`,
+			wantFinalBody: `{"handle": "@@user", "tag": "@target_name@"}`,
+		},
+		{
+			name: "target_vars",
+			conf: &configpb.ProbeConf{
+				RelativeUrl: proto.String("/tenants/@target.label.tenant@/@probe@"),
+				Header:      map[string]string{"X-Tenant-ID": "@target.label.tenant@", "Host": "@target@.internal"},
+				Headers:     []*configpb.ProbeConf_Header{{Name: proto.String("X-Target"), Value: proto.String("@target.name@:@target.port@")}},
+				Body:        []string{`{"tenant": "@target.label.tenant@"}`},
+			},
+			wantURL:       "http://t1.example.com:8080/tenants/tenant-a/test",
+			wantHeaders:   map[string]string{"X-Tenant-ID": "tenant-a", "X-Target": "t1.example.com:8080", "Content-Type": "application/json"},
+			wantHost:      "t1.example.com.internal",
+			wantBody:      `{"tenant": "tenant-a"}`,
+			wantFinalBody: `{"tenant": "tenant-a"}`,
+		},
+		{
+			// Target and request level placeholders are substituted in a
+			// single pass: a substituted value is not substituted again.
+			name: "request_vars_single_pass",
+			conf: &configpb.ProbeConf{
+				Body: []string{"@target.label.tenant@ @run_id@"},
+			},
+			tenant:        "@uuid@",
+			wantReqVars:   true,
+			wantURL:       "http://t1.example.com:8080",
+			wantBody:      "@uuid@ @run_id@",
+			wantFinalBody: "@uuid@ 7",
+		},
+		{
+			name: "request_vars",
+			conf: &configpb.ProbeConf{
+				RelativeUrl: proto.String("/ping?ts=@timestamp@&run=@run_id@"),
+				Header:      map[string]string{"X-Request-ID": "@uuid@"},
+				BodyFile:    proto.String(bodyFile),
+			},
+			wantReqVars:    true,
+			wantURL:        "http://t1.example.com:8080/ping?ts=@timestamp@&run=@run_id@",
+			wantHeaders:    map[string]string{"X-Request-ID": "@uuid@"},
+			wantBody:       `{"tenant": "tenant-a", "run": @run_id@}`,
+			wantFinalBody:  `{"tenant": "tenant-a", "run": 7}`,
+			wantFinalURLRe: `^http://t1.example.com:8080/ping\?ts=[0-9]{10}&run=7$`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := options.DefaultOptions()
+			opts.ProbeConf = tt.conf
+			p := &Probe{}
+			require.NoError(t, p.Init("test", opts))
+			assert.Equal(t, tt.wantReqVars, p.useRequestVars)
+
+			if tt.tenant == "" {
+				tt.tenant = "tenant-a"
+			}
+			target := endpoint.Endpoint{
+				Name:   "t1.example.com",
+				Port:   8080,
+				Labels: map[string]string{"tenant": tt.tenant},
+			}
+			req, err := p.httpRequestForTarget(target)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantURL, req.URL.String())
+			for k, v := range tt.wantHeaders {
+				assert.Equal(t, v, req.Header.Get(k), "header %s", k)
+			}
+			if tt.wantHost != "" {
+				assert.Equal(t, tt.wantHost, req.Host)
+			}
+			body, _ := io.ReadAll(req.Body)
+			assert.Equal(t, tt.wantBody, string(body))
+
+			finalReq, err := p.requestForRun(req, target, 7)
+			require.NoError(t, err)
+			finalReq = p.prepareRequest(finalReq)
+			body, _ = io.ReadAll(finalReq.Body)
+			assert.Equal(t, tt.wantFinalBody, string(body))
+			assert.Equal(t, int64(len(tt.wantFinalBody)), finalReq.ContentLength)
+
+			if tt.wantFinalURLRe == "" {
+				assert.Equal(t, req.URL.String(), finalReq.URL.String())
+				return
+			}
+			assert.Regexp(t, tt.wantFinalURLRe, finalReq.URL.String())
+			_, err = uuid.Parse(finalReq.Header.Get("X-Request-ID"))
+			assert.NoError(t, err, "X-Request-ID is not a UUID: %s", finalReq.Header.Get("X-Request-ID"))
+
+			// Request level placeholders are not substituted in the original
+			// (cached) request, and each request gets a new UUID.
+			assert.Equal(t, "@uuid@", req.Header.Get("X-Request-ID"))
+			finalReq2, err := p.requestForRun(req, target, 8)
+			require.NoError(t, err)
+			assert.NotEqual(t, finalReq.Header.Get("X-Request-ID"), finalReq2.Header.Get("X-Request-ID"))
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signer
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	configpb "github.com/cloudprober/cloudprober/probes/http/signer/proto"
+)
+
+type hmacSigner struct {
+	c             *configpb.HMAC
+	key           []byte
+	newHash       func() hash.Hash
+	signedHeaders []string
+}
+
+func newHMAC(c *configpb.HMAC) (*hmacSigner, error) {
+	s := &hmacSigner{c: c}
+
+	switch c.GetKeySource().(type) {
+	case *configpb.HMAC_Key:
+		s.key = []byte(c.GetKey())
+	case *configpb.HMAC_KeyFile:
+		b, err := os.ReadFile(c.GetKeyFile())
+		if err != nil {
+			return nil, fmt.Errorf("hmac: error reading key file: %v", err)
+		}
+		s.key = []byte(strings.TrimRight(string(b), " \t\r\n"))
+	}
+	if len(s.key) == 0 {
+		return nil, fmt.Errorf("hmac: key is empty")
+	}
+
+	switch c.GetAlgorithm() {
+	case configpb.HMAC_SHA1:
+		s.newHash = sha1.New
+	case configpb.HMAC_SHA512:
+		s.newHash = sha512.New
+	default:
+		s.newHash = sha256.New
+	}
+
+	if c.GetTimestampHeader() != "" {
+		s.signedHeaders = append(s.signedHeaders, c.GetTimestampHeader())
+	}
+	s.signedHeaders = append(s.signedHeaders, c.GetSignedHeaders()...)
+
+	return s, nil
+}
+
+// StringToSign returns the string that is signed by the HMAC signer for the
+// given request and signed headers. It's exported to make it easier to verify
+// signatures, e.g. in tests.
+func StringToSign(req *http.Request, signedHeaders []string) (string, error) {
+	var b strings.Builder
+	b.WriteString(req.Method + "\n")
+	b.WriteString(req.URL.RequestURI() + "\n")
+	for _, h := range signedHeaders {
+		// Host is not kept in the request's header map.
+		v := req.Header.Get(h)
+		if strings.EqualFold(h, "host") {
+			v = req.Host
+			if v == "" {
+				v = req.URL.Host
+			}
+		}
+		b.WriteString(strings.ToLower(h) + ":" + strings.TrimSpace(v) + "\n")
+	}
+
+	payloadHash, err := bodyHash(req)
+	if err != nil {
+		return "", err
+	}
+	b.WriteString(payloadHash)
+	return b.String(), nil
+}
+
+func (s *hmacSigner) Sign(req *http.Request) error {
+	if s.c.GetTimestampHeader() != "" {
+		req.Header.Set(s.c.GetTimestampHeader(), strconv.FormatInt(time.Now().Unix(), 10))
+	}
+
+	sts, err := StringToSign(req, s.signedHeaders)
+	if err != nil {
+		return err
+	}
+
+	mac := hmac.New(s.newHash, s.key)
+	mac.Write([]byte(sts))
+
+	var sig string
+	if s.c.GetEncoding() == configpb.HMAC_BASE64 {
+		sig = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	} else {
+		sig = hex.EncodeToString(mac.Sum(nil))
+	}
+
+	req.Header.Set(s.c.GetSignatureHeader(), strings.ReplaceAll(s.c.GetSignatureFormat(), "%s", sig))
+	return nil
+}
@@ -0,0 +1,596 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.5
+// source: github.com/cloudprober/cloudprober/probes/http/signer/proto/config.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type HMAC_Algorithm int32
+
+const (
+	HMAC_SHA256 HMAC_Algorithm = 0
+	HMAC_SHA1   HMAC_Algorithm = 1
+	HMAC_SHA512 HMAC_Algorithm = 2
+)
+
+// Enum value maps for HMAC_Algorithm.
+var (
+	HMAC_Algorithm_name = map[int32]string{
+		0: "SHA256",
+		1: "SHA1",
+		2: "SHA512",
+	}
+	HMAC_Algorithm_value = map[string]int32{
+		"SHA256": 0,
+		"SHA1":   1,
+		"SHA512": 2,
+	}
+)
+
+func (x HMAC_Algorithm) Enum() *HMAC_Algorithm {
+	p := new(HMAC_Algorithm)
+	*p = x
+	return p
+}
+
+func (x HMAC_Algorithm) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HMAC_Algorithm) Descriptor() protoreflect.EnumDescriptor {
+	return file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_enumTypes[0].Descriptor()
+}
+
+func (HMAC_Algorithm) Type() protoreflect.EnumType {
+	return &file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_enumTypes[0]
+}
+
+func (x HMAC_Algorithm) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *HMAC_Algorithm) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = HMAC_Algorithm(num)
+	return nil
+}
+
+// Deprecated: Use HMAC_Algorithm.Descriptor instead.
+func (HMAC_Algorithm) EnumDescriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_rawDescGZIP(), []int{2, 0}
+}
+
+type HMAC_Encoding int32
+
+const (
+	HMAC_HEX    HMAC_Encoding = 0
+	HMAC_BASE64 HMAC_Encoding = 1
+)
+
+// Enum value maps for HMAC_Encoding.
+var (
+	HMAC_Encoding_name = map[int32]string{
+		0: "HEX",
+		1: "BASE64",
+	}
+	HMAC_Encoding_value = map[string]int32{
+		"HEX":    0,
+		"BASE64": 1,
+	}
+)
+
+func (x HMAC_Encoding) Enum() *HMAC_Encoding {
+	p := new(HMAC_Encoding)
+	*p = x
+	return p
+}
+
+func (x HMAC_Encoding) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HMAC_Encoding) Descriptor() protoreflect.EnumDescriptor {
+	return file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_enumTypes[1].Descriptor()
+}
+
+func (HMAC_Encoding) Type() protoreflect.EnumType {
+	return &file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_enumTypes[1]
+}
+
+func (x HMAC_Encoding) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *HMAC_Encoding) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = HMAC_Encoding(num)
+	return nil
+}
+
+// Deprecated: Use HMAC_Encoding.Descriptor instead.
+func (HMAC_Encoding) EnumDescriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_rawDescGZIP(), []int{2, 1}
+}
+
+// Request signer config. Requests are signed just before they are sent, after
+// all other modifications (placeholders, OAuth token, etc).
+type SignerConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Signer:
+	//
+	//	*SignerConfig_AwsSigv4
+	//	*SignerConfig_Hmac
+	Signer        isSignerConfig_Signer `protobuf_oneof:"signer"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SignerConfig) Reset() {
+	*x = SignerConfig{}
+	mi := &file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignerConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignerConfig) ProtoMessage() {}
+
+func (x *SignerConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignerConfig.ProtoReflect.Descriptor instead.
+func (*SignerConfig) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SignerConfig) GetSigner() isSignerConfig_Signer {
+	if x != nil {
+		return x.Signer
+	}
+	return nil
+}
+
+func (x *SignerConfig) GetAwsSigv4() *AWSSigV4 {
+	if x != nil {
+		if x, ok := x.Signer.(*SignerConfig_AwsSigv4); ok {
+			return x.AwsSigv4
+		}
+	}
+	return nil
+}
+
+func (x *SignerConfig) GetHmac() *HMAC {
+	if x != nil {
+		if x, ok := x.Signer.(*SignerConfig_Hmac); ok {
+			return x.Hmac
+		}
+	}
+	return nil
+}
+
+type isSignerConfig_Signer interface {
+	isSignerConfig_Signer()
+}
+
+type SignerConfig_AwsSigv4 struct {
+	AwsSigv4 *AWSSigV4 `protobuf:"bytes,1,opt,name=aws_sigv4,json=awsSigv4,oneof"`
+}
+
+type SignerConfig_Hmac struct {
+	Hmac *HMAC `protobuf:"bytes,2,opt,name=hmac,oneof"`
+}
+
+func (*SignerConfig_AwsSigv4) isSignerConfig_Signer() {}
+
+func (*SignerConfig_Hmac) isSignerConfig_Signer() {}
+
+// AWS Signature Version 4.
+type AWSSigV4 struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// AWS region, e.g. "us-east-1". If not set, AWS_REGION environment variable
+	// is used.
+	Region *string `protobuf:"bytes,1,opt,name=region" json:"region,omitempty"`
+	// Service name to sign the requests for, e.g. "s3", "execute-api".
+	Service *string `protobuf:"bytes,2,opt,name=service" json:"service,omitempty"`
+	// Static credentials. If not set, credentials are loaded using the default
+	// AWS credentials chain: environment variables (AWS_ACCESS_KEY_ID,
+	// AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN), shared credentials file, etc.
+	// Use environment variables to avoid putting secrets in the config, e.g.
+	// secret_access_key: "{{envSecret "MY_SECRET_KEY"}}"
+	AccessKeyId     *string `protobuf:"bytes,3,opt,name=access_key_id,json=accessKeyId" json:"access_key_id,omitempty"`
+	SecretAccessKey *string `protobuf:"bytes,4,opt,name=secret_access_key,json=secretAccessKey" json:"secret_access_key,omitempty"`
+	SessionToken    *string `protobuf:"bytes,5,opt,name=session_token,json=sessionToken" json:"session_token,omitempty"`
+	// Shared credentials file and profile to load the credentials from, e.g.
+	// "/etc/cloudprober/aws_credentials". Default is ~/.aws/credentials and
+	// the default profile.
+	CredentialsFile *string `protobuf:"bytes,6,opt,name=credentials_file,json=credentialsFile" json:"credentials_file,omitempty"`
+	Profile         *string `protobuf:"bytes,7,opt,name=profile" json:"profile,omitempty"`
+	// Don't include the payload hash in the signature. Payload hash is set to
+	// "UNSIGNED-PAYLOAD" (supported by S3) instead.
+	UnsignedPayload *bool `protobuf:"varint,8,opt,name=unsigned_payload,json=unsignedPayload" json:"unsigned_payload,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AWSSigV4) Reset() {
+	*x = AWSSigV4{}
+	mi := &file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AWSSigV4) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AWSSigV4) ProtoMessage() {}
+
+func (x *AWSSigV4) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AWSSigV4.ProtoReflect.Descriptor instead.
+func (*AWSSigV4) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AWSSigV4) GetRegion() string {
+	if x != nil && x.Region != nil {
+		return *x.Region
+	}
+	return ""
+}
+
+func (x *AWSSigV4) GetService() string {
+	if x != nil && x.Service != nil {
+		return *x.Service
+	}
+	return ""
+}
+
+func (x *AWSSigV4) GetAccessKeyId() string {
+	if x != nil && x.AccessKeyId != nil {
+		return *x.AccessKeyId
+	}
+	return ""
+}
+
+func (x *AWSSigV4) GetSecretAccessKey() string {
+	if x != nil && x.SecretAccessKey != nil {
+		return *x.SecretAccessKey
+	}
+	return ""
+}
+
+func (x *AWSSigV4) GetSessionToken() string {
+	if x != nil && x.SessionToken != nil {
+		return *x.SessionToken
+	}
+	return ""
+}
+
+func (x *AWSSigV4) GetCredentialsFile() string {
+	if x != nil && x.CredentialsFile != nil {
+		return *x.CredentialsFile
+	}
+	return ""
+}
+
+func (x *AWSSigV4) GetProfile() string {
+	if x != nil && x.Profile != nil {
+		return *x.Profile
+	}
+	return ""
+}
+
+func (x *AWSSigV4) GetUnsignedPayload() bool {
+	if x != nil && x.UnsignedPayload != nil {
+		return *x.UnsignedPayload
+	}
+	return false
+}
+
+// HMAC signer signs the following string:
+//
+//	<method>\n<request-uri>\n<header1-name>:<header1-value>\n...<hex(sha256(body))>
+//
+// where request-uri is the URL path with query (e.g. /api/v1?x=y), and
+// headers are the signed headers (lowercase names, in the configured order,
+// starting with the timestamp header if configured). Signature is added to
+// the request in the signature header.
+type HMAC struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to KeySource:
+	//
+	//	*HMAC_Key
+	//	*HMAC_KeyFile
+	KeySource isHMAC_KeySource `protobuf_oneof:"key_source"`
+	Algorithm *HMAC_Algorithm  `protobuf:"varint,3,opt,name=algorithm,enum=cloudprober.probes.http.signer.HMAC_Algorithm,def=0" json:"algorithm,omitempty"`
+	// Headers to include in the signature, in this order.
+	SignedHeaders []string `protobuf:"bytes,4,rep,name=signed_headers,json=signedHeaders" json:"signed_headers,omitempty"`
+	// If set, this header is set to the current Unix timestamp (seconds) and
+	// is included in the signature.
+	TimestampHeader *string `protobuf:"bytes,5,opt,name=timestamp_header,json=timestampHeader" json:"timestamp_header,omitempty"`
+	// Header to add the signature in.
+	SignatureHeader *string `protobuf:"bytes,6,opt,name=signature_header,json=signatureHeader,def=X-Signature" json:"signature_header,omitempty"`
+	// Format of the signature header value. "%s" is replaced by the signature,
+	// e.g. "HMAC-SHA256 keyId=probe,signature=%s".
+	SignatureFormat *string        `protobuf:"bytes,7,opt,name=signature_format,json=signatureFormat,def=%s" json:"signature_format,omitempty"`
+	Encoding        *HMAC_Encoding `protobuf:"varint,8,opt,name=encoding,enum=cloudprober.probes.http.signer.HMAC_Encoding,def=0" json:"encoding,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+// Default values for HMAC fields.
+const (
+	Default_HMAC_Algorithm       = HMAC_SHA256
+	Default_HMAC_SignatureHeader = string("X-Signature")
+	Default_HMAC_SignatureFormat = string("%s")
+	Default_HMAC_Encoding        = HMAC_HEX
+)
+
+func (x *HMAC) Reset() {
+	*x = HMAC{}
+	mi := &file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HMAC) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HMAC) ProtoMessage() {}
+
+func (x *HMAC) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HMAC.ProtoReflect.Descriptor instead.
+func (*HMAC) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *HMAC) GetKeySource() isHMAC_KeySource {
+	if x != nil {
+		return x.KeySource
+	}
+	return nil
+}
+
+func (x *HMAC) GetKey() string {
+	if x != nil {
+		if x, ok := x.KeySource.(*HMAC_Key); ok {
+			return x.Key
+		}
+	}
+	return ""
+}
+
+func (x *HMAC) GetKeyFile() string {
+	if x != nil {
+		if x, ok := x.KeySource.(*HMAC_KeyFile); ok {
+			return x.KeyFile
+		}
+	}
+	return ""
+}
+
+func (x *HMAC) GetAlgorithm() HMAC_Algorithm {
+	if x != nil && x.Algorithm != nil {
+		return *x.Algorithm
+	}
+	return Default_HMAC_Algorithm
+}
+
+func (x *HMAC) GetSignedHeaders() []string {
+	if x != nil {
+		return x.SignedHeaders
+	}
+	return nil
+}
+
+func (x *HMAC) GetTimestampHeader() string {
+	if x != nil && x.TimestampHeader != nil {
+		return *x.TimestampHeader
+	}
+	return ""
+}
+
+func (x *HMAC) GetSignatureHeader() string {
+	if x != nil && x.SignatureHeader != nil {
+		return *x.SignatureHeader
+	}
+	return Default_HMAC_SignatureHeader
+}
+
+func (x *HMAC) GetSignatureFormat() string {
+	if x != nil && x.SignatureFormat != nil {
+		return *x.SignatureFormat
+	}
+	return Default_HMAC_SignatureFormat
+}
+
+func (x *HMAC) GetEncoding() HMAC_Encoding {
+	if x != nil && x.Encoding != nil {
+		return *x.Encoding
+	}
+	return Default_HMAC_Encoding
+}
+
+type isHMAC_KeySource interface {
+	isHMAC_KeySource()
+}
+
+type HMAC_Key struct {
+	// HMAC key. Use environment variables to avoid putting secrets in the
+	// config, e.g. key: "{{envSecret "HMAC_KEY"}}"
+	Key string `protobuf:"bytes,1,opt,name=key,oneof"`
+}
+
+type HMAC_KeyFile struct {
+	// File to read the HMAC key from. Trailing whitespace is ignored.
+	KeyFile string `protobuf:"bytes,2,opt,name=key_file,json=keyFile,oneof"`
+}
+
+func (*HMAC_Key) isHMAC_KeySource() {}
+
+func (*HMAC_KeyFile) isHMAC_KeySource() {}
+
+var File_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto protoreflect.FileDescriptor
+
+const file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_rawDesc = "" +
+	"\n" +
+	"Hgithub.com/cloudprober/cloudprober/probes/http/signer/proto/config.proto\x12\x1ecloudprober.probes.http.signer\"\x9d\x01\n" +
+	"\fSignerConfig\x12G\n" +
+	"\taws_sigv4\x18\x01 \x01(\v2(.cloudprober.probes.http.signer.AWSSigV4H\x00R\bawsSigv4\x12:\n" +
+	"\x04hmac\x18\x02 \x01(\v2$.cloudprober.probes.http.signer.HMACH\x00R\x04hmacB\b\n" +
+	"\x06signer\"\xa1\x02\n" +
+	"\bAWSSigV4\x12\x16\n" +
+	"\x06region\x18\x01 \x01(\tR\x06region\x12\x18\n" +
+	"\aservice\x18\x02 \x01(\tR\aservice\x12\"\n" +
+	"\raccess_key_id\x18\x03 \x01(\tR\vaccessKeyId\x12*\n" +
+	"\x11secret_access_key\x18\x04 \x01(\tR\x0fsecretAccessKey\x12#\n" +
+	"\rsession_token\x18\x05 \x01(\tR\fsessionToken\x12)\n" +
+	"\x10credentials_file\x18\x06 \x01(\tR\x0fcredentialsFile\x12\x18\n" +
+	"\aprofile\x18\a \x01(\tR\aprofile\x12)\n" +
+	"\x10unsigned_payload\x18\b \x01(\bR\x0funsignedPayload\"\xf4\x03\n" +
+	"\x04HMAC\x12\x12\n" +
+	"\x03key\x18\x01 \x01(\tH\x00R\x03key\x12\x1b\n" +
+	"\bkey_file\x18\x02 \x01(\tH\x00R\akeyFile\x12T\n" +
+	"\talgorithm\x18\x03 \x01(\x0e2..cloudprober.probes.http.signer.HMAC.Algorithm:\x06SHA256R\talgorithm\x12%\n" +
+	"\x0esigned_headers\x18\x04 \x03(\tR\rsignedHeaders\x12)\n" +
+	"\x10timestamp_header\x18\x05 \x01(\tR\x0ftimestampHeader\x126\n" +
+	"\x10signature_header\x18\x06 \x01(\t:\vX-SignatureR\x0fsignatureHeader\x12-\n" +
+	"\x10signature_format\x18\a \x01(\t:\x02%sR\x0fsignatureFormat\x12N\n" +
+	"\bencoding\x18\b \x01(\x0e2-.cloudprober.probes.http.signer.HMAC.Encoding:\x03HEXR\bencoding\"-\n" +
+	"\tAlgorithm\x12\n" +
+	"\n" +
+	"\x06SHA256\x10\x00\x12\b\n" +
+	"\x04SHA1\x10\x01\x12\n" +
+	"\n" +
+	"\x06SHA512\x10\x02\"\x1f\n" +
+	"\bEncoding\x12\a\n" +
+	"\x03HEX\x10\x00\x12\n" +
+	"\n" +
+	"\x06BASE64\x10\x01B\f\n" +
+	"\n" +
+	"key_sourceB=Z;github.com/cloudprober/cloudprober/probes/http/signer/proto"
+
+var (
+	file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_rawDescOnce sync.Once
+	file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_rawDescData []byte
+)
+
+func file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_rawDescGZIP() []byte {
+	file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_rawDescOnce.Do(func() {
+		file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_rawDesc), len(file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_rawDesc)))
+	})
+	return file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_rawDescData
+}
+
+var file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_goTypes = []any{
+	(HMAC_Algorithm)(0),  // 0: cloudprober.probes.http.signer.HMAC.Algorithm
+	(HMAC_Encoding)(0),   // 1: cloudprober.probes.http.signer.HMAC.Encoding
+	(*SignerConfig)(nil), // 2: cloudprober.probes.http.signer.SignerConfig
+	(*AWSSigV4)(nil),     // 3: cloudprober.probes.http.signer.AWSSigV4
+	(*HMAC)(nil),         // 4: cloudprober.probes.http.signer.HMAC
+}
+var file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_depIdxs = []int32{
+	3, // 0: cloudprober.probes.http.signer.SignerConfig.aws_sigv4:type_name -> cloudprober.probes.http.signer.AWSSigV4
+	4, // 1: cloudprober.probes.http.signer.SignerConfig.hmac:type_name -> cloudprober.probes.http.signer.HMAC
+	0, // 2: cloudprober.probes.http.signer.HMAC.algorithm:type_name -> cloudprober.probes.http.signer.HMAC.Algorithm
+	1, // 3: cloudprober.probes.http.signer.HMAC.encoding:type_name -> cloudprober.probes.http.signer.HMAC.Encoding
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_init() }
+func file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_init() {
+	if File_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto != nil {
+		return
+	}
+	file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_msgTypes[0].OneofWrappers = []any{
+		(*SignerConfig_AwsSigv4)(nil),
+		(*SignerConfig_Hmac)(nil),
+	}
+	file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_msgTypes[2].OneofWrappers = []any{
+		(*HMAC_Key)(nil),
+		(*HMAC_KeyFile)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_rawDesc), len(file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_goTypes,
+		DependencyIndexes: file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_depIdxs,
+		EnumInfos:         file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_enumTypes,
+		MessageInfos:      file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_msgTypes,
+	}.Build()
+	File_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto = out.File
+	file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_goTypes = nil
+	file_github_com_cloudprober_cloudprober_probes_http_signer_proto_config_proto_depIdxs = nil
+}
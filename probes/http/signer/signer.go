@@ -0,0 +1,63 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package signer implements HTTP request signers (AWS SigV4 and HMAC) for
+// the HTTP probe.
+package signer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+
+	configpb "github.com/cloudprober/cloudprober/probes/http/signer/proto"
+)
+
+// Signer signs HTTP requests.
+type Signer interface {
+	// Sign signs the request in place, usually by adding headers to it.
+	Sign(req *http.Request) error
+}
+
+// New returns a new Signer for the given config.
+func New(ctx context.Context, c *configpb.SignerConfig) (Signer, error) {
+	switch c.GetSigner().(type) {
+	case *configpb.SignerConfig_AwsSigv4:
+		return newSigV4(ctx, c.GetAwsSigv4())
+	case *configpb.SignerConfig_Hmac:
+		return newHMAC(c.GetHmac())
+	default:
+		return nil, fmt.Errorf("signer type not specified")
+	}
+}
+
+// bodyHash returns the hex encoded SHA-256 hash of the request body. Request
+// body is read using req.GetBody, so that request's Body is left untouched.
+func bodyHash(req *http.Request) (string, error) {
+	h := sha256.New()
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return "", fmt.Errorf("error getting request body: %v", err)
+		}
+		defer body.Close()
+		if _, err := io.Copy(h, body); err != nil {
+			return "", fmt.Errorf("error reading request body: %v", err)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,260 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signer
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	configpb "github.com/cloudprober/cloudprober/probes/http/signer/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// verifyingServer starts a test server that verifies the request signature
+// using the verify function. It responds with 200 if signature is valid,
+// 403 otherwise.
+func verifyingServer(t *testing.T, verify func(r *http.Request, body []byte) bool) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !verify(r, body) {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func doSignedRequest(t *testing.T, s Signer, method, url, body string) int {
+	t.Helper()
+	var req *http.Request
+	if body != "" {
+		req, _ = http.NewRequest(method, url, strings.NewReader(body))
+	} else {
+		req, _ = http.NewRequest(method, url, nil)
+	}
+	require.NoError(t, s.Sign(req))
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestNew(t *testing.T) {
+	t.Setenv("AWS_REGION", "")
+
+	keyFile := filepath.Join(t.TempDir(), "key")
+	require.NoError(t, os.WriteFile(keyFile, []byte("secret\n"), 0600))
+
+	tests := []struct {
+		name    string
+		c       *configpb.SignerConfig
+		wantErr bool
+	}{
+		{
+			name:    "no_signer",
+			c:       &configpb.SignerConfig{},
+			wantErr: true,
+		},
+		{
+			name:    "sigv4_no_service",
+			c:       &configpb.SignerConfig{Signer: &configpb.SignerConfig_AwsSigv4{AwsSigv4: &configpb.AWSSigV4{Region: proto.String("us-east-1")}}},
+			wantErr: true,
+		},
+		{
+			name:    "sigv4_no_region",
+			c:       &configpb.SignerConfig{Signer: &configpb.SignerConfig_AwsSigv4{AwsSigv4: &configpb.AWSSigV4{Service: proto.String("s3")}}},
+			wantErr: true,
+		},
+		{
+			name: "sigv4",
+			c:    &configpb.SignerConfig{Signer: &configpb.SignerConfig_AwsSigv4{AwsSigv4: &configpb.AWSSigV4{Service: proto.String("s3"), Region: proto.String("us-east-1")}}},
+		},
+		{
+			name:    "hmac_no_key",
+			c:       &configpb.SignerConfig{Signer: &configpb.SignerConfig_Hmac{Hmac: &configpb.HMAC{}}},
+			wantErr: true,
+		},
+		{
+			name:    "hmac_bad_key_file",
+			c:       &configpb.SignerConfig{Signer: &configpb.SignerConfig_Hmac{Hmac: &configpb.HMAC{KeySource: &configpb.HMAC_KeyFile{KeyFile: "/does/not/exist"}}}},
+			wantErr: true,
+		},
+		{
+			name: "hmac_key_file",
+			c:    &configpb.SignerConfig{Signer: &configpb.SignerConfig_Hmac{Hmac: &configpb.HMAC{KeySource: &configpb.HMAC_KeyFile{KeyFile: keyFile}}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := New(context.Background(), tt.c)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, s)
+		})
+	}
+
+	s, _ := newHMAC(&configpb.HMAC{KeySource: &configpb.HMAC_KeyFile{KeyFile: keyFile}})
+	assert.Equal(t, "secret", string(s.key), "trailing newline should be trimmed")
+}
+
+func TestSigV4(t *testing.T) {
+	creds := aws.Credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+
+	ts := verifyingServer(t, func(r *http.Request, body []byte) bool {
+		signingTime, err := time.Parse("20060102T150405Z", r.Header.Get("X-Amz-Date"))
+		if err != nil {
+			return false
+		}
+		payloadHash := r.Header.Get("X-Amz-Content-Sha256")
+		if payloadHash != unsignedPayload {
+			req := &http.Request{GetBody: func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }}
+			if h, _ := bodyHash(req); h != payloadHash {
+				return false
+			}
+		}
+
+		// Re-sign the request and compare the signatures.
+		req := r.Clone(context.Background())
+		req.URL.Scheme, req.URL.Host = "http", r.Host
+		gotAuth := req.Header.Get("Authorization")
+		// Keep only the signed headers, transport adds some headers after
+		// signing, e.g. Accept-Encoding.
+		_, signedHeaders, _ := strings.Cut(gotAuth, "SignedHeaders=")
+		signedHeaders, _, _ = strings.Cut(signedHeaders, ",")
+		for k := range req.Header {
+			if !slices.Contains(strings.Split(signedHeaders, ";"), strings.ToLower(k)) {
+				req.Header.Del(k)
+			}
+		}
+		if err := v4.NewSigner().SignHTTP(context.Background(), creds, req, payloadHash, "execute-api", "eu-west-1", signingTime); err != nil {
+			return false
+		}
+		return gotAuth != "" && gotAuth == req.Header.Get("Authorization")
+	})
+
+	newSigner := func(c *configpb.AWSSigV4) Signer {
+		t.Helper()
+		s, err := newSigV4(context.Background(), c)
+		require.NoError(t, err)
+		return s
+	}
+
+	conf := &configpb.AWSSigV4{
+		Region:          proto.String("eu-west-1"),
+		Service:         proto.String("execute-api"),
+		AccessKeyId:     proto.String(creds.AccessKeyID),
+		SecretAccessKey: proto.String(creds.SecretAccessKey),
+	}
+	s := newSigner(conf)
+	assert.Equal(t, http.StatusOK, doSignedRequest(t, s, "GET", ts.URL+"/api/v1/items?a=b", ""))
+	assert.Equal(t, http.StatusOK, doSignedRequest(t, s, "POST", ts.URL+"/api/v1/items", `{"name":"x"}`))
+
+	unsigned := proto.CloneOf(conf)
+	unsigned.UnsignedPayload = proto.Bool(true)
+	assert.Equal(t, http.StatusOK, doSignedRequest(t, newSigner(unsigned), "PUT", ts.URL+"/bucket/key", "data"))
+
+	wrongKey := proto.CloneOf(conf)
+	wrongKey.SecretAccessKey = proto.String("wrong")
+	assert.Equal(t, http.StatusForbidden, doSignedRequest(t, newSigner(wrongKey), "GET", ts.URL+"/api/v1/items", ""))
+
+	// Credentials from environment variables.
+	t.Setenv("AWS_ACCESS_KEY_ID", creds.AccessKeyID)
+	t.Setenv("AWS_SECRET_ACCESS_KEY", creds.SecretAccessKey)
+	assert.Equal(t, http.StatusOK, doSignedRequest(t, newSigner(&configpb.AWSSigV4{Region: conf.Region, Service: conf.Service}), "GET", ts.URL+"/api", ""))
+
+	// Credentials from a shared credentials file.
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	credsFile := filepath.Join(t.TempDir(), "credentials")
+	require.NoError(t, os.WriteFile(credsFile, []byte("[probe]\naws_access_key_id = "+creds.AccessKeyID+"\naws_secret_access_key = "+creds.SecretAccessKey+"\n"), 0600))
+	fileConf := &configpb.AWSSigV4{Region: conf.Region, Service: conf.Service, CredentialsFile: proto.String(credsFile), Profile: proto.String("probe")}
+	assert.Equal(t, http.StatusOK, doSignedRequest(t, newSigner(fileConf), "GET", ts.URL+"/api", ""))
+}
+
+func TestHMAC(t *testing.T) {
+	key := []byte("hmac-secret")
+
+	ts := verifyingServer(t, func(r *http.Request, body []byte) bool {
+		r.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+		sts, err := StringToSign(r, []string{"X-Timestamp", "Host", "Content-Type"})
+		if err != nil {
+			return false
+		}
+		mac := hmac.New(sha512.New, key)
+		mac.Write([]byte(sts))
+		want := "HMAC-SHA512 keyId=probe,signature=" + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+		return r.Header.Get("Authorization") == want && r.Header.Get("X-Timestamp") != ""
+	})
+
+	conf := &configpb.HMAC{
+		KeySource:       &configpb.HMAC_Key{Key: string(key)},
+		Algorithm:       configpb.HMAC_SHA512.Enum(),
+		SignedHeaders:   []string{"Host", "Content-Type"},
+		TimestampHeader: proto.String("X-Timestamp"),
+		SignatureHeader: proto.String("Authorization"),
+		SignatureFormat: proto.String("HMAC-SHA512 keyId=probe,signature=%s"),
+		Encoding:        configpb.HMAC_BASE64.Enum(),
+	}
+	s, err := newHMAC(conf)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, doSignedRequest(t, s, "GET", ts.URL+"/api/v1/items?a=b", ""))
+	assert.Equal(t, http.StatusOK, doSignedRequest(t, s, "POST", ts.URL+"/api/v1/items", `{"name":"x"}`))
+
+	conf.KeySource = &configpb.HMAC_Key{Key: "wrong"}
+	s, _ = newHMAC(conf)
+	assert.Equal(t, http.StatusForbidden, doSignedRequest(t, s, "GET", ts.URL+"/api/v1/items", ""))
+}
+
+func TestStringToSign(t *testing.T) {
+	req, _ := http.NewRequest("POST", "http://api.example.com/v1/items?b=2&a=1", strings.NewReader("hello"))
+	req.Header.Set("Content-Type", "application/json")
+
+	sts, err := StringToSign(req, []string{"Content-Type", "host", "X-Missing"})
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Join([]string{
+		"POST",
+		"/v1/items?b=2&a=1",
+		"content-type:application/json",
+		"host:api.example.com",
+		"x-missing:",
+		"2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", // sha256("hello")
+	}, "\n"), sts)
+
+	// Body is still readable.
+	b, _ := io.ReadAll(req.Body)
+	assert.Equal(t, "hello", string(b))
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpflow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	configpb "github.com/cloudprober/cloudprober/probes/httpflow/proto"
+	"github.com/itchyny/gojq"
+)
+
+// extractor extracts a variable from an HTTP response.
+type extractor struct {
+	name   string
+	jq     *gojq.Query
+	re     *regexp.Regexp
+	header string
+}
+
+func newExtractor(c *configpb.Extract) (*extractor, error) {
+	name := c.GetName()
+	if name == "" {
+		return nil, errors.New("variable name is required")
+	}
+	if name == "probe" || strings.HasPrefix(name, "target") || strings.Contains(name, "@") {
+		return nil, fmt.Errorf("invalid variable name: %s, it should not contain '@', start with 'target' or be 'probe'", name)
+	}
+
+	e := &extractor{name: name}
+
+	switch c.GetSource().(type) {
+	case *configpb.Extract_JqFilter:
+		q, err := gojq.Parse(c.GetJqFilter())
+		if err != nil {
+			return nil, fmt.Errorf("error parsing jq filter (%s) for variable %s: %v", c.GetJqFilter(), name, err)
+		}
+		e.jq = q
+	case *configpb.Extract_Regex:
+		re, err := regexp.Compile(c.GetRegex())
+		if err != nil {
+			return nil, fmt.Errorf("error parsing regex (%s) for variable %s: %v", c.GetRegex(), name, err)
+		}
+		e.re = re
+	case *configpb.Extract_Header:
+		e.header = c.GetHeader()
+	default:
+		return nil, fmt.Errorf("no extraction source specified for variable %s", name)
+	}
+
+	return e, nil
+}
+
+func (e *extractor) extractJQ(body []byte) (string, error) {
+	var input any
+	if err := json.Unmarshal(body, &input); err != nil {
+		return "", fmt.Errorf("response is not a valid JSON: %v", err)
+	}
+
+	iter := e.jq.Run(input)
+	v, ok := iter.Next()
+	if !ok || v == nil {
+		return "", fmt.Errorf("jq filter (%s) returned no value", e.jq.String())
+	}
+	if err, ok := v.(error); ok {
+		return "", err
+	}
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func (e *extractor) extract(resp *http.Response, body []byte) (string, error) {
+	switch {
+	case e.jq != nil:
+		return e.extractJQ(body)
+
+	case e.re != nil:
+		matches := e.re.FindSubmatch(body)
+		if matches == nil {
+			return "", fmt.Errorf("regex (%s) didn't match the response", e.re.String())
+		}
+		if len(matches) > 1 {
+			return string(matches[1]), nil
+		}
+		return string(matches[0]), nil
+
+	default:
+		v := resp.Header.Get(e.header)
+		if v == "" {
+			return "", fmt.Errorf("header %s not found in the response", e.header)
+		}
+		return v, nil
+	}
+}
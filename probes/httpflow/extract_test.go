@@ -0,0 +1,115 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpflow
+
+import (
+	"net/http"
+	"testing"
+
+	configpb "github.com/cloudprober/cloudprober/probes/httpflow/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestExtract(t *testing.T) {
+	resp := &http.Response{Header: http.Header{"X-Token": []string{"h1"}}}
+	body := []byte(`{"token": "t1", "count": 3, "items": [{"id": "a"}, {"id": "b"}], "obj": {"k": "v"}}`)
+
+	tests := []struct {
+		name    string
+		conf    *configpb.Extract
+		body    []byte
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "jq_string",
+			conf: &configpb.Extract{Source: &configpb.Extract_JqFilter{JqFilter: ".token"}},
+			want: "t1",
+		},
+		{
+			name: "jq_number",
+			conf: &configpb.Extract{Source: &configpb.Extract_JqFilter{JqFilter: ".count"}},
+			want: "3",
+		},
+		{
+			name: "jq_first_output",
+			conf: &configpb.Extract{Source: &configpb.Extract_JqFilter{JqFilter: ".items[].id"}},
+			want: "a",
+		},
+		{
+			name: "jq_object",
+			conf: &configpb.Extract{Source: &configpb.Extract_JqFilter{JqFilter: ".obj"}},
+			want: `{"k":"v"}`,
+		},
+		{
+			name:    "jq_missing",
+			conf:    &configpb.Extract{Source: &configpb.Extract_JqFilter{JqFilter: ".missing"}},
+			wantErr: true,
+		},
+		{
+			name:    "jq_not_json",
+			conf:    &configpb.Extract{Source: &configpb.Extract_JqFilter{JqFilter: ".token"}},
+			body:    []byte("not json"),
+			wantErr: true,
+		},
+		{
+			name: "regex_group",
+			conf: &configpb.Extract{Source: &configpb.Extract_Regex{Regex: `"token": "([^"]+)"`}},
+			want: "t1",
+		},
+		{
+			name: "regex_no_group",
+			conf: &configpb.Extract{Source: &configpb.Extract_Regex{Regex: `t[0-9]`}},
+			want: "t1",
+		},
+		{
+			name:    "regex_no_match",
+			conf:    &configpb.Extract{Source: &configpb.Extract_Regex{Regex: `xyz`}},
+			wantErr: true,
+		},
+		{
+			name: "header",
+			conf: &configpb.Extract{Source: &configpb.Extract_Header{Header: "x-token"}},
+			want: "h1",
+		},
+		{
+			name:    "header_missing",
+			conf:    &configpb.Extract{Source: &configpb.Extract_Header{Header: "X-Missing"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.conf.Name = proto.String("v")
+			e, err := newExtractor(tt.conf)
+			require.NoError(t, err)
+
+			b := body
+			if tt.body != nil {
+				b = tt.body
+			}
+			got, err := e.extract(resp, b)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,412 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package httpflow implements the HTTP_FLOW probe type. HTTP flow probe runs
+// an ordered sequence of HTTP requests (steps) for each target, for example
+// login, fetch token, call API and logout. Later steps can use variables
+// extracted from the earlier steps' responses.
+package httpflow
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/cookiejar"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudprober/cloudprober/common/strtemplate"
+	"github.com/cloudprober/cloudprober/common/tlsconfig"
+	"github.com/cloudprober/cloudprober/internal/httpreq"
+	"github.com/cloudprober/cloudprober/internal/validators"
+	"github.com/cloudprober/cloudprober/logger"
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/metrics/singlerun"
+	"github.com/cloudprober/cloudprober/probes/common/sched"
+	configpb "github.com/cloudprober/cloudprober/probes/httpflow/proto"
+	"github.com/cloudprober/cloudprober/probes/options"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+)
+
+// Probe holds aggregate information about all probe runs, per-target.
+type Probe struct {
+	name string
+	opts *options.Options
+	c    *configpb.ProbeConf
+	l    *logger.Logger
+
+	steps        []*step
+	transport    http.RoundTripper
+	redirectFunc func(req *http.Request, via []*http.Request) error
+}
+
+type step struct {
+	c          *configpb.Step
+	method     string
+	extractors []*extractor
+	validators []*validators.Validator
+}
+
+type stepResult struct {
+	name              string
+	total, success    int64
+	latency           metrics.LatencyValue
+	respCodes         *metrics.Map[int64]
+	validationFailure *metrics.Map[int64]
+}
+
+type probeResult struct {
+	total, success, timeouts int64
+	latency                  metrics.LatencyValue
+	steps                    []*stepResult
+}
+
+func (p *Probe) initSteps() error {
+	if len(p.c.GetStep()) == 0 {
+		return errors.New("no steps configured")
+	}
+
+	names := make(map[string]bool)
+	for _, sc := range p.c.GetStep() {
+		if names[sc.GetName()] {
+			return fmt.Errorf("step %s is defined twice", sc.GetName())
+		}
+		names[sc.GetName()] = true
+
+		s := &step{
+			c:      sc,
+			method: sc.GetMethod().String(),
+		}
+		for _, ec := range sc.GetExtract() {
+			e, err := newExtractor(ec)
+			if err != nil {
+				return fmt.Errorf("step %s: %v", sc.GetName(), err)
+			}
+			s.extractors = append(s.extractors, e)
+		}
+
+		var err error
+		if s.validators, err = validators.Init(sc.GetValidator()); err != nil {
+			return fmt.Errorf("step %s: error initializing validators: %v", sc.GetName(), err)
+		}
+		p.steps = append(p.steps, s)
+	}
+	return nil
+}
+
+func (p *Probe) getTransport() (*http.Transport, error) {
+	dialer := &net.Dialer{
+		Timeout:   p.opts.Timeout,
+		KeepAlive: 30 * time.Second, // TCP keep-alive
+	}
+	if p.opts.SourceIP != nil {
+		dialer.LocalAddr = &net.TCPAddr{
+			IP: p.opts.SourceIP,
+		}
+	}
+
+	transport := &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		ForceAttemptHTTP2:   true,
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: p.opts.Timeout,
+		DisableKeepAlives:   !p.c.GetKeepAlive(),
+		IdleConnTimeout:     2 * p.opts.Interval,
+	}
+
+	if p.c.GetTlsConfig() != nil {
+		transport.TLSClientConfig = &tls.Config{}
+		if err := tlsconfig.UpdateTLSConfig(transport.TLSClientConfig, p.c.GetTlsConfig()); err != nil {
+			return nil, err
+		}
+	}
+	return transport, nil
+}
+
+// Init initializes the probe with the given params.
+func (p *Probe) Init(name string, opts *options.Options) error {
+	c, ok := opts.ProbeConf.(*configpb.ProbeConf)
+	if !ok {
+		return fmt.Errorf("not http_flow config")
+	}
+	p.name = name
+	p.opts = opts
+	if p.l = opts.Logger; p.l == nil {
+		p.l = &logger.Logger{}
+	}
+	p.c = c
+	if p.c == nil {
+		p.c = &configpb.ProbeConf{}
+	}
+
+	if len(p.opts.Validators) > 0 {
+		return errors.New("probe level validators are not supported by the HTTP_FLOW probe, use step level validators instead")
+	}
+
+	if err := p.initSteps(); err != nil {
+		return err
+	}
+
+	transport, err := p.getTransport()
+	if err != nil {
+		return err
+	}
+	p.transport = transport
+
+	if p.c.MaxRedirects != nil {
+		p.redirectFunc = func(req *http.Request, via []*http.Request) error {
+			if len(via) > int(p.c.GetMaxRedirects()) {
+				return http.ErrUseLastResponse
+			}
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (p *Probe) newResult() *probeResult {
+	newLatency := func() metrics.LatencyValue {
+		if p.opts.LatencyDist != nil {
+			return p.opts.LatencyDist.CloneDist()
+		}
+		return metrics.NewFloat(0)
+	}
+
+	result := &probeResult{
+		latency: newLatency(),
+	}
+	for _, s := range p.steps {
+		sr := &stepResult{
+			name:      s.c.GetName(),
+			latency:   newLatency(),
+			respCodes: metrics.NewMap("code"),
+		}
+		if len(s.validators) > 0 {
+			sr.validationFailure = validators.ValidationFailureMap(s.validators)
+		}
+		result.steps = append(result.steps, sr)
+	}
+	return result
+}
+
+// Metrics returns the flow level EventMetrics, followed by per-step
+// EventMetrics. Per-step EventMetrics have a "step" label and are not used
+// for alerting.
+func (result *probeResult) Metrics(ts time.Time, _ int64, opts *options.Options) []*metrics.EventMetrics {
+	ems := []*metrics.EventMetrics{
+		metrics.NewEventMetrics(ts).
+			AddMetric("total", metrics.NewInt(result.total)).
+			AddMetric("success", metrics.NewInt(result.success)).
+			AddMetric(opts.LatencyMetricName, result.latency.Clone()).
+			AddMetric("timeouts", metrics.NewInt(result.timeouts)).
+			AddLabel("ptype", "http_flow"),
+	}
+
+	for _, sr := range result.steps {
+		em := metrics.NewEventMetrics(ts).
+			AddMetric("total", metrics.NewInt(sr.total)).
+			AddMetric("success", metrics.NewInt(sr.success)).
+			AddMetric(opts.LatencyMetricName, sr.latency.Clone()).
+			AddMetric("resp-code", sr.respCodes.Clone())
+		if sr.validationFailure != nil {
+			em.AddMetric("validation_failure", sr.validationFailure.Clone())
+		}
+		em.AddLabel("ptype", "http_flow").
+			AddLabel("step", sr.name)
+		em.SetNotForAlerting()
+		ems = append(ems, em)
+	}
+	return ems
+}
+
+// baseVars returns the variables available to all steps.
+func (p *Probe) baseVars(target endpoint.Endpoint) map[string]string {
+	vars := map[string]string{
+		"probe":       p.name,
+		"target":      target.Name,
+		"target.name": target.Name,
+		"target.port": strconv.Itoa(target.Port),
+	}
+	for k, v := range target.Labels {
+		vars["target.label."+k] = v
+	}
+	return vars
+}
+
+func substitute(s string, vars map[string]string) string {
+	out, _ := strtemplate.SubstituteLabels(s, vars)
+	return out
+}
+
+func (p *Probe) stepURL(s *step, target endpoint.Endpoint, vars map[string]string) string {
+	url := substitute(s.c.GetUrl(), vars)
+	if url != "" && !strings.HasPrefix(url, "/") {
+		return url
+	}
+
+	port := int(p.c.GetPort())
+	if port == 0 {
+		port = target.Port
+	}
+	host := target.Name
+	if ip := net.ParseIP(host); ip != nil && ip.To4() == nil {
+		host = "[" + host + "]"
+	}
+	if port != 0 {
+		host = fmt.Sprintf("%s:%d", host, port)
+	}
+	return fmt.Sprintf("%s://%s%s", strings.ToLower(p.c.GetScheme().String()), host, url)
+}
+
+func (p *Probe) stepRequest(ctx context.Context, s *step, target endpoint.Endpoint, vars map[string]string) (*http.Request, error) {
+	var body []string
+	for _, b := range s.c.GetBody() {
+		body = append(body, substitute(b, vars))
+	}
+
+	req, err := httpreq.NewRequest(s.method, p.stepURL(s, target, vars), httpreq.NewRequestBody(body...))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	if p.c.GetUserAgent() != "" {
+		req.Header.Set("User-Agent", p.c.GetUserAgent())
+	}
+	for _, headers := range []map[string]string{p.c.GetHeader(), s.c.GetHeader()} {
+		for k, v := range headers {
+			if k == "Host" {
+				req.Host = substitute(v, vars)
+				continue
+			}
+			req.Header.Set(k, substitute(v, vars))
+		}
+	}
+	return req, nil
+}
+
+// runStep runs a step and extracts variables from its response into vars.
+func (p *Probe) runStep(ctx context.Context, client *http.Client, s *step, sr *stepResult, target endpoint.Endpoint, vars map[string]string) error {
+	req, err := p.stepRequest(ctx, s, target, vars)
+	if err != nil {
+		return fmt.Errorf("error creating request: %v", err)
+	}
+	l := p.l.WithAttributes(slog.String("target", target.Name), slog.String("step", s.c.GetName()), slog.String("url", req.URL.String()))
+
+	start := time.Now()
+	sr.total++
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	latency := time.Since(start)
+
+	l.Debug("Response: \n" + string(respBody))
+	sr.respCodes.IncKey(strconv.Itoa(resp.StatusCode))
+
+	if len(s.validators) > 0 {
+		failedValidations := validators.RunValidators(s.validators, &validators.Input{Response: resp, ResponseBody: respBody}, sr.validationFailure, l)
+		if len(failedValidations) > 0 {
+			return fmt.Errorf("failed validations: %s", strings.Join(failedValidations, ","))
+		}
+	}
+
+	for _, e := range s.extractors {
+		v, err := e.extract(resp, respBody)
+		if err != nil {
+			return fmt.Errorf("error extracting variable %s: %v", e.name, err)
+		}
+		vars[e.name] = v
+	}
+
+	sr.success++
+	sr.latency.AddFloat64(latency.Seconds() / p.opts.LatencyUnit.Seconds())
+	return nil
+}
+
+func (p *Probe) httpClient() *http.Client {
+	client := &http.Client{Transport: p.transport, CheckRedirect: p.redirectFunc}
+	if p.c.GetEnableCookies() {
+		// cookiejar.New never returns an error.
+		client.Jar, _ = cookiejar.New(nil)
+	}
+	return client
+}
+
+func (p *Probe) runProbe(ctx context.Context, runReq *sched.RunProbeForTargetRequest) {
+	if runReq.Result == nil {
+		runReq.Result = p.newResult()
+	}
+	target, result := runReq.Target, runReq.Result.(*probeResult)
+
+	// Each flow run gets its own client, so that cookies are not shared
+	// across runs.
+	client := p.httpClient()
+	vars := p.baseVars(target)
+
+	start := time.Now()
+	var flowErr error
+	for i, s := range p.steps {
+		if flowErr != nil && !s.c.GetAlwaysRun() {
+			continue
+		}
+		if err := p.runStep(ctx, client, s, result.steps[i], target, vars); err != nil {
+			p.l.Warningf("HTTP flow step %s failed for target %s: %v", s.c.GetName(), target.Name, err)
+			if flowErr == nil {
+				flowErr = fmt.Errorf("step %s: %v", s.c.GetName(), err)
+			}
+		}
+	}
+	latency := time.Since(start)
+
+	result.total++
+	if flowErr == nil {
+		result.success++
+		result.latency.AddFloat64(latency.Seconds() / p.opts.LatencyUnit.Seconds())
+	} else if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		result.timeouts++
+	}
+	runReq.LastRun.Set(flowErr == nil, latency, flowErr)
+}
+
+// RunOnce runs the probe just once.
+func (p *Probe) RunOnce(ctx context.Context) []*singlerun.ProbeRunResult {
+	p.l.Info("Running HTTP flow probe once.")
+	return sched.RunOnce(ctx, p.opts, p.runProbe)
+}
+
+// Start starts and runs the probe indefinitely.
+func (p *Probe) Start(ctx context.Context, dataChan chan *metrics.EventMetrics) {
+	s := &sched.Scheduler{
+		ProbeName:         p.name,
+		DataChan:          dataChan,
+		Opts:              p.opts,
+		RunProbeForTarget: p.runProbe,
+	}
+
+	s.UpdateTargetsAndStartProbes(ctx)
+}
@@ -0,0 +1,347 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpflow
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cloudprober/cloudprober/internal/validators"
+	httpvalpb "github.com/cloudprober/cloudprober/internal/validators/http/proto"
+	validatorspb "github.com/cloudprober/cloudprober/internal/validators/proto"
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/probes/common/sched"
+	httppb "github.com/cloudprober/cloudprober/probes/http/proto"
+	configpb "github.com/cloudprober/cloudprober/probes/httpflow/proto"
+	"github.com/cloudprober/cloudprober/probes/options"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// testServer implements a simple login -> API -> logout flow.
+func testServer(t *testing.T, logouts *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPost || string(b) != "user=u1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "s1"})
+		w.Header().Set("X-Request-Id", "r1")
+		fmt.Fprint(w, `{"token": "t123", "user": {"id": 42}}`)
+	})
+	mux.HandleFunc("/api/", func(w http.ResponseWriter, r *http.Request) {
+		c, err := r.Cookie("session")
+		if err != nil || c.Value != "s1" || r.Header.Get("Authorization") != "Bearer t123" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/api/42" || r.Header.Get("X-Request-Id") != "r1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, "order_id=o-77;")
+	})
+	mux.HandleFunc("/logout", func(w http.ResponseWriter, r *http.Request) {
+		logouts.Add(1)
+	})
+	return httptest.NewServer(mux)
+}
+
+func testSteps(apiPath string) []*configpb.Step {
+	return []*configpb.Step{
+		{
+			Name:   proto.String("login"),
+			Method: httppb.ProbeConf_POST.Enum(),
+			Url:    proto.String("/login"),
+			Body:   []string{"user=u1"},
+			Extract: []*configpb.Extract{
+				{Name: proto.String("token"), Source: &configpb.Extract_JqFilter{JqFilter: ".token"}},
+				{Name: proto.String("user_id"), Source: &configpb.Extract_JqFilter{JqFilter: ".user.id"}},
+				{Name: proto.String("req_id"), Source: &configpb.Extract_Header{Header: "X-Request-Id"}},
+			},
+		},
+		{
+			Name: proto.String("api"),
+			Url:  proto.String(apiPath),
+			Header: map[string]string{
+				"Authorization": "Bearer @token@",
+				"X-Request-Id":  "@req_id@",
+			},
+			Extract: []*configpb.Extract{
+				{Name: proto.String("order"), Source: &configpb.Extract_Regex{Regex: "order_id=([^;]+)"}},
+			},
+			Validator: []*validatorspb.Validator{
+				{
+					Name: "status",
+					Type: &validatorspb.Validator_HttpValidator{
+						HttpValidator: &httpvalpb.Validator{SuccessStatusCodes: proto.String("200")},
+					},
+				},
+			},
+		},
+		{
+			Name:      proto.String("logout"),
+			Url:       proto.String("/logout"),
+			AlwaysRun: proto.Bool(true),
+		},
+	}
+}
+
+func testProbe(t *testing.T, conf *configpb.ProbeConf) *Probe {
+	t.Helper()
+
+	opts := options.DefaultOptions()
+	opts.ProbeConf = conf
+	opts.Timeout = 2 * time.Second
+	opts.LatencyUnit = time.Millisecond
+
+	p := &Probe{}
+	require.NoError(t, p.Init("test_flow", opts))
+	return p
+}
+
+func serverTarget(t *testing.T, ts *httptest.Server) endpoint.Endpoint {
+	t.Helper()
+
+	host, port, err := net.SplitHostPort(ts.Listener.Addr().String())
+	require.NoError(t, err)
+	portNum, _ := strconv.Atoi(port)
+	return endpoint.Endpoint{Name: host, Port: portNum}
+}
+
+func metricInt(em *metrics.EventMetrics, name string) int64 {
+	return em.Metric(name).(metrics.NumValue).Int64()
+}
+
+func TestRunProbe(t *testing.T) {
+	var logouts atomic.Int32
+	ts := testServer(t, &logouts)
+	defer ts.Close()
+
+	tests := []struct {
+		name         string
+		apiPath      string
+		wantSuccess  bool
+		wantStepSucc []int64
+		wantStepTot  []int64
+		wantFailures int64
+	}{
+		{
+			name:         "success",
+			apiPath:      "/api/@user_id@",
+			wantSuccess:  true,
+			wantStepTot:  []int64{1, 1, 1},
+			wantStepSucc: []int64{1, 1, 1},
+		},
+		{
+			name:         "api_validation_failure",
+			apiPath:      "/api/0",
+			wantStepTot:  []int64{1, 1, 1},
+			wantStepSucc: []int64{1, 0, 1},
+			wantFailures: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logouts.Store(0)
+			p := testProbe(t, &configpb.ProbeConf{Step: testSteps(tt.apiPath)})
+
+			runReq := &sched.RunProbeForTargetRequest{
+				Target:  serverTarget(t, ts),
+				LastRun: &sched.LastRunResult{},
+			}
+			p.runProbe(context.Background(), runReq)
+
+			assert.Equal(t, tt.wantSuccess, runReq.LastRun.Success, "last run: %v", runReq.LastRun.Error)
+			assert.Equal(t, int32(1), logouts.Load(), "logout (always_run) step should run")
+
+			ems := runReq.Result.Metrics(time.Now(), 0, p.opts)
+			require.Len(t, ems, 4)
+
+			wantSuccess := int64(0)
+			if tt.wantSuccess {
+				wantSuccess = 1
+			}
+			assert.Equal(t, int64(1), metricInt(ems[0], "total"))
+			assert.Equal(t, wantSuccess, metricInt(ems[0], "success"))
+			assert.Equal(t, "", ems[0].Label("step"))
+
+			for i, em := range ems[1:] {
+				assert.Equal(t, p.steps[i].c.GetName(), em.Label("step"))
+				assert.False(t, em.IsForAlerting(), "step metrics should not be used for alerting")
+				assert.Equal(t, tt.wantStepTot[i], metricInt(em, "total"), "step %d total", i)
+				assert.Equal(t, tt.wantStepSucc[i], metricInt(em, "success"), "step %d success", i)
+			}
+
+			vf := ems[2].Metric("validation_failure").(*metrics.Map[int64])
+			assert.Equal(t, tt.wantFailures, vf.GetKey("status"))
+		})
+	}
+}
+
+func TestRunProbeStopOnFailure(t *testing.T) {
+	var logouts atomic.Int32
+	ts := testServer(t, &logouts)
+	defer ts.Close()
+
+	steps := testSteps("/api/@user_id@")
+	steps[0].Body = []string{"user=bad"} // Login fails.
+	steps[2].AlwaysRun = nil
+
+	p := testProbe(t, &configpb.ProbeConf{Step: steps})
+	runReq := &sched.RunProbeForTargetRequest{
+		Target:  serverTarget(t, ts),
+		LastRun: &sched.LastRunResult{},
+	}
+	p.runProbe(context.Background(), runReq)
+
+	assert.False(t, runReq.LastRun.Success)
+	assert.ErrorContains(t, runReq.LastRun.Error, "step login")
+	assert.Equal(t, int32(0), logouts.Load())
+
+	result := runReq.Result.(*probeResult)
+	assert.Equal(t, []int64{1, 0, 0}, []int64{result.steps[0].total, result.steps[1].total, result.steps[2].total})
+	assert.Equal(t, int64(1), result.steps[0].respCodes.GetKey("401"))
+}
+
+func TestRunProbeCookies(t *testing.T) {
+	var logouts atomic.Int32
+	ts := testServer(t, &logouts)
+	defer ts.Close()
+
+	p := testProbe(t, &configpb.ProbeConf{
+		Step:          testSteps("/api/@user_id@"),
+		EnableCookies: proto.Bool(false),
+	})
+	runReq := &sched.RunProbeForTargetRequest{
+		Target:  serverTarget(t, ts),
+		LastRun: &sched.LastRunResult{},
+	}
+	p.runProbe(context.Background(), runReq)
+
+	// Without cookies, API call fails.
+	assert.False(t, runReq.LastRun.Success)
+	assert.Equal(t, int64(1), runReq.Result.(*probeResult).steps[1].respCodes.GetKey("403"))
+}
+
+func TestStepRequest(t *testing.T) {
+	p := testProbe(t, &configpb.ProbeConf{
+		Step: []*configpb.Step{
+			{
+				Name:   proto.String("s1"),
+				Url:    proto.String("/path/@target.label.env@?v=@var1@"),
+				Header: map[string]string{"Host": "@target@.example.com", "X-Var": "@var1@"},
+			},
+			{
+				Name: proto.String("s2"),
+				Url:  proto.String("https://auth.example.com/token"),
+			},
+		},
+		Scheme:    httppb.ProbeConf_HTTPS.Enum(),
+		Port:      proto.Int32(8443),
+		Header:    map[string]string{"X-Var": "global", "X-Probe": "@probe@"},
+		UserAgent: proto.String("cloudprober-flow"),
+	})
+
+	target := endpoint.Endpoint{Name: "host1", Port: 80, Labels: map[string]string{"env": "prod"}}
+	vars := p.baseVars(target)
+	vars["var1"] = "v1"
+
+	req, err := p.stepRequest(context.Background(), p.steps[0], target, vars)
+	require.NoError(t, err)
+	assert.Equal(t, "https://host1:8443/path/prod?v=v1", req.URL.String())
+	assert.Equal(t, "host1.example.com", req.Host)
+	assert.Equal(t, "v1", req.Header.Get("X-Var"))
+	assert.Equal(t, "test_flow", req.Header.Get("X-Probe"))
+	assert.Equal(t, "cloudprober-flow", req.Header.Get("User-Agent"))
+
+	req, err = p.stepRequest(context.Background(), p.steps[1], target, vars)
+	require.NoError(t, err)
+	assert.Equal(t, "https://auth.example.com/token", req.URL.String())
+}
+
+func TestInitErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		conf *configpb.ProbeConf
+	}{
+		{
+			name: "no_steps",
+			conf: &configpb.ProbeConf{},
+		},
+		{
+			name: "duplicate_step",
+			conf: &configpb.ProbeConf{
+				Step: []*configpb.Step{{Name: proto.String("s1")}, {Name: proto.String("s1")}},
+			},
+		},
+		{
+			name: "bad_jq",
+			conf: &configpb.ProbeConf{
+				Step: []*configpb.Step{{
+					Name:    proto.String("s1"),
+					Extract: []*configpb.Extract{{Name: proto.String("v"), Source: &configpb.Extract_JqFilter{JqFilter: ".["}}},
+				}},
+			},
+		},
+		{
+			name: "no_source",
+			conf: &configpb.ProbeConf{
+				Step: []*configpb.Step{{
+					Name:    proto.String("s1"),
+					Extract: []*configpb.Extract{{Name: proto.String("v")}},
+				}},
+			},
+		},
+		{
+			name: "reserved_var_name",
+			conf: &configpb.ProbeConf{
+				Step: []*configpb.Step{{
+					Name:    proto.String("s1"),
+					Extract: []*configpb.Extract{{Name: proto.String("target.name"), Source: &configpb.Extract_Header{Header: "X"}}},
+				}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := options.DefaultOptions()
+			opts.ProbeConf = tt.conf
+			assert.Error(t, (&Probe{}).Init("test_flow", opts))
+		})
+	}
+
+	// Probe level validators are not supported.
+	opts := options.DefaultOptions()
+	opts.ProbeConf = &configpb.ProbeConf{Step: []*configpb.Step{{Name: proto.String("s1")}}}
+	opts.Validators = []*validators.Validator{{Name: "v"}}
+	assert.Error(t, (&Probe{}).Init("test_flow", opts))
+}
@@ -0,0 +1,517 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.5
+// source: github.com/cloudprober/cloudprober/probes/httpflow/proto/config.proto
+
+package proto
+
+import (
+	proto2 "github.com/cloudprober/cloudprober/common/tlsconfig/proto"
+	proto1 "github.com/cloudprober/cloudprober/internal/validators/proto"
+	proto "github.com/cloudprober/cloudprober/probes/http/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Extract extracts a variable from a step's response. Extracted variables can
+// be used in the later steps' URL, headers and body as @name@.
+type Extract struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Variable name.
+	Name *string `protobuf:"bytes,1,req,name=name" json:"name,omitempty"`
+	// Types that are valid to be assigned to Source:
+	//
+	//	*Extract_JqFilter
+	//	*Extract_Regex
+	//	*Extract_Header
+	Source        isExtract_Source `protobuf_oneof:"source"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Extract) Reset() {
+	*x = Extract{}
+	mi := &file_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Extract) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Extract) ProtoMessage() {}
+
+func (x *Extract) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Extract.ProtoReflect.Descriptor instead.
+func (*Extract) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Extract) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *Extract) GetSource() isExtract_Source {
+	if x != nil {
+		return x.Source
+	}
+	return nil
+}
+
+func (x *Extract) GetJqFilter() string {
+	if x != nil {
+		if x, ok := x.Source.(*Extract_JqFilter); ok {
+			return x.JqFilter
+		}
+	}
+	return ""
+}
+
+func (x *Extract) GetRegex() string {
+	if x != nil {
+		if x, ok := x.Source.(*Extract_Regex); ok {
+			return x.Regex
+		}
+	}
+	return ""
+}
+
+func (x *Extract) GetHeader() string {
+	if x != nil {
+		if x, ok := x.Source.(*Extract_Header); ok {
+			return x.Header
+		}
+	}
+	return ""
+}
+
+type isExtract_Source interface {
+	isExtract_Source()
+}
+
+type Extract_JqFilter struct {
+	// jq filter to run on the JSON response body. If filter returns a string,
+	// it's used as it is, otherwise its JSON representation is used.
+	// Example: ".access_token"
+	JqFilter string `protobuf:"bytes,2,opt,name=jq_filter,json=jqFilter,oneof"`
+}
+
+type Extract_Regex struct {
+	// Regex to run on the response body. If regex has a capturing group,
+	// first group's match is used, otherwise the whole match is used.
+	// Example: "csrf_token=([a-z0-9]+)"
+	Regex string `protobuf:"bytes,3,opt,name=regex,oneof"`
+}
+
+type Extract_Header struct {
+	// Response header name.
+	Header string `protobuf:"bytes,4,opt,name=header,oneof"`
+}
+
+func (*Extract_JqFilter) isExtract_Source() {}
+
+func (*Extract_Regex) isExtract_Source() {}
+
+func (*Extract_Header) isExtract_Source() {}
+
+type Step struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Step name. It's used as the "step" label in the per-step metrics.
+	Name *string `protobuf:"bytes,1,req,name=name" json:"name,omitempty"`
+	// HTTP request method.
+	Method *proto.ProbeConf_Method `protobuf:"varint,2,opt,name=method,enum=cloudprober.probes.http.ProbeConf_Method,def=0" json:"method,omitempty"`
+	// URL for the request. If it starts with a '/', it's used as the relative
+	// URL and the final URL is constructed like this:
+	//
+	//	<scheme>://<target>:<port>/<url>
+	//
+	// Otherwise, it's used as an absolute URL.
+	//
+	// URL, header values, and body can use the following placeholders:
+	//
+	//	@target@, @target.port@, @target.label.<label>@, @probe@, and
+	//	@<variable>@ for the variables extracted in the previous steps.
+	Url *string `protobuf:"bytes,3,opt,name=url" json:"url,omitempty"`
+	// HTTP request headers. These are merged with the probe level headers.
+	Header map[string]string `protobuf:"bytes,4,rep,name=header" json:"header,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Request body. Similar to the HTTP probe's body field, multiple body
+	// fields are joined with '&' and content-type is guessed from the data.
+	Body []string `protobuf:"bytes,5,rep,name=body" json:"body,omitempty"`
+	// Variables to extract from the response.
+	Extract []*Extract `protobuf:"bytes,6,rep,name=extract" json:"extract,omitempty"`
+	// Validators for the step's response. If a validator fails, step and the
+	// flow fail.
+	Validator []*proto1.Validator `protobuf:"bytes,7,rep,name=validator" json:"validator,omitempty"`
+	// Run this step even if one of the previous steps failed. This is useful
+	// for cleanup steps, e.g. logout.
+	AlwaysRun     *bool `protobuf:"varint,8,opt,name=always_run,json=alwaysRun" json:"always_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+// Default values for Step fields.
+const (
+	Default_Step_Method = proto.ProbeConf_Method(0) // proto.ProbeConf_GET
+)
+
+func (x *Step) Reset() {
+	*x = Step{}
+	mi := &file_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Step) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Step) ProtoMessage() {}
+
+func (x *Step) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Step.ProtoReflect.Descriptor instead.
+func (*Step) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Step) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *Step) GetMethod() proto.ProbeConf_Method {
+	if x != nil && x.Method != nil {
+		return *x.Method
+	}
+	return Default_Step_Method
+}
+
+func (x *Step) GetUrl() string {
+	if x != nil && x.Url != nil {
+		return *x.Url
+	}
+	return ""
+}
+
+func (x *Step) GetHeader() map[string]string {
+	if x != nil {
+		return x.Header
+	}
+	return nil
+}
+
+func (x *Step) GetBody() []string {
+	if x != nil {
+		return x.Body
+	}
+	return nil
+}
+
+func (x *Step) GetExtract() []*Extract {
+	if x != nil {
+		return x.Extract
+	}
+	return nil
+}
+
+func (x *Step) GetValidator() []*proto1.Validator {
+	if x != nil {
+		return x.Validator
+	}
+	return nil
+}
+
+func (x *Step) GetAlwaysRun() bool {
+	if x != nil && x.AlwaysRun != nil {
+		return *x.AlwaysRun
+	}
+	return false
+}
+
+// HTTP flow probe runs an ordered sequence of HTTP requests (steps) for each
+// target. A flow succeeds only if all its steps succeed. Probe's timeout
+// applies to the whole flow.
+type ProbeConf struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Steps to run, in order.
+	Step []*Step `protobuf:"bytes,1,rep,name=step" json:"step,omitempty"`
+	// URL scheme for the relative URLs.
+	Scheme *proto.ProbeConf_Scheme `protobuf:"varint,2,opt,name=scheme,enum=cloudprober.probes.http.ProbeConf_Scheme,def=0" json:"scheme,omitempty"`
+	// Port for the relative URLs. Default is to use the target's port if
+	// available, or scheme's default port.
+	Port *int32 `protobuf:"varint,3,opt,name=port" json:"port,omitempty"`
+	// HTTP request headers, used for all steps.
+	Header map[string]string `protobuf:"bytes,4,rep,name=header" json:"header,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Keep cookies across steps, e.g. for session cookies set by a login step.
+	// Cookies are not shared across flow runs.
+	EnableCookies *bool `protobuf:"varint,5,opt,name=enable_cookies,json=enableCookies,def=1" json:"enable_cookies,omitempty"`
+	// Enable HTTP keep-alive across steps and flow runs.
+	KeepAlive *bool `protobuf:"varint,6,opt,name=keep_alive,json=keepAlive" json:"keep_alive,omitempty"`
+	// TLS config.
+	TlsConfig *proto2.TLSConfig `protobuf:"bytes,7,opt,name=tls_config,json=tlsConfig" json:"tls_config,omitempty"`
+	// The maximum number of redirects to follow. To disable redirects, use
+	// max_redirects: 0.
+	MaxRedirects *int32 `protobuf:"varint,8,opt,name=max_redirects,json=maxRedirects" json:"max_redirects,omitempty"`
+	// User agent. Default user agent is Go's default user agent.
+	UserAgent *string `protobuf:"bytes,9,opt,name=user_agent,json=userAgent" json:"user_agent,omitempty"`
+	// Interval between targets.
+	IntervalBetweenTargetsMsec *int32 `protobuf:"varint,97,opt,name=interval_between_targets_msec,json=intervalBetweenTargetsMsec,def=10" json:"interval_between_targets_msec,omitempty"`
+	unknownFields              protoimpl.UnknownFields
+	sizeCache                  protoimpl.SizeCache
+}
+
+// Default values for ProbeConf fields.
+const (
+	Default_ProbeConf_Scheme                     = proto.ProbeConf_Scheme(0) // proto.ProbeConf_HTTP
+	Default_ProbeConf_EnableCookies              = bool(true)
+	Default_ProbeConf_IntervalBetweenTargetsMsec = int32(10)
+)
+
+func (x *ProbeConf) Reset() {
+	*x = ProbeConf{}
+	mi := &file_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProbeConf) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProbeConf) ProtoMessage() {}
+
+func (x *ProbeConf) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProbeConf.ProtoReflect.Descriptor instead.
+func (*ProbeConf) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ProbeConf) GetStep() []*Step {
+	if x != nil {
+		return x.Step
+	}
+	return nil
+}
+
+func (x *ProbeConf) GetScheme() proto.ProbeConf_Scheme {
+	if x != nil && x.Scheme != nil {
+		return *x.Scheme
+	}
+	return Default_ProbeConf_Scheme
+}
+
+func (x *ProbeConf) GetPort() int32 {
+	if x != nil && x.Port != nil {
+		return *x.Port
+	}
+	return 0
+}
+
+func (x *ProbeConf) GetHeader() map[string]string {
+	if x != nil {
+		return x.Header
+	}
+	return nil
+}
+
+func (x *ProbeConf) GetEnableCookies() bool {
+	if x != nil && x.EnableCookies != nil {
+		return *x.EnableCookies
+	}
+	return Default_ProbeConf_EnableCookies
+}
+
+func (x *ProbeConf) GetKeepAlive() bool {
+	if x != nil && x.KeepAlive != nil {
+		return *x.KeepAlive
+	}
+	return false
+}
+
+func (x *ProbeConf) GetTlsConfig() *proto2.TLSConfig {
+	if x != nil {
+		return x.TlsConfig
+	}
+	return nil
+}
+
+func (x *ProbeConf) GetMaxRedirects() int32 {
+	if x != nil && x.MaxRedirects != nil {
+		return *x.MaxRedirects
+	}
+	return 0
+}
+
+func (x *ProbeConf) GetUserAgent() string {
+	if x != nil && x.UserAgent != nil {
+		return *x.UserAgent
+	}
+	return ""
+}
+
+func (x *ProbeConf) GetIntervalBetweenTargetsMsec() int32 {
+	if x != nil && x.IntervalBetweenTargetsMsec != nil {
+		return *x.IntervalBetweenTargetsMsec
+	}
+	return Default_ProbeConf_IntervalBetweenTargetsMsec
+}
+
+var File_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto protoreflect.FileDescriptor
+
+const file_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto_rawDesc = "" +
+	"\n" +
+	"Egithub.com/cloudprober/cloudprober/probes/httpflow/proto/config.proto\x12\x1bcloudprober.probes.httpflow\x1aFgithub.com/cloudprober/cloudprober/common/tlsconfig/proto/config.proto\x1aIgithub.com/cloudprober/cloudprober/internal/validators/proto/config.proto\x1aAgithub.com/cloudprober/cloudprober/probes/http/proto/config.proto\"x\n" +
+	"\aExtract\x12\x12\n" +
+	"\x04name\x18\x01 \x02(\tR\x04name\x12\x1d\n" +
+	"\tjq_filter\x18\x02 \x01(\tH\x00R\bjqFilter\x12\x16\n" +
+	"\x05regex\x18\x03 \x01(\tH\x00R\x05regex\x12\x18\n" +
+	"\x06header\x18\x04 \x01(\tH\x00R\x06headerB\b\n" +
+	"\x06source\"\xaa\x03\n" +
+	"\x04Step\x12\x12\n" +
+	"\x04name\x18\x01 \x02(\tR\x04name\x12F\n" +
+	"\x06method\x18\x02 \x01(\x0e2).cloudprober.probes.http.ProbeConf.Method:\x03GETR\x06method\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\x12E\n" +
+	"\x06header\x18\x04 \x03(\v2-.cloudprober.probes.httpflow.Step.HeaderEntryR\x06header\x12\x12\n" +
+	"\x04body\x18\x05 \x03(\tR\x04body\x12>\n" +
+	"\aextract\x18\x06 \x03(\v2$.cloudprober.probes.httpflow.ExtractR\aextract\x12?\n" +
+	"\tvalidator\x18\a \x03(\v2!.cloudprober.validators.ValidatorR\tvalidator\x12\x1d\n" +
+	"\n" +
+	"always_run\x18\b \x01(\bR\talwaysRun\x1a9\n" +
+	"\vHeaderEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xbe\x04\n" +
+	"\tProbeConf\x125\n" +
+	"\x04step\x18\x01 \x03(\v2!.cloudprober.probes.httpflow.StepR\x04step\x12G\n" +
+	"\x06scheme\x18\x02 \x01(\x0e2).cloudprober.probes.http.ProbeConf.Scheme:\x04HTTPR\x06scheme\x12\x12\n" +
+	"\x04port\x18\x03 \x01(\x05R\x04port\x12J\n" +
+	"\x06header\x18\x04 \x03(\v22.cloudprober.probes.httpflow.ProbeConf.HeaderEntryR\x06header\x12+\n" +
+	"\x0eenable_cookies\x18\x05 \x01(\b:\x04trueR\renableCookies\x12\x1d\n" +
+	"\n" +
+	"keep_alive\x18\x06 \x01(\bR\tkeepAlive\x12?\n" +
+	"\n" +
+	"tls_config\x18\a \x01(\v2 .cloudprober.tlsconfig.TLSConfigR\ttlsConfig\x12#\n" +
+	"\rmax_redirects\x18\b \x01(\x05R\fmaxRedirects\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\t \x01(\tR\tuserAgent\x12E\n" +
+	"\x1dinterval_between_targets_msec\x18a \x01(\x05:\x0210R\x1aintervalBetweenTargetsMsec\x1a9\n" +
+	"\vHeaderEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B:Z8github.com/cloudprober/cloudprober/probes/httpflow/proto"
+
+var (
+	file_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto_rawDescOnce sync.Once
+	file_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto_rawDescData []byte
+)
+
+func file_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto_rawDescGZIP() []byte {
+	file_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto_rawDescOnce.Do(func() {
+		file_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto_rawDesc), len(file_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto_rawDesc)))
+	})
+	return file_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto_rawDescData
+}
+
+var file_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto_goTypes = []any{
+	(*Extract)(nil),             // 0: cloudprober.probes.httpflow.Extract
+	(*Step)(nil),                // 1: cloudprober.probes.httpflow.Step
+	(*ProbeConf)(nil),           // 2: cloudprober.probes.httpflow.ProbeConf
+	nil,                         // 3: cloudprober.probes.httpflow.Step.HeaderEntry
+	nil,                         // 4: cloudprober.probes.httpflow.ProbeConf.HeaderEntry
+	(proto.ProbeConf_Method)(0), // 5: cloudprober.probes.http.ProbeConf.Method
+	(*proto1.Validator)(nil),    // 6: cloudprober.validators.Validator
+	(proto.ProbeConf_Scheme)(0), // 7: cloudprober.probes.http.ProbeConf.Scheme
+	(*proto2.TLSConfig)(nil),    // 8: cloudprober.tlsconfig.TLSConfig
+}
+var file_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto_depIdxs = []int32{
+	5, // 0: cloudprober.probes.httpflow.Step.method:type_name -> cloudprober.probes.http.ProbeConf.Method
+	3, // 1: cloudprober.probes.httpflow.Step.header:type_name -> cloudprober.probes.httpflow.Step.HeaderEntry
+	0, // 2: cloudprober.probes.httpflow.Step.extract:type_name -> cloudprober.probes.httpflow.Extract
+	6, // 3: cloudprober.probes.httpflow.Step.validator:type_name -> cloudprober.validators.Validator
+	1, // 4: cloudprober.probes.httpflow.ProbeConf.step:type_name -> cloudprober.probes.httpflow.Step
+	7, // 5: cloudprober.probes.httpflow.ProbeConf.scheme:type_name -> cloudprober.probes.http.ProbeConf.Scheme
+	4, // 6: cloudprober.probes.httpflow.ProbeConf.header:type_name -> cloudprober.probes.httpflow.ProbeConf.HeaderEntry
+	8, // 7: cloudprober.probes.httpflow.ProbeConf.tls_config:type_name -> cloudprober.tlsconfig.TLSConfig
+	8, // [8:8] is the sub-list for method output_type
+	8, // [8:8] is the sub-list for method input_type
+	8, // [8:8] is the sub-list for extension type_name
+	8, // [8:8] is the sub-list for extension extendee
+	0, // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto_init() }
+func file_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto_init() {
+	if File_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto != nil {
+		return
+	}
+	file_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto_msgTypes[0].OneofWrappers = []any{
+		(*Extract_JqFilter)(nil),
+		(*Extract_Regex)(nil),
+		(*Extract_Header)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto_rawDesc), len(file_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto_goTypes,
+		DependencyIndexes: file_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto_depIdxs,
+		MessageInfos:      file_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto_msgTypes,
+	}.Build()
+	File_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto = out.File
+	file_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto_goTypes = nil
+	file_github_com_cloudprober_cloudprober_probes_httpflow_proto_config_proto_depIdxs = nil
+}
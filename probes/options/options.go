@@ -22,6 +22,8 @@ import (
 	"log/slog"
 	"net"
 	"slices"
+	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/cloudprober/cloudprober/common/iputils"
@@ -30,6 +32,7 @@ import (
 	"github.com/cloudprober/cloudprober/internal/validators"
 	"github.com/cloudprober/cloudprober/logger"
 	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/probes/common/dependency"
 	configpb "github.com/cloudprober/cloudprober/probes/proto"
 	"github.com/cloudprober/cloudprober/targets"
 	"github.com/cloudprober/cloudprober/targets/endpoint"
@@ -54,14 +57,22 @@ type Options struct {
 	Schedule            *Schedule
 	NegativeTest        bool
 	AlertHandlers       []*alerting.AlertHandler
+	// Dependencies is set if the probe depends on other probes (depends_on).
+	Dependencies *dependency.Dependencies
 	// TargetsUpdateInterval overrides the default scheduler target update
 	// interval (1 minute). Set from targets.re_eval_sec if configured.
 	TargetsUpdateInterval time.Duration
+	// DualStack is set if targets should be probed over both IPv4 and IPv6
+	// (dual_stack). If DualStackAllIPs is also set, every resolved IP is
+	// probed, instead of just one IP per address family.
+	DualStack, DualStackAllIPs bool
 	// Prober config at the prober initialization time. This config is not
 	// reliable for things that may change after initialization, e.g. probes
 	// that can be added or removed through gRPC.
 	ProberConfig       *proberconfigpb.ProberConfig
 	logMetricsOverride func(*metrics.EventMetrics)
+	paused             atomic.Bool
+	dependencyTracker  *dependency.Tracker
 }
 
 // StatsExportFrequency returns how often to export metrics (in probe counts),
@@ -90,6 +101,23 @@ var negativeTestSupported = map[configpb.ProbeDef_Type]bool{
 	configpb.ProbeDef_HTTP: true,
 }
 
+var dualStackSupported = map[configpb.ProbeDef_Type]bool{
+	configpb.ProbeDef_HTTP: true,
+	configpb.ProbeDef_TCP:  true,
+}
+
+// Probe types that support skipping runs when an upstream dependency is
+// failing. These are the probe types that use the common scheduler.
+var dependencySkipSupported = map[configpb.ProbeDef_Type]bool{
+	configpb.ProbeDef_HTTP:      true,
+	configpb.ProbeDef_TCP:       true,
+	configpb.ProbeDef_DNS:       true,
+	configpb.ProbeDef_GRPC:      true,
+	configpb.ProbeDef_BROWSER:   true,
+	configpb.ProbeDef_HTTP_FLOW: true,
+	configpb.ProbeDef_WEBSOCKET: true,
+}
+
 func defaultStatsExportInterval(p *configpb.ProbeDef, opts *Options) time.Duration {
 	minIntv := opts.Interval
 	if opts.Timeout > opts.Interval {
@@ -253,6 +281,16 @@ func BuildProbeOptions(p *configpb.ProbeDef, ldLister endpoint.Lister, proberCon
 		}
 	}
 
+	if p.DualStack != nil {
+		if !dualStackSupported[p.GetType()] {
+			return nil, fmt.Errorf("dual_stack is not supported by %s probes", p.GetType().String())
+		}
+		if opts.IPVersion != 0 {
+			return nil, fmt.Errorf("dual_stack cannot be used along with ip_version or source_ip")
+		}
+		opts.DualStack, opts.DualStackAllIPs = true, p.GetDualStack().GetAllIps()
+	}
+
 	if p.StatsExportIntervalMsec == nil {
 		opts.StatsExportInterval = defaultStatsExportInterval(p, opts)
 	} else {
@@ -264,11 +302,24 @@ func BuildProbeOptions(p *configpb.ProbeDef, ldLister endpoint.Lister, proberCon
 
 	opts.AdditionalLabels = parseAdditionalLabels(p)
 
+	if len(p.GetDependsOn()) > 0 {
+		opts.Dependencies, err = dependency.New(p.GetDependsOn(), p.GetName())
+		if err != nil {
+			return nil, fmt.Errorf("invalid depends_on config: %v", err)
+		}
+		if opts.Dependencies.HasSkipAction() && !dependencySkipSupported[p.GetType()] {
+			return nil, fmt.Errorf("dependency action SKIP is not supported by %s probes, use LABEL instead", p.GetType())
+		}
+	}
+
 	for _, alertConf := range p.GetAlert() {
 		ah, err := alerting.NewAlertHandler(alertConf, p.GetName(), opts.Logger)
 		if err != nil {
 			return nil, fmt.Errorf("error creating alert handler for the probe (%s): %v", p.GetName(), err)
 		}
+		if opts.Dependencies != nil {
+			ah.SetSuppressFunc(opts.upstreamFailed)
+		}
 		opts.AlertHandlers = append(opts.AlertHandlers, ah)
 	}
 
@@ -307,8 +358,46 @@ func DefaultOptions() *Options {
 	return opts
 }
 
+// IsScheduled returns true if the probe should run at this time, i.e. it's
+// not paused and current time is within the probe's schedule.
 func (opts *Options) IsScheduled() bool {
-	return opts.Schedule.isIn(time.Now())
+	return !opts.paused.Load() && opts.Schedule.isIn(time.Now())
+}
+
+// SetPaused pauses or resumes the probe. Paused probes keep running, but
+// IsScheduled returns false for them, so no new probe runs are made.
+func (opts *Options) SetPaused(paused bool) {
+	opts.paused.Store(paused)
+}
+
+// IsPaused returns true if the probe has been paused.
+func (opts *Options) IsPaused() bool {
+	return opts.paused.Load()
+}
+
+// SetDependencyTracker sets the tracker that keeps track of the state of all
+// the probes. Probe's results are recorded in the tracker, and if the probe
+// has dependencies, the tracker is used to look up its parents' state.
+func (opts *Options) SetDependencyTracker(t *dependency.Tracker) {
+	opts.dependencyTracker = t
+	if opts.Dependencies != nil {
+		opts.Dependencies.SetTracker(t)
+	}
+}
+
+func (opts *Options) upstreamFailed(ep endpoint.Endpoint) bool {
+	parent, _ := opts.Dependencies.Check(ep)
+	return parent != ""
+}
+
+// SkipForUpstreamFailure returns true if probe runs for the target should be
+// skipped because one of the probe's upstream dependencies is failing.
+func (opts *Options) SkipForUpstreamFailure(ep endpoint.Endpoint) bool {
+	parent, skip := opts.Dependencies.Check(ep)
+	if skip {
+		opts.Logger.Debugf("Skipping target %s, upstream probe %s is failing", ep.Name, parent)
+	}
+	return skip
 }
 
 // RecordMetrics updates EventMetrics with additional labels and pushes it to
@@ -318,6 +407,9 @@ func (opts *Options) IsScheduled() bool {
 // caller to not modify it after calling this function.
 func (opts *Options) RecordMetrics(ep endpoint.Endpoint, em *metrics.EventMetrics, dataChan chan<- *metrics.EventMetrics) {
 	em.LatencyUnit = opts.LatencyUnit
+	if opts.Dependencies.HasLabelAction() {
+		em.AddLabel(dependency.UpstreamFailedLabel, strconv.FormatBool(opts.upstreamFailed(ep)))
+	}
 	for _, al := range opts.AdditionalLabels {
 		em.AddLabel(al.KeyValueForTarget(ep))
 	}
@@ -326,6 +418,7 @@ func (opts *Options) RecordMetrics(ep endpoint.Endpoint, em *metrics.EventMetric
 	dataChan <- em
 
 	if em.IsForAlerting() {
+		opts.dependencyTracker.Record(opts.Name, ep.Name, em, 2*opts.StatsExportInterval)
 		for _, ah := range opts.AlertHandlers {
 			ah.Record(ep, em)
 		}
@@ -26,6 +26,7 @@ import (
 	alerting_configpb "github.com/cloudprober/cloudprober/internal/alerting/proto"
 	"github.com/cloudprober/cloudprober/logger"
 	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/probes/common/dependency"
 	configpb "github.com/cloudprober/cloudprober/probes/proto"
 	"github.com/cloudprober/cloudprober/targets/endpoint"
 	targetspb "github.com/cloudprober/cloudprober/targets/proto"
@@ -365,6 +366,45 @@ func TestNegativeTestSupport(t *testing.T) {
 	}
 }
 
+func TestDualStack(t *testing.T) {
+	tests := []struct {
+		name       string
+		ptype      configpb.ProbeDef_Type
+		ipVersion  *configpb.ProbeDef_IPVersion
+		allIPs     bool
+		wantErr    bool
+		wantAllIPs bool
+	}{
+		{name: "http", ptype: configpb.ProbeDef_HTTP},
+		{name: "tcp_all_ips", ptype: configpb.ProbeDef_TCP, allIPs: true, wantAllIPs: true},
+		{name: "dns", ptype: configpb.ProbeDef_DNS, wantErr: true},
+		{name: "with_ip_version", ptype: configpb.ProbeDef_HTTP, ipVersion: configpb.ProbeDef_IPV6.Enum(), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := BuildProbeOptions(&configpb.ProbeDef{
+				Type:      tt.ptype.Enum(),
+				Targets:   testTargets,
+				IpVersion: tt.ipVersion,
+				DualStack: &configpb.DualStack{AllIps: proto.Bool(tt.allIPs)},
+			}, nil, nil, nil)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error for probe type: %v, ip_version: %v", tt.ptype, tt.ipVersion)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !opts.DualStack || opts.DualStackAllIPs != tt.wantAllIPs {
+				t.Errorf("DualStack=%v, DualStackAllIPs=%v, want: true, %v", opts.DualStack, opts.DualStackAllIPs, tt.wantAllIPs)
+			}
+		})
+	}
+}
+
 func TestRecordMetrics(t *testing.T) {
 	ep := endpoint.Endpoint{Name: "test_target"}
 	opts := DefaultOptions()
@@ -571,3 +611,83 @@ func TestOptions_StatsExportFrequency(t *testing.T) {
 		})
 	}
 }
+
+func TestOptionsPaused(t *testing.T) {
+	opts := DefaultOptions()
+	assert.False(t, opts.IsPaused())
+	assert.True(t, opts.IsScheduled())
+
+	opts.SetPaused(true)
+	assert.True(t, opts.IsPaused())
+	assert.False(t, opts.IsScheduled())
+
+	opts.SetPaused(false)
+	assert.False(t, opts.IsPaused())
+	assert.True(t, opts.IsScheduled())
+}
+
+func TestDependencies(t *testing.T) {
+	probeConf := func(ptype configpb.ProbeDef_Type, action configpb.Dependency_Action) *configpb.ProbeDef {
+		return &configpb.ProbeDef{
+			Name:    proto.String("child"),
+			Type:    ptype.Enum(),
+			Targets: testTargets,
+			DependsOn: []*configpb.Dependency{
+				{
+					Probe:  proto.String("parent"),
+					Action: action.Enum(),
+				},
+			},
+			Alert: []*alerting_configpb.AlertConf{{}},
+		}
+	}
+
+	_, err := BuildProbeOptions(probeConf(configpb.ProbeDef_PING, configpb.Dependency_SKIP), nil, nil, nil)
+	assert.Error(t, err, "SKIP action should not be supported by PING probes")
+
+	_, err = BuildProbeOptions(probeConf(configpb.ProbeDef_HTTP, configpb.Dependency_SKIP), nil, nil, nil)
+	assert.NoError(t, err)
+
+	opts, err := BuildProbeOptions(probeConf(configpb.ProbeDef_PING, configpb.Dependency_LABEL), nil, nil, nil)
+	assert.NoError(t, err)
+
+	tracker := dependency.NewTracker()
+	opts.SetDependencyTracker(tracker)
+
+	// Replace alert handler with one that logs to a buffer.
+	var buf bytes.Buffer
+	l := logger.New(logger.WithWriter(&buf))
+	alertHandler, _ := alerting.NewAlertHandler(&alerting_configpb.AlertConf{}, "child", l)
+	alertHandler.SetSuppressFunc(opts.upstreamFailed)
+	opts.AlertHandlers = []*alerting.AlertHandler{alertHandler}
+
+	ep := endpoint.Endpoint{Name: "host1"}
+	dataChan := make(chan *metrics.EventMetrics, 10)
+	record := func(opts *Options, total, success int64) *metrics.EventMetrics {
+		em := metrics.NewEventMetrics(time.Now()).
+			AddMetric("total", metrics.NewInt(total)).
+			AddMetric("success", metrics.NewInt(success))
+		opts.RecordMetrics(ep, em, dataChan)
+		return <-dataChan
+	}
+
+	parentOpts := DefaultOptions()
+	parentOpts.Name = "parent"
+	parentOpts.SetDependencyTracker(tracker)
+
+	record(parentOpts, 1, 1)
+	em := record(opts, 1, 1)
+	assert.Equal(t, "false", em.Label("upstream_failed"))
+
+	record(parentOpts, 2, 1)
+	em = record(opts, 2, 1)
+	assert.Equal(t, "true", em.Label("upstream_failed"))
+	assert.True(t, tracker.Failing("child", "host1"))
+	assert.Contains(t, buf.String(), "ALERT suppressed (child)")
+	assert.NotContains(t, buf.String(), "ALERT (child)")
+
+	// Parent recovers, child alert fires.
+	record(parentOpts, 3, 2)
+	record(opts, 3, 1)
+	assert.Contains(t, buf.String(), "ALERT (child)")
+}
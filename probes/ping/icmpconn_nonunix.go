@@ -46,9 +46,9 @@ func (p *Probe) newICMPConn(sourceIP net.IP) (icmpConn, error) {
 	return &icmpPacketConn{c}, nil
 }
 
-func (ipc *icmpPacketConn) read(buf []byte) (int, net.Addr, time.Time, error) {
+func (ipc *icmpPacketConn) read(buf []byte) (int, net.Addr, time.Time, int, error) {
 	n, addr, err := ipc.c.ReadFrom(buf)
-	return n, addr, time.Now(), err
+	return n, addr, time.Now(), 0, err
 }
 
 func (ipc *icmpPacketConn) write(buf []byte, peer net.Addr) (int, error) {
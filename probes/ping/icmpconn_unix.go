@@ -26,6 +26,9 @@ import (
 	"syscall"
 	"time"
 	"unsafe"
+
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
 )
 
 // NativeEndian is the machine native endian implementation of ByteOrder.
@@ -127,10 +130,25 @@ func (p *Probe) listenPacket(sourceIP net.IP) (*icmpPacketConn, error) {
 		return nil, cerr
 	}
 
-	ipc := &icmpPacketConn{c: c}
+	ipc := &icmpPacketConn{c: c, ipVer: p.ipVer}
 	ipc.ipConn, _ = c.(*net.IPConn)
 	ipc.udpConn, _ = c.(*net.UDPConn)
 
+	// Ask the kernel to pass TTL (hop limit for IPv6) of the received packets.
+	if p.collectTTL {
+		var err error
+		if p.ipVer == 6 {
+			err = ipv6.NewPacketConn(c).SetControlMessage(ipv6.FlagHopLimit, true)
+		} else {
+			err = ipv4.NewPacketConn(c).SetControlMessage(ipv4.FlagTTL, true)
+		}
+		if err != nil {
+			p.l.Warningf("Error enabling TTL reception, TTL will not be reported: %v", err)
+		} else {
+			ipc.recvTTL = true
+		}
+	}
+
 	return ipc, nil
 }
 
@@ -140,6 +158,9 @@ type icmpPacketConn struct {
 	// We use ipConn and udpConn for reading OOB data from the connection.
 	ipConn  *net.IPConn
 	udpConn *net.UDPConn
+
+	ipVer   int
+	recvTTL bool // Whether TTL control messages are enabled.
 }
 
 func timestampFromControlMessage(oob []byte) (time.Time, error) {
@@ -180,11 +201,29 @@ func timestampFromControlMessage(oob []byte) (time.Time, error) {
 	return time.Time{}, nil
 }
 
-func (ipc *icmpPacketConn) read(buf []byte) (n int, addr net.Addr, recvTime time.Time, err error) {
+// ttlFromControlMessage returns TTL (hop limit for IPv6) from the control
+// messages, or 0 if it's not present.
+func ttlFromControlMessage(oob []byte, ipVer int) int {
+	if ipVer == 6 {
+		cm := &ipv6.ControlMessage{}
+		if cm.Parse(oob) != nil {
+			return 0
+		}
+		return cm.HopLimit
+	}
+
+	cm := &ipv4.ControlMessage{}
+	if cm.Parse(oob) != nil {
+		return 0
+	}
+	return cm.TTL
+}
+
+func (ipc *icmpPacketConn) read(buf []byte) (n int, addr net.Addr, recvTime time.Time, ttl int, err error) {
 	// We need to convert to IPConn/UDPConn so that we can read out-of-band data
 	// using ReadMsg<IP,UDP> functions. PacketConn interface doesn't have method
 	// that exposes OOB data.
-	oob := make([]byte, 64)
+	oob := make([]byte, 128)
 	var oobn int
 	if ipc.ipConn != nil {
 		n, oobn, _, addr, err = ipc.ipConn.ReadMsgIP(buf, oob)
@@ -196,6 +235,9 @@ func (ipc *icmpPacketConn) read(buf []byte) (n int, addr net.Addr, recvTime time
 	if err != nil {
 		return
 	}
+	if ipc.recvTTL {
+		ttl = ttlFromControlMessage(oob[:oobn], ipc.ipVer)
+	}
 	recvTime, err = timestampFromControlMessage(oob[:oobn])
 	return
 }
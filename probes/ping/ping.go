@@ -73,11 +73,14 @@ type result struct {
 	sent, rcvd        int64
 	latency           metrics.LatencyValue
 	validationFailure *metrics.Map[int64]
+	pktStats          *packetStats // nil if packet stats are not enabled.
 }
 
 // icmpConn is an interface wrapper for *icmp.PacketConn to allow testing.
+// read returns TTL (hop limit for IPv6) of the packet if it's known and
+// the connection has been set up to receive it, 0 otherwise.
 type icmpConn interface {
-	read(buf []byte) (n int, peer net.Addr, recvTime time.Time, err error)
+	read(buf []byte) (n int, peer net.Addr, recvTime time.Time, ttl int, err error)
 	write(buf []byte, peer net.Addr) (int, error)
 	setReadDeadline(deadline time.Time)
 	close()
@@ -102,6 +105,11 @@ type Probe struct {
 	useDatagramSocket    bool
 	disableFragmentation bool
 	statsExportFreq      int // Export frequency
+
+	// Packet stats config.
+	collectTTL bool
+	jitterDist *metrics.Distribution
+	ttlDist    *metrics.Distribution
 }
 
 // Init initliazes the probe with the given params.
@@ -151,6 +159,10 @@ func (p *Probe) initInternal() error {
 		return err
 	}
 
+	if err := p.initPacketStats(); err != nil {
+		return err
+	}
+
 	p.statsExportFreq = int(p.opts.StatsExportInterval.Nanoseconds() / p.opts.Interval.Nanoseconds())
 	if p.statsExportFreq == 0 {
 		p.statsExportFreq = 1
@@ -270,6 +282,7 @@ func (p *Probe) updateResultForTarget(t string) {
 	p.results[t] = &result{
 		latency:           latencyValue,
 		validationFailure: validators.ValidationFailureMap(p.opts.Validators),
+		pktStats:          p.newPacketStats(),
 	}
 }
 
@@ -337,6 +350,8 @@ type packetKey struct {
 func (p *Probe) recvPackets(runID uint16, tracker chan bool) {
 	// Number of expected packets: p.c.GetPacketsPerProbe() * len(p.targets)
 	received := make(map[packetKey]bool, int(p.c.GetPacketsPerProbe())*len(p.targets))
+	// Highest sequence number received so far, by target.
+	maxSeq := make(map[string]uint16, len(p.targets))
 	outstandingPkts := 0
 	p.conn.setReadDeadline(time.Now().Add(p.opts.Timeout))
 	pktbuf := make([]byte, maxPacketSize)
@@ -358,7 +373,7 @@ func (p *Probe) recvPackets(runID uint16, tracker chan bool) {
 		}
 
 		// Read packet from the socket
-		pktLen, peer, recvTime, err := p.conn.read(pktbuf)
+		pktLen, peer, recvTime, ttl, err := p.conn.read(pktbuf)
 
 		if err != nil {
 			if !p.opts.NegativeTest {
@@ -415,6 +430,9 @@ func (p *Probe) recvPackets(runID uint16, tracker chan bool) {
 				p.l.Warning("packet too small: size (", strconv.Itoa(pktLen), ") < minPacketSize+ipHdrLen (", strconv.Itoa(minPacketSize+offset), "), from peer: ", peer.String())
 				continue
 			}
+
+			// TTL is the 9th byte of the IPv4 header.
+			ttl = int(pktbuf[8])
 		}
 
 		if !validEchoReply(p.ipVer, pktbuf[offset+0]) {
@@ -439,10 +457,16 @@ func (p *Probe) recvPackets(runID uint16, tracker chan bool) {
 			continue
 		}
 
+		// Update probe result
+		result := p.results[pkt.target]
+
 		key := packetKey{pkt.target, pkt.seq}
 		// Check if we have already seen this packet.
 		if received[key] {
 			p.l.Info("Duplicate reply ", pkt.String(rtt), " (DUP)")
+			if result.pktStats != nil {
+				result.pktStats.duplicates++
+			}
 			continue
 		}
 		received[key] = true
@@ -451,8 +475,15 @@ func (p *Probe) recvPackets(runID uint16, tracker chan bool) {
 		// we were looking for.
 		outstandingPkts--
 
-		// Update probe result
-		result := p.results[pkt.target]
+		// Sequence numbers increase within a run, so a reply with a lower
+		// sequence number than a previous reply has been received out of order.
+		if last, ok := maxSeq[pkt.target]; ok && pkt.seq < last {
+			if result.pktStats != nil {
+				result.pktStats.outOfOrder++
+			}
+		} else {
+			maxSeq[pkt.target] = pkt.seq
+		}
 
 		if p.opts.Validators != nil {
 			failedValidations := validators.RunValidators(p.opts.Validators, &validators.Input{ResponseBody: pkt.data}, result.validationFailure, p.l)
@@ -467,6 +498,9 @@ func (p *Probe) recvPackets(runID uint16, tracker chan bool) {
 
 		result.rcvd++
 		result.latency.AddFloat64(rtt.Seconds() / p.opts.LatencyUnit.Seconds())
+		if result.pktStats != nil {
+			result.pktStats.addReply(rtt.Seconds()/p.opts.LatencyUnit.Seconds(), ttl)
+		}
 	}
 }
 
@@ -510,6 +544,52 @@ func (p *Probe) runProbe() {
 	}()
 	p.sendPackets(runID, tracker)
 	wg.Wait()
+
+	for _, target := range p.targets {
+		if ps := p.results[target.Name].pktStats; ps != nil {
+			ps.endRun()
+		}
+	}
+}
+
+// targetMetrics returns the metrics for the given target.
+func (p *Probe) targetMetrics(ts time.Time, target string) []*metrics.EventMetrics {
+	result := p.results[target]
+	success := result.rcvd
+	if p.opts.NegativeTest {
+		success = result.sent - result.rcvd
+	}
+	em := metrics.NewEventMetrics(ts).
+		AddMetric("total", metrics.NewInt(result.sent)).
+		AddMetric("success", metrics.NewInt(success)).
+		AddMetric(p.opts.LatencyMetricName, result.latency.Clone()).
+		AddLabel("ptype", "ping").
+		AddLabel("probe", p.name).
+		AddLabel("dst", target)
+
+	em.LatencyUnit = p.opts.LatencyUnit
+
+	if p.opts.Validators != nil {
+		em.AddMetric("validation_failure", result.validationFailure)
+	}
+
+	ems := []*metrics.EventMetrics{em}
+	if result.pktStats == nil {
+		return ems
+	}
+
+	result.pktStats.addCumulativeMetrics(em)
+
+	// Loss, RTT and TTL gauges are exported in an independent EM.
+	gaugeEM := metrics.NewEventMetrics(ts).
+		AddLabel("ptype", "ping").
+		AddLabel("probe", p.name).
+		AddLabel("dst", target)
+	if gaugeEM = result.pktStats.gaugeMetrics(gaugeEM, result.sent, result.rcvd); gaugeEM != nil {
+		gaugeEM.SetNotForAlerting()
+		ems = append(ems, gaugeEM)
+	}
+	return ems
 }
 
 // Start starts the probe and writes back the data on the provided channel.
@@ -542,26 +622,9 @@ func (p *Probe) Start(ctx context.Context, dataChan chan *metrics.EventMetrics)
 			continue
 		}
 		for _, target := range p.targets {
-			result := p.results[target.Name]
-			success := result.rcvd
-			if p.opts.NegativeTest {
-				success = result.sent - result.rcvd
-			}
-			em := metrics.NewEventMetrics(ts).
-				AddMetric("total", metrics.NewInt(result.sent)).
-				AddMetric("success", metrics.NewInt(success)).
-				AddMetric(p.opts.LatencyMetricName, result.latency.Clone()).
-				AddLabel("ptype", "ping").
-				AddLabel("probe", p.name).
-				AddLabel("dst", target.Name)
-
-			em.LatencyUnit = p.opts.LatencyUnit
-
-			if p.opts.Validators != nil {
-				em.AddMetric("validation_failure", result.validationFailure)
+			for _, em := range p.targetMetrics(ts, target.Name) {
+				p.opts.RecordMetrics(target, em, dataChan)
 			}
-
-			p.opts.RecordMetrics(target, em, dataChan)
 		}
 	}
 }
@@ -76,6 +76,8 @@ type testICMPConn struct {
 
 	flipLastByte   bool
 	flipLastByteMu sync.Mutex
+
+	ttl int // TTL returned with the replies.
 }
 
 func newTestICMPConn(opts *options.Options, targets []endpoint.Endpoint) *testICMPConn {
@@ -96,7 +98,7 @@ func (tic *testICMPConn) setFlipLastByte() {
 	tic.flipLastByte = true
 }
 
-func (tic *testICMPConn) read(buf []byte) (int, net.Addr, time.Time, error) {
+func (tic *testICMPConn) read(buf []byte) (int, net.Addr, time.Time, int, error) {
 	// We create per-target select cases, with each target's select-case
 	// pointing to that target's sentPackets channel.
 	var cases []reflect.SelectCase
@@ -109,7 +111,7 @@ func (tic *testICMPConn) read(buf []byte) (int, net.Addr, time.Time, error) {
 	// Select over the select cases.
 	chosen, value, ok := reflect.Select(cases)
 	if !ok {
-		return 0, nil, time.Now(), fmt.Errorf("nothing to read")
+		return 0, nil, time.Now(), 0, fmt.Errorf("nothing to read")
 	}
 
 	pkt := value.Bytes()
@@ -132,7 +134,7 @@ func (tic *testICMPConn) read(buf []byte) (int, net.Addr, time.Time, error) {
 	if tic.c.GetUseDatagramSocket() {
 		peer = &net.UDPAddr{IP: peerIP}
 	}
-	return len(pkt), peer, time.Now(), nil
+	return len(pkt), peer, time.Now(), tic.ttl, nil
 }
 
 // write simply queues packets into the sentPackets channel. These packets are
@@ -7,6 +7,7 @@
 package proto
 
 import (
+	proto "github.com/cloudprober/cloudprober/metrics/proto"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -21,7 +22,7 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-// Next tag: 1
+// Next tag: 18
 type ProbeConf struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Packets per probe
@@ -54,8 +55,31 @@ type ProbeConf struct {
 	DisableIntegrityCheck *bool `protobuf:"varint,13,opt,name=disable_integrity_check,json=disableIntegrityCheck,def=0" json:"disable_integrity_check,omitempty"`
 	// Do not allow OS-level fragmentation, only works on Linux systems.
 	DisableFragmentation *bool `protobuf:"varint,14,opt,name=disable_fragmentation,json=disableFragmentation,def=0" json:"disable_fragmentation,omitempty"`
-	unknownFields        protoimpl.UnknownFields
-	sizeCache            protoimpl.SizeCache
+	// Export additional per-target packet stats:
+	//
+	//	duplicates: number of duplicate replies (cumulative).
+	//	out_of_order: number of replies received out of order (cumulative).
+	//
+	// and, for the packets sent and received since the last export, as gauges:
+	//
+	//	loss_percent: percentage of the packets that didn't get a reply.
+	//	rtt_min, rtt_max: minimum and maximum RTT.
+	//	rtt_stddev: standard deviation of the RTTs (jitter).
+	//	rtt_mad: mean absolute deviation of the RTTs from their mean (jitter).
+	//	ttl: TTL (hop limit for IPv6) of the last reply. A change in TTL
+	//	     usually indicates a change in the network path.
+	//
+	// RTT stats use the probe's latency unit.
+	ExportPacketStats *bool `protobuf:"varint,15,opt,name=export_packet_stats,json=exportPacketStats,def=0" json:"export_packet_stats,omitempty"`
+	// If specified, RTT jitter of each probe run, i.e. mean absolute deviation
+	// of the RTTs of a target's replies in that run, is also exported as a
+	// distribution: "rtt_jitter". It requires packets_per_probe > 1.
+	JitterDistribution *proto.Dist `protobuf:"bytes,16,opt,name=jitter_distribution,json=jitterDistribution" json:"jitter_distribution,omitempty"`
+	// If specified, TTLs of all the replies are exported as a distribution,
+	// instead of the "ttl" gauge.
+	TtlDistribution *proto.Dist `protobuf:"bytes,17,opt,name=ttl_distribution,json=ttlDistribution" json:"ttl_distribution,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 // Default values for ProbeConf fields.
@@ -67,6 +91,7 @@ const (
 	Default_ProbeConf_UseDatagramSocket      = bool(true)
 	Default_ProbeConf_DisableIntegrityCheck  = bool(false)
 	Default_ProbeConf_DisableFragmentation   = bool(false)
+	Default_ProbeConf_ExportPacketStats      = bool(false)
 )
 
 func (x *ProbeConf) Reset() {
@@ -148,11 +173,32 @@ func (x *ProbeConf) GetDisableFragmentation() bool {
 	return Default_ProbeConf_DisableFragmentation
 }
 
+func (x *ProbeConf) GetExportPacketStats() bool {
+	if x != nil && x.ExportPacketStats != nil {
+		return *x.ExportPacketStats
+	}
+	return Default_ProbeConf_ExportPacketStats
+}
+
+func (x *ProbeConf) GetJitterDistribution() *proto.Dist {
+	if x != nil {
+		return x.JitterDistribution
+	}
+	return nil
+}
+
+func (x *ProbeConf) GetTtlDistribution() *proto.Dist {
+	if x != nil {
+		return x.TtlDistribution
+	}
+	return nil
+}
+
 var File_github_com_cloudprober_cloudprober_probes_ping_proto_config_proto protoreflect.FileDescriptor
 
 const file_github_com_cloudprober_cloudprober_probes_ping_proto_config_proto_rawDesc = "" +
 	"\n" +
-	"Agithub.com/cloudprober/cloudprober/probes/ping/proto/config.proto\x12\x17cloudprober.probes.ping\"\x87\x03\n" +
+	"Agithub.com/cloudprober/cloudprober/probes/ping/proto/config.proto\x12\x17cloudprober.probes.ping\x1a;github.com/cloudprober/cloudprober/metrics/proto/dist.proto\"\xd0\x04\n" +
 	"\tProbeConf\x12-\n" +
 	"\x11packets_per_probe\x18\x06 \x01(\x05:\x012R\x0fpacketsPerProbe\x126\n" +
 	"\x15packets_interval_msec\x18\a \x01(\x05:\x0225R\x13packetsIntervalMsec\x12;\n" +
@@ -161,7 +207,10 @@ const file_github_com_cloudprober_cloudprober_probes_ping_proto_config_proto_raw
 	" \x01(\x05:\x0256R\vpayloadSize\x124\n" +
 	"\x13use_datagram_socket\x18\f \x01(\b:\x04trueR\x11useDatagramSocket\x12=\n" +
 	"\x17disable_integrity_check\x18\r \x01(\b:\x05falseR\x15disableIntegrityCheck\x12:\n" +
-	"\x15disable_fragmentation\x18\x0e \x01(\b:\x05falseR\x14disableFragmentationB6Z4github.com/cloudprober/cloudprober/probes/ping/proto"
+	"\x15disable_fragmentation\x18\x0e \x01(\b:\x05falseR\x14disableFragmentation\x125\n" +
+	"\x13export_packet_stats\x18\x0f \x01(\b:\x05falseR\x11exportPacketStats\x12J\n" +
+	"\x13jitter_distribution\x18\x10 \x01(\v2\x19.cloudprober.metrics.DistR\x12jitterDistribution\x12D\n" +
+	"\x10ttl_distribution\x18\x11 \x01(\v2\x19.cloudprober.metrics.DistR\x0fttlDistributionB6Z4github.com/cloudprober/cloudprober/probes/ping/proto"
 
 var (
 	file_github_com_cloudprober_cloudprober_probes_ping_proto_config_proto_rawDescOnce sync.Once
@@ -177,14 +226,17 @@ func file_github_com_cloudprober_cloudprober_probes_ping_proto_config_proto_rawD
 
 var file_github_com_cloudprober_cloudprober_probes_ping_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
 var file_github_com_cloudprober_cloudprober_probes_ping_proto_config_proto_goTypes = []any{
-	(*ProbeConf)(nil), // 0: cloudprober.probes.ping.ProbeConf
+	(*ProbeConf)(nil),  // 0: cloudprober.probes.ping.ProbeConf
+	(*proto.Dist)(nil), // 1: cloudprober.metrics.Dist
 }
 var file_github_com_cloudprober_cloudprober_probes_ping_proto_config_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	1, // 0: cloudprober.probes.ping.ProbeConf.jitter_distribution:type_name -> cloudprober.metrics.Dist
+	1, // 1: cloudprober.probes.ping.ProbeConf.ttl_distribution:type_name -> cloudprober.metrics.Dist
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_github_com_cloudprober_cloudprober_probes_ping_proto_config_proto_init() }
@@ -0,0 +1,180 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ping
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/cloudprober/cloudprober/metrics"
+)
+
+// packetStats keeps the additional per-target packet stats. See
+// export_packet_stats, jitter_distribution and ttl_distribution in the probe
+// config for details.
+type packetStats struct {
+	export bool // export_packet_stats
+
+	duplicates, outOfOrder int64
+
+	rtts    []float64 // RTTs received since the last export.
+	runRTTs []float64 // RTTs received in the current probe run.
+	ttl     int       // TTL of the last reply, 0 if not known.
+
+	// Sent and received packets at the time of the last export, used to
+	// compute the loss percentage for the export interval.
+	lastSent, lastRcvd int64
+
+	jitterDist *metrics.Distribution
+	ttlDist    *metrics.Distribution
+}
+
+// initPacketStats parses the packet stats config.
+func (p *Probe) initPacketStats() error {
+	if p.c.GetJitterDistribution() != nil {
+		d, err := metrics.NewDistributionFromProto(p.c.GetJitterDistribution())
+		if err != nil {
+			return fmt.Errorf("invalid jitter_distribution: %v", err)
+		}
+		p.jitterDist = d
+	}
+
+	if p.c.GetTtlDistribution() != nil {
+		d, err := metrics.NewDistributionFromProto(p.c.GetTtlDistribution())
+		if err != nil {
+			return fmt.Errorf("invalid ttl_distribution: %v", err)
+		}
+		p.ttlDist = d
+	}
+
+	p.collectTTL = p.c.GetExportPacketStats() || p.ttlDist != nil
+	return nil
+}
+
+// newPacketStats returns a new packetStats, or nil if packet stats are not
+// enabled.
+func (p *Probe) newPacketStats() *packetStats {
+	if !p.c.GetExportPacketStats() && p.jitterDist == nil && p.ttlDist == nil {
+		return nil
+	}
+
+	ps := &packetStats{export: p.c.GetExportPacketStats()}
+	if p.jitterDist != nil {
+		ps.jitterDist = p.jitterDist.CloneDist()
+	}
+	if p.ttlDist != nil {
+		ps.ttlDist = p.ttlDist.CloneDist()
+	}
+	return ps
+}
+
+// addReply records a reply's RTT (in latency unit) and TTL.
+func (ps *packetStats) addReply(rtt float64, ttl int) {
+	ps.rtts = append(ps.rtts, rtt)
+	ps.runRTTs = append(ps.runRTTs, rtt)
+
+	if ttl > 0 {
+		ps.ttl = ttl
+		if ps.ttlDist != nil {
+			ps.ttlDist.AddFloat64(float64(ttl))
+		}
+	}
+}
+
+// endRun is called at the end of each probe run.
+func (ps *packetStats) endRun() {
+	if ps.jitterDist != nil && len(ps.runRTTs) > 1 {
+		ps.jitterDist.AddFloat64(meanAbsDeviation(ps.runRTTs))
+	}
+	ps.runRTTs = ps.runRTTs[:0]
+}
+
+func mean(vals []float64) float64 {
+	var sum float64
+	for _, v := range vals {
+		sum += v
+	}
+	return sum / float64(len(vals))
+}
+
+// stddev returns the population standard deviation of vals.
+func stddev(vals []float64) float64 {
+	m := mean(vals)
+	var sum float64
+	for _, v := range vals {
+		sum += (v - m) * (v - m)
+	}
+	return math.Sqrt(sum / float64(len(vals)))
+}
+
+// meanAbsDeviation returns the mean absolute deviation of vals from their
+// mean.
+func meanAbsDeviation(vals []float64) float64 {
+	m := mean(vals)
+	var sum float64
+	for _, v := range vals {
+		sum += math.Abs(v - m)
+	}
+	return sum / float64(len(vals))
+}
+
+// addCumulativeMetrics adds the cumulative metrics to the given EventMetrics.
+func (ps *packetStats) addCumulativeMetrics(em *metrics.EventMetrics) {
+	if ps.export {
+		em.AddMetric("duplicates", metrics.NewInt(ps.duplicates)).
+			AddMetric("out_of_order", metrics.NewInt(ps.outOfOrder))
+	}
+	if ps.jitterDist != nil {
+		em.AddMetric("rtt_jitter", ps.jitterDist.Clone())
+	}
+	if ps.ttlDist != nil {
+		em.AddMetric("ttl", ps.ttlDist.Clone())
+	}
+}
+
+// gaugeMetrics adds the gauge metrics for the packets sent and received since
+// the last export to the given EventMetrics, and resets them. sent and rcvd are
+// the cumulative packet counts. It returns nil if there is nothing to export.
+func (ps *packetStats) gaugeMetrics(em *metrics.EventMetrics, sent, rcvd int64) *metrics.EventMetrics {
+	defer func() {
+		ps.rtts = ps.rtts[:0]
+		ps.lastSent, ps.lastRcvd = sent, rcvd
+	}()
+
+	if !ps.export {
+		return nil
+	}
+
+	if dSent := sent - ps.lastSent; dSent > 0 {
+		lost := dSent - (rcvd - ps.lastRcvd)
+		em.AddMetric("loss_percent", metrics.NewFloat(100*float64(lost)/float64(dSent)))
+	}
+	if len(ps.rtts) > 0 {
+		em.AddMetric("rtt_min", metrics.NewFloat(slices.Min(ps.rtts))).
+			AddMetric("rtt_max", metrics.NewFloat(slices.Max(ps.rtts))).
+			AddMetric("rtt_stddev", metrics.NewFloat(stddev(ps.rtts))).
+			AddMetric("rtt_mad", metrics.NewFloat(meanAbsDeviation(ps.rtts)))
+	}
+	if ps.ttl > 0 && ps.ttlDist == nil {
+		em.AddMetric("ttl", metrics.NewInt(int64(ps.ttl)))
+	}
+
+	if len(em.MetricsKeys()) == 0 {
+		return nil
+	}
+	em.Kind = metrics.GAUGE
+	return em
+}
@@ -0,0 +1,234 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ping
+
+import (
+	"encoding/binary"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cloudprober/cloudprober/metrics"
+	distpb "github.com/cloudprober/cloudprober/metrics/proto"
+	configpb "github.com/cloudprober/cloudprober/probes/ping/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestJitterFuncs(t *testing.T) {
+	vals := []float64{2, 4, 4, 4, 5, 5, 7, 9}
+	assert.Equal(t, 5.0, mean(vals))
+	assert.Equal(t, 2.0, stddev(vals))
+	assert.Equal(t, 1.5, meanAbsDeviation(vals))
+
+	assert.Equal(t, 0.0, stddev([]float64{3}))
+	assert.Equal(t, 0.0, meanAbsDeviation([]float64{3}))
+}
+
+// scriptedICMPConn is an icmpConn that replies to the ping requests for a
+// single target, duplicating, reordering or dropping the replies if asked.
+type scriptedICMPConn struct {
+	replies   chan []byte
+	peer      net.Addr
+	ttl       int
+	duplicate bool
+	swapFirst bool // Deliver the first two replies in reverse order.
+	dropLast  bool // Drop the reply to the last packet of each run.
+
+	held     []byte
+	deadline time.Time
+}
+
+func newScriptedICMPConn(target string) *scriptedICMPConn {
+	return &scriptedICMPConn{
+		replies: make(chan []byte, 100),
+		peer:    &net.UDPAddr{IP: net.ParseIP(target)},
+	}
+}
+
+func (sc *scriptedICMPConn) read(buf []byte) (int, net.Addr, time.Time, int, error) {
+	select {
+	case pkt := <-sc.replies:
+		return copy(buf, pkt), sc.peer, time.Now(), sc.ttl, nil
+	case <-time.After(time.Until(sc.deadline)):
+		return 0, nil, time.Time{}, 0, &net.OpError{Op: "read", Err: os.ErrDeadlineExceeded}
+	}
+}
+
+func (sc *scriptedICMPConn) write(in []byte, peer net.Addr) (int, error) {
+	reply := replyPkt(in, 4)
+	seq := binary.BigEndian.Uint16(in[6:8])
+
+	if sc.dropLast && seq&0xff == 2 {
+		return len(in), nil
+	}
+	if sc.swapFirst && seq&0xff == 0 {
+		sc.held = reply
+		return len(in), nil
+	}
+	sc.replies <- reply
+	if sc.duplicate {
+		sc.replies <- reply
+	}
+	if sc.held != nil {
+		sc.replies <- sc.held
+		sc.held = nil
+	}
+	return len(in), nil
+}
+
+func (sc *scriptedICMPConn) setReadDeadline(deadline time.Time) {
+	sc.deadline = deadline
+}
+
+func (sc *scriptedICMPConn) close() {}
+
+func TestPacketStats(t *testing.T) {
+	const target = "2.2.2.2"
+
+	tests := []struct {
+		name           string
+		duplicate      bool
+		swapFirst      bool
+		dropLast       bool
+		ttlDist        bool
+		wantSuccess    int64
+		wantDuplicates int64
+		wantOutOfOrder int64
+		wantLoss       float64
+	}{
+		{
+			name:        "in_order",
+			wantSuccess: 6,
+		},
+		{
+			name:           "duplicates",
+			duplicate:      true,
+			wantSuccess:    6,
+			wantDuplicates: 2, // Duplicate of the last reply is not read.
+		},
+		{
+			name:           "out_of_order",
+			swapFirst:      true,
+			wantSuccess:    6,
+			wantOutOfOrder: 1,
+		},
+		{
+			name:        "loss",
+			dropLast:    true,
+			wantSuccess: 4,
+			wantLoss:    100.0 / 3,
+		},
+		{
+			name:        "ttl_distribution",
+			ttlDist:     true,
+			wantSuccess: 6,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &configpb.ProbeConf{
+				PacketsPerProbe:     proto.Int32(3),
+				PacketsIntervalMsec: proto.Int32(5),
+				ExportPacketStats:   proto.Bool(true),
+				JitterDistribution: &distpb.Dist{
+					Buckets: &distpb.Dist_ExplicitBuckets{ExplicitBuckets: "1,10,100"},
+				},
+			}
+			if tt.ttlDist {
+				c.TtlDistribution = &distpb.Dist{
+					Buckets: &distpb.Dist_ExplicitBuckets{ExplicitBuckets: "32,64,128"},
+				}
+			}
+			p, err := newProbe(c, 4, []string{target})
+			require.NoError(t, err)
+			p.opts.LatencyMetricName = "latency"
+
+			sc := newScriptedICMPConn(target)
+			sc.ttl, sc.duplicate, sc.swapFirst, sc.dropLast = 57, tt.duplicate, tt.swapFirst, tt.dropLast
+			p.conn = sc
+
+			for i := 0; i < 2; i++ {
+				p.runProbe()
+			}
+
+			ems := p.targetMetrics(time.Now(), target)
+			require.Len(t, ems, 2)
+
+			em := ems[0]
+			assert.Equal(t, tt.wantSuccess, em.Metric("success").(metrics.NumValue).Int64())
+			assert.Equal(t, tt.wantDuplicates*2, em.Metric("duplicates").(metrics.NumValue).Int64())
+			assert.Equal(t, tt.wantOutOfOrder*2, em.Metric("out_of_order").(metrics.NumValue).Int64())
+			assert.Equal(t, int64(2), em.Metric("rtt_jitter").(*metrics.Distribution).Data().Count, "one jitter value per run")
+
+			gem := ems[1]
+			assert.Equal(t, metrics.Kind(metrics.GAUGE), gem.Kind)
+			assert.False(t, gem.IsForAlerting())
+			assert.Equal(t, target, gem.Label("dst"))
+			assert.InDelta(t, tt.wantLoss, gem.Metric("loss_percent").(metrics.NumValue).Float64(), 0.001)
+			rttMin := gem.Metric("rtt_min").(metrics.NumValue).Float64()
+			rttMax := gem.Metric("rtt_max").(metrics.NumValue).Float64()
+			assert.Greater(t, rttMin, 0.0)
+			assert.GreaterOrEqual(t, rttMax, rttMin)
+			assert.LessOrEqual(t, gem.Metric("rtt_stddev").(metrics.NumValue).Float64(), rttMax-rttMin)
+			assert.LessOrEqual(t, gem.Metric("rtt_mad").(metrics.NumValue).Float64(), rttMax-rttMin)
+
+			if tt.ttlDist {
+				assert.Nil(t, gem.Metric("ttl"))
+				assert.Equal(t, tt.wantSuccess, em.Metric("ttl").(*metrics.Distribution).Data().Count)
+			} else {
+				assert.Equal(t, int64(57), gem.Metric("ttl").(metrics.NumValue).Int64())
+			}
+
+			// Loss and RTT gauges are reset after export. TTL gauge is retained.
+			ems = p.targetMetrics(time.Now(), target)
+			if tt.ttlDist {
+				assert.Len(t, ems, 1)
+			} else {
+				require.Len(t, ems, 2)
+				assert.Nil(t, ems[1].Metric("rtt_min"))
+				assert.Nil(t, ems[1].Metric("loss_percent"))
+				assert.Equal(t, int64(57), ems[1].Metric("ttl").(metrics.NumValue).Int64())
+			}
+		})
+	}
+}
+
+func TestPacketStatsDisabled(t *testing.T) {
+	p, err := newProbe(&configpb.ProbeConf{}, 4, []string{"2.2.2.2"})
+	require.NoError(t, err)
+	p.conn = newScriptedICMPConn("2.2.2.2")
+	p.runProbe()
+
+	ems := p.targetMetrics(time.Now(), "2.2.2.2")
+	require.Len(t, ems, 1)
+	assert.Nil(t, ems[0].Metric("duplicates"))
+	assert.False(t, p.collectTTL)
+}
+
+func TestInitPacketStatsErrors(t *testing.T) {
+	badDist := &distpb.Dist{Buckets: &distpb.Dist_ExplicitBuckets{ExplicitBuckets: "1,x"}}
+
+	for _, c := range []*configpb.ProbeConf{
+		{JitterDistribution: badDist},
+		{TtlDistribution: badDist},
+	} {
+		_, err := newProbe(c, 4, []string{"2.2.2.2"})
+		assert.Error(t, err)
+	}
+}
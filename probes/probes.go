@@ -29,6 +29,7 @@ import (
 	"github.com/cloudprober/cloudprober/probes/external"
 	grpcprobe "github.com/cloudprober/cloudprober/probes/grpc"
 	httpprobe "github.com/cloudprober/cloudprober/probes/http"
+	"github.com/cloudprober/cloudprober/probes/httpflow"
 	"github.com/cloudprober/cloudprober/probes/options"
 	"github.com/cloudprober/cloudprober/probes/ping"
 	configpb "github.com/cloudprober/cloudprober/probes/proto"
@@ -36,6 +37,7 @@ import (
 	"github.com/cloudprober/cloudprober/probes/tcp"
 	"github.com/cloudprober/cloudprober/probes/udp"
 	"github.com/cloudprober/cloudprober/probes/udplistener"
+	"github.com/cloudprober/cloudprober/probes/websocket"
 	"github.com/cloudprober/cloudprober/web/formatutils"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
@@ -86,6 +88,11 @@ type ProbeInfo struct {
 	SourceIP      string
 }
 
+// Paused returns true if the probe has been paused.
+func (p *ProbeInfo) Paused() bool {
+	return p.Options != nil && p.Options.IsPaused()
+}
+
 func getExtensionProbe(p *configpb.ProbeDef) (Probe, interface{}, error) {
 	extensionMapMu.RLock()
 	defer extensionMapMu.RUnlock()
@@ -167,6 +174,12 @@ func initProbe(p *configpb.ProbeDef, opts *options.Options) (probe Probe, probeC
 	case configpb.ProbeDef_BROWSER:
 		probe = &browser.Probe{}
 		probeConf = p.GetBrowserProbe()
+	case configpb.ProbeDef_HTTP_FLOW:
+		probe = &httpflow.Probe{}
+		probeConf = p.GetHttpFlowProbe()
+	case configpb.ProbeDef_WEBSOCKET:
+		probe = &websocket.Probe{}
+		probeConf = p.GetWebsocketProbe()
 	case configpb.ProbeDef_SYSTEM:
 		probe = &system.Probe{}
 		probeConf = p.GetSystemProbe()
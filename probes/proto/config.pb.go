@@ -15,11 +15,13 @@ import (
 	proto7 "github.com/cloudprober/cloudprober/probes/external/proto"
 	proto10 "github.com/cloudprober/cloudprober/probes/grpc/proto"
 	proto5 "github.com/cloudprober/cloudprober/probes/http/proto"
+	proto14 "github.com/cloudprober/cloudprober/probes/httpflow/proto"
 	proto4 "github.com/cloudprober/cloudprober/probes/ping/proto"
 	proto13 "github.com/cloudprober/cloudprober/probes/system/proto"
 	proto11 "github.com/cloudprober/cloudprober/probes/tcp/proto"
 	proto8 "github.com/cloudprober/cloudprober/probes/udp/proto"
 	proto9 "github.com/cloudprober/cloudprober/probes/udplistener/proto"
+	proto15 "github.com/cloudprober/cloudprober/probes/websocket/proto"
 	proto "github.com/cloudprober/cloudprober/targets/proto"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
@@ -48,6 +50,8 @@ const (
 	ProbeDef_TCP          ProbeDef_Type = 7
 	ProbeDef_BROWSER      ProbeDef_Type = 8
 	ProbeDef_SYSTEM       ProbeDef_Type = 9
+	ProbeDef_HTTP_FLOW    ProbeDef_Type = 10
+	ProbeDef_WEBSOCKET    ProbeDef_Type = 11
 	// One of the extension probe types. See "extensions" below for more
 	// details.
 	ProbeDef_EXTENSION ProbeDef_Type = 98
@@ -71,6 +75,8 @@ var (
 		7:  "TCP",
 		8:  "BROWSER",
 		9:  "SYSTEM",
+		10: "HTTP_FLOW",
+		11: "WEBSOCKET",
 		98: "EXTENSION",
 		99: "USER_DEFINED",
 	}
@@ -85,6 +91,8 @@ var (
 		"TCP":          7,
 		"BROWSER":      8,
 		"SYSTEM":       9,
+		"HTTP_FLOW":    10,
+		"WEBSOCKET":    11,
 		"EXTENSION":    98,
 		"USER_DEFINED": 99,
 	}
@@ -331,6 +339,67 @@ func (Schedule_ScheduleType) EnumDescriptor() ([]byte, []int) {
 	return file_github_com_cloudprober_cloudprober_probes_proto_config_proto_rawDescGZIP(), []int{2, 1}
 }
 
+type Dependency_Action int32
+
+const (
+	// Skip probe runs for the target while the parent is failing. This is
+	// supported only by the HTTP, TCP, DNS, GRPC and BROWSER probes.
+	Dependency_SKIP Dependency_Action = 0
+	// Keep running the probe, but add the "upstream_failed" label to the
+	// results. The label is set to "true" while the parent is failing and to
+	// "false" otherwise.
+	Dependency_LABEL Dependency_Action = 1
+)
+
+// Enum value maps for Dependency_Action.
+var (
+	Dependency_Action_name = map[int32]string{
+		0: "SKIP",
+		1: "LABEL",
+	}
+	Dependency_Action_value = map[string]int32{
+		"SKIP":  0,
+		"LABEL": 1,
+	}
+)
+
+func (x Dependency_Action) Enum() *Dependency_Action {
+	p := new(Dependency_Action)
+	*p = x
+	return p
+}
+
+func (x Dependency_Action) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Dependency_Action) Descriptor() protoreflect.EnumDescriptor {
+	return file_github_com_cloudprober_cloudprober_probes_proto_config_proto_enumTypes[4].Descriptor()
+}
+
+func (Dependency_Action) Type() protoreflect.EnumType {
+	return &file_github_com_cloudprober_cloudprober_probes_proto_config_proto_enumTypes[4]
+}
+
+func (x Dependency_Action) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *Dependency_Action) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = Dependency_Action(num)
+	return nil
+}
+
+// Deprecated: Use Dependency_Action.Descriptor instead.
+func (Dependency_Action) EnumDescriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_probes_proto_config_proto_rawDescGZIP(), []int{3, 0}
+}
+
 // Next tag: 101
 type ProbeDef struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -450,6 +519,8 @@ type ProbeDef struct {
 	//	*ProbeDef_TcpProbe
 	//	*ProbeDef_BrowserProbe
 	//	*ProbeDef_SystemProbe
+	//	*ProbeDef_HttpFlowProbe
+	//	*ProbeDef_WebsocketProbe
 	//	*ProbeDef_UserDefinedProbe
 	Probe isProbeDef_Probe `protobuf_oneof:"probe"`
 	// Which machines this probe should run on. If defined, cloudprober will run
@@ -495,7 +566,29 @@ type ProbeDef struct {
 	//	}
 	Schedule []*Schedule `protobuf:"bytes,101,rep,name=schedule" json:"schedule,omitempty"`
 	// Debug options. Currently only used to enable logging metrics.
-	DebugOptions    *DebugOptions `protobuf:"bytes,100,opt,name=debug_options,json=debugOptions" json:"debug_options,omitempty"`
+	DebugOptions *DebugOptions `protobuf:"bytes,100,opt,name=debug_options,json=debugOptions" json:"debug_options,omitempty"`
+	// Probes that this probe depends on. If a parent probe is failing for a
+	// target, this probe either skips runs for that target or marks its results
+	// with the "upstream_failed" label (see Dependency.action). In both cases,
+	// alert notifications for that target are suppressed.
+	//
+	// For example, to not alert on HTTP probe failures while the load balancer
+	// in front of the HTTP servers is failing:
+	//
+	//	depends_on {
+	//	  probe: "lb-health"
+	//	  target: "lb.example.com"
+	//	}
+	DependsOn []*Dependency `protobuf:"bytes,103,rep,name=depends_on,json=dependsOn" json:"depends_on,omitempty"`
+	// Probe both IPv4 and IPv6 addresses of the targets. If set, targets are
+	// resolved for both A and AAAA records and each address family is probed
+	// separately, with results labeled by "ip_version" and "ip". This is useful
+	// to catch broken IPv6 (or IPv4) paths that Happy Eyeballs hides from
+	// browsers.
+	//
+	// This is currently supported only by HTTP and TCP probes, and cannot be
+	// used along with ip_version or source_ip config.
+	DualStack       *DualStack `protobuf:"bytes,104,opt,name=dual_stack,json=dualStack" json:"dual_stack,omitempty"`
 	extensionFields protoimpl.ExtensionFields
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
@@ -771,6 +864,24 @@ func (x *ProbeDef) GetSystemProbe() *proto13.ProbeConf {
 	return nil
 }
 
+func (x *ProbeDef) GetHttpFlowProbe() *proto14.ProbeConf {
+	if x != nil {
+		if x, ok := x.Probe.(*ProbeDef_HttpFlowProbe); ok {
+			return x.HttpFlowProbe
+		}
+	}
+	return nil
+}
+
+func (x *ProbeDef) GetWebsocketProbe() *proto15.ProbeConf {
+	if x != nil {
+		if x, ok := x.Probe.(*ProbeDef_WebsocketProbe); ok {
+			return x.WebsocketProbe
+		}
+	}
+	return nil
+}
+
 func (x *ProbeDef) GetUserDefinedProbe() string {
 	if x != nil {
 		if x, ok := x.Probe.(*ProbeDef_UserDefinedProbe); ok {
@@ -808,6 +919,20 @@ func (x *ProbeDef) GetDebugOptions() *DebugOptions {
 	return nil
 }
 
+func (x *ProbeDef) GetDependsOn() []*Dependency {
+	if x != nil {
+		return x.DependsOn
+	}
+	return nil
+}
+
+func (x *ProbeDef) GetDualStack() *DualStack {
+	if x != nil {
+		return x.DualStack
+	}
+	return nil
+}
+
 type isProbeDef_SourceIpConfig interface {
 	isProbeDef_SourceIpConfig()
 }
@@ -868,6 +993,14 @@ type ProbeDef_SystemProbe struct {
 	SystemProbe *proto13.ProbeConf `protobuf:"bytes,29,opt,name=system_probe,json=systemProbe,oneof"`
 }
 
+type ProbeDef_HttpFlowProbe struct {
+	HttpFlowProbe *proto14.ProbeConf `protobuf:"bytes,30,opt,name=http_flow_probe,json=httpFlowProbe,oneof"`
+}
+
+type ProbeDef_WebsocketProbe struct {
+	WebsocketProbe *proto15.ProbeConf `protobuf:"bytes,31,opt,name=websocket_probe,json=websocketProbe,oneof"`
+}
+
 type ProbeDef_UserDefinedProbe struct {
 	// This field's contents are passed on to the user defined probe,
 	// registered for this probe's name through probes.RegisterUserDefined().
@@ -894,6 +1027,10 @@ func (*ProbeDef_BrowserProbe) isProbeDef_Probe() {}
 
 func (*ProbeDef_SystemProbe) isProbeDef_Probe() {}
 
+func (*ProbeDef_HttpFlowProbe) isProbeDef_Probe() {}
+
+func (*ProbeDef_WebsocketProbe) isProbeDef_Probe() {}
+
 func (*ProbeDef_UserDefinedProbe) isProbeDef_Probe() {}
 
 type AdditionalLabel struct {
@@ -1050,6 +1187,165 @@ func (x *Schedule) GetTimezone() string {
 	return Default_Schedule_Timezone
 }
 
+type Dependency struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Name of the parent probe.
+	Probe *string `protobuf:"bytes,1,req,name=probe" json:"probe,omitempty"`
+	// Parent probe's target to check for a given target of this probe. If
+	// neither of these fields is set, parent probe's target with the same name
+	// is checked.
+	//
+	// Types that are valid to be assigned to ParentTarget:
+	//
+	//	*Dependency_Target
+	//	*Dependency_TargetLabel
+	ParentTarget  isDependency_ParentTarget `protobuf_oneof:"parent_target"`
+	Action        *Dependency_Action        `protobuf:"varint,4,opt,name=action,enum=cloudprober.probes.Dependency_Action,def=0" json:"action,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+// Default values for Dependency fields.
+const (
+	Default_Dependency_Action = Dependency_SKIP
+)
+
+func (x *Dependency) Reset() {
+	*x = Dependency{}
+	mi := &file_github_com_cloudprober_cloudprober_probes_proto_config_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Dependency) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Dependency) ProtoMessage() {}
+
+func (x *Dependency) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_probes_proto_config_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Dependency.ProtoReflect.Descriptor instead.
+func (*Dependency) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_probes_proto_config_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Dependency) GetProbe() string {
+	if x != nil && x.Probe != nil {
+		return *x.Probe
+	}
+	return ""
+}
+
+func (x *Dependency) GetParentTarget() isDependency_ParentTarget {
+	if x != nil {
+		return x.ParentTarget
+	}
+	return nil
+}
+
+func (x *Dependency) GetTarget() string {
+	if x != nil {
+		if x, ok := x.ParentTarget.(*Dependency_Target); ok {
+			return x.Target
+		}
+	}
+	return ""
+}
+
+func (x *Dependency) GetTargetLabel() string {
+	if x != nil {
+		if x, ok := x.ParentTarget.(*Dependency_TargetLabel); ok {
+			return x.TargetLabel
+		}
+	}
+	return ""
+}
+
+func (x *Dependency) GetAction() Dependency_Action {
+	if x != nil && x.Action != nil {
+		return *x.Action
+	}
+	return Default_Dependency_Action
+}
+
+type isDependency_ParentTarget interface {
+	isDependency_ParentTarget()
+}
+
+type Dependency_Target struct {
+	// Check this target of the parent probe for all targets of this probe,
+	// e.g. a shared load balancer or DNS server.
+	Target string `protobuf:"bytes,2,opt,name=target,oneof"`
+}
+
+type Dependency_TargetLabel struct {
+	// Check the parent probe's target named by this label of the target.
+	TargetLabel string `protobuf:"bytes,3,opt,name=target_label,json=targetLabel,oneof"`
+}
+
+func (*Dependency_Target) isDependency_ParentTarget() {}
+
+func (*Dependency_TargetLabel) isDependency_ParentTarget() {}
+
+type DualStack struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// By default, only one IP per address family (the first one returned by
+	// the resolver) is probed. If all_ips is set, every resolved IP is probed.
+	// Note that all_ips uses the system resolver, dns_resolver_override is not
+	// used for it.
+	AllIps        *bool `protobuf:"varint,1,opt,name=all_ips,json=allIps" json:"all_ips,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DualStack) Reset() {
+	*x = DualStack{}
+	mi := &file_github_com_cloudprober_cloudprober_probes_proto_config_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DualStack) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DualStack) ProtoMessage() {}
+
+func (x *DualStack) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_probes_proto_config_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DualStack.ProtoReflect.Descriptor instead.
+func (*DualStack) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_probes_proto_config_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DualStack) GetAllIps() bool {
+	if x != nil && x.AllIps != nil {
+		return *x.AllIps
+	}
+	return false
+}
+
 type DebugOptions struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Whether to log metrics or not.
@@ -1060,7 +1356,7 @@ type DebugOptions struct {
 
 func (x *DebugOptions) Reset() {
 	*x = DebugOptions{}
-	mi := &file_github_com_cloudprober_cloudprober_probes_proto_config_proto_msgTypes[3]
+	mi := &file_github_com_cloudprober_cloudprober_probes_proto_config_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1072,7 +1368,7 @@ func (x *DebugOptions) String() string {
 func (*DebugOptions) ProtoMessage() {}
 
 func (x *DebugOptions) ProtoReflect() protoreflect.Message {
-	mi := &file_github_com_cloudprober_cloudprober_probes_proto_config_proto_msgTypes[3]
+	mi := &file_github_com_cloudprober_cloudprober_probes_proto_config_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1085,7 +1381,7 @@ func (x *DebugOptions) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DebugOptions.ProtoReflect.Descriptor instead.
 func (*DebugOptions) Descriptor() ([]byte, []int) {
-	return file_github_com_cloudprober_cloudprober_probes_proto_config_proto_rawDescGZIP(), []int{3}
+	return file_github_com_cloudprober_cloudprober_probes_proto_config_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *DebugOptions) GetLogMetrics() bool {
@@ -1099,7 +1395,7 @@ var File_github_com_cloudprober_cloudprober_probes_proto_config_proto protorefle
 
 const file_github_com_cloudprober_cloudprober_probes_proto_config_proto_rawDesc = "" +
 	"\n" +
-	"<github.com/cloudprober/cloudprober/probes/proto/config.proto\x12\x12cloudprober.probes\x1a;github.com/cloudprober/cloudprober/metrics/proto/dist.proto\x1aGgithub.com/cloudprober/cloudprober/internal/alerting/proto/config.proto\x1aDgithub.com/cloudprober/cloudprober/probes/browser/proto/config.proto\x1a@github.com/cloudprober/cloudprober/probes/dns/proto/config.proto\x1aEgithub.com/cloudprober/cloudprober/probes/external/proto/config.proto\x1aAgithub.com/cloudprober/cloudprober/probes/grpc/proto/config.proto\x1aAgithub.com/cloudprober/cloudprober/probes/http/proto/config.proto\x1aAgithub.com/cloudprober/cloudprober/probes/ping/proto/config.proto\x1a@github.com/cloudprober/cloudprober/probes/tcp/proto/config.proto\x1a@github.com/cloudprober/cloudprober/probes/udp/proto/config.proto\x1aHgithub.com/cloudprober/cloudprober/probes/udplistener/proto/config.proto\x1aCgithub.com/cloudprober/cloudprober/probes/system/proto/config.proto\x1a>github.com/cloudprober/cloudprober/targets/proto/targets.proto\x1aIgithub.com/cloudprober/cloudprober/internal/validators/proto/config.proto\"\xca\x10\n" +
+	"<github.com/cloudprober/cloudprober/probes/proto/config.proto\x12\x12cloudprober.probes\x1a;github.com/cloudprober/cloudprober/metrics/proto/dist.proto\x1aGgithub.com/cloudprober/cloudprober/internal/alerting/proto/config.proto\x1aDgithub.com/cloudprober/cloudprober/probes/browser/proto/config.proto\x1a@github.com/cloudprober/cloudprober/probes/dns/proto/config.proto\x1aEgithub.com/cloudprober/cloudprober/probes/external/proto/config.proto\x1aAgithub.com/cloudprober/cloudprober/probes/grpc/proto/config.proto\x1aAgithub.com/cloudprober/cloudprober/probes/http/proto/config.proto\x1aEgithub.com/cloudprober/cloudprober/probes/httpflow/proto/config.proto\x1aAgithub.com/cloudprober/cloudprober/probes/ping/proto/config.proto\x1a@github.com/cloudprober/cloudprober/probes/tcp/proto/config.proto\x1a@github.com/cloudprober/cloudprober/probes/udp/proto/config.proto\x1aHgithub.com/cloudprober/cloudprober/probes/udplistener/proto/config.proto\x1aFgithub.com/cloudprober/cloudprober/probes/websocket/proto/config.proto\x1aCgithub.com/cloudprober/cloudprober/probes/system/proto/config.proto\x1a>github.com/cloudprober/cloudprober/targets/proto/targets.proto\x1aIgithub.com/cloudprober/cloudprober/internal/validators/proto/config.proto\"\x8b\x13\n" +
 	"\bProbeDef\x12\x12\n" +
 	"\x04name\x18\x01 \x02(\tR\x04name\x125\n" +
 	"\x04type\x18\x02 \x02(\x0e2!.cloudprober.probes.ProbeDef.TypeR\x04type\x12#\n" +
@@ -1133,12 +1429,18 @@ const file_github_com_cloudprober_cloudprober_probes_proto_config_proto_rawDesc
 	"grpc_probe\x18\x1a \x01(\v2\".cloudprober.probes.grpc.ProbeConfH\x01R\tgrpcProbe\x12@\n" +
 	"\ttcp_probe\x18\x1b \x01(\v2!.cloudprober.probes.tcp.ProbeConfH\x01R\btcpProbe\x12L\n" +
 	"\rbrowser_probe\x18\x1c \x01(\v2%.cloudprober.probes.browser.ProbeConfH\x01R\fbrowserProbe\x12I\n" +
-	"\fsystem_probe\x18\x1d \x01(\v2$.cloudprober.probes.system.ProbeConfH\x01R\vsystemProbe\x12.\n" +
+	"\fsystem_probe\x18\x1d \x01(\v2$.cloudprober.probes.system.ProbeConfH\x01R\vsystemProbe\x12P\n" +
+	"\x0fhttp_flow_probe\x18\x1e \x01(\v2&.cloudprober.probes.httpflow.ProbeConfH\x01R\rhttpFlowProbe\x12R\n" +
+	"\x0fwebsocket_probe\x18\x1f \x01(\v2'.cloudprober.probes.websocket.ProbeConfH\x01R\x0ewebsocketProbe\x12.\n" +
 	"\x12user_defined_probe\x18c \x01(\tH\x01R\x10userDefinedProbe\x12\x15\n" +
 	"\x06run_on\x18\x03 \x01(\tR\x05runOn\x12,\n" +
 	"\x12startup_delay_msec\x18f \x01(\rR\x10startupDelayMsec\x128\n" +
 	"\bschedule\x18e \x03(\v2\x1c.cloudprober.probes.ScheduleR\bschedule\x12E\n" +
-	"\rdebug_options\x18d \x01(\v2 .cloudprober.probes.DebugOptionsR\fdebugOptions\"\x99\x01\n" +
+	"\rdebug_options\x18d \x01(\v2 .cloudprober.probes.DebugOptionsR\fdebugOptions\x12=\n" +
+	"\n" +
+	"depends_on\x18g \x03(\v2\x1e.cloudprober.probes.DependencyR\tdependsOn\x12<\n" +
+	"\n" +
+	"dual_stack\x18h \x01(\v2\x1d.cloudprober.probes.DualStackR\tdualStack\"\xb7\x01\n" +
 	"\x04Type\x12\b\n" +
 	"\x04PING\x10\x00\x12\b\n" +
 	"\x04HTTP\x10\x01\x12\a\n" +
@@ -1151,6 +1453,9 @@ const file_github_com_cloudprober_cloudprober_probes_proto_config_proto_rawDesc
 	"\aBROWSER\x10\b\x12\n" +
 	"\n" +
 	"\x06SYSTEM\x10\t\x12\r\n" +
+	"\tHTTP_FLOW\x10\n" +
+	"\x12\r\n" +
+	"\tWEBSOCKET\x10\v\x12\r\n" +
 	"\tEXTENSION\x10b\x12\x10\n" +
 	"\fUSER_DEFINED\x10c\";\n" +
 	"\tIPVersion\x12\x1a\n" +
@@ -1187,7 +1492,19 @@ const file_github_com_cloudprober_cloudprober_probes_proto_config_proto_rawDesc
 	"\x18ScheduleType_UNSPECIFIED\x10\x00\x12\n" +
 	"\n" +
 	"\x06ENABLE\x10\x01\x12\v\n" +
-	"\aDISABLE\x10\x02\"/\n" +
+	"\aDISABLE\x10\x02\"\xd6\x01\n" +
+	"\n" +
+	"Dependency\x12\x14\n" +
+	"\x05probe\x18\x01 \x02(\tR\x05probe\x12\x18\n" +
+	"\x06target\x18\x02 \x01(\tH\x00R\x06target\x12#\n" +
+	"\ftarget_label\x18\x03 \x01(\tH\x00R\vtargetLabel\x12C\n" +
+	"\x06action\x18\x04 \x01(\x0e2%.cloudprober.probes.Dependency.Action:\x04SKIPR\x06action\"\x1d\n" +
+	"\x06Action\x12\b\n" +
+	"\x04SKIP\x10\x00\x12\t\n" +
+	"\x05LABEL\x10\x01B\x0f\n" +
+	"\rparent_target\"$\n" +
+	"\tDualStack\x12\x17\n" +
+	"\aall_ips\x18\x01 \x01(\bR\x06allIps\"/\n" +
 	"\fDebugOptions\x12\x1f\n" +
 	"\vlog_metrics\x18\x01 \x01(\bR\n" +
 	"logMetricsB1Z/github.com/cloudprober/cloudprober/probes/proto"
@@ -1204,60 +1521,70 @@ func file_github_com_cloudprober_cloudprober_probes_proto_config_proto_rawDescGZ
 	return file_github_com_cloudprober_cloudprober_probes_proto_config_proto_rawDescData
 }
 
-var file_github_com_cloudprober_cloudprober_probes_proto_config_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
-var file_github_com_cloudprober_cloudprober_probes_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_github_com_cloudprober_cloudprober_probes_proto_config_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_github_com_cloudprober_cloudprober_probes_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
 var file_github_com_cloudprober_cloudprober_probes_proto_config_proto_goTypes = []any{
 	(ProbeDef_Type)(0),         // 0: cloudprober.probes.ProbeDef.Type
 	(ProbeDef_IPVersion)(0),    // 1: cloudprober.probes.ProbeDef.IPVersion
 	(Schedule_Weekday)(0),      // 2: cloudprober.probes.Schedule.Weekday
 	(Schedule_ScheduleType)(0), // 3: cloudprober.probes.Schedule.ScheduleType
-	(*ProbeDef)(nil),           // 4: cloudprober.probes.ProbeDef
-	(*AdditionalLabel)(nil),    // 5: cloudprober.probes.AdditionalLabel
-	(*Schedule)(nil),           // 6: cloudprober.probes.Schedule
-	(*DebugOptions)(nil),       // 7: cloudprober.probes.DebugOptions
-	(*proto.TargetsDef)(nil),   // 8: cloudprober.targets.TargetsDef
-	(*proto1.Dist)(nil),        // 9: cloudprober.metrics.Dist
-	(*proto2.Validator)(nil),   // 10: cloudprober.validators.Validator
-	(*proto3.AlertConf)(nil),   // 11: cloudprober.alerting.AlertConf
-	(*proto4.ProbeConf)(nil),   // 12: cloudprober.probes.ping.ProbeConf
-	(*proto5.ProbeConf)(nil),   // 13: cloudprober.probes.http.ProbeConf
-	(*proto6.ProbeConf)(nil),   // 14: cloudprober.probes.dns.ProbeConf
-	(*proto7.ProbeConf)(nil),   // 15: cloudprober.probes.external.ProbeConf
-	(*proto8.ProbeConf)(nil),   // 16: cloudprober.probes.udp.ProbeConf
-	(*proto9.ProbeConf)(nil),   // 17: cloudprober.probes.udplistener.ProbeConf
-	(*proto10.ProbeConf)(nil),  // 18: cloudprober.probes.grpc.ProbeConf
-	(*proto11.ProbeConf)(nil),  // 19: cloudprober.probes.tcp.ProbeConf
-	(*proto12.ProbeConf)(nil),  // 20: cloudprober.probes.browser.ProbeConf
-	(*proto13.ProbeConf)(nil),  // 21: cloudprober.probes.system.ProbeConf
+	(Dependency_Action)(0),     // 4: cloudprober.probes.Dependency.Action
+	(*ProbeDef)(nil),           // 5: cloudprober.probes.ProbeDef
+	(*AdditionalLabel)(nil),    // 6: cloudprober.probes.AdditionalLabel
+	(*Schedule)(nil),           // 7: cloudprober.probes.Schedule
+	(*Dependency)(nil),         // 8: cloudprober.probes.Dependency
+	(*DualStack)(nil),          // 9: cloudprober.probes.DualStack
+	(*DebugOptions)(nil),       // 10: cloudprober.probes.DebugOptions
+	(*proto.TargetsDef)(nil),   // 11: cloudprober.targets.TargetsDef
+	(*proto1.Dist)(nil),        // 12: cloudprober.metrics.Dist
+	(*proto2.Validator)(nil),   // 13: cloudprober.validators.Validator
+	(*proto3.AlertConf)(nil),   // 14: cloudprober.alerting.AlertConf
+	(*proto4.ProbeConf)(nil),   // 15: cloudprober.probes.ping.ProbeConf
+	(*proto5.ProbeConf)(nil),   // 16: cloudprober.probes.http.ProbeConf
+	(*proto6.ProbeConf)(nil),   // 17: cloudprober.probes.dns.ProbeConf
+	(*proto7.ProbeConf)(nil),   // 18: cloudprober.probes.external.ProbeConf
+	(*proto8.ProbeConf)(nil),   // 19: cloudprober.probes.udp.ProbeConf
+	(*proto9.ProbeConf)(nil),   // 20: cloudprober.probes.udplistener.ProbeConf
+	(*proto10.ProbeConf)(nil),  // 21: cloudprober.probes.grpc.ProbeConf
+	(*proto11.ProbeConf)(nil),  // 22: cloudprober.probes.tcp.ProbeConf
+	(*proto12.ProbeConf)(nil),  // 23: cloudprober.probes.browser.ProbeConf
+	(*proto13.ProbeConf)(nil),  // 24: cloudprober.probes.system.ProbeConf
+	(*proto14.ProbeConf)(nil),  // 25: cloudprober.probes.httpflow.ProbeConf
+	(*proto15.ProbeConf)(nil),  // 26: cloudprober.probes.websocket.ProbeConf
 }
 var file_github_com_cloudprober_cloudprober_probes_proto_config_proto_depIdxs = []int32{
 	0,  // 0: cloudprober.probes.ProbeDef.type:type_name -> cloudprober.probes.ProbeDef.Type
-	8,  // 1: cloudprober.probes.ProbeDef.targets:type_name -> cloudprober.targets.TargetsDef
-	9,  // 2: cloudprober.probes.ProbeDef.latency_distribution:type_name -> cloudprober.metrics.Dist
-	10, // 3: cloudprober.probes.ProbeDef.validator:type_name -> cloudprober.validators.Validator
+	11, // 1: cloudprober.probes.ProbeDef.targets:type_name -> cloudprober.targets.TargetsDef
+	12, // 2: cloudprober.probes.ProbeDef.latency_distribution:type_name -> cloudprober.metrics.Dist
+	13, // 3: cloudprober.probes.ProbeDef.validator:type_name -> cloudprober.validators.Validator
 	1,  // 4: cloudprober.probes.ProbeDef.ip_version:type_name -> cloudprober.probes.ProbeDef.IPVersion
-	5,  // 5: cloudprober.probes.ProbeDef.additional_label:type_name -> cloudprober.probes.AdditionalLabel
-	11, // 6: cloudprober.probes.ProbeDef.alert:type_name -> cloudprober.alerting.AlertConf
-	12, // 7: cloudprober.probes.ProbeDef.ping_probe:type_name -> cloudprober.probes.ping.ProbeConf
-	13, // 8: cloudprober.probes.ProbeDef.http_probe:type_name -> cloudprober.probes.http.ProbeConf
-	14, // 9: cloudprober.probes.ProbeDef.dns_probe:type_name -> cloudprober.probes.dns.ProbeConf
-	15, // 10: cloudprober.probes.ProbeDef.external_probe:type_name -> cloudprober.probes.external.ProbeConf
-	16, // 11: cloudprober.probes.ProbeDef.udp_probe:type_name -> cloudprober.probes.udp.ProbeConf
-	17, // 12: cloudprober.probes.ProbeDef.udp_listener_probe:type_name -> cloudprober.probes.udplistener.ProbeConf
-	18, // 13: cloudprober.probes.ProbeDef.grpc_probe:type_name -> cloudprober.probes.grpc.ProbeConf
-	19, // 14: cloudprober.probes.ProbeDef.tcp_probe:type_name -> cloudprober.probes.tcp.ProbeConf
-	20, // 15: cloudprober.probes.ProbeDef.browser_probe:type_name -> cloudprober.probes.browser.ProbeConf
-	21, // 16: cloudprober.probes.ProbeDef.system_probe:type_name -> cloudprober.probes.system.ProbeConf
-	6,  // 17: cloudprober.probes.ProbeDef.schedule:type_name -> cloudprober.probes.Schedule
-	7,  // 18: cloudprober.probes.ProbeDef.debug_options:type_name -> cloudprober.probes.DebugOptions
-	3,  // 19: cloudprober.probes.Schedule.type:type_name -> cloudprober.probes.Schedule.ScheduleType
-	2,  // 20: cloudprober.probes.Schedule.start_weekday:type_name -> cloudprober.probes.Schedule.Weekday
-	2,  // 21: cloudprober.probes.Schedule.end_weekday:type_name -> cloudprober.probes.Schedule.Weekday
-	22, // [22:22] is the sub-list for method output_type
-	22, // [22:22] is the sub-list for method input_type
-	22, // [22:22] is the sub-list for extension type_name
-	22, // [22:22] is the sub-list for extension extendee
-	0,  // [0:22] is the sub-list for field type_name
+	6,  // 5: cloudprober.probes.ProbeDef.additional_label:type_name -> cloudprober.probes.AdditionalLabel
+	14, // 6: cloudprober.probes.ProbeDef.alert:type_name -> cloudprober.alerting.AlertConf
+	15, // 7: cloudprober.probes.ProbeDef.ping_probe:type_name -> cloudprober.probes.ping.ProbeConf
+	16, // 8: cloudprober.probes.ProbeDef.http_probe:type_name -> cloudprober.probes.http.ProbeConf
+	17, // 9: cloudprober.probes.ProbeDef.dns_probe:type_name -> cloudprober.probes.dns.ProbeConf
+	18, // 10: cloudprober.probes.ProbeDef.external_probe:type_name -> cloudprober.probes.external.ProbeConf
+	19, // 11: cloudprober.probes.ProbeDef.udp_probe:type_name -> cloudprober.probes.udp.ProbeConf
+	20, // 12: cloudprober.probes.ProbeDef.udp_listener_probe:type_name -> cloudprober.probes.udplistener.ProbeConf
+	21, // 13: cloudprober.probes.ProbeDef.grpc_probe:type_name -> cloudprober.probes.grpc.ProbeConf
+	22, // 14: cloudprober.probes.ProbeDef.tcp_probe:type_name -> cloudprober.probes.tcp.ProbeConf
+	23, // 15: cloudprober.probes.ProbeDef.browser_probe:type_name -> cloudprober.probes.browser.ProbeConf
+	24, // 16: cloudprober.probes.ProbeDef.system_probe:type_name -> cloudprober.probes.system.ProbeConf
+	25, // 17: cloudprober.probes.ProbeDef.http_flow_probe:type_name -> cloudprober.probes.httpflow.ProbeConf
+	26, // 18: cloudprober.probes.ProbeDef.websocket_probe:type_name -> cloudprober.probes.websocket.ProbeConf
+	7,  // 19: cloudprober.probes.ProbeDef.schedule:type_name -> cloudprober.probes.Schedule
+	10, // 20: cloudprober.probes.ProbeDef.debug_options:type_name -> cloudprober.probes.DebugOptions
+	8,  // 21: cloudprober.probes.ProbeDef.depends_on:type_name -> cloudprober.probes.Dependency
+	9,  // 22: cloudprober.probes.ProbeDef.dual_stack:type_name -> cloudprober.probes.DualStack
+	3,  // 23: cloudprober.probes.Schedule.type:type_name -> cloudprober.probes.Schedule.ScheduleType
+	2,  // 24: cloudprober.probes.Schedule.start_weekday:type_name -> cloudprober.probes.Schedule.Weekday
+	2,  // 25: cloudprober.probes.Schedule.end_weekday:type_name -> cloudprober.probes.Schedule.Weekday
+	4,  // 26: cloudprober.probes.Dependency.action:type_name -> cloudprober.probes.Dependency.Action
+	27, // [27:27] is the sub-list for method output_type
+	27, // [27:27] is the sub-list for method input_type
+	27, // [27:27] is the sub-list for extension type_name
+	27, // [27:27] is the sub-list for extension extendee
+	0,  // [0:27] is the sub-list for field type_name
 }
 
 func init() { file_github_com_cloudprober_cloudprober_probes_proto_config_proto_init() }
@@ -1278,15 +1605,21 @@ func file_github_com_cloudprober_cloudprober_probes_proto_config_proto_init() {
 		(*ProbeDef_TcpProbe)(nil),
 		(*ProbeDef_BrowserProbe)(nil),
 		(*ProbeDef_SystemProbe)(nil),
+		(*ProbeDef_HttpFlowProbe)(nil),
+		(*ProbeDef_WebsocketProbe)(nil),
 		(*ProbeDef_UserDefinedProbe)(nil),
 	}
+	file_github_com_cloudprober_cloudprober_probes_proto_config_proto_msgTypes[3].OneofWrappers = []any{
+		(*Dependency_Target)(nil),
+		(*Dependency_TargetLabel)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_github_com_cloudprober_cloudprober_probes_proto_config_proto_rawDesc), len(file_github_com_cloudprober_cloudprober_probes_proto_config_proto_rawDesc)),
-			NumEnums:      4,
-			NumMessages:   4,
+			NumEnums:      5,
+			NumMessages:   6,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
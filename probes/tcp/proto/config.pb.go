@@ -8,6 +8,7 @@ package proto
 
 import (
 	proto "github.com/cloudprober/cloudprober/common/tlsconfig/proto"
+	proto1 "github.com/cloudprober/cloudprober/probes/common/tlsinspect/proto"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -22,7 +23,242 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-// Next tag: 4
+// Protocols for STARTTLS, i.e. upgrading a plain-text connection to TLS
+// using a protocol specific negotiation.
+type ProbeConf_StartTLS int32
+
+const (
+	ProbeConf_NONE     ProbeConf_StartTLS = 0
+	ProbeConf_SMTP     ProbeConf_StartTLS = 1
+	ProbeConf_IMAP     ProbeConf_StartTLS = 2
+	ProbeConf_POP3     ProbeConf_StartTLS = 3
+	ProbeConf_LDAP     ProbeConf_StartTLS = 4
+	ProbeConf_POSTGRES ProbeConf_StartTLS = 5
+	ProbeConf_MYSQL    ProbeConf_StartTLS = 6
+	ProbeConf_XMPP     ProbeConf_StartTLS = 7 // Client-to-server, server name is used as the stream domain.
+)
+
+// Enum value maps for ProbeConf_StartTLS.
+var (
+	ProbeConf_StartTLS_name = map[int32]string{
+		0: "NONE",
+		1: "SMTP",
+		2: "IMAP",
+		3: "POP3",
+		4: "LDAP",
+		5: "POSTGRES",
+		6: "MYSQL",
+		7: "XMPP",
+	}
+	ProbeConf_StartTLS_value = map[string]int32{
+		"NONE":     0,
+		"SMTP":     1,
+		"IMAP":     2,
+		"POP3":     3,
+		"LDAP":     4,
+		"POSTGRES": 5,
+		"MYSQL":    6,
+		"XMPP":     7,
+	}
+)
+
+func (x ProbeConf_StartTLS) Enum() *ProbeConf_StartTLS {
+	p := new(ProbeConf_StartTLS)
+	*p = x
+	return p
+}
+
+func (x ProbeConf_StartTLS) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProbeConf_StartTLS) Descriptor() protoreflect.EnumDescriptor {
+	return file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_enumTypes[0].Descriptor()
+}
+
+func (ProbeConf_StartTLS) Type() protoreflect.EnumType {
+	return &file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_enumTypes[0]
+}
+
+func (x ProbeConf_StartTLS) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *ProbeConf_StartTLS) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = ProbeConf_StartTLS(num)
+	return nil
+}
+
+// Deprecated: Use ProbeConf_StartTLS.Descriptor instead.
+func (ProbeConf_StartTLS) EnumDescriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_rawDescGZIP(), []int{1, 0}
+}
+
+// Step of a send/expect conversation with the target.
+type Step struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Step name. It's used as the "step" label for the per-step metrics.
+	Name *string `protobuf:"bytes,1,req,name=name" json:"name,omitempty"`
+	// Data to send to the target, if any.
+	//
+	// Types that are valid to be assigned to Send:
+	//
+	//	*Step_SendBytes
+	//	*Step_SendLine
+	Send isStep_Send `protobuf_oneof:"send"`
+	// What to expect from the target, if anything. We keep reading until
+	// the expectation is met, or until step times out. After a successful
+	// expectation, all data received so far is consumed, i.e. the next step
+	// looks only at the data received after that.
+	//
+	// Types that are valid to be assigned to Expect:
+	//
+	//	*Step_ExpectRegex
+	//	*Step_ExpectPrefix
+	Expect isStep_Expect `protobuf_oneof:"expect"`
+	// Step timeout. By default, steps are limited only by the probe timeout.
+	TimeoutMsec   *int32 `protobuf:"varint,6,opt,name=timeout_msec,json=timeoutMsec" json:"timeout_msec,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Step) Reset() {
+	*x = Step{}
+	mi := &file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Step) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Step) ProtoMessage() {}
+
+func (x *Step) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Step.ProtoReflect.Descriptor instead.
+func (*Step) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Step) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *Step) GetSend() isStep_Send {
+	if x != nil {
+		return x.Send
+	}
+	return nil
+}
+
+func (x *Step) GetSendBytes() []byte {
+	if x != nil {
+		if x, ok := x.Send.(*Step_SendBytes); ok {
+			return x.SendBytes
+		}
+	}
+	return nil
+}
+
+func (x *Step) GetSendLine() string {
+	if x != nil {
+		if x, ok := x.Send.(*Step_SendLine); ok {
+			return x.SendLine
+		}
+	}
+	return ""
+}
+
+func (x *Step) GetExpect() isStep_Expect {
+	if x != nil {
+		return x.Expect
+	}
+	return nil
+}
+
+func (x *Step) GetExpectRegex() string {
+	if x != nil {
+		if x, ok := x.Expect.(*Step_ExpectRegex); ok {
+			return x.ExpectRegex
+		}
+	}
+	return ""
+}
+
+func (x *Step) GetExpectPrefix() string {
+	if x != nil {
+		if x, ok := x.Expect.(*Step_ExpectPrefix); ok {
+			return x.ExpectPrefix
+		}
+	}
+	return ""
+}
+
+func (x *Step) GetTimeoutMsec() int32 {
+	if x != nil && x.TimeoutMsec != nil {
+		return *x.TimeoutMsec
+	}
+	return 0
+}
+
+type isStep_Send interface {
+	isStep_Send()
+}
+
+type Step_SendBytes struct {
+	// Bytes to send as is. Use escape sequences for binary data, e.g.
+	// "\x00\x01".
+	SendBytes []byte `protobuf:"bytes,2,opt,name=send_bytes,json=sendBytes,oneof"`
+}
+
+type Step_SendLine struct {
+	// Line to send, "\r\n" is appended to it.
+	SendLine string `protobuf:"bytes,3,opt,name=send_line,json=sendLine,oneof"`
+}
+
+func (*Step_SendBytes) isStep_Send() {}
+
+func (*Step_SendLine) isStep_Send() {}
+
+type isStep_Expect interface {
+	isStep_Expect()
+}
+
+type Step_ExpectRegex struct {
+	// Regex that the received data should match, e.g. "^SSH-2\\.0-".
+	ExpectRegex string `protobuf:"bytes,4,opt,name=expect_regex,json=expectRegex,oneof"`
+}
+
+type Step_ExpectPrefix struct {
+	// Exact prefix that the received data should start with, e.g. "+PONG".
+	ExpectPrefix string `protobuf:"bytes,5,opt,name=expect_prefix,json=expectPrefix,oneof"`
+}
+
+func (*Step_ExpectRegex) isStep_Expect() {}
+
+func (*Step_ExpectPrefix) isStep_Expect() {}
+
+// Next tag: 9
 type ProbeConf struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Port for TCP requests. If not specfied, and port is provided by the
@@ -34,6 +270,16 @@ type ProbeConf struct {
 	TlsHandshake *bool `protobuf:"varint,2,opt,name=tls_handshake,json=tlsHandshake,def=0" json:"tls_handshake,omitempty"`
 	// TLS configuration for TLS handshake.
 	TlsConfig *proto.TLSConfig `protobuf:"bytes,3,opt,name=tls_config,json=tlsConfig" json:"tls_config,omitempty"`
+	// If set, probe negotiates the TLS upgrade using the given protocol, before
+	// doing the TLS handshake. It implies tls_handshake. Time taken by the
+	// negotiation is exported as "starttls_latency". To check certificates
+	// presented after the upgrade (e.g. their expiry), use tls_inspect.
+	Starttls *ProbeConf_StartTLS `protobuf:"varint,8,opt,name=starttls,enum=cloudprober.probes.tcp.ProbeConf_StartTLS,def=0" json:"starttls,omitempty"`
+	// Inspect the TLS connection and server certificates, and export TLS
+	// version, cipher, SAN match, chain validity, OCSP stapling and per
+	// certificate expiry metrics. See TLSInspectConf for the details. It
+	// implies tls_handshake.
+	TlsInspect *proto1.TLSInspectConf `protobuf:"bytes,6,opt,name=tls_inspect,json=tlsInspect" json:"tls_inspect,omitempty"`
 	// Whether to resolve the target before making the request. If set to false,
 	// we hand over the target golang's net.Dial module, Otherwise, we resolve
 	// the target first to an IP address and make a request using that. By
@@ -42,19 +288,38 @@ type ProbeConf struct {
 	ResolveFirst *bool `protobuf:"varint,4,opt,name=resolve_first,json=resolveFirst" json:"resolve_first,omitempty"`
 	// Interval between targets.
 	IntervalBetweenTargetsMsec *int32 `protobuf:"varint,5,opt,name=interval_between_targets_msec,json=intervalBetweenTargetsMsec,def=10" json:"interval_between_targets_msec,omitempty"`
-	unknownFields              protoimpl.UnknownFields
-	sizeCache                  protoimpl.SizeCache
+	// Send/expect steps to run after the connection is established (and TLS
+	// handshake is done, if enabled), e.g. to check SSH/SMTP banners or a
+	// Redis PING. Steps run in order, and the first failing step fails the
+	// probe. Probe level validators are applied to all the data received from
+	// the target during the conversation, and can only be used with steps.
+	// Data received after an expectation's match is kept for the next steps.
+	//
+	// Example:
+	//
+	//	step {
+	//	  name: "ping"
+	//	  send_line: "PING"
+	//	  expect_prefix: "+PONG"
+	//	}
+	//
+	// Per-step total, success and latency metrics are exported with the
+	// "step" label.
+	Step          []*Step `protobuf:"bytes,7,rep,name=step" json:"step,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 // Default values for ProbeConf fields.
 const (
 	Default_ProbeConf_TlsHandshake               = bool(false)
+	Default_ProbeConf_Starttls                   = ProbeConf_NONE
 	Default_ProbeConf_IntervalBetweenTargetsMsec = int32(10)
 )
 
 func (x *ProbeConf) Reset() {
 	*x = ProbeConf{}
-	mi := &file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_msgTypes[0]
+	mi := &file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -66,7 +331,7 @@ func (x *ProbeConf) String() string {
 func (*ProbeConf) ProtoMessage() {}
 
 func (x *ProbeConf) ProtoReflect() protoreflect.Message {
-	mi := &file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_msgTypes[0]
+	mi := &file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -79,7 +344,7 @@ func (x *ProbeConf) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProbeConf.ProtoReflect.Descriptor instead.
 func (*ProbeConf) Descriptor() ([]byte, []int) {
-	return file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_rawDescGZIP(), []int{0}
+	return file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *ProbeConf) GetPort() int32 {
@@ -103,6 +368,20 @@ func (x *ProbeConf) GetTlsConfig() *proto.TLSConfig {
 	return nil
 }
 
+func (x *ProbeConf) GetStarttls() ProbeConf_StartTLS {
+	if x != nil && x.Starttls != nil {
+		return *x.Starttls
+	}
+	return Default_ProbeConf_Starttls
+}
+
+func (x *ProbeConf) GetTlsInspect() *proto1.TLSInspectConf {
+	if x != nil {
+		return x.TlsInspect
+	}
+	return nil
+}
+
 func (x *ProbeConf) GetResolveFirst() bool {
 	if x != nil && x.ResolveFirst != nil {
 		return *x.ResolveFirst
@@ -117,18 +396,48 @@ func (x *ProbeConf) GetIntervalBetweenTargetsMsec() int32 {
 	return Default_ProbeConf_IntervalBetweenTargetsMsec
 }
 
+func (x *ProbeConf) GetStep() []*Step {
+	if x != nil {
+		return x.Step
+	}
+	return nil
+}
+
 var File_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto protoreflect.FileDescriptor
 
 const file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_rawDesc = "" +
 	"\n" +
-	"@github.com/cloudprober/cloudprober/probes/tcp/proto/config.proto\x12\x16cloudprober.probes.tcp\x1aFgithub.com/cloudprober/cloudprober/common/tlsconfig/proto/config.proto\"\xf8\x01\n" +
+	"@github.com/cloudprober/cloudprober/probes/tcp/proto/config.proto\x12\x16cloudprober.probes.tcp\x1aFgithub.com/cloudprober/cloudprober/common/tlsconfig/proto/config.proto\x1aNgithub.com/cloudprober/cloudprober/probes/common/tlsinspect/proto/config.proto\"\xdb\x01\n" +
+	"\x04Step\x12\x12\n" +
+	"\x04name\x18\x01 \x02(\tR\x04name\x12\x1f\n" +
+	"\n" +
+	"send_bytes\x18\x02 \x01(\fH\x00R\tsendBytes\x12\x1d\n" +
+	"\tsend_line\x18\x03 \x01(\tH\x00R\bsendLine\x12#\n" +
+	"\fexpect_regex\x18\x04 \x01(\tH\x01R\vexpectRegex\x12%\n" +
+	"\rexpect_prefix\x18\x05 \x01(\tH\x01R\fexpectPrefix\x12!\n" +
+	"\ftimeout_msec\x18\x06 \x01(\x05R\vtimeoutMsecB\x06\n" +
+	"\x04sendB\b\n" +
+	"\x06expect\"\xa9\x04\n" +
 	"\tProbeConf\x12\x12\n" +
 	"\x04port\x18\x01 \x01(\x05R\x04port\x12*\n" +
 	"\rtls_handshake\x18\x02 \x01(\b:\x05falseR\ftlsHandshake\x12?\n" +
 	"\n" +
-	"tls_config\x18\x03 \x01(\v2 .cloudprober.tlsconfig.TLSConfigR\ttlsConfig\x12#\n" +
+	"tls_config\x18\x03 \x01(\v2 .cloudprober.tlsconfig.TLSConfigR\ttlsConfig\x12L\n" +
+	"\bstarttls\x18\b \x01(\x0e2*.cloudprober.probes.tcp.ProbeConf.StartTLS:\x04NONER\bstarttls\x12N\n" +
+	"\vtls_inspect\x18\x06 \x01(\v2-.cloudprober.probes.tlsinspect.TLSInspectConfR\n" +
+	"tlsInspect\x12#\n" +
 	"\rresolve_first\x18\x04 \x01(\bR\fresolveFirst\x12E\n" +
-	"\x1dinterval_between_targets_msec\x18\x05 \x01(\x05:\x0210R\x1aintervalBetweenTargetsMsecB5Z3github.com/cloudprober/cloudprober/probes/tcp/proto"
+	"\x1dinterval_between_targets_msec\x18\x05 \x01(\x05:\x0210R\x1aintervalBetweenTargetsMsec\x120\n" +
+	"\x04step\x18\a \x03(\v2\x1c.cloudprober.probes.tcp.StepR\x04step\"_\n" +
+	"\bStartTLS\x12\b\n" +
+	"\x04NONE\x10\x00\x12\b\n" +
+	"\x04SMTP\x10\x01\x12\b\n" +
+	"\x04IMAP\x10\x02\x12\b\n" +
+	"\x04POP3\x10\x03\x12\b\n" +
+	"\x04LDAP\x10\x04\x12\f\n" +
+	"\bPOSTGRES\x10\x05\x12\t\n" +
+	"\x05MYSQL\x10\x06\x12\b\n" +
+	"\x04XMPP\x10\aB5Z3github.com/cloudprober/cloudprober/probes/tcp/proto"
 
 var (
 	file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_rawDescOnce sync.Once
@@ -142,18 +451,25 @@ func file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_rawDe
 	return file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_rawDescData
 }
 
-var file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_goTypes = []any{
-	(*ProbeConf)(nil),       // 0: cloudprober.probes.tcp.ProbeConf
-	(*proto.TLSConfig)(nil), // 1: cloudprober.tlsconfig.TLSConfig
+	(ProbeConf_StartTLS)(0),       // 0: cloudprober.probes.tcp.ProbeConf.StartTLS
+	(*Step)(nil),                  // 1: cloudprober.probes.tcp.Step
+	(*ProbeConf)(nil),             // 2: cloudprober.probes.tcp.ProbeConf
+	(*proto.TLSConfig)(nil),       // 3: cloudprober.tlsconfig.TLSConfig
+	(*proto1.TLSInspectConf)(nil), // 4: cloudprober.probes.tlsinspect.TLSInspectConf
 }
 var file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_depIdxs = []int32{
-	1, // 0: cloudprober.probes.tcp.ProbeConf.tls_config:type_name -> cloudprober.tlsconfig.TLSConfig
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	3, // 0: cloudprober.probes.tcp.ProbeConf.tls_config:type_name -> cloudprober.tlsconfig.TLSConfig
+	0, // 1: cloudprober.probes.tcp.ProbeConf.starttls:type_name -> cloudprober.probes.tcp.ProbeConf.StartTLS
+	4, // 2: cloudprober.probes.tcp.ProbeConf.tls_inspect:type_name -> cloudprober.probes.tlsinspect.TLSInspectConf
+	1, // 3: cloudprober.probes.tcp.ProbeConf.step:type_name -> cloudprober.probes.tcp.Step
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_init() }
@@ -161,18 +477,25 @@ func file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_init(
 	if File_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto != nil {
 		return
 	}
+	file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_msgTypes[0].OneofWrappers = []any{
+		(*Step_SendBytes)(nil),
+		(*Step_SendLine)(nil),
+		(*Step_ExpectRegex)(nil),
+		(*Step_ExpectPrefix)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_rawDesc), len(file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   1,
+			NumEnums:      1,
+			NumMessages:   2,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_goTypes,
 		DependencyIndexes: file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_depIdxs,
+		EnumInfos:         file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_enumTypes,
 		MessageInfos:      file_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto_msgTypes,
 	}.Build()
 	File_github_com_cloudprober_cloudprober_probes_tcp_proto_config_proto = out.File
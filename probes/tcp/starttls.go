@@ -0,0 +1,380 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+
+	configpb "github.com/cloudprober/cloudprober/probes/tcp/proto"
+)
+
+// maxNegotiationBytes limits the data that we read from the server during
+// the STARTTLS negotiation.
+const maxNegotiationBytes = 64 * 1024
+
+// starttls negotiates the TLS upgrade on the connection. Once it returns
+// successfully, the server is waiting for the TLS handshake. Server name is
+// used only by the protocols that need it.
+//
+// Note that servers don't send anything after accepting the upgrade, so it's
+// safe to use buffered readers here.
+func starttls(conn net.Conn, protocol configpb.ProbeConf_StartTLS, serverName string) error {
+	br := bufio.NewReader(io.LimitReader(conn, maxNegotiationBytes))
+
+	switch protocol {
+	case configpb.ProbeConf_SMTP:
+		return starttlsSMTP(conn, br)
+	case configpb.ProbeConf_IMAP:
+		return starttlsIMAP(conn, br)
+	case configpb.ProbeConf_POP3:
+		return starttlsPOP3(conn, br)
+	case configpb.ProbeConf_LDAP:
+		return starttlsLDAP(conn, br)
+	case configpb.ProbeConf_POSTGRES:
+		return starttlsPostgres(conn, br)
+	case configpb.ProbeConf_MYSQL:
+		return starttlsMySQL(conn, br)
+	case configpb.ProbeConf_XMPP:
+		return starttlsXMPP(conn, br, serverName)
+	}
+	return fmt.Errorf("unsupported STARTTLS protocol: %v", protocol)
+}
+
+func readLine(br *bufio.Reader) (string, error) {
+	line, err := br.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// readSMTPReply reads a (possibly multi-line) SMTP reply, and returns the
+// reply code and the text lines.
+func readSMTPReply(br *bufio.Reader) (string, []string, error) {
+	var lines []string
+	for {
+		line, err := readLine(br)
+		if err != nil {
+			return "", nil, err
+		}
+		if len(line) < 3 {
+			return "", nil, fmt.Errorf("malformed SMTP reply: %q", line)
+		}
+		lines = append(lines, line)
+		// Lines of a multi-line reply, except the last one, have a "-" after
+		// the code.
+		if len(line) == 3 || line[3] != '-' {
+			return line[:3], lines, nil
+		}
+	}
+}
+
+// ehloDomain returns the address literal for the connection's local address,
+// to be used as the EHLO domain.
+func ehloDomain(conn net.Conn) string {
+	addr, ok := conn.LocalAddr().(*net.TCPAddr)
+	if !ok {
+		return "[127.0.0.1]"
+	}
+	if addr.IP.To4() != nil {
+		return "[" + addr.IP.String() + "]"
+	}
+	return "[IPv6:" + addr.IP.String() + "]"
+}
+
+func starttlsSMTP(conn net.Conn, br *bufio.Reader) error {
+	if code, lines, err := readSMTPReply(br); err != nil || code != "220" {
+		return fmt.Errorf("SMTP: unexpected greeting: %v, error: %v", lines, err)
+	}
+
+	if _, err := fmt.Fprintf(conn, "EHLO %s\r\n", ehloDomain(conn)); err != nil {
+		return err
+	}
+	code, lines, err := readSMTPReply(br)
+	if err != nil || code != "250" {
+		return fmt.Errorf("SMTP: unexpected EHLO reply: %v, error: %v", lines, err)
+	}
+	supported := false
+	for _, line := range lines {
+		if len(line) > 4 && strings.EqualFold(strings.TrimSpace(line[4:]), "STARTTLS") {
+			supported = true
+		}
+	}
+	if !supported {
+		return fmt.Errorf("SMTP: server doesn't support STARTTLS, EHLO reply: %v", lines)
+	}
+
+	if _, err := conn.Write([]byte("STARTTLS\r\n")); err != nil {
+		return err
+	}
+	if code, lines, err := readSMTPReply(br); err != nil || code != "220" {
+		return fmt.Errorf("SMTP: unexpected STARTTLS reply: %v, error: %v", lines, err)
+	}
+	return nil
+}
+
+func starttlsIMAP(conn net.Conn, br *bufio.Reader) error {
+	greeting, err := readLine(br)
+	if err != nil || !strings.HasPrefix(greeting, "* OK") {
+		return fmt.Errorf("IMAP: unexpected greeting: %q, error: %v", greeting, err)
+	}
+
+	if _, err := conn.Write([]byte("a001 STARTTLS\r\n")); err != nil {
+		return err
+	}
+	// Skip untagged responses.
+	for {
+		line, err := readLine(br)
+		if err != nil {
+			return fmt.Errorf("IMAP: error reading STARTTLS response: %v", err)
+		}
+		if strings.HasPrefix(line, "a001 ") {
+			if !strings.HasPrefix(line, "a001 OK") {
+				return fmt.Errorf("IMAP: unexpected STARTTLS response: %q", line)
+			}
+			return nil
+		}
+	}
+}
+
+func starttlsPOP3(conn net.Conn, br *bufio.Reader) error {
+	greeting, err := readLine(br)
+	if err != nil || !strings.HasPrefix(greeting, "+OK") {
+		return fmt.Errorf("POP3: unexpected greeting: %q, error: %v", greeting, err)
+	}
+
+	if _, err := conn.Write([]byte("STLS\r\n")); err != nil {
+		return err
+	}
+	resp, err := readLine(br)
+	if err != nil || !strings.HasPrefix(resp, "+OK") {
+		return fmt.Errorf("POP3: unexpected STLS response: %q, error: %v", resp, err)
+	}
+	return nil
+}
+
+// LDAP StartTLS extended operation (RFC 4511, section 4.14).
+const ldapStartTLSOID = "1.3.6.1.4.1.1466.20037"
+
+// BER tags used in the LDAP messages.
+const (
+	berSequence         = 0x30
+	berInteger          = 0x02
+	berEnumerated       = 0x0a
+	ldapExtendedRequest = 0x77 // [APPLICATION 23], constructed
+	ldapExtendedResp    = 0x78 // [APPLICATION 24], constructed
+	ldapRequestName     = 0x80 // [0], primitive
+)
+
+// berTLV encodes a BER TLV, only supporting the short form length.
+func berTLV(tag byte, value []byte) []byte {
+	return append([]byte{tag, byte(len(value))}, value...)
+}
+
+// readBERTLV reads a BER TLV, supporting both short and long form lengths.
+func readBERTLV(r io.Reader) (tag byte, value []byte, err error) {
+	var h [2]byte
+	if _, err := io.ReadFull(r, h[:]); err != nil {
+		return 0, nil, err
+	}
+	tag, length := h[0], int(h[1])
+	if length&0x80 != 0 {
+		n := length & 0x7f
+		if n == 0 || n > 4 {
+			return 0, nil, fmt.Errorf("unsupported BER length encoding: 0x%x", h[1])
+		}
+		b := make([]byte, n)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return 0, nil, err
+		}
+		length = 0
+		for _, c := range b {
+			length = length<<8 | int(c)
+		}
+	}
+	if length > maxNegotiationBytes {
+		return 0, nil, fmt.Errorf("BER value too large: %d", length)
+	}
+	value = make([]byte, length)
+	_, err = io.ReadFull(r, value)
+	return tag, value, err
+}
+
+func starttlsLDAP(conn net.Conn, br *bufio.Reader) error {
+	req := berTLV(berSequence, bytes.Join([][]byte{
+		berTLV(berInteger, []byte{1}), // Message ID
+		berTLV(ldapExtendedRequest, berTLV(ldapRequestName, []byte(ldapStartTLSOID))),
+	}, nil))
+	if _, err := conn.Write(req); err != nil {
+		return err
+	}
+
+	tag, msg, err := readBERTLV(br)
+	if err != nil || tag != berSequence {
+		return fmt.Errorf("LDAP: error reading the response message (tag: 0x%x): %v", tag, err)
+	}
+	r := bytes.NewReader(msg)
+	if tag, _, err := readBERTLV(r); err != nil || tag != berInteger {
+		return fmt.Errorf("LDAP: error reading the message ID (tag: 0x%x): %v", tag, err)
+	}
+	tag, op, err := readBERTLV(r)
+	if err != nil || tag != ldapExtendedResp {
+		return fmt.Errorf("LDAP: unexpected response operation (tag: 0x%x): %v", tag, err)
+	}
+	tag, resultCode, err := readBERTLV(bytes.NewReader(op))
+	if err != nil || tag != berEnumerated || len(resultCode) != 1 {
+		return fmt.Errorf("LDAP: error reading the result code (tag: 0x%x): %v", tag, err)
+	}
+	if resultCode[0] != 0 {
+		return fmt.Errorf("LDAP: StartTLS failed, result code: %d", resultCode[0])
+	}
+	return nil
+}
+
+// PostgreSQL SSLRequest code (PostgreSQL docs, section 55.2.10).
+const postgresSSLRequestCode = 80877103
+
+func starttlsPostgres(conn net.Conn, br *bufio.Reader) error {
+	req := binary.BigEndian.AppendUint32(nil, 8)
+	req = binary.BigEndian.AppendUint32(req, postgresSSLRequestCode)
+	if _, err := conn.Write(req); err != nil {
+		return err
+	}
+
+	resp, err := br.ReadByte()
+	if err != nil {
+		return fmt.Errorf("POSTGRES: error reading SSLRequest response: %v", err)
+	}
+	if resp != 'S' {
+		return fmt.Errorf("POSTGRES: server doesn't support SSL, response: %q", resp)
+	}
+	return nil
+}
+
+// MySQL capability flags.
+const (
+	mysqlClientLongPassword     = 0x00000001
+	mysqlClientProtocol41       = 0x00000200
+	mysqlClientSSL              = 0x00000800
+	mysqlClientSecureConnection = 0x00008000
+)
+
+func readMySQLPacket(r io.Reader) (seq byte, payload []byte, err error) {
+	var h [4]byte
+	if _, err := io.ReadFull(r, h[:]); err != nil {
+		return 0, nil, err
+	}
+	length := int(h[0]) | int(h[1])<<8 | int(h[2])<<16
+	payload = make([]byte, length)
+	_, err = io.ReadFull(r, payload)
+	return h[3], payload, err
+}
+
+func starttlsMySQL(conn net.Conn, br *bufio.Reader) error {
+	seq, hs, err := readMySQLPacket(br)
+	if err != nil {
+		return fmt.Errorf("MYSQL: error reading the initial handshake: %v", err)
+	}
+	if len(hs) == 0 {
+		return errors.New("MYSQL: empty initial handshake")
+	}
+	if hs[0] == 0xff {
+		return fmt.Errorf("MYSQL: server returned error: %q", hs[1:])
+	}
+	if hs[0] != 10 {
+		return fmt.Errorf("MYSQL: unsupported protocol version: %d", hs[0])
+	}
+
+	// Initial handshake: protocol version (1), server version (NUL
+	// terminated), connection ID (4), auth plugin data (8), filler (1),
+	// capability flags, lower 2 bytes.
+	i := bytes.IndexByte(hs[1:], 0)
+	if i < 0 || len(hs) < 1+i+1+4+8+1+2 {
+		return errors.New("MYSQL: malformed initial handshake")
+	}
+	capsOffset := 1 + i + 1 + 4 + 8 + 1
+	if caps := binary.LittleEndian.Uint16(hs[capsOffset:]); caps&mysqlClientSSL == 0 {
+		return errors.New("MYSQL: server doesn't support SSL")
+	}
+
+	// SSLRequest packet: capability flags (4), max packet size (4), character
+	// set (1), filler (23).
+	payload := binary.LittleEndian.AppendUint32(nil, mysqlClientLongPassword|mysqlClientProtocol41|mysqlClientSSL|mysqlClientSecureConnection)
+	payload = binary.LittleEndian.AppendUint32(payload, 1<<24)
+	payload = append(payload, 45) // utf8mb4_general_ci
+	payload = append(payload, make([]byte, 23)...)
+
+	pkt := []byte{byte(len(payload)), 0, 0, seq + 1}
+	if _, err := conn.Write(append(pkt, payload...)); err != nil {
+		return err
+	}
+	return nil
+}
+
+func starttlsXMPP(conn net.Conn, br *bufio.Reader, serverName string) error {
+	if serverName == "" {
+		return errors.New("XMPP: server name is required")
+	}
+
+	// readUntil reads until one of the given markers is found, and returns the
+	// matching marker.
+	var buf []byte
+	readUntil := func(markers ...string) (string, error) {
+		for {
+			for _, m := range markers {
+				if bytes.Contains(buf, []byte(m)) {
+					return m, nil
+				}
+			}
+			b, err := br.ReadByte()
+			if err != nil {
+				return "", fmt.Errorf("XMPP: error reading the stream: %v, received: %q", err, buf)
+			}
+			buf = append(buf, b)
+		}
+	}
+
+	if _, err := fmt.Fprintf(conn, "<?xml version='1.0'?><stream:stream to='%s' xmlns='jabber:client' xmlns:stream='http://etherx.jabber.org/streams' version='1.0'>", serverName); err != nil {
+		return err
+	}
+	if _, err := readUntil("</stream:features>"); err != nil {
+		return err
+	}
+	if !bytes.Contains(buf, []byte("<starttls")) {
+		return fmt.Errorf("XMPP: server doesn't support STARTTLS, features: %q", buf)
+	}
+
+	buf = nil
+	if _, err := conn.Write([]byte("<starttls xmlns='urn:ietf:params:xml:ns:xmpp-tls'/>")); err != nil {
+		return err
+	}
+	m, err := readUntil("<proceed", "<failure")
+	if err != nil {
+		return err
+	}
+	if m != "<proceed" {
+		return fmt.Errorf("XMPP: STARTTLS failed: %q", buf)
+	}
+	// Read the rest of the proceed element.
+	_, err = readUntil("/>", "</proceed>")
+	return err
+}
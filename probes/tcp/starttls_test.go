@@ -0,0 +1,317 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tlsconfigpb "github.com/cloudprober/cloudprober/common/tlsconfig/proto"
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/probes/common/sched"
+	"github.com/cloudprober/cloudprober/probes/options"
+	configpb "github.com/cloudprober/cloudprober/probes/tcp/proto"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// Server side of the STARTTLS negotiation. It returns true if the client
+// should proceed with the TLS handshake.
+type starttlsServerFunc func(conn net.Conn, br *bufio.Reader) bool
+
+func smtpServer(advertise bool) starttlsServerFunc {
+	return func(conn net.Conn, br *bufio.Reader) bool {
+		conn.Write([]byte("220 mx.example.com ESMTP\r\n"))
+		if line, _ := br.ReadString('\n'); !strings.HasPrefix(line, "EHLO [") {
+			return false
+		}
+		conn.Write([]byte("250-mx.example.com\r\n250-PIPELINING\r\n"))
+		if advertise {
+			conn.Write([]byte("250-STARTTLS\r\n"))
+		}
+		conn.Write([]byte("250 8BITMIME\r\n"))
+		if line, _ := br.ReadString('\n'); line != "STARTTLS\r\n" {
+			conn.Write([]byte("502 command not implemented\r\n"))
+			return false
+		}
+		conn.Write([]byte("220 2.0.0 Ready to start TLS\r\n"))
+		return true
+	}
+}
+
+func imapServer(conn net.Conn, br *bufio.Reader) bool {
+	conn.Write([]byte("* OK IMAP4rev1 Service Ready\r\n"))
+	if line, _ := br.ReadString('\n'); line != "a001 STARTTLS\r\n" {
+		return false
+	}
+	conn.Write([]byte("* CAPABILITY IMAP4rev1\r\na001 OK Begin TLS negotiation now\r\n"))
+	return true
+}
+
+func pop3Server(conn net.Conn, br *bufio.Reader) bool {
+	conn.Write([]byte("+OK POP3 server ready\r\n"))
+	if line, _ := br.ReadString('\n'); line != "STLS\r\n" {
+		return false
+	}
+	conn.Write([]byte("+OK Begin TLS negotiation\r\n"))
+	return true
+}
+
+func ldapServer(resultCode byte) starttlsServerFunc {
+	return func(conn net.Conn, br *bufio.Reader) bool {
+		tag, msg, err := readBERTLV(br)
+		if err != nil || tag != berSequence || !bytes.Contains(msg, []byte(ldapStartTLSOID)) {
+			return false
+		}
+		op := append(berTLV(berEnumerated, []byte{resultCode}), berTLV(0x04, nil)...)
+		op = append(op, berTLV(0x04, nil)...)
+		conn.Write(berTLV(berSequence, append(berTLV(berInteger, []byte{1}), berTLV(ldapExtendedResp, op)...)))
+		return resultCode == 0
+	}
+}
+
+func postgresServer(resp byte) starttlsServerFunc {
+	return func(conn net.Conn, br *bufio.Reader) bool {
+		req := make([]byte, 8)
+		if _, err := io.ReadFull(br, req); err != nil || binary.BigEndian.Uint32(req[4:]) != postgresSSLRequestCode {
+			return false
+		}
+		conn.Write([]byte{resp})
+		return resp == 'S'
+	}
+}
+
+func mysqlServer(caps uint16) starttlsServerFunc {
+	return func(conn net.Conn, br *bufio.Reader) bool {
+		hs := append([]byte{10}, []byte("8.0.36\x00")...)
+		hs = append(hs, 1, 0, 0, 0)            // Connection ID
+		hs = append(hs, []byte("abcdefgh")...) // Auth plugin data
+		hs = append(hs, 0)                     // Filler
+		hs = binary.LittleEndian.AppendUint16(hs, caps)
+		conn.Write(append([]byte{byte(len(hs)), 0, 0, 0}, hs...))
+
+		seq, payload, err := readMySQLPacket(br)
+		if err != nil || seq != 1 || len(payload) != 32 {
+			return false
+		}
+		return binary.LittleEndian.Uint32(payload)&mysqlClientSSL != 0
+	}
+}
+
+// xmppServer returns an XMPP server for the given domain.
+func xmppServer(domain string) starttlsServerFunc {
+	return func(conn net.Conn, br *bufio.Reader) bool {
+		var buf []byte
+		for !bytes.Contains(buf, []byte("version='1.0'>")) {
+			b, err := br.ReadByte()
+			if err != nil {
+				return false
+			}
+			buf = append(buf, b)
+		}
+		if !bytes.Contains(buf, []byte("to='"+domain+"'")) {
+			return false
+		}
+		conn.Write([]byte("<?xml version='1.0'?><stream:stream from='" + domain + "' id='1' version='1.0' xmlns='jabber:client' xmlns:stream='http://etherx.jabber.org/streams'>"))
+		conn.Write([]byte("<stream:features><starttls xmlns='urn:ietf:params:xml:ns:xmpp-tls'><required/></starttls></stream:features>"))
+
+		req := make([]byte, len("<starttls xmlns='urn:ietf:params:xml:ns:xmpp-tls'/>"))
+		if _, err := io.ReadFull(br, req); err != nil {
+			return false
+		}
+		conn.Write([]byte("<proceed xmlns='urn:ietf:params:xml:ns:xmpp-tls'/>"))
+		return true
+	}
+}
+
+type bufferedConn struct {
+	net.Conn
+	br *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) { return c.br.Read(b) }
+
+// startSTARTTLSServer starts a server that runs the given negotiation and then
+// the TLS handshake, and writes "hello" over TLS.
+func startSTARTTLSServer(t *testing.T, serverFunc starttlsServerFunc) endpoint.Endpoint {
+	t.Helper()
+
+	// Borrow test certificate from the httptest server.
+	ts := httptest.NewTLSServer(http.NotFoundHandler())
+	tlsConfig := ts.TLS.Clone()
+	ts.Close()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				conn.SetDeadline(time.Now().Add(5 * time.Second))
+				br := bufio.NewReader(conn)
+				if !serverFunc(conn, br) {
+					return
+				}
+				// MySQL clients start the TLS handshake without waiting for
+				// a response, so TLS handshake data may already be buffered.
+				tlsConn := tls.Server(&bufferedConn{Conn: conn, br: br}, tlsConfig)
+				if err := tlsConn.Handshake(); err != nil {
+					return
+				}
+				tlsConn.Write([]byte("hello"))
+			}()
+		}
+	}()
+
+	return endpoint.Endpoint{Name: "127.0.0.1", Port: ln.Addr().(*net.TCPAddr).Port}
+}
+
+func TestRunProbeStartTLS(t *testing.T) {
+	tests := []struct {
+		name        string
+		protocol    configpb.ProbeConf_StartTLS
+		serverFunc  starttlsServerFunc
+		wantSuccess bool
+	}{
+		{name: "smtp", protocol: configpb.ProbeConf_SMTP, serverFunc: smtpServer(true), wantSuccess: true},
+		{name: "smtp_not_advertised", protocol: configpb.ProbeConf_SMTP, serverFunc: smtpServer(false)},
+		{name: "imap", protocol: configpb.ProbeConf_IMAP, serverFunc: imapServer, wantSuccess: true},
+		{name: "pop3", protocol: configpb.ProbeConf_POP3, serverFunc: pop3Server, wantSuccess: true},
+		{name: "ldap", protocol: configpb.ProbeConf_LDAP, serverFunc: ldapServer(0), wantSuccess: true},
+		{name: "ldap_unavailable", protocol: configpb.ProbeConf_LDAP, serverFunc: ldapServer(52)},
+		{name: "postgres", protocol: configpb.ProbeConf_POSTGRES, serverFunc: postgresServer('S'), wantSuccess: true},
+		{name: "postgres_no_ssl", protocol: configpb.ProbeConf_POSTGRES, serverFunc: postgresServer('N')},
+		{name: "mysql", protocol: configpb.ProbeConf_MYSQL, serverFunc: mysqlServer(0xffff), wantSuccess: true},
+		{name: "mysql_no_ssl", protocol: configpb.ProbeConf_MYSQL, serverFunc: mysqlServer(0xffff &^ mysqlClientSSL)},
+		{name: "xmpp", protocol: configpb.ProbeConf_XMPP, serverFunc: xmppServer("example.com"), wantSuccess: true},
+		{name: "wrong_protocol", protocol: configpb.ProbeConf_POP3, serverFunc: imapServer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := startSTARTTLSServer(t, tt.serverFunc)
+
+			p := testStepsProbe(t, &configpb.ProbeConf{
+				Starttls: tt.protocol.Enum(),
+				TlsConfig: &tlsconfigpb.TLSConfig{
+					DisableCertValidation: proto.Bool(true),
+					ServerName:            proto.String("example.com"),
+				},
+				Step: []*configpb.Step{{
+					Name:   proto.String("hello"),
+					Expect: &configpb.Step_ExpectPrefix{ExpectPrefix: "hello"},
+				}},
+			}, nil)
+
+			runReq := &sched.RunProbeForTargetRequest{Target: target, LastRun: &sched.LastRunResult{}}
+			p.runProbe(context.Background(), runReq)
+			assert.Equal(t, tt.wantSuccess, runReq.LastRun.Success, "last run error: %v", runReq.LastRun.Error)
+
+			// Latencies are recorded only if negotiation succeeds.
+			em := runReq.Result.Metrics(time.Now(), 0, p.opts)[0]
+			assert.Equal(t, tt.wantSuccess, em.Metric("starttls_latency").(metrics.NumValue).Float64() > 0)
+			assert.Equal(t, tt.wantSuccess, em.Metric("tls_handshake_latency").(metrics.NumValue).Float64() > 0)
+		})
+	}
+}
+
+func TestRunProbeStartTLSMultipleTargets(t *testing.T) {
+	var targets []endpoint.Endpoint
+	for _, domain := range []string{"example.com", "other.test", "third.test"} {
+		ep := startSTARTTLSServer(t, xmppServer(domain))
+		targets = append(targets, endpoint.Endpoint{Name: domain, IP: net.ParseIP(ep.Name), Port: ep.Port})
+	}
+
+	p := testStepsProbe(t, &configpb.ProbeConf{
+		Starttls:  configpb.ProbeConf_XMPP.Enum(),
+		TlsConfig: &tlsconfigpb.TLSConfig{DisableCertValidation: proto.Bool(true)},
+		Step: []*configpb.Step{{
+			Name:   proto.String("hello"),
+			Expect: &configpb.Step_ExpectPrefix{ExpectPrefix: "hello"},
+		}},
+	}, nil)
+
+	var mu sync.Mutex
+	gotServerNames := make(map[string][]string) // Keyed by target address.
+	p.handshakeContext = func(ctx context.Context, nc net.Conn, tlsConfig *tls.Config) (net.Conn, tls.ConnectionState, error) {
+		mu.Lock()
+		gotServerNames[nc.RemoteAddr().String()] = append(gotServerNames[nc.RemoteAddr().String()], tlsConfig.ServerName)
+		mu.Unlock()
+		tlsConn := tls.Client(nc, tlsConfig)
+		err := tlsConn.HandshakeContext(ctx)
+		return tlsConn, tlsConn.ConnectionState(), err
+	}
+
+	// Probe all targets concurrently, a few times. XMPP stream's "to" domain
+	// and TLS SNI should always be the target's own name.
+	runReqs := make([]*sched.RunProbeForTargetRequest, len(targets))
+	for i := range targets {
+		runReqs[i] = &sched.RunProbeForTargetRequest{Target: targets[i], LastRun: &sched.LastRunResult{}}
+	}
+	for run := 0; run < 3; run++ {
+		var wg sync.WaitGroup
+		for _, runReq := range runReqs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				p.runProbe(context.Background(), runReq)
+			}()
+		}
+		wg.Wait()
+	}
+
+	for _, runReq := range runReqs {
+		result := runReq.Result.(*probeResult)
+		assert.Equal(t, int64(3), result.success, "target: %s, last run error: %v", runReq.Target.Name, runReq.LastRun.Error)
+
+		addr := net.JoinHostPort(runReq.Target.IP.String(), strconv.Itoa(runReq.Target.Port))
+		name := runReq.Target.Name
+		assert.Equal(t, []string{name, name, name}, gotServerNames[addr], "SNI for target: %s", name)
+	}
+}
+
+func TestInitStartTLS(t *testing.T) {
+	opts := options.DefaultOptions()
+	opts.ProbeConf = &configpb.ProbeConf{Starttls: configpb.ProbeConf_SMTP.Enum()}
+	p := &Probe{}
+	require.NoError(t, p.Init("test_tcp_starttls", opts))
+	assert.True(t, p.c.GetTlsHandshake(), "starttls should imply tls_handshake")
+
+	opts.ProbeConf = &configpb.ProbeConf{
+		Starttls:     configpb.ProbeConf_SMTP.Enum(),
+		TlsHandshake: proto.Bool(false),
+	}
+	assert.Error(t, (&Probe{}).Init("test_tcp_starttls", opts))
+}
@@ -0,0 +1,185 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"time"
+
+	"github.com/cloudprober/cloudprober/metrics"
+	configpb "github.com/cloudprober/cloudprober/probes/tcp/proto"
+)
+
+// maxReceivedBytes limits the data that we read from the target during a
+// conversation.
+const maxReceivedBytes = 1 << 20
+
+type step struct {
+	c       *configpb.Step
+	send    []byte
+	re      *regexp.Regexp
+	timeout time.Duration
+}
+
+type stepResult struct {
+	name           string
+	total, success int64
+	latency        metrics.LatencyValue
+}
+
+func (p *Probe) initSteps() error {
+	names := make(map[string]bool)
+	for _, sc := range p.c.GetStep() {
+		if sc.GetName() == "" {
+			return errors.New("step name is required")
+		}
+		if names[sc.GetName()] {
+			return fmt.Errorf("step %s is defined twice", sc.GetName())
+		}
+		names[sc.GetName()] = true
+
+		s := &step{
+			c:       sc,
+			timeout: time.Duration(sc.GetTimeoutMsec()) * time.Millisecond,
+		}
+		switch sc.GetSend().(type) {
+		case *configpb.Step_SendBytes:
+			s.send = sc.GetSendBytes()
+		case *configpb.Step_SendLine:
+			s.send = []byte(sc.GetSendLine() + "\r\n")
+		}
+
+		switch sc.GetExpect().(type) {
+		case *configpb.Step_ExpectRegex:
+			re, err := regexp.Compile(sc.GetExpectRegex())
+			if err != nil {
+				return fmt.Errorf("step %s: error compiling expect_regex: %v", sc.GetName(), err)
+			}
+			s.re = re
+		case *configpb.Step_ExpectPrefix:
+			if sc.GetExpectPrefix() == "" {
+				return fmt.Errorf("step %s: expect_prefix cannot be empty", sc.GetName())
+			}
+		case nil:
+			if sc.GetSend() == nil {
+				return fmt.Errorf("step %s: neither send nor expect is set", sc.GetName())
+			}
+		}
+		p.steps = append(p.steps, s)
+	}
+	return nil
+}
+
+// conversation keeps the state of a send/expect conversation.
+type conversation struct {
+	conn     net.Conn
+	deadline time.Time // Overall deadline.
+	received []byte    // All the data received so far.
+	pending  []byte    // Data received but not consumed by an expect yet.
+}
+
+// read reads more data from the connection.
+func (cv *conversation) read() error {
+	if len(cv.received) >= maxReceivedBytes {
+		return fmt.Errorf("received data exceeds the limit (%d bytes)", maxReceivedBytes)
+	}
+	buf := make([]byte, 4096)
+	n, err := cv.conn.Read(buf)
+	cv.received = append(cv.received, buf[:n]...)
+	cv.pending = append(cv.pending, buf[:n]...)
+	return err
+}
+
+// expect reads from the connection until step's expectation is met. Data
+// received after the match is kept for the next steps.
+func (cv *conversation) expect(s *step) error {
+	var matchEnd int
+	switch s.c.GetExpect().(type) {
+	case *configpb.Step_ExpectRegex:
+		loc := s.re.FindIndex(cv.pending)
+		for loc == nil {
+			err := cv.read()
+			if loc = s.re.FindIndex(cv.pending); loc == nil && err != nil {
+				return fmt.Errorf("expect_regex %q not matched, received: %q, error: %v", s.re.String(), cv.pending, err)
+			}
+		}
+		matchEnd = loc[1]
+	case *configpb.Step_ExpectPrefix:
+		prefix := []byte(s.c.GetExpectPrefix())
+		for len(cv.pending) < len(prefix) {
+			// Fail early if data received so far doesn't match the prefix.
+			if !bytes.HasPrefix(prefix, cv.pending) {
+				break
+			}
+			if err := cv.read(); err != nil && len(cv.pending) < len(prefix) {
+				return fmt.Errorf("expect_prefix %q not matched, received: %q, error: %v", prefix, cv.pending, err)
+			}
+		}
+		if !bytes.HasPrefix(cv.pending, prefix) {
+			return fmt.Errorf("expect_prefix %q not matched, received: %q", prefix, cv.pending)
+		}
+		matchEnd = len(prefix)
+	default:
+		return nil
+	}
+	cv.pending = cv.pending[matchEnd:]
+	return nil
+}
+
+func (cv *conversation) runStep(s *step) error {
+	deadline := cv.deadline
+	if s.timeout > 0 {
+		if stepDeadline := time.Now().Add(s.timeout); stepDeadline.Before(deadline) {
+			deadline = stepDeadline
+		}
+	}
+	if err := cv.conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+
+	if len(s.send) > 0 {
+		if _, err := cv.conn.Write(s.send); err != nil {
+			return fmt.Errorf("send error: %v", err)
+		}
+	}
+	return cv.expect(s)
+}
+
+// runSteps runs the configured steps on the connection and returns all the
+// data received from the target.
+func (p *Probe) runSteps(ctx context.Context, conn net.Conn, result *probeResult) ([]byte, error) {
+	cv := &conversation{conn: conn, deadline: time.Now().Add(p.opts.Timeout)}
+	if deadline, ok := ctx.Deadline(); ok {
+		cv.deadline = deadline
+	}
+
+	for i, s := range p.steps {
+		sr := result.steps[i]
+		sr.total++
+
+		start := time.Now()
+		if err := cv.runStep(s); err != nil {
+			return cv.received, fmt.Errorf("step %s: %v", s.c.GetName(), err)
+		}
+		sr.success++
+		sr.latency.AddFloat64(time.Since(start).Seconds() / p.opts.LatencyUnit.Seconds())
+	}
+	return cv.received, nil
+}
@@ -0,0 +1,283 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcp
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	tlsconfigpb "github.com/cloudprober/cloudprober/common/tlsconfig/proto"
+	"github.com/cloudprober/cloudprober/internal/validators"
+	validatorspb "github.com/cloudprober/cloudprober/internal/validators/proto"
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/probes/common/sched"
+	"github.com/cloudprober/cloudprober/probes/options"
+	configpb "github.com/cloudprober/cloudprober/probes/tcp/proto"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// startLineServer starts a server that sends a banner and then replies to
+// "PING" with "+PONG". It ignores all other lines.
+func startLineServer(t *testing.T) endpoint.Endpoint {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				conn.Write([]byte("SSH-2.0-Test_1.0\r\n"))
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					if scanner.Text() == "PING" {
+						conn.Write([]byte("+PONG\r\n"))
+					}
+				}
+			}()
+		}
+	}()
+
+	return endpoint.Endpoint{Name: "127.0.0.1", Port: ln.Addr().(*net.TCPAddr).Port}
+}
+
+func testStepsProbe(t *testing.T, conf *configpb.ProbeConf, vs []*validatorspb.Validator) *Probe {
+	t.Helper()
+
+	opts := options.DefaultOptions()
+	opts.ProbeConf = conf
+	opts.Timeout = 2 * time.Second
+	opts.LatencyUnit = time.Millisecond
+	if vs != nil {
+		var err error
+		opts.Validators, err = validators.Init(vs)
+		require.NoError(t, err)
+	}
+
+	p := &Probe{}
+	require.NoError(t, p.Init("test_tcp_steps", opts))
+	return p
+}
+
+func TestRunProbeSteps(t *testing.T) {
+	target := startLineServer(t)
+
+	banner := &configpb.Step{
+		Name:   proto.String("banner"),
+		Expect: &configpb.Step_ExpectRegex{ExpectRegex: `^SSH-2\.0-\S+\r\n`},
+	}
+	ping := &configpb.Step{
+		Name:   proto.String("ping"),
+		Send:   &configpb.Step_SendLine{SendLine: "PING"},
+		Expect: &configpb.Step_ExpectPrefix{ExpectPrefix: "+PONG"},
+	}
+
+	tests := []struct {
+		name        string
+		steps       []*configpb.Step
+		validator   string
+		wantSuccess bool
+		wantStepSuc []int64
+		wantStepTot []int64
+	}{
+		{
+			name:        "success",
+			steps:       []*configpb.Step{banner, ping},
+			validator:   "Test_1.0",
+			wantSuccess: true,
+			wantStepSuc: []int64{1, 1},
+			wantStepTot: []int64{1, 1},
+		},
+		{
+			name: "send_bytes",
+			steps: []*configpb.Step{banner, {
+				Name:   proto.String("ping_bytes"),
+				Send:   &configpb.Step_SendBytes{SendBytes: []byte("PING\n")},
+				Expect: &configpb.Step_ExpectRegex{ExpectRegex: `PONG`},
+			}},
+			wantSuccess: true,
+			wantStepSuc: []int64{1, 1},
+			wantStepTot: []int64{1, 1},
+		},
+		{
+			name: "prefix_mismatch",
+			steps: []*configpb.Step{{
+				Name:   proto.String("smtp_banner"),
+				Expect: &configpb.Step_ExpectPrefix{ExpectPrefix: "220 "},
+			}, ping},
+			wantStepSuc: []int64{0, 0},
+			wantStepTot: []int64{1, 0},
+		},
+		{
+			name: "step_timeout",
+			steps: []*configpb.Step{banner, {
+				Name:        proto.String("no_reply"),
+				Send:        &configpb.Step_SendLine{SendLine: "HELLO"},
+				Expect:      &configpb.Step_ExpectPrefix{ExpectPrefix: "+OK"},
+				TimeoutMsec: proto.Int32(100),
+			}},
+			wantStepSuc: []int64{1, 0},
+			wantStepTot: []int64{1, 1},
+		},
+		{
+			name:        "validation_failure",
+			steps:       []*configpb.Step{banner, ping},
+			validator:   "OpenSSH",
+			wantStepSuc: []int64{1, 1},
+			wantStepTot: []int64{1, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var vs []*validatorspb.Validator
+			if tt.validator != "" {
+				vs = []*validatorspb.Validator{{Name: "banner_re", Type: &validatorspb.Validator_Regex{Regex: tt.validator}}}
+			}
+			p := testStepsProbe(t, &configpb.ProbeConf{Step: tt.steps}, vs)
+
+			runReq := &sched.RunProbeForTargetRequest{Target: target, LastRun: &sched.LastRunResult{}}
+			start := time.Now()
+			p.runProbe(context.Background(), runReq)
+			assert.Less(t, time.Since(start), time.Second)
+
+			assert.Equal(t, tt.wantSuccess, runReq.LastRun.Success, "last run error: %v", runReq.LastRun.Error)
+
+			ems := runReq.Result.Metrics(time.Now(), 0, p.opts)
+			require.Len(t, ems, 1+len(tt.steps))
+			for i, em := range ems[1:] {
+				assert.Equal(t, tt.steps[i].GetName(), em.Label("step"))
+				assert.False(t, em.IsForAlerting())
+				assert.Equal(t, tt.wantStepTot[i], em.Metric("total").(metrics.NumValue).Int64(), "step %d total", i)
+				assert.Equal(t, tt.wantStepSuc[i], em.Metric("success").(metrics.NumValue).Int64(), "step %d success", i)
+			}
+		})
+	}
+}
+
+func TestRunProbeStepsPipelined(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	// Server sends everything in a single write, so that data for multiple
+	// steps is received together.
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Write([]byte("220 ready\r\n250 ok\r\n+PONG\r\n"))
+			time.AfterFunc(time.Second, func() { conn.Close() })
+		}
+	}()
+	target := endpoint.Endpoint{Name: "127.0.0.1", Port: ln.Addr().(*net.TCPAddr).Port}
+
+	p := testStepsProbe(t, &configpb.ProbeConf{
+		Step: []*configpb.Step{
+			{Name: proto.String("greeting"), Expect: &configpb.Step_ExpectRegex{ExpectRegex: `220 .*\r\n`}},
+			{Name: proto.String("ok"), Expect: &configpb.Step_ExpectRegex{ExpectRegex: `^250 ok\r\n`}},
+			{Name: proto.String("pong"), Expect: &configpb.Step_ExpectPrefix{ExpectPrefix: "+PONG"}},
+		},
+	}, nil)
+
+	runReq := &sched.RunProbeForTargetRequest{Target: target, LastRun: &sched.LastRunResult{}}
+	start := time.Now()
+	p.runProbe(context.Background(), runReq)
+	assert.True(t, runReq.LastRun.Success, "last run error: %v", runReq.LastRun.Error)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestRunProbeStepsTLS(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	}))
+	defer ts.Close()
+	host, port, _ := net.SplitHostPort(ts.Listener.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+
+	p := testStepsProbe(t, &configpb.ProbeConf{
+		TlsConfig: &tlsconfigpb.TLSConfig{DisableCertValidation: proto.Bool(true)},
+		Step: []*configpb.Step{{
+			Name:   proto.String("http_get"),
+			Send:   &configpb.Step_SendBytes{SendBytes: []byte("GET / HTTP/1.0\r\n\r\n")},
+			Expect: &configpb.Step_ExpectRegex{ExpectRegex: `(?s)^HTTP/1\.0 200 OK.*hello`},
+		}},
+	}, nil)
+
+	runReq := &sched.RunProbeForTargetRequest{Target: endpoint.Endpoint{Name: host, Port: portNum}, LastRun: &sched.LastRunResult{}}
+	p.runProbe(context.Background(), runReq)
+	assert.True(t, runReq.LastRun.Success, "last run error: %v", runReq.LastRun.Error)
+}
+
+func TestInitStepsErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []*configpb.Step
+	}{
+		{
+			name: "duplicate_step",
+			steps: []*configpb.Step{
+				{Name: proto.String("s1"), Send: &configpb.Step_SendLine{SendLine: "a"}},
+				{Name: proto.String("s1"), Send: &configpb.Step_SendLine{SendLine: "b"}},
+			},
+		},
+		{
+			name:  "nothing_to_do",
+			steps: []*configpb.Step{{Name: proto.String("s1")}},
+		},
+		{
+			name:  "bad_regex",
+			steps: []*configpb.Step{{Name: proto.String("s1"), Expect: &configpb.Step_ExpectRegex{ExpectRegex: "("}}},
+		},
+		{
+			name:  "empty_prefix",
+			steps: []*configpb.Step{{Name: proto.String("s1"), Expect: &configpb.Step_ExpectPrefix{}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := options.DefaultOptions()
+			opts.ProbeConf = &configpb.ProbeConf{Step: tt.steps}
+			assert.Error(t, (&Probe{}).Init("test_tcp_steps", opts))
+		})
+	}
+
+	t.Run("validators_without_steps", func(t *testing.T) {
+		opts := options.DefaultOptions()
+		opts.ProbeConf = &configpb.ProbeConf{}
+		var err error
+		opts.Validators, err = validators.Init([]*validatorspb.Validator{{Name: "v", Type: &validatorspb.Validator_Regex{Regex: "OK"}}})
+		require.NoError(t, err)
+		assert.Error(t, (&Probe{}).Init("test_tcp_steps", opts))
+	})
+}
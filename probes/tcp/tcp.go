@@ -22,6 +22,7 @@ import (
 	"log/slog"
 	"net"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cloudprober/cloudprober/common/tlsconfig"
@@ -30,6 +31,7 @@ import (
 	"github.com/cloudprober/cloudprober/metrics"
 	"github.com/cloudprober/cloudprober/metrics/singlerun"
 	"github.com/cloudprober/cloudprober/probes/common/sched"
+	"github.com/cloudprober/cloudprober/probes/common/tlsinspect"
 	"github.com/cloudprober/cloudprober/probes/options"
 	configpb "github.com/cloudprober/cloudprober/probes/tcp/proto"
 	"google.golang.org/protobuf/proto"
@@ -46,7 +48,9 @@ type Probe struct {
 	network          string
 	tlsConfig        *tls.Config
 	dialContext      func(context.Context, string, string) (net.Conn, error) // Keeps some dialing related config
-	handshakeContext func(context.Context, net.Conn, *tls.Config) error
+	handshakeContext func(context.Context, net.Conn, *tls.Config) (net.Conn, tls.ConnectionState, error)
+	tlsInspector     *tlsinspect.Inspector
+	steps            []*step
 }
 
 type probeResult struct {
@@ -54,7 +58,10 @@ type probeResult struct {
 	latency             metrics.LatencyValue
 	connLatency         metrics.LatencyValue
 	tlsHandshakeLatency metrics.LatencyValue
+	starttlsLatency     metrics.LatencyValue
 	validationFailure   *metrics.Map[int64]
+	tlsInspection       *tlsinspect.Result
+	steps               []*stepResult
 }
 
 func (p *Probe) newResult() sched.ProbeResult {
@@ -70,6 +77,16 @@ func (p *Probe) newResult() sched.ProbeResult {
 		result.latency = metrics.NewFloat(0)
 	}
 
+	for _, s := range p.steps {
+		sr := &stepResult{name: s.c.GetName()}
+		if p.opts.LatencyDist != nil {
+			sr.latency = p.opts.LatencyDist.CloneDist()
+		} else {
+			sr.latency = metrics.NewFloat(0)
+		}
+		result.steps = append(result.steps, sr)
+	}
+
 	if p.c.GetTlsHandshake() {
 		if p.opts.LatencyDist != nil {
 			result.connLatency = p.opts.LatencyDist.CloneDist()
@@ -80,6 +97,14 @@ func (p *Probe) newResult() sched.ProbeResult {
 		}
 	}
 
+	if p.c.GetStarttls() != configpb.ProbeConf_NONE {
+		if p.opts.LatencyDist != nil {
+			result.starttlsLatency = p.opts.LatencyDist.CloneDist()
+		} else {
+			result.starttlsLatency = metrics.NewFloat(0)
+		}
+	}
+
 	return result
 }
 
@@ -96,11 +121,30 @@ func (result *probeResult) Metrics(ts time.Time, _ int64, opts *options.Options)
 		em.AddMetric("tls_handshake_latency", result.tlsHandshakeLatency.Clone())
 	}
 
+	if result.starttlsLatency != nil {
+		em.AddMetric("starttls_latency", result.starttlsLatency.Clone())
+	}
+
 	if result.validationFailure != nil {
 		em.AddMetric("validation_failure", result.validationFailure)
 	}
 
-	return []*metrics.EventMetrics{em}
+	ems := []*metrics.EventMetrics{em}
+
+	// Per-step metrics are not used for alerting.
+	for _, sr := range result.steps {
+		em := metrics.NewEventMetrics(ts).
+			AddMetric("total", metrics.NewInt(sr.total)).
+			AddMetric("success", metrics.NewInt(sr.success)).
+			AddMetric(opts.LatencyMetricName, sr.latency.Clone()).
+			AddLabel("ptype", "tcp").
+			AddLabel("step", sr.name)
+		em.SetNotForAlerting()
+		ems = append(ems, em)
+	}
+
+	// TLS inspection metrics are exported as independent GAUGE EMs.
+	return append(ems, result.tlsInspection.EventMetrics(ts, "tcp")...)
 }
 
 // Init initializes the probe with the given params.
@@ -124,6 +168,11 @@ func (p *Probe) Init(name string, opts *options.Options) error {
 		p.c = &configpb.ProbeConf{}
 	}
 
+	// In dual-stack mode, targets come with the IP to probe.
+	if p.opts.DualStack && p.c.ResolveFirst != nil && !p.c.GetResolveFirst() {
+		return fmt.Errorf("resolve_first cannot be false with dual_stack")
+	}
+
 	p.network = "tcp"
 	if p.opts.IPVersion != 0 {
 		p.network += strconv.Itoa(p.opts.IPVersion)
@@ -141,59 +190,131 @@ func (p *Probe) Init(name string, opts *options.Options) error {
 	}
 	p.dialContext = dialer.DialContext
 
-	if p.c.GetTlsConfig() != nil {
+	if p.c.GetTlsConfig() != nil || p.c.GetTlsInspect() != nil || p.c.GetStarttls() != configpb.ProbeConf_NONE {
 		if p.c.TlsHandshake == nil {
 			p.c.TlsHandshake = proto.Bool(true)
 		}
 
 		// tls_handshake is explicitly set to false, return error
 		if !p.c.GetTlsHandshake() {
-			return fmt.Errorf("tls_config is set, but tls_handshake is false")
+			return fmt.Errorf("tls_config, tls_inspect or starttls is set, but tls_handshake is false")
 		}
+	}
 
+	if p.c.GetTlsConfig() != nil {
 		p.tlsConfig = &tls.Config{}
 		if err := tlsconfig.UpdateTLSConfig(p.tlsConfig, p.c.GetTlsConfig()); err != nil {
 			return fmt.Errorf("tls_config error: %v", err)
 		}
 	}
 
+	if p.c.GetTlsInspect() != nil {
+		var err error
+		if p.tlsInspector, err = tlsinspect.New(p.c.GetTlsInspect()); err != nil {
+			return fmt.Errorf("tls_inspect config error: %v", err)
+		}
+	}
+
+	// Validators run on the data received during the steps, they are no-op
+	// without steps.
+	if p.opts.Validators != nil && len(p.c.GetStep()) == 0 {
+		return fmt.Errorf("validators are set, but no steps are configured")
+	}
+	if err := p.initSteps(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
-func (p *Probe) connectAndHandshake(ctx context.Context, addr, targetName string, result *probeResult) error {
+// tlsConfigForTarget returns the TLS config to use for a connection to the
+// given target. ServerName, which is required for the TLS handshake, defaults
+// to the target's name. Probe's TLS config is shared by all targets, so we
+// always return a copy.
+func (p *Probe) tlsConfigForTarget(targetName string) *tls.Config {
+	tlsConfig := &tls.Config{}
+	if p.tlsConfig != nil {
+		tlsConfig = p.tlsConfig.Clone()
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = targetName
+	}
+	return tlsConfig
+}
+
+// connectAndHandshake connects to the target, does the TLS handshake, if
+// enabled, and runs the send/expect steps, if configured. It returns the data
+// received from the target during the steps.
+func (p *Probe) connectAndHandshake(ctx context.Context, addr, targetName string, result *probeResult) ([]byte, error) {
 	start := time.Now()
 	conn, err := p.dialContext(ctx, p.network, addr)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer func() {
+		if conn != nil {
+			conn.Close()
+		}
+	}()
 
 	if p.c.GetTlsHandshake() {
 		result.connLatency.AddFloat64(time.Since(start).Seconds() / p.opts.LatencyUnit.Seconds())
 		start = time.Now()
 
-		// ServerName is required for TLS handshake
-		if p.tlsConfig == nil {
-			p.tlsConfig = &tls.Config{}
-		}
-		if p.tlsConfig.ServerName == "" {
-			p.tlsConfig.ServerName = targetName
+		tlsConfig := p.tlsConfigForTarget(targetName)
+
+		if p.c.GetStarttls() != configpb.ProbeConf_NONE {
+			if err := p.negotiateStartTLS(ctx, conn, tlsConfig.ServerName, result); err != nil {
+				return nil, err
+			}
+			start = time.Now()
 		}
 
 		if p.handshakeContext == nil {
-			p.handshakeContext = func(ctx context.Context, nc net.Conn, tlsConfig *tls.Config) error {
-				return tls.Client(nc, tlsConfig).HandshakeContext(ctx)
+			p.handshakeContext = func(ctx context.Context, nc net.Conn, tlsConfig *tls.Config) (net.Conn, tls.ConnectionState, error) {
+				tlsConn := tls.Client(nc, tlsConfig)
+				err := tlsConn.HandshakeContext(ctx)
+				return tlsConn, tlsConn.ConnectionState(), err
 			}
 		}
-		if err := p.handshakeContext(ctx, conn, p.tlsConfig); err != nil {
-			return err
+		tlsConn, cs, err := p.handshakeContext(ctx, conn, tlsConfig)
+		if err != nil {
+			return nil, err
 		}
+		conn = tlsConn
 		result.tlsHandshakeLatency.AddFloat64(time.Since(start).Seconds() / p.opts.LatencyUnit.Seconds())
+
+		if p.tlsInspector != nil {
+			result.tlsInspection = p.tlsInspector.Inspect(&cs, tlsConfig.ServerName, time.Now())
+		}
 	}
 
-	if conn != nil {
-		conn.Close()
+	if len(p.steps) > 0 {
+		return p.runSteps(ctx, conn, result)
 	}
-	return nil
+	return nil, nil
+}
+
+// negotiateStartTLS runs the STARTTLS negotiation on conn. serverName is the
+// connection's TLS server name (see tlsConfigForTarget); protocols that need a
+// domain, e.g. XMPP, use it as well.
+func (p *Probe) negotiateStartTLS(ctx context.Context, conn net.Conn, serverName string, result *probeResult) error {
+	start := time.Now()
+
+	deadline := start.Add(p.opts.Timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok {
+		deadline = ctxDeadline
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+	if err := starttls(conn, p.c.GetStarttls(), serverName); err != nil {
+		return err
+	}
+	result.starttlsLatency.AddFloat64(time.Since(start).Seconds() / p.opts.LatencyUnit.Seconds())
+
+	// Reset the deadline, TLS handshake uses the context.
+	return conn.SetDeadline(time.Time{})
 }
 
 func (p *Probe) runProbe(ctx context.Context, runReq *sched.RunProbeForTargetRequest) {
@@ -236,7 +357,7 @@ func (p *Probe) runProbe(ctx context.Context, runReq *sched.RunProbeForTargetReq
 	addr := net.JoinHostPort(host, strconv.Itoa(port))
 
 	start := time.Now()
-	err := p.connectAndHandshake(ctx, addr, target.Name, result)
+	received, err := p.connectAndHandshake(ctx, addr, target.Name, result)
 	latency := time.Since(start)
 
 	if p.opts.NegativeTest {
@@ -254,6 +375,17 @@ func (p *Probe) runProbe(ctx context.Context, runReq *sched.RunProbeForTargetReq
 		runReq.LastRun.Set(false, 0, err)
 		return
 	}
+
+	if len(p.steps) > 0 && p.opts.Validators != nil {
+		failedValidations := validators.RunValidators(p.opts.Validators, &validators.Input{ResponseBody: received}, result.validationFailure, l)
+		if len(failedValidations) > 0 {
+			err := fmt.Errorf("failed validations: %s", strings.Join(failedValidations, ","))
+			l.Error(err.Error())
+			runReq.LastRun.Set(false, 0, err)
+			return
+		}
+	}
+
 	result.success++
 	result.latency.AddFloat64(latency.Seconds() / p.opts.LatencyUnit.Seconds())
 
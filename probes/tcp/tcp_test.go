@@ -17,19 +17,27 @@ package tcp
 import (
 	"context"
 	"crypto/tls"
+	"encoding/pem"
 	"fmt"
 	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
 	"time"
 
+	tlsconfigpb "github.com/cloudprober/cloudprober/common/tlsconfig/proto"
 	"github.com/cloudprober/cloudprober/metrics"
 	"github.com/cloudprober/cloudprober/probes/common/sched"
+	tlsinspectpb "github.com/cloudprober/cloudprober/probes/common/tlsinspect/proto"
 	"github.com/cloudprober/cloudprober/probes/options"
 	configpb "github.com/cloudprober/cloudprober/probes/tcp/proto"
 	"github.com/cloudprober/cloudprober/targets/endpoint"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -122,6 +130,31 @@ func TestRunProbe(t *testing.T) {
 
 }
 
+func TestRunProbeDualStack(t *testing.T) {
+	opts := options.DefaultOptions()
+	opts.DualStack = true
+
+	p := &Probe{}
+	assert.Error(t, p.Init("test-probe", &options.Options{
+		DualStack: true,
+		ProbeConf: &configpb.ProbeConf{ResolveFirst: proto.Bool(false)},
+	}), "resolve_first=false should not be allowed with dual_stack")
+
+	assert.NoError(t, p.Init("test-probe", opts))
+
+	for _, ip := range []string{"192.0.2.1", "2001:db8::1"} {
+		ds := &dialState{}
+		p.dialContext = testDialContext(ds)
+
+		runReq := &sched.RunProbeForTargetRequest{Target: endpoint.Endpoint{Name: "test.com", IP: net.ParseIP(ip), Port: 80}}
+		p.runProbe(context.Background(), runReq)
+
+		assert.Equal(t, "tcp", ds.network)
+		assert.Equal(t, net.JoinHostPort(ip, "80"), ds.address)
+		assert.Equal(t, int64(1), runReq.Result.(*probeResult).success)
+	}
+}
+
 func TestConnectAndHandshake(t *testing.T) {
 	tests := []struct {
 		desc                   string
@@ -188,13 +221,13 @@ func TestConnectAndHandshake(t *testing.T) {
 				return nil, test.dialError
 			}
 
-			p.handshakeContext = func(ctx context.Context, _ net.Conn, tlsConfig *tls.Config) error {
+			p.handshakeContext = func(ctx context.Context, nc net.Conn, tlsConfig *tls.Config) (net.Conn, tls.ConnectionState, error) {
 				if tlsConfig.ServerName == "error.com" {
-					return fmt.Errorf("handshake error")
+					return nil, tls.ConnectionState{}, fmt.Errorf("handshake error")
 				}
 				assert.Equal(t, host, tlsConfig.ServerName)
 				time.Sleep(1 * time.Millisecond)
-				return nil
+				return nc, tls.ConnectionState{}, nil
 			}
 
 			result := &probeResult{
@@ -202,7 +235,7 @@ func TestConnectAndHandshake(t *testing.T) {
 				tlsHandshakeLatency: metrics.NewFloat(0),
 			}
 
-			err := p.connectAndHandshake(context.Background(), test.addr, host, result)
+			_, err := p.connectAndHandshake(context.Background(), test.addr, host, result)
 
 			if test.wantSuccess {
 				if err != nil {
@@ -314,3 +347,80 @@ func TestNewResultAndMetrics(t *testing.T) {
 		})
 	}
 }
+
+func TestTLSInspect(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	caBundle := filepath.Join(t.TempDir(), "ca.pem")
+	assert.NoError(t, os.WriteFile(caBundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw}), 0644))
+
+	opts := options.DefaultOptions()
+	opts.ProbeConf = &configpb.ProbeConf{
+		TlsHandshake: proto.Bool(false),
+		TlsInspect:   &tlsinspectpb.TLSInspectConf{},
+	}
+	assert.Error(t, (&Probe{}).Init("test", opts), "tls_inspect with tls_handshake false")
+
+	opts.ProbeConf = &configpb.ProbeConf{
+		TlsConfig:  &tlsconfigpb.TLSConfig{DisableCertValidation: proto.Bool(true)},
+		TlsInspect: &tlsinspectpb.TLSInspectConf{CaBundleFile: proto.String(caBundle)},
+	}
+	p := &Probe{}
+	assert.NoError(t, p.Init("test", opts))
+
+	result := p.newResult().(*probeResult)
+	addr := ts.Listener.Addr().String()
+	_, err := p.connectAndHandshake(context.Background(), addr, "example.com", result)
+	assert.NoError(t, err)
+
+	ems := result.Metrics(time.Now(), 0, p.opts)
+	assert.Len(t, ems, 3, "probe metrics, TLS metrics and leaf cert expiry")
+
+	em := ems[1]
+	assert.Equal(t, "TLS 1.3", em.Label("tls_version"))
+	assert.NotEmpty(t, em.Label("tls_cipher"))
+	assert.Equal(t, int64(1), em.Metric("tls_san_match").(metrics.NumValue).Int64())
+	assert.Equal(t, int64(1), em.Metric("tls_chain_valid").(metrics.NumValue).Int64())
+	assert.Equal(t, int64(0), em.Metric("tls_ocsp_stapled").(metrics.NumValue).Int64())
+	assert.Greater(t, ems[2].Metric("tls_cert_days_to_expiry").(metrics.NumValue).Int64(), int64(0))
+}
+
+func TestTLSInspectMultipleTargets(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	opts := options.DefaultOptions()
+	opts.ProbeConf = &configpb.ProbeConf{
+		TlsConfig:  &tlsconfigpb.TLSConfig{DisableCertValidation: proto.Bool(true)},
+		TlsInspect: &tlsinspectpb.TLSInspectConf{},
+	}
+	p := &Probe{}
+	require.NoError(t, p.Init("test", opts))
+
+	// httptest server's certificate is valid for example.com only.
+	for _, tt := range []struct {
+		target       string
+		wantSANMatch int64
+	}{
+		{target: "example.com", wantSANMatch: 1},
+		{target: "other.test", wantSANMatch: 0},
+		{target: "example.com", wantSANMatch: 1},
+	} {
+		var gotServerName string
+		p.handshakeContext = func(ctx context.Context, nc net.Conn, tlsConfig *tls.Config) (net.Conn, tls.ConnectionState, error) {
+			gotServerName = tlsConfig.ServerName
+			tlsConn := tls.Client(nc, tlsConfig)
+			err := tlsConn.HandshakeContext(ctx)
+			return tlsConn, tlsConn.ConnectionState(), err
+		}
+
+		result := p.newResult().(*probeResult)
+		_, err := p.connectAndHandshake(context.Background(), ts.Listener.Addr().String(), tt.target, result)
+		require.NoError(t, err)
+		assert.Equal(t, tt.target, gotServerName, "SNI")
+		assert.Equal(t, tt.wantSANMatch, result.Metrics(time.Now(), 0, p.opts)[1].Metric("tls_san_match").(metrics.NumValue).Int64(), "target: %s", tt.target)
+	}
+
+	assert.Empty(t, p.tlsConfig.ServerName, "probe's TLS config should not be modified")
+}
@@ -0,0 +1,264 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package websocket
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// WebSocket opcodes (RFC 6455, section 5.2).
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xa
+)
+
+// Close codes that are never sent on the wire, but are used to report the
+// close status (RFC 6455, section 7.4.1).
+const (
+	closeNoStatus = 1005
+	closeAbnormal = 1006
+)
+
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+func acceptKey(key string) string {
+	h := sha1.Sum([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// closeError is returned when peer closes the connection.
+type closeError struct {
+	code   int
+	reason string
+}
+
+func (ce *closeError) Error() string {
+	return fmt.Sprintf("connection closed by peer, code: %d, reason: %q", ce.code, ce.reason)
+}
+
+// conn is a minimal WebSocket connection. It supports everything that the
+// probe needs: text and binary messages, fragmentation, ping/pong and the
+// close handshake. Extensions (e.g. compression) are not supported.
+type conn struct {
+	nc             net.Conn
+	br             *bufio.Reader
+	client         bool // Frames sent by the client are masked.
+	maxMessageSize int
+}
+
+// handshake performs the client side of the WebSocket opening handshake on
+// nc, using req as the upgrade request.
+func handshake(nc net.Conn, req *http.Request, subprotocols []string, maxMessageSize int) (*conn, error) {
+	b := make([]byte, 16)
+	rand.Read(b)
+	key := base64.StdEncoding.EncodeToString(b)
+
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	if len(subprotocols) > 0 {
+		req.Header.Set("Sec-WebSocket-Protocol", strings.Join(subprotocols, ", "))
+	}
+
+	if err := req.Write(nc); err != nil {
+		return nil, fmt.Errorf("error writing upgrade request: %v", err)
+	}
+
+	br := bufio.NewReader(nc)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		return nil, fmt.Errorf("error reading upgrade response: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		return nil, fmt.Errorf("unexpected upgrade response status: %s", resp.Status)
+	}
+	if !strings.EqualFold(resp.Header.Get("Upgrade"), "websocket") {
+		return nil, fmt.Errorf("unexpected Upgrade header in response: %q", resp.Header.Get("Upgrade"))
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		return nil, errors.New("invalid Sec-WebSocket-Accept header in response")
+	}
+	if p := resp.Header.Get("Sec-WebSocket-Protocol"); p != "" && !slices.Contains(subprotocols, p) {
+		return nil, fmt.Errorf("server selected a subprotocol that was not requested: %s", p)
+	}
+
+	return &conn{nc: nc, br: br, client: true, maxMessageSize: maxMessageSize}, nil
+}
+
+func (c *conn) writeFrame(op byte, payload []byte) error {
+	buf := make([]byte, 0, 14+len(payload))
+	buf = append(buf, 0x80|op) // FIN bit is always set, we don't fragment.
+
+	var maskBit byte
+	if c.client {
+		maskBit = 0x80
+	}
+	switch n := len(payload); {
+	case n < 126:
+		buf = append(buf, maskBit|byte(n))
+	case n <= 0xffff:
+		buf = append(buf, maskBit|126)
+		buf = binary.BigEndian.AppendUint16(buf, uint16(n))
+	default:
+		buf = append(buf, maskBit|127)
+		buf = binary.BigEndian.AppendUint64(buf, uint64(n))
+	}
+
+	if !c.client {
+		buf = append(buf, payload...)
+	} else {
+		var mask [4]byte
+		rand.Read(mask[:])
+		buf = append(buf, mask[:]...)
+		for i, b := range payload {
+			buf = append(buf, b^mask[i%4])
+		}
+	}
+
+	_, err := c.nc.Write(buf)
+	return err
+}
+
+func (c *conn) readFrame() (fin bool, op byte, payload []byte, err error) {
+	var h [2]byte
+	if _, err := io.ReadFull(c.br, h[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin, op = h[0]&0x80 != 0, h[0]&0x0f
+	masked := h[1]&0x80 != 0
+
+	n := uint64(h[1] & 0x7f)
+	switch n {
+	case 126:
+		var b [2]byte
+		if _, err := io.ReadFull(c.br, b[:]); err != nil {
+			return false, 0, nil, err
+		}
+		n = uint64(binary.BigEndian.Uint16(b[:]))
+	case 127:
+		var b [8]byte
+		if _, err := io.ReadFull(c.br, b[:]); err != nil {
+			return false, 0, nil, err
+		}
+		n = binary.BigEndian.Uint64(b[:])
+	}
+	if n > uint64(c.maxMessageSize) {
+		return false, 0, nil, fmt.Errorf("frame size (%d) exceeds the max message size (%d)", n, c.maxMessageSize)
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.br, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload = make([]byte, n)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return fin, op, payload, nil
+}
+
+// readMessage reads the next data message, reassembling fragmented messages.
+// Pings are answered and pongs are ignored. If peer sends a close frame,
+// a *closeError is returned.
+func (c *conn) readMessage() (op byte, msg []byte, err error) {
+	for {
+		fin, frameOp, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch frameOp {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			ce := &closeError{code: closeNoStatus}
+			if len(payload) >= 2 {
+				ce.code = int(binary.BigEndian.Uint16(payload))
+				ce.reason = string(payload[2:])
+			}
+			return 0, nil, ce
+		case opContinuation:
+			if op == 0 {
+				return 0, nil, errors.New("unexpected continuation frame")
+			}
+		case opText, opBinary:
+			if op != 0 {
+				return 0, nil, errors.New("expected a continuation frame")
+			}
+			op = frameOp
+		default:
+			return 0, nil, fmt.Errorf("unknown opcode: %d", frameOp)
+		}
+
+		msg = append(msg, payload...)
+		if len(msg) > c.maxMessageSize {
+			return 0, nil, fmt.Errorf("message size exceeds the max message size (%d)", c.maxMessageSize)
+		}
+		if fin {
+			return op, msg, nil
+		}
+	}
+}
+
+// close sends a close frame with the given code and waits for the peer's
+// close frame. It returns peer's close code, or closeAbnormal if peer didn't
+// send a close frame.
+func (c *conn) close(code int) (int, error) {
+	if err := c.writeFrame(opClose, binary.BigEndian.AppendUint16(nil, uint16(code))); err != nil {
+		return closeAbnormal, err
+	}
+	for {
+		// Data messages received after sending the close frame are discarded.
+		_, _, err := c.readMessage()
+		var ce *closeError
+		if errors.As(err, &ce) {
+			return ce.code, nil
+		}
+		if err != nil {
+			return closeAbnormal, err
+		}
+	}
+}
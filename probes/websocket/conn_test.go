@@ -0,0 +1,144 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package websocket
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordConn records the data written to it.
+type recordConn struct {
+	net.Conn
+	buf bytes.Buffer
+}
+
+func (rc *recordConn) Write(b []byte) (int, error) {
+	return rc.buf.Write(b)
+}
+
+func testConnPair(t *testing.T) (client, server *conn) {
+	t.Helper()
+	c1, c2 := net.Pipe()
+	t.Cleanup(func() { c1.Close(); c2.Close() })
+	client = &conn{nc: c1, br: bufio.NewReader(c1), client: true, maxMessageSize: 1 << 20}
+	server = &conn{nc: c2, br: bufio.NewReader(c2), maxMessageSize: 1 << 20}
+	return client, server
+}
+
+func TestAcceptKey(t *testing.T) {
+	// Example from RFC 6455, section 1.3.
+	assert.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", acceptKey("dGhlIHNhbXBsZSBub25jZQ=="))
+}
+
+func TestReadWriteMessage(t *testing.T) {
+	client, server := testConnPair(t)
+
+	for _, payload := range [][]byte{[]byte("hello"), bytes.Repeat([]byte("a"), 200), bytes.Repeat([]byte("b"), 70000)} {
+		go client.writeFrame(opBinary, payload)
+		op, msg, err := server.readMessage()
+		require.NoError(t, err)
+		assert.Equal(t, byte(opBinary), op)
+		assert.Equal(t, payload, msg)
+	}
+
+	// Client frames are masked, server frames are not.
+	for _, isClient := range []bool{true, false} {
+		rc := &recordConn{}
+		c := &conn{nc: rc, client: isClient}
+		require.NoError(t, c.writeFrame(opText, []byte("hi")))
+		b := rc.buf.Bytes()
+		assert.Equal(t, byte(0x80|opText), b[0])
+		if isClient {
+			assert.Equal(t, byte(0x80|2), b[1], "client frame should be masked")
+			assert.Len(t, b, 8)
+			assert.Equal(t, "hi", string([]byte{b[6] ^ b[2], b[7] ^ b[3]}))
+		} else {
+			assert.Equal(t, []byte{2, 'h', 'i'}, b[1:])
+		}
+	}
+}
+
+func TestReadMessageControlFrames(t *testing.T) {
+	client, server := testConnPair(t)
+
+	// Server sends a ping, a fragmented message and a close frame. Client
+	// should answer the ping and reassemble the message.
+	go func() {
+		server.writeFrame(opPing, []byte("p"))
+		server.nc.Write([]byte{opText, 3, 'a', 'b', 'c'})           // FIN not set
+		server.nc.Write([]byte{0x80 | opContinuation, 2, 'd', 'e'}) // FIN set
+		server.writeFrame(opClose, []byte{0x0f, 0xa0, 'b', 'y', 'e'})
+	}()
+	pongCh := make(chan []byte)
+	go func() {
+		_, op, payload, _ := server.readFrame()
+		if op == opPong {
+			pongCh <- payload
+		}
+	}()
+
+	op, msg, err := client.readMessage()
+	require.NoError(t, err)
+	assert.Equal(t, byte(opText), op)
+	assert.Equal(t, "abcde", string(msg))
+	assert.Equal(t, "p", string(<-pongCh))
+
+	_, _, err = client.readMessage()
+	var ce *closeError
+	require.True(t, errors.As(err, &ce), "expected close error, got: %v", err)
+	assert.Equal(t, 4000, ce.code)
+	assert.Equal(t, "bye", ce.reason)
+}
+
+func TestReadMessageErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr string
+	}{
+		{
+			name:    "unexpected_continuation",
+			data:    []byte{0x80 | opContinuation, 1, 'a'},
+			wantErr: "unexpected continuation",
+		},
+		{
+			name:    "expected_continuation",
+			data:    []byte{opText, 1, 'a', 0x80 | opText, 1, 'b'},
+			wantErr: "expected a continuation",
+		},
+		{
+			name:    "too_large",
+			data:    []byte{0x80 | opText, 126, 0x01, 0x00},
+			wantErr: "exceeds the max message size",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &conn{br: bufio.NewReader(bytes.NewReader(tt.data)), maxMessageSize: 100}
+			_, _, err := c.readMessage()
+			require.Error(t, err)
+			assert.True(t, strings.Contains(err.Error(), tt.wantErr), "error: %v", err)
+		})
+	}
+}
@@ -0,0 +1,458 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.5
+// source: github.com/cloudprober/cloudprober/probes/websocket/proto/config.proto
+
+package proto
+
+import (
+	proto1 "github.com/cloudprober/cloudprober/common/oauth/proto"
+	proto2 "github.com/cloudprober/cloudprober/common/tlsconfig/proto"
+	proto "github.com/cloudprober/cloudprober/internal/validators/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ProbeConf_Scheme int32
+
+const (
+	ProbeConf_WS  ProbeConf_Scheme = 0
+	ProbeConf_WSS ProbeConf_Scheme = 1
+)
+
+// Enum value maps for ProbeConf_Scheme.
+var (
+	ProbeConf_Scheme_name = map[int32]string{
+		0: "WS",
+		1: "WSS",
+	}
+	ProbeConf_Scheme_value = map[string]int32{
+		"WS":  0,
+		"WSS": 1,
+	}
+)
+
+func (x ProbeConf_Scheme) Enum() *ProbeConf_Scheme {
+	p := new(ProbeConf_Scheme)
+	*p = x
+	return p
+}
+
+func (x ProbeConf_Scheme) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProbeConf_Scheme) Descriptor() protoreflect.EnumDescriptor {
+	return file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_enumTypes[0].Descriptor()
+}
+
+func (ProbeConf_Scheme) Type() protoreflect.EnumType {
+	return &file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_enumTypes[0]
+}
+
+func (x ProbeConf_Scheme) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *ProbeConf_Scheme) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = ProbeConf_Scheme(num)
+	return nil
+}
+
+// Deprecated: Use ProbeConf_Scheme.Descriptor instead.
+func (ProbeConf_Scheme) EnumDescriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_rawDescGZIP(), []int{1, 0}
+}
+
+// Message to exchange with the target after the WebSocket handshake.
+type Message struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Message name. It's used as the "message" label in the per-message
+	// metrics.
+	Name *string `protobuf:"bytes,1,req,name=name" json:"name,omitempty"`
+	// Message payload. If not set, nothing is sent and probe just waits for a
+	// message from the target, e.g. a greeting message.
+	//
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*Message_Text
+	//	*Message_Binary
+	Payload isMessage_Payload `protobuf_oneof:"payload"`
+	// Whether to wait for a reply from the target. Round-trip latency is
+	// measured from sending the message to receiving the reply.
+	ExpectReply *bool `protobuf:"varint,4,opt,name=expect_reply,json=expectReply,def=1" json:"expect_reply,omitempty"`
+	// Validators for the reply. If a validator fails, message exchange and the
+	// probe run fail. Validators require expect_reply to be true.
+	Validator     []*proto.Validator `protobuf:"bytes,5,rep,name=validator" json:"validator,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+// Default values for Message fields.
+const (
+	Default_Message_ExpectReply = bool(true)
+)
+
+func (x *Message) Reset() {
+	*x = Message{}
+	mi := &file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Message) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message) ProtoMessage() {}
+
+func (x *Message) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message.ProtoReflect.Descriptor instead.
+func (*Message) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Message) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *Message) GetPayload() isMessage_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *Message) GetText() string {
+	if x != nil {
+		if x, ok := x.Payload.(*Message_Text); ok {
+			return x.Text
+		}
+	}
+	return ""
+}
+
+func (x *Message) GetBinary() []byte {
+	if x != nil {
+		if x, ok := x.Payload.(*Message_Binary); ok {
+			return x.Binary
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetExpectReply() bool {
+	if x != nil && x.ExpectReply != nil {
+		return *x.ExpectReply
+	}
+	return Default_Message_ExpectReply
+}
+
+func (x *Message) GetValidator() []*proto.Validator {
+	if x != nil {
+		return x.Validator
+	}
+	return nil
+}
+
+type isMessage_Payload interface {
+	isMessage_Payload()
+}
+
+type Message_Text struct {
+	Text string `protobuf:"bytes,2,opt,name=text,oneof"`
+}
+
+type Message_Binary struct {
+	Binary []byte `protobuf:"bytes,3,opt,name=binary,oneof"`
+}
+
+func (*Message_Text) isMessage_Payload() {}
+
+func (*Message_Binary) isMessage_Payload() {}
+
+// WebSocket probe performs the WebSocket upgrade handshake with the target,
+// exchanges the configured messages in order, and closes the connection. A
+// probe run succeeds only if handshake and all message exchanges succeed.
+//
+// Probe exports handshake latency, per-message round-trip latency and the
+// close codes received from the target. If target doesn't send a close frame,
+// close code is recorded as 1006 (abnormal closure).
+type ProbeConf struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// URL scheme.
+	Scheme *ProbeConf_Scheme `protobuf:"varint,1,opt,name=scheme,enum=cloudprober.probes.websocket.ProbeConf_Scheme,def=0" json:"scheme,omitempty"`
+	// Port for the WebSocket connection. Default is to use the target's port
+	// if available, or scheme's default port.
+	Port *int32 `protobuf:"varint,2,opt,name=port" json:"port,omitempty"`
+	// Relative URL, including the query string if any. We construct the final
+	// URL like this: <scheme>://<host>:<port><relative_url>.
+	RelativeUrl *string `protobuf:"bytes,3,opt,name=relative_url,json=relativeUrl,def=/" json:"relative_url,omitempty"`
+	// Whether to resolve the target before connecting. Similar to the HTTP
+	// probe's resolve_first, default is to resolve first if target comes with
+	// an IP address, e.g. k8s endpoints.
+	ResolveFirst *bool `protobuf:"varint,4,opt,name=resolve_first,json=resolveFirst" json:"resolve_first,omitempty"`
+	// HTTP headers for the upgrade request. "Host" header can be used to
+	// override the host.
+	Header map[string]string `protobuf:"bytes,5,rep,name=header" json:"header,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Subprotocols to request, sent in the Sec-WebSocket-Protocol header.
+	Subprotocol []string `protobuf:"bytes,6,rep,name=subprotocol" json:"subprotocol,omitempty"`
+	// OAuth config. Token is sent in the Authorization header of the upgrade
+	// request.
+	OauthConfig *proto1.Config `protobuf:"bytes,7,opt,name=oauth_config,json=oauthConfig" json:"oauth_config,omitempty"`
+	// TLS config, used for the "wss" scheme.
+	TlsConfig *proto2.TLSConfig `protobuf:"bytes,8,opt,name=tls_config,json=tlsConfig" json:"tls_config,omitempty"`
+	// Messages to exchange, in order.
+	Message []*Message `protobuf:"bytes,9,rep,name=message" json:"message,omitempty"`
+	// Close code to send while closing the connection.
+	CloseCode *int32 `protobuf:"varint,10,opt,name=close_code,json=closeCode,def=1000" json:"close_code,omitempty"`
+	// Maximum size of a received message. Larger messages fail the probe.
+	MaxMessageBytes *int32 `protobuf:"varint,11,opt,name=max_message_bytes,json=maxMessageBytes,def=1048576" json:"max_message_bytes,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+// Default values for ProbeConf fields.
+const (
+	Default_ProbeConf_Scheme          = ProbeConf_WS
+	Default_ProbeConf_RelativeUrl     = string("/")
+	Default_ProbeConf_CloseCode       = int32(1000)
+	Default_ProbeConf_MaxMessageBytes = int32(1048576)
+)
+
+func (x *ProbeConf) Reset() {
+	*x = ProbeConf{}
+	mi := &file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProbeConf) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProbeConf) ProtoMessage() {}
+
+func (x *ProbeConf) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProbeConf.ProtoReflect.Descriptor instead.
+func (*ProbeConf) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ProbeConf) GetScheme() ProbeConf_Scheme {
+	if x != nil && x.Scheme != nil {
+		return *x.Scheme
+	}
+	return Default_ProbeConf_Scheme
+}
+
+func (x *ProbeConf) GetPort() int32 {
+	if x != nil && x.Port != nil {
+		return *x.Port
+	}
+	return 0
+}
+
+func (x *ProbeConf) GetRelativeUrl() string {
+	if x != nil && x.RelativeUrl != nil {
+		return *x.RelativeUrl
+	}
+	return Default_ProbeConf_RelativeUrl
+}
+
+func (x *ProbeConf) GetResolveFirst() bool {
+	if x != nil && x.ResolveFirst != nil {
+		return *x.ResolveFirst
+	}
+	return false
+}
+
+func (x *ProbeConf) GetHeader() map[string]string {
+	if x != nil {
+		return x.Header
+	}
+	return nil
+}
+
+func (x *ProbeConf) GetSubprotocol() []string {
+	if x != nil {
+		return x.Subprotocol
+	}
+	return nil
+}
+
+func (x *ProbeConf) GetOauthConfig() *proto1.Config {
+	if x != nil {
+		return x.OauthConfig
+	}
+	return nil
+}
+
+func (x *ProbeConf) GetTlsConfig() *proto2.TLSConfig {
+	if x != nil {
+		return x.TlsConfig
+	}
+	return nil
+}
+
+func (x *ProbeConf) GetMessage() []*Message {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+func (x *ProbeConf) GetCloseCode() int32 {
+	if x != nil && x.CloseCode != nil {
+		return *x.CloseCode
+	}
+	return Default_ProbeConf_CloseCode
+}
+
+func (x *ProbeConf) GetMaxMessageBytes() int32 {
+	if x != nil && x.MaxMessageBytes != nil {
+		return *x.MaxMessageBytes
+	}
+	return Default_ProbeConf_MaxMessageBytes
+}
+
+var File_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto protoreflect.FileDescriptor
+
+const file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_rawDesc = "" +
+	"\n" +
+	"Fgithub.com/cloudprober/cloudprober/probes/websocket/proto/config.proto\x12\x1ccloudprober.probes.websocket\x1aBgithub.com/cloudprober/cloudprober/common/oauth/proto/config.proto\x1aFgithub.com/cloudprober/cloudprober/common/tlsconfig/proto/config.proto\x1aIgithub.com/cloudprober/cloudprober/internal/validators/proto/config.proto\"\xc2\x01\n" +
+	"\aMessage\x12\x12\n" +
+	"\x04name\x18\x01 \x02(\tR\x04name\x12\x14\n" +
+	"\x04text\x18\x02 \x01(\tH\x00R\x04text\x12\x18\n" +
+	"\x06binary\x18\x03 \x01(\fH\x00R\x06binary\x12'\n" +
+	"\fexpect_reply\x18\x04 \x01(\b:\x04trueR\vexpectReply\x12?\n" +
+	"\tvalidator\x18\x05 \x03(\v2!.cloudprober.validators.ValidatorR\tvalidatorB\t\n" +
+	"\apayload\"\x95\x05\n" +
+	"\tProbeConf\x12J\n" +
+	"\x06scheme\x18\x01 \x01(\x0e2..cloudprober.probes.websocket.ProbeConf.Scheme:\x02WSR\x06scheme\x12\x12\n" +
+	"\x04port\x18\x02 \x01(\x05R\x04port\x12$\n" +
+	"\frelative_url\x18\x03 \x01(\t:\x01/R\vrelativeUrl\x12#\n" +
+	"\rresolve_first\x18\x04 \x01(\bR\fresolveFirst\x12K\n" +
+	"\x06header\x18\x05 \x03(\v23.cloudprober.probes.websocket.ProbeConf.HeaderEntryR\x06header\x12 \n" +
+	"\vsubprotocol\x18\x06 \x03(\tR\vsubprotocol\x12<\n" +
+	"\foauth_config\x18\a \x01(\v2\x19.cloudprober.oauth.ConfigR\voauthConfig\x12?\n" +
+	"\n" +
+	"tls_config\x18\b \x01(\v2 .cloudprober.tlsconfig.TLSConfigR\ttlsConfig\x12?\n" +
+	"\amessage\x18\t \x03(\v2%.cloudprober.probes.websocket.MessageR\amessage\x12#\n" +
+	"\n" +
+	"close_code\x18\n" +
+	" \x01(\x05:\x041000R\tcloseCode\x123\n" +
+	"\x11max_message_bytes\x18\v \x01(\x05:\a1048576R\x0fmaxMessageBytes\x1a9\n" +
+	"\vHeaderEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x19\n" +
+	"\x06Scheme\x12\x06\n" +
+	"\x02WS\x10\x00\x12\a\n" +
+	"\x03WSS\x10\x01B;Z9github.com/cloudprober/cloudprober/probes/websocket/proto"
+
+var (
+	file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_rawDescOnce sync.Once
+	file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_rawDescData []byte
+)
+
+func file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_rawDescGZIP() []byte {
+	file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_rawDescOnce.Do(func() {
+		file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_rawDesc), len(file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_rawDesc)))
+	})
+	return file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_rawDescData
+}
+
+var file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_goTypes = []any{
+	(ProbeConf_Scheme)(0),    // 0: cloudprober.probes.websocket.ProbeConf.Scheme
+	(*Message)(nil),          // 1: cloudprober.probes.websocket.Message
+	(*ProbeConf)(nil),        // 2: cloudprober.probes.websocket.ProbeConf
+	nil,                      // 3: cloudprober.probes.websocket.ProbeConf.HeaderEntry
+	(*proto.Validator)(nil),  // 4: cloudprober.validators.Validator
+	(*proto1.Config)(nil),    // 5: cloudprober.oauth.Config
+	(*proto2.TLSConfig)(nil), // 6: cloudprober.tlsconfig.TLSConfig
+}
+var file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_depIdxs = []int32{
+	4, // 0: cloudprober.probes.websocket.Message.validator:type_name -> cloudprober.validators.Validator
+	0, // 1: cloudprober.probes.websocket.ProbeConf.scheme:type_name -> cloudprober.probes.websocket.ProbeConf.Scheme
+	3, // 2: cloudprober.probes.websocket.ProbeConf.header:type_name -> cloudprober.probes.websocket.ProbeConf.HeaderEntry
+	5, // 3: cloudprober.probes.websocket.ProbeConf.oauth_config:type_name -> cloudprober.oauth.Config
+	6, // 4: cloudprober.probes.websocket.ProbeConf.tls_config:type_name -> cloudprober.tlsconfig.TLSConfig
+	1, // 5: cloudprober.probes.websocket.ProbeConf.message:type_name -> cloudprober.probes.websocket.Message
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_init() }
+func file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_init() {
+	if File_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto != nil {
+		return
+	}
+	file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_msgTypes[0].OneofWrappers = []any{
+		(*Message_Text)(nil),
+		(*Message_Binary)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_rawDesc), len(file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_goTypes,
+		DependencyIndexes: file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_depIdxs,
+		EnumInfos:         file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_enumTypes,
+		MessageInfos:      file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_msgTypes,
+	}.Build()
+	File_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto = out.File
+	file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_goTypes = nil
+	file_github_com_cloudprober_cloudprober_probes_websocket_proto_config_proto_depIdxs = nil
+}
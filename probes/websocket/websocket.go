@@ -0,0 +1,435 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package websocket implements the WEBSOCKET probe type. WebSocket probe
+// performs the WebSocket upgrade handshake with the target, exchanges the
+// configured messages with it, and closes the connection.
+package websocket
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudprober/cloudprober/common/oauth"
+	"github.com/cloudprober/cloudprober/common/tlsconfig"
+	"github.com/cloudprober/cloudprober/internal/validators"
+	"github.com/cloudprober/cloudprober/logger"
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/metrics/singlerun"
+	"github.com/cloudprober/cloudprober/probes/common/sched"
+	"github.com/cloudprober/cloudprober/probes/options"
+	configpb "github.com/cloudprober/cloudprober/probes/websocket/proto"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+	"golang.org/x/oauth2"
+)
+
+// Probe holds aggregate information about all probe runs, per-target.
+type Probe struct {
+	name string
+	opts *options.Options
+	c    *configpb.ProbeConf
+	l    *logger.Logger
+
+	messages    []*message
+	tlsConfig   *tls.Config
+	oauthTS     oauth2.TokenSource
+	network     string
+	dialContext func(context.Context, string, string) (net.Conn, error)
+}
+
+type message struct {
+	c          *configpb.Message
+	validators []*validators.Validator
+}
+
+type messageResult struct {
+	name              string
+	total, success    int64
+	latency           metrics.LatencyValue
+	validationFailure *metrics.Map[int64]
+}
+
+type probeResult struct {
+	total, success, timeouts int64
+	latency                  metrics.LatencyValue
+	handshakeLatency         metrics.LatencyValue
+	closeCodes               *metrics.Map[int64]
+	messages                 []*messageResult
+}
+
+func (p *Probe) initMessages() error {
+	names := make(map[string]bool)
+	for _, mc := range p.c.GetMessage() {
+		if names[mc.GetName()] {
+			return fmt.Errorf("message %s is defined twice", mc.GetName())
+		}
+		names[mc.GetName()] = true
+
+		if mc.GetPayload() == nil && !mc.GetExpectReply() {
+			return fmt.Errorf("message %s: neither payload is set nor reply is expected", mc.GetName())
+		}
+		if len(mc.GetValidator()) > 0 && !mc.GetExpectReply() {
+			return fmt.Errorf("message %s: validators require expect_reply to be true", mc.GetName())
+		}
+
+		m := &message{c: mc}
+		var err error
+		if m.validators, err = validators.Init(mc.GetValidator()); err != nil {
+			return fmt.Errorf("message %s: error initializing validators: %v", mc.GetName(), err)
+		}
+		p.messages = append(p.messages, m)
+	}
+	return nil
+}
+
+// Init initializes the probe with the given params.
+func (p *Probe) Init(name string, opts *options.Options) error {
+	if opts.ProbeConf == nil {
+		opts.ProbeConf = &configpb.ProbeConf{}
+	}
+
+	c, ok := opts.ProbeConf.(*configpb.ProbeConf)
+	if !ok {
+		return fmt.Errorf("not websocket config")
+	}
+	p.name = name
+	p.opts = opts
+	if p.l = opts.Logger; p.l == nil {
+		p.l = &logger.Logger{}
+	}
+	p.c = c
+	if p.c == nil {
+		p.c = &configpb.ProbeConf{}
+	}
+
+	if len(p.opts.Validators) > 0 {
+		return errors.New("probe level validators are not supported by the WEBSOCKET probe, use message level validators instead")
+	}
+
+	if !strings.HasPrefix(p.c.GetRelativeUrl(), "/") {
+		return fmt.Errorf("invalid relative URL: %s, must begin with '/'", p.c.GetRelativeUrl())
+	}
+
+	if err := p.initMessages(); err != nil {
+		return err
+	}
+
+	if p.c.GetTlsConfig() != nil {
+		p.tlsConfig = &tls.Config{}
+		if err := tlsconfig.UpdateTLSConfig(p.tlsConfig, p.c.GetTlsConfig()); err != nil {
+			return fmt.Errorf("tls_config error: %v", err)
+		}
+	}
+
+	if p.c.GetOauthConfig() != nil {
+		oauthTS, err := oauth.TokenSourceFromConfig(p.c.GetOauthConfig(), p.l)
+		if err != nil {
+			return err
+		}
+		p.oauthTS = oauthTS
+	}
+
+	p.network = "tcp"
+	if p.opts.IPVersion != 0 {
+		p.network += strconv.Itoa(p.opts.IPVersion)
+	}
+	dialer := &net.Dialer{Timeout: p.opts.Timeout}
+	if p.opts.SourceIP != nil {
+		dialer.LocalAddr = &net.TCPAddr{IP: p.opts.SourceIP}
+	}
+	p.dialContext = dialer.DialContext
+
+	return nil
+}
+
+func (p *Probe) newResult() *probeResult {
+	newLatency := func() metrics.LatencyValue {
+		if p.opts.LatencyDist != nil {
+			return p.opts.LatencyDist.CloneDist()
+		}
+		return metrics.NewFloat(0)
+	}
+
+	result := &probeResult{
+		latency:          newLatency(),
+		handshakeLatency: newLatency(),
+		closeCodes:       metrics.NewMap("code"),
+	}
+	for _, m := range p.messages {
+		mr := &messageResult{
+			name:    m.c.GetName(),
+			latency: newLatency(),
+		}
+		if len(m.validators) > 0 {
+			mr.validationFailure = validators.ValidationFailureMap(m.validators)
+		}
+		result.messages = append(result.messages, mr)
+	}
+	return result
+}
+
+// Metrics returns the probe level EventMetrics, followed by per-message
+// EventMetrics. Per-message EventMetrics have a "message" label, their
+// latency is the message round-trip latency, and they are not used for
+// alerting.
+func (result *probeResult) Metrics(ts time.Time, _ int64, opts *options.Options) []*metrics.EventMetrics {
+	ems := []*metrics.EventMetrics{
+		metrics.NewEventMetrics(ts).
+			AddMetric("total", metrics.NewInt(result.total)).
+			AddMetric("success", metrics.NewInt(result.success)).
+			AddMetric(opts.LatencyMetricName, result.latency.Clone()).
+			AddMetric("handshake_latency", result.handshakeLatency.Clone()).
+			AddMetric("timeouts", metrics.NewInt(result.timeouts)).
+			AddMetric("close_code", result.closeCodes.Clone()).
+			AddLabel("ptype", "websocket"),
+	}
+
+	for _, mr := range result.messages {
+		em := metrics.NewEventMetrics(ts).
+			AddMetric("total", metrics.NewInt(mr.total)).
+			AddMetric("success", metrics.NewInt(mr.success)).
+			AddMetric(opts.LatencyMetricName, mr.latency.Clone())
+		if mr.validationFailure != nil {
+			em.AddMetric("validation_failure", mr.validationFailure.Clone())
+		}
+		em.AddLabel("ptype", "websocket").
+			AddLabel("message", mr.name)
+		em.SetNotForAlerting()
+		ems = append(ems, em)
+	}
+	return ems
+}
+
+func (p *Probe) resolveFirst(target endpoint.Endpoint) bool {
+	if p.c.ResolveFirst != nil {
+		return p.c.GetResolveFirst()
+	}
+	return target.IP != nil
+}
+
+// upgradeRequest returns the upgrade request for the target, and the address
+// to connect to.
+func (p *Probe) upgradeRequest(target endpoint.Endpoint) (*http.Request, string, error) {
+	scheme, port := "ws", int(p.c.GetPort())
+	if p.c.GetScheme() == configpb.ProbeConf_WSS {
+		scheme = "wss"
+	}
+	if port == 0 {
+		port = target.Port
+	}
+
+	host := target.Host()
+	urlHost := host
+	if port != 0 {
+		urlHost = net.JoinHostPort(host, strconv.Itoa(port))
+	} else if strings.Contains(host, ":") {
+		urlHost = "[" + host + "]"
+	}
+	if port == 0 {
+		port = 80
+		if scheme == "wss" {
+			port = 443
+		}
+	}
+
+	dialHost, ipLabel := host, ""
+	if p.resolveFirst(target) {
+		ip, err := target.Resolve(p.opts.IPVersion, p.opts.Targets, endpoint.WithNameOverride(host))
+		if err != nil {
+			return nil, "", fmt.Errorf("error resolving target: %s, %v", target.Name, err)
+		}
+		dialHost, ipLabel = ip.String(), ip.String()
+	}
+
+	for _, al := range p.opts.AdditionalLabels {
+		al.UpdateForTarget(target, ipLabel, port)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, scheme+"://"+urlHost+p.c.GetRelativeUrl(), nil)
+	if err != nil {
+		return nil, "", err
+	}
+	for k, v := range p.c.GetHeader() {
+		if k == "Host" {
+			req.Host = v
+			continue
+		}
+		req.Header.Set(k, v)
+	}
+	if p.oauthTS != nil {
+		req.Header.Set("Authorization", oauth.AuthorizationHeader(p.oauthTS, p.c.GetOauthConfig(), p.l))
+	}
+
+	return req, net.JoinHostPort(dialHost, strconv.Itoa(port)), nil
+}
+
+func (p *Probe) connect(ctx context.Context, addr, serverName string) (net.Conn, error) {
+	nc, err := p.dialContext(ctx, p.network, addr)
+	if err != nil {
+		return nil, err
+	}
+	if p.c.GetScheme() != configpb.ProbeConf_WSS {
+		return nc, nil
+	}
+
+	tlsConfig := &tls.Config{}
+	if p.tlsConfig != nil {
+		tlsConfig = p.tlsConfig.Clone()
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = serverName
+	}
+	tlsConn := tls.Client(nc, tlsConfig)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("TLS handshake error: %v", err)
+	}
+	return tlsConn, nil
+}
+
+// exchange sends the message (if it has a payload) and waits for the reply
+// (if a reply is expected).
+func (p *Probe) exchange(c *conn, m *message, mr *messageResult, l *logger.Logger) error {
+	mr.total++
+	start := time.Now()
+
+	switch m.c.GetPayload().(type) {
+	case *configpb.Message_Text:
+		if err := c.writeFrame(opText, []byte(m.c.GetText())); err != nil {
+			return err
+		}
+	case *configpb.Message_Binary:
+		if err := c.writeFrame(opBinary, m.c.GetBinary()); err != nil {
+			return err
+		}
+	}
+
+	if m.c.GetExpectReply() {
+		_, reply, err := c.readMessage()
+		if err != nil {
+			return err
+		}
+		l.Debug("Reply: ", string(reply))
+
+		if len(m.validators) > 0 {
+			failedValidations := validators.RunValidators(m.validators, &validators.Input{ResponseBody: reply}, mr.validationFailure, l)
+			if len(failedValidations) > 0 {
+				return fmt.Errorf("failed validations: %s", strings.Join(failedValidations, ","))
+			}
+		}
+	}
+
+	mr.success++
+	mr.latency.AddFloat64(time.Since(start).Seconds() / p.opts.LatencyUnit.Seconds())
+	return nil
+}
+
+// runSession runs a WebSocket session with the target: handshake, message
+// exchanges and close. It returns the close code received from the target,
+// which is closeAbnormal if target didn't send a close frame.
+func (p *Probe) runSession(ctx context.Context, target endpoint.Endpoint, result *probeResult) (int, error) {
+	req, addr, err := p.upgradeRequest(target)
+	if err != nil {
+		return closeAbnormal, err
+	}
+	l := p.l.WithAttributes(slog.String("target", target.Name), slog.String("url", req.URL.String()))
+
+	start := time.Now()
+	nc, err := p.connect(ctx, addr, target.Host())
+	if err != nil {
+		return closeAbnormal, err
+	}
+	defer nc.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		nc.SetDeadline(deadline)
+	}
+
+	c, err := handshake(nc, req, p.c.GetSubprotocol(), int(p.c.GetMaxMessageBytes()))
+	if err != nil {
+		return closeAbnormal, err
+	}
+	result.handshakeLatency.AddFloat64(time.Since(start).Seconds() / p.opts.LatencyUnit.Seconds())
+
+	for i, m := range p.messages {
+		if err := p.exchange(c, m, result.messages[i], l); err != nil {
+			err = fmt.Errorf("message %s: %w", m.c.GetName(), err)
+			var ce *closeError
+			if errors.As(err, &ce) {
+				return ce.code, err
+			}
+			// Try to close the connection cleanly, e.g. after a validation
+			// failure. This fails fast if connection is already broken.
+			closeCode, _ := c.close(int(p.c.GetCloseCode()))
+			return closeCode, err
+		}
+	}
+
+	// Not getting a close frame back is not a probe failure, it shows up in
+	// the close_code metric.
+	closeCode, err := c.close(int(p.c.GetCloseCode()))
+	if err != nil {
+		l.Warning("Error while closing the connection: ", err.Error())
+	}
+	return closeCode, nil
+}
+
+func (p *Probe) runProbe(ctx context.Context, runReq *sched.RunProbeForTargetRequest) {
+	if runReq.Result == nil {
+		runReq.Result = p.newResult()
+	}
+	target, result := runReq.Target, runReq.Result.(*probeResult)
+
+	start := time.Now()
+	closeCode, err := p.runSession(ctx, target, result)
+	latency := time.Since(start)
+
+	result.total++
+	result.closeCodes.IncKey(strconv.Itoa(closeCode))
+	if err == nil {
+		result.success++
+		result.latency.AddFloat64(latency.Seconds() / p.opts.LatencyUnit.Seconds())
+	} else {
+		p.l.Warningf("WebSocket probe failed for target %s: %v", target.Name, err)
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			result.timeouts++
+		}
+	}
+	runReq.LastRun.Set(err == nil, latency, err)
+}
+
+// RunOnce runs the probe just once.
+func (p *Probe) RunOnce(ctx context.Context) []*singlerun.ProbeRunResult {
+	p.l.Info("Running WebSocket probe once.")
+	return sched.RunOnce(ctx, p.opts, p.runProbe)
+}
+
+// Start starts and runs the probe indefinitely.
+func (p *Probe) Start(ctx context.Context, dataChan chan *metrics.EventMetrics) {
+	s := &sched.Scheduler{
+		ProbeName:         p.name,
+		DataChan:          dataChan,
+		Opts:              p.opts,
+		RunProbeForTarget: p.runProbe,
+	}
+
+	s.UpdateTargetsAndStartProbes(ctx)
+}
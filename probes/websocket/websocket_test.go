@@ -0,0 +1,288 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package websocket
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	tlsconfigpb "github.com/cloudprober/cloudprober/common/tlsconfig/proto"
+	validatorspb "github.com/cloudprober/cloudprober/internal/validators/proto"
+	"github.com/cloudprober/cloudprober/metrics"
+	"github.com/cloudprober/cloudprober/probes/common/sched"
+	"github.com/cloudprober/cloudprober/probes/options"
+	configpb "github.com/cloudprober/cloudprober/probes/websocket/proto"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// testHandler implements a WebSocket echo server. Special messages:
+//
+//	"close": server closes the connection with code 4000.
+//	"drop":  server drops the connection without a close frame.
+//
+// Server replies to client's close frame with the same code.
+func testHandler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "websocket" || r.Header.Get("Authorization") != "Bearer tok" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		nc, brw, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			t.Errorf("hijack error: %v", err)
+			return
+		}
+		defer nc.Close()
+
+		brw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n")
+		brw.WriteString("Sec-WebSocket-Accept: " + acceptKey(r.Header.Get("Sec-WebSocket-Key")) + "\r\n")
+		if r.Header.Get("Sec-WebSocket-Protocol") != "" {
+			brw.WriteString("Sec-WebSocket-Protocol: chat.v2\r\n")
+		}
+		brw.WriteString("\r\n")
+		brw.Flush()
+
+		c := &conn{nc: nc, br: brw.Reader, maxMessageSize: 1 << 20}
+		for {
+			op, msg, err := c.readMessage()
+			var ce *closeError
+			if errors.As(err, &ce) {
+				c.writeFrame(opClose, binary.BigEndian.AppendUint16(nil, uint16(ce.code)))
+				return
+			}
+			if err != nil {
+				return
+			}
+			switch string(msg) {
+			case "close":
+				c.writeFrame(opClose, binary.BigEndian.AppendUint16(nil, 4000))
+				return
+			case "drop":
+				return
+			}
+			c.writeFrame(op, append([]byte("echo: "), msg...))
+		}
+	}
+}
+
+func testProbe(t *testing.T, conf *configpb.ProbeConf) *Probe {
+	t.Helper()
+
+	if conf.Header == nil {
+		conf.Header = map[string]string{"Authorization": "Bearer tok"}
+	}
+	opts := options.DefaultOptions()
+	opts.ProbeConf = conf
+	opts.Timeout = 2 * time.Second
+	opts.LatencyUnit = time.Millisecond
+
+	p := &Probe{}
+	require.NoError(t, p.Init("test_ws", opts))
+	return p
+}
+
+func serverTarget(t *testing.T, ts *httptest.Server) endpoint.Endpoint {
+	t.Helper()
+
+	host, port, err := net.SplitHostPort(ts.Listener.Addr().String())
+	require.NoError(t, err)
+	portNum, _ := strconv.Atoi(port)
+	return endpoint.Endpoint{Name: host, Port: portNum}
+}
+
+func textMessage(name, text string, validatorRegex string) *configpb.Message {
+	m := &configpb.Message{
+		Name:    proto.String(name),
+		Payload: &configpb.Message_Text{Text: text},
+	}
+	if validatorRegex != "" {
+		m.Validator = []*validatorspb.Validator{{Name: "regex", Type: &validatorspb.Validator_Regex{Regex: validatorRegex}}}
+	}
+	return m
+}
+
+func metricInt(em *metrics.EventMetrics, name string) int64 {
+	return em.Metric(name).(metrics.NumValue).Int64()
+}
+
+func TestRunProbe(t *testing.T) {
+	ts := httptest.NewServer(testHandler(t))
+	defer ts.Close()
+	tlsTS := httptest.NewTLSServer(testHandler(t))
+	defer tlsTS.Close()
+
+	tests := []struct {
+		name          string
+		conf          *configpb.ProbeConf
+		tls           bool
+		wantSuccess   bool
+		wantCloseCode string
+		wantMsgSucc   []int64
+	}{
+		{
+			name: "success",
+			conf: &configpb.ProbeConf{
+				Message: []*configpb.Message{
+					textMessage("hello", "hello", "^echo: hello$"),
+					{Name: proto.String("bin"), Payload: &configpb.Message_Binary{Binary: []byte{0, 1}}},
+				},
+				Subprotocol: []string{"chat.v1", "chat.v2"},
+			},
+			wantSuccess:   true,
+			wantCloseCode: "1000",
+			wantMsgSucc:   []int64{1, 1},
+		},
+		{
+			name:          "handshake_only_wss",
+			conf:          &configpb.ProbeConf{Scheme: configpb.ProbeConf_WSS.Enum(), TlsConfig: &tlsconfigpb.TLSConfig{DisableCertValidation: proto.Bool(true)}, CloseCode: proto.Int32(1001)},
+			tls:           true,
+			wantSuccess:   true,
+			wantCloseCode: "1001",
+		},
+		{
+			name: "validation_failure",
+			conf: &configpb.ProbeConf{
+				Message: []*configpb.Message{textMessage("hello", "hello", "^bye$")},
+			},
+			wantCloseCode: "1000",
+			wantMsgSucc:   []int64{0},
+		},
+		{
+			name: "closed_by_server",
+			conf: &configpb.ProbeConf{
+				Message: []*configpb.Message{textMessage("m1", "close", ""), textMessage("m2", "hello", "")},
+			},
+			wantCloseCode: "4000",
+			wantMsgSucc:   []int64{0, 0},
+		},
+		{
+			name: "dropped_by_server",
+			conf: &configpb.ProbeConf{
+				Message: []*configpb.Message{{Name: proto.String("m1"), Payload: &configpb.Message_Text{Text: "drop"}, ExpectReply: proto.Bool(false)}},
+			},
+			wantSuccess:   true,
+			wantCloseCode: "1006",
+			wantMsgSucc:   []int64{1},
+		},
+		{
+			name:          "handshake_failure",
+			conf:          &configpb.ProbeConf{Header: map[string]string{"Authorization": "Bearer bad"}},
+			wantCloseCode: "1006",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := testProbe(t, tt.conf)
+
+			target := serverTarget(t, ts)
+			if tt.tls {
+				target = serverTarget(t, tlsTS)
+			}
+			runReq := &sched.RunProbeForTargetRequest{Target: target, LastRun: &sched.LastRunResult{}}
+			p.runProbe(context.Background(), runReq)
+
+			assert.Equal(t, tt.wantSuccess, runReq.LastRun.Success, "last run: %v", runReq.LastRun.Error)
+
+			ems := runReq.Result.Metrics(time.Now(), 0, p.opts)
+			require.Len(t, ems, 1+len(tt.wantMsgSucc))
+			em := ems[0]
+			assert.Equal(t, int64(1), metricInt(em, "total"))
+			assert.Equal(t, map[bool]int64{true: 1, false: 0}[tt.wantSuccess], metricInt(em, "success"))
+			assert.Equal(t, "websocket", em.Label("ptype"))
+			closeCodes := em.Metric("close_code").(*metrics.Map[int64])
+			assert.Equal(t, []string{tt.wantCloseCode}, closeCodes.Keys())
+
+			for i, em := range ems[1:] {
+				assert.Equal(t, tt.conf.GetMessage()[i].GetName(), em.Label("message"))
+				assert.False(t, em.IsForAlerting())
+				assert.Equal(t, tt.wantMsgSucc[i], metricInt(em, "success"), "message %d success", i)
+			}
+		})
+	}
+}
+
+func TestUpgradeRequest(t *testing.T) {
+	p := testProbe(t, &configpb.ProbeConf{
+		Scheme:      configpb.ProbeConf_WSS.Enum(),
+		RelativeUrl: proto.String("/ws?room=1"),
+		Header:      map[string]string{"Host": "ws.example.com", "X-Test": "v"},
+	})
+
+	req, addr, err := p.upgradeRequest(endpoint.Endpoint{Name: "test.example.com", IP: net.ParseIP("2001:db8::1")})
+	require.NoError(t, err)
+	assert.Equal(t, "wss://test.example.com/ws?room=1", req.URL.String())
+	assert.Equal(t, "ws.example.com", req.Host)
+	assert.Equal(t, "v", req.Header.Get("X-Test"))
+	assert.Equal(t, "[2001:db8::1]:443", addr)
+
+	req, addr, err = p.upgradeRequest(endpoint.Endpoint{Name: "test.example.com", Port: 8443})
+	require.NoError(t, err)
+	assert.Equal(t, "wss://test.example.com:8443/ws?room=1", req.URL.String())
+	assert.Equal(t, "test.example.com:8443", addr)
+}
+
+func TestInitErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		conf *configpb.ProbeConf
+	}{
+		{
+			name: "bad_relative_url",
+			conf: &configpb.ProbeConf{RelativeUrl: proto.String("ws")},
+		},
+		{
+			name: "duplicate_message",
+			conf: &configpb.ProbeConf{
+				Message: []*configpb.Message{textMessage("m1", "a", ""), textMessage("m1", "b", "")},
+			},
+		},
+		{
+			name: "nothing_to_do",
+			conf: &configpb.ProbeConf{
+				Message: []*configpb.Message{{Name: proto.String("m1"), ExpectReply: proto.Bool(false)}},
+			},
+		},
+		{
+			name: "validator_without_reply",
+			conf: &configpb.ProbeConf{
+				Message: []*configpb.Message{{
+					Name:        proto.String("m1"),
+					Payload:     &configpb.Message_Text{Text: "a"},
+					ExpectReply: proto.Bool(false),
+					Validator:   []*validatorspb.Validator{{Name: "v", Type: &validatorspb.Validator_Regex{Regex: "a"}}},
+				}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := options.DefaultOptions()
+			opts.ProbeConf = tt.conf
+			assert.Error(t, (&Probe{}).Init("test_ws", opts))
+		})
+	}
+}
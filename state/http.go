@@ -15,6 +15,7 @@
 package state
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -30,6 +31,7 @@ func SetDefaultHTTPServeMux(mux *http.ServeMux) {
 	st.httpServeMux = mux
 	st.webURLs = make([]string, 0)
 	st.artifactsURLs = make([]string, 0)
+	st.ctxHandlers = make(map[string][]*contextHandler)
 }
 
 // DefaultHTTPServeMux returns the default HTTP ServeMux.
@@ -47,13 +49,22 @@ func IsHandled(url string) bool {
 		return false
 	}
 
+	if _, ok := st.ctxHandlers[url]; ok {
+		return liveContextHandler(url) != nil
+	}
+
 	_, matchedPattern := st.httpServeMux.Handler(httptest.NewRequest("", url, nil))
 	return matchedPattern == url
 }
 
+// ErrAlreadyRegistered is returned by AddWebHandler if the path is already
+// registered.
+var ErrAlreadyRegistered = errors.New("already registered")
+
 type handlerOptions struct {
 	isArtifact       bool
 	artifactLinkPath string
+	ctx              context.Context
 }
 
 type HandlerOption func(*handlerOptions)
@@ -65,6 +76,97 @@ func WithArtifactsLink(linkPath string) HandlerOption {
 	}
 }
 
+// WithContext binds the handler to the given context: the handler is
+// unregistered when the context is canceled, and requests to its path get a
+// 404 after that. Unlike other handlers, more than one context-bound handler
+// can be registered for a path, e.g. when a surfacer is replaced on config
+// reload. Requests are served by the earliest registered handler whose
+// context is still active, so a replacement takes over only once the
+// handler it replaces is gone.
+func WithContext(ctx context.Context) HandlerOption {
+	return func(o *handlerOptions) {
+		o.ctx = ctx
+	}
+}
+
+// contextHandler is a handler registered using the WithContext option.
+type contextHandler struct {
+	ctx  context.Context
+	f    func(w http.ResponseWriter, r *http.Request)
+	link string // Artifacts link, if any.
+}
+
+// liveContextHandler returns the handler that should serve the given path, or
+// nil if there is none. It must be called with st locked.
+func liveContextHandler(path string) *contextHandler {
+	for _, h := range st.ctxHandlers[path] {
+		if h.ctx.Err() == nil {
+			return h
+		}
+	}
+	return nil
+}
+
+func serveContextHandler(path string, w http.ResponseWriter, r *http.Request) {
+	st.RLock()
+	h := liveContextHandler(path)
+	st.RUnlock()
+
+	if h == nil {
+		http.NotFound(w, r)
+		return
+	}
+	h.f(w, r)
+}
+
+// addContextHandler adds a context-bound handler. It must be called with st
+// locked.
+func addContextHandler(path string, h *contextHandler) error {
+	handlers, ok := st.ctxHandlers[path]
+	if !ok {
+		if slices.Contains(st.webURLs, path) {
+			return fmt.Errorf("path %s %w", path, ErrAlreadyRegistered)
+		}
+		// ServeMux doesn't support removing handlers, so we register a
+		// handler that dispatches to the live context-bound handler. It
+		// stays registered even after all the context-bound handlers are
+		// gone.
+		st.httpServeMux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			serveContextHandler(path, w, r)
+		})
+	}
+
+	if len(handlers) == 0 {
+		st.webURLs = append(st.webURLs, path)
+		if h.link != "" {
+			st.artifactsURLs = append(st.artifactsURLs, h.link)
+		}
+	}
+	st.ctxHandlers[path] = append(handlers, h)
+
+	context.AfterFunc(h.ctx, func() { removeContextHandler(path, h) })
+	return nil
+}
+
+func removeContextHandler(path string, h *contextHandler) {
+	st.Lock()
+	defer st.Unlock()
+
+	handlers, ok := st.ctxHandlers[path]
+	if !ok || !slices.Contains(handlers, h) {
+		return
+	}
+	handlers = slices.DeleteFunc(handlers, func(hh *contextHandler) bool { return hh == h })
+	st.ctxHandlers[path] = handlers
+
+	if len(handlers) == 0 {
+		st.webURLs = slices.DeleteFunc(st.webURLs, func(u string) bool { return u == path })
+		if h.link != "" {
+			st.artifactsURLs = slices.DeleteFunc(st.artifactsURLs, func(u string) bool { return u == h.link })
+		}
+	}
+}
+
 func AddWebHandler(path string, f func(w http.ResponseWriter, r *http.Request), opts ...HandlerOption) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -84,18 +186,26 @@ func AddWebHandler(path string, f func(w http.ResponseWriter, r *http.Request),
 		return errors.New("default http server not initialized")
 	}
 
-	if slices.Contains(st.webURLs, path) {
-		return fmt.Errorf("path %s already registered", path)
+	var link string
+	if hOptions.isArtifact {
+		link = path
+		if hOptions.artifactLinkPath != "" {
+			link = hOptions.artifactLinkPath
+		}
+	}
+
+	if hOptions.ctx != nil {
+		return addContextHandler(path, &contextHandler{ctx: hOptions.ctx, f: f, link: link})
+	}
+
+	if _, ok := st.ctxHandlers[path]; ok || slices.Contains(st.webURLs, path) {
+		return fmt.Errorf("path %s %w", path, ErrAlreadyRegistered)
 	}
 
 	st.httpServeMux.HandleFunc(path, f)
 
 	st.webURLs = append(st.webURLs, path)
-	if hOptions.isArtifact {
-		link := path
-		if hOptions.artifactLinkPath != "" {
-			link = hOptions.artifactLinkPath
-		}
+	if link != "" {
 		st.artifactsURLs = append(st.artifactsURLs, link)
 	}
 
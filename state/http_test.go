@@ -15,8 +15,12 @@
 package state
 
 import (
+	"context"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -120,3 +124,54 @@ func TestLinksAreReturnedAsCopies(t *testing.T) {
 	assert.Equal(t, []string{"/test-artifact"}, AllLinks())
 	assert.Equal(t, []string{"/custom-artifact"}, ArtifactsURLs())
 }
+
+func TestAddWebHandlerWithContext(t *testing.T) {
+	testMux := http.NewServeMux()
+	SetDefaultHTTPServeMux(testMux)
+	defer SetDefaultHTTPServeMux(nil)
+
+	get := func(path string) (int, string) {
+		w := httptest.NewRecorder()
+		testMux.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		b, _ := io.ReadAll(w.Result().Body)
+		return w.Code, string(b)
+	}
+	handler := func(body string) func(w http.ResponseWriter, r *http.Request) {
+		return func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(body)) }
+	}
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	assert.NoError(t, AddWebHandler("/metrics", handler("h1"), WithContext(ctx1), WithArtifactsLink("")))
+
+	// Replacement handler, h1 keeps serving till its context is canceled.
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	assert.NoError(t, AddWebHandler("/metrics", handler("h2"), WithContext(ctx2), WithArtifactsLink("")))
+	assert.Equal(t, []string{"/metrics"}, AllLinks())
+	_, body := get("/metrics")
+	assert.Equal(t, "h1", body)
+
+	cancel1()
+	_, body = get("/metrics")
+	assert.Equal(t, "h2", body)
+
+	// Once all the handlers are gone, path is not handled anymore.
+	cancel2()
+	assert.Eventually(t, func() bool { return len(AllLinks()) == 0 }, time.Second, time.Millisecond)
+	assert.False(t, IsHandled("/metrics"))
+	code, _ := get("/metrics")
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Empty(t, AllLinks())
+	assert.Empty(t, ArtifactsURLs())
+
+	// Path can be registered again, but only with a context.
+	err := AddWebHandler("/metrics", handler("h3"))
+	assert.ErrorIs(t, err, ErrAlreadyRegistered)
+	assert.NoError(t, AddWebHandler("/metrics", handler("h3"), WithContext(context.Background())))
+	_, body = get("/metrics")
+	assert.Equal(t, "h3", body)
+
+	// Context-bound handler can't take over a regular handler's path.
+	assert.NoError(t, AddWebHandler("/status", handler("status")))
+	err = AddWebHandler("/status", handler("h4"), WithContext(context.Background()))
+	assert.ErrorIs(t, err, ErrAlreadyRegistered)
+}
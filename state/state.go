@@ -39,6 +39,9 @@ type state struct {
 	configFilePath string
 	webURLs        []string
 	artifactsURLs  []string
+
+	// Handlers registered with the WithContext option, by path.
+	ctxHandlers map[string][]*contextHandler
 }
 
 var st state
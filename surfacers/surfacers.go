@@ -28,6 +28,7 @@ import (
 	"fmt"
 	"html/template"
 	"log/slog"
+	"slices"
 	"strings"
 	"sync"
 
@@ -164,6 +165,17 @@ type SurfacerInfo struct {
 	Name        string
 	SurfacerDef *surfacerpb.SurfacerDef
 	Conf        string
+
+	// Context the surfacer was created with, and its cancel func. The latter
+	// is used to stop the surfacer when it's removed on config reload.
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (si *SurfacerInfo) stop() {
+	if si.cancel != nil {
+		si.cancel()
+	}
 }
 
 // UnwrapSurfacer returns the underlying Surfacer, unwrapping the
@@ -287,18 +299,63 @@ func getExtensionSurfacer(p *surfacerpb.SurfacerDef) (Surfacer, any, error) {
 	return surfacer, value, nil
 }
 
-// Init initializes the surfacers from the config protobufs and returns them as
-// a list.
-func Init(ctx context.Context, sDefs []*surfacerpb.SurfacerDef) ([]*SurfacerInfo, error) {
+func newSurfacerInfo(ctx context.Context, sDef *surfacerpb.SurfacerDef, sType surfacerpb.Type) (*SurfacerInfo, error) {
+	// Each surfacer gets its own context, so that it can be stopped
+	// independently of other surfacers.
+	sctx, cancel := context.WithCancel(ctx)
+	s, err := initSurfacer(sctx, sDef, sType)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	return &SurfacerInfo{
+		Surfacer:    s,
+		Type:        sType.String(),
+		Name:        sDef.GetName(),
+		SurfacerDef: sDef,
+		Conf:        formatutils.ConfToString(sDef),
+		ctx:         sctx,
+		cancel:      cancel,
+	}, nil
+}
+
+// initSurfacers initializes surfacers for the given surfacer definitions. If
+// a surfacer in current has the same definition as one of the sDefs, it's
+// reused instead of creating a new one.
+func initSurfacers(ctx context.Context, sDefs []*surfacerpb.SurfacerDef, current []*SurfacerInfo) (result []*SurfacerInfo, err error) {
 	// If no surfacers are defined, return default surfacers. This behavior
 	// can be disabled by explicitly specifying "surfacer {}" in the config.
 	if len(sDefs) == 0 {
 		sDefs = defaultSurfacers
 	}
 
+	reused := make(map[*SurfacerInfo]bool)
+	getSurfacer := func(sDef *surfacerpb.SurfacerDef, sType surfacerpb.Type) (*SurfacerInfo, error) {
+		for _, si := range current {
+			if !reused[si] && si.Type == sType.String() && proto.Equal(si.SurfacerDef, sDef) {
+				reused[si] = true
+				return si, nil
+			}
+		}
+		return newSurfacerInfo(ctx, sDef, sType)
+	}
+
+	// Stop newly created surfacers if we run into an error.
+	defer func() {
+		if err == nil {
+			return
+		}
+		for _, si := range result {
+			if !reused[si] {
+				si.stop()
+			}
+		}
+		result = nil
+	}()
+
 	foundSurfacers := make(map[surfacerpb.Type]bool)
 
-	var result []*SurfacerInfo
 	for _, sDef := range sDefs {
 		sType := sDef.GetType()
 
@@ -314,40 +371,59 @@ func Init(ctx context.Context, sDefs []*surfacerpb.SurfacerDef) ([]*SurfacerInfo
 		}
 
 		if sType == surfacerpb.Type_PROBESTATUS && foundSurfacers[sType] {
-			return nil, fmt.Errorf("probestatus surfacer cannot be defined more than once")
+			return result, fmt.Errorf("probestatus surfacer cannot be defined more than once")
 		}
 
-		s, err := initSurfacer(ctx, sDef, sType)
+		si, err := getSurfacer(sDef, sType)
 		if err != nil {
-			return nil, err
+			return result, err
 		}
 
 		foundSurfacers[sType] = true
-
-		result = append(result, &SurfacerInfo{
-			Surfacer:    s,
-			Type:        sType.String(),
-			Name:        sDef.GetName(),
-			SurfacerDef: sDef,
-			Conf:        formatutils.ConfToString(sDef),
-		})
+		result = append(result, si)
 	}
 
 	for _, s := range requiredSurfacers {
 		if !foundSurfacers[s.GetType()] {
-			surfacer, err := initSurfacer(ctx, s, s.GetType())
+			si, err := getSurfacer(s, s.GetType())
 			if err != nil {
-				return nil, err
+				return result, err
 			}
-			result = append(result, &SurfacerInfo{
-				Surfacer: surfacer,
-				Type:     s.GetType().String(),
-			})
+			si.Conf = ""
+			result = append(result, si)
 		}
 	}
 	return result, nil
 }
 
+// Init initializes the surfacers from the config protobufs and returns them as
+// a list.
+func Init(ctx context.Context, sDefs []*surfacerpb.SurfacerDef) ([]*SurfacerInfo, error) {
+	return initSurfacers(ctx, sDefs, nil)
+}
+
+// Update returns the surfacers for the new surfacer definitions, for example,
+// on config reload. Surfacers in current whose definitions haven't changed
+// are carried over as is, and new surfacers are created for the new or
+// modified definitions. Update doesn't stop any surfacer, so that the caller
+// can still back out: once it switches to the returned surfacers, it should
+// stop the surfacers that are no longer needed using StopUnused. On error,
+// current surfacers are left untouched.
+func Update(ctx context.Context, current []*SurfacerInfo, sDefs []*surfacerpb.SurfacerDef) ([]*SurfacerInfo, error) {
+	return initSurfacers(ctx, sDefs, current)
+}
+
+// StopUnused stops the surfacers in old that are not in current. It's used to
+// stop the surfacers that are replaced after Update, or to discard the
+// surfacers returned by Update if the caller decides not to use them.
+func StopUnused(old, current []*SurfacerInfo) {
+	for _, si := range old {
+		if !slices.Contains(current, si) {
+			si.stop()
+		}
+	}
+}
+
 // Register allows you to register a user defined surfacer with cloudprober.
 // Example usage:
 //
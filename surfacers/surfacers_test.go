@@ -379,3 +379,39 @@ func TestExtensionSurfacer(t *testing.T) {
 		assert.Equal(t, em.String(), ts.received[i].String())
 	}
 }
+
+func TestUpdate(t *testing.T) {
+	state.SetDefaultHTTPServeMux(http.NewServeMux())
+
+	for _, name := range []string{"s1", "s2", "s3"} {
+		Register(name, &testSurfacer{})
+	}
+	sDef := func(name string) *surfacerpb.SurfacerDef {
+		return &surfacerpb.SurfacerDef{
+			Name: proto.String(name),
+			Type: surfacerpb.Type_USER_DEFINED.Enum(),
+		}
+	}
+
+	current, err := Init(context.Background(), []*surfacerpb.SurfacerDef{sDef("s1"), sDef("s2")})
+	assert.NoError(t, err)
+	assert.Len(t, current, 3) // Includes probestatus surfacer.
+
+	// Replace s2 with s3.
+	updated, err := Update(context.Background(), current, []*surfacerpb.SurfacerDef{sDef("s1"), sDef("s3")})
+	assert.NoError(t, err)
+	assert.Len(t, updated, 3)
+	assert.Same(t, current[0], updated[0], "s1 should be reused")
+	assert.Equal(t, "s3", updated[1].Name)
+	assert.Same(t, current[2], updated[2], "probestatus surfacer should be reused")
+
+	// Update doesn't stop anything, StopUnused stops the replaced s2.
+	assert.NoError(t, current[1].ctx.Err())
+	StopUnused(current, updated)
+	assert.Error(t, current[1].ctx.Err())
+	assert.NoError(t, updated[1].ctx.Err())
+
+	// Unregistered user-defined surfacer should result in an error.
+	_, err = Update(context.Background(), updated, []*surfacerpb.SurfacerDef{sDef("s1"), sDef("unknown")})
+	assert.Error(t, err)
+}
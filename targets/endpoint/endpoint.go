@@ -120,6 +120,18 @@ func (ep *Endpoint) Dst() string {
 	return net.JoinHostPort(ep.Name, strconv.Itoa(ep.Port))
 }
 
+// Host returns the host name to use for connecting to the endpoint. It's the
+// value of the "fqdn" label or the "__cp_host__" label (set for URL targets),
+// if available, and the endpoint name otherwise.
+func (ep *Endpoint) Host() string {
+	for _, label := range []string{"fqdn", "__cp_host__"} {
+		if ep.Labels[label] != "" {
+			return ep.Labels[label]
+		}
+	}
+	return ep.Name
+}
+
 type resolverOptions struct {
 	nameOverride string
 }
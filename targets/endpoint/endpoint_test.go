@@ -126,6 +126,31 @@ func TestEndpointDst(t *testing.T) {
 	}
 }
 
+func TestEndpointHost(t *testing.T) {
+	tests := []struct {
+		ep   Endpoint
+		want string
+	}{
+		{
+			ep:   Endpoint{Name: "ep-1"},
+			want: "ep-1",
+		},
+		{
+			ep:   Endpoint{Name: "ep-2", Labels: map[string]string{"fqdn": "ep-2.example.com"}},
+			want: "ep-2.example.com",
+		},
+		{
+			ep:   Endpoint{Name: "https://ep-3.example.com/status", Labels: map[string]string{"__cp_host__": "ep-3.example.com"}},
+			want: "ep-3.example.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ep.Name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.ep.Host())
+		})
+	}
+}
+
 func TestParseURL(t *testing.T) {
 	type parts struct {
 		scheme, host, path string
@@ -9,10 +9,6 @@
 package proto
 
 import (
-	reflect "reflect"
-	sync "sync"
-	unsafe "unsafe"
-
 	proto "github.com/cloudprober/cloudprober/internal/rds/client/proto"
 	proto1 "github.com/cloudprober/cloudprober/internal/rds/proto"
 	proto2 "github.com/cloudprober/cloudprober/targets/endpoint/proto"
@@ -21,6 +17,9 @@ import (
 	proto5 "github.com/cloudprober/cloudprober/targets/lameduck/proto"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
 )
 
 const (
@@ -432,7 +431,20 @@ type TargetsDef struct {
 	// probe scheduler's target update interval — how often new targets start
 	// being probed and removed targets stop being probed. If not set, defaults
 	// to 1 minute.
-	ReEvalSec       *int32 `protobuf:"varint,32,opt,name=re_eval_sec,json=reEvalSec" json:"re_eval_sec,omitempty"`
+	ReEvalSec *int32 `protobuf:"varint,32,opt,name=re_eval_sec,json=reEvalSec" json:"re_eval_sec,omitempty"`
+	// Sharding splits targets among a pool of Cloudprober instances running the
+	// same configuration, so that each target is probed by only one instance.
+	// Example:
+	//
+	//	sharding {
+	//	  peers {
+	//	    k8s {
+	//	      namespace: "monitoring"
+	//	      endpoints: "cloudprober"
+	//	    }
+	//	  }
+	//	}
+	Sharding        *ShardingOptions `protobuf:"bytes,33,opt,name=sharding" json:"sharding,omitempty"`
 	extensionFields protoimpl.ExtensionFields
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
@@ -585,6 +597,13 @@ func (x *TargetsDef) GetReEvalSec() int32 {
 	return 0
 }
 
+func (x *TargetsDef) GetSharding() *ShardingOptions {
+	if x != nil {
+		return x.Sharding
+	}
+	return nil
+}
+
 type isTargetsDef_Type interface {
 	isTargetsDef_Type()
 }
@@ -683,6 +702,93 @@ func (*TargetsDef_K8S) isTargetsDef_Type() {}
 
 func (*TargetsDef_DummyTargets) isTargetsDef_Type() {}
 
+// ShardingOptions configure how targets are split among a pool of Cloudprober
+// instances. Targets are assigned to shards using consistent hashing on the
+// target name and port, so only a small fraction of the targets move when the
+// pool size changes.
+//
+// There are two ways to configure sharding:
+//   - Static: shard_index and shard_count. If not set here, these are taken
+//     from the --targets_shard_index and --targets_shard_count flags, or from
+//     the "shard_index" and "shard_count" sysvars.
+//   - Dynamic: peers. Peers are discovered using a targets definition, e.g.
+//     k8s endpoints or file targets, and targets are rebalanced automatically
+//     whenever the peer set changes.
+type ShardingOptions struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Shard index of this instance, between 0 and shard_count-1.
+	ShardIndex *int32 `protobuf:"varint,1,opt,name=shard_index,json=shardIndex" json:"shard_index,omitempty"`
+	// Total number of shards.
+	ShardCount *int32 `protobuf:"varint,2,opt,name=shard_count,json=shardCount" json:"shard_count,omitempty"`
+	// Peers (Cloudprober instances) to split targets among. If this instance
+	// doesn't show up in the peers list (e.g. while it's still being
+	// discovered), it's added to the list implicitly.
+	Peers *TargetsDef `protobuf:"bytes,3,opt,name=peers" json:"peers,omitempty"`
+	// Name of this instance in the peers list. A peer matches this instance if
+	// its name or IP address is equal to this field. Default is the hostname.
+	Self          *string `protobuf:"bytes,4,opt,name=self" json:"self,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShardingOptions) Reset() {
+	*x = ShardingOptions{}
+	mi := &file_github_com_cloudprober_cloudprober_targets_proto_targets_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShardingOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShardingOptions) ProtoMessage() {}
+
+func (x *ShardingOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_cloudprober_cloudprober_targets_proto_targets_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShardingOptions.ProtoReflect.Descriptor instead.
+func (*ShardingOptions) Descriptor() ([]byte, []int) {
+	return file_github_com_cloudprober_cloudprober_targets_proto_targets_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ShardingOptions) GetShardIndex() int32 {
+	if x != nil && x.ShardIndex != nil {
+		return *x.ShardIndex
+	}
+	return 0
+}
+
+func (x *ShardingOptions) GetShardCount() int32 {
+	if x != nil && x.ShardCount != nil {
+		return *x.ShardCount
+	}
+	return 0
+}
+
+func (x *ShardingOptions) GetPeers() *TargetsDef {
+	if x != nil {
+		return x.Peers
+	}
+	return nil
+}
+
+func (x *ShardingOptions) GetSelf() string {
+	if x != nil && x.Self != nil {
+		return *x.Self
+	}
+	return ""
+}
+
 // DummyTargets represent empty targets, which are useful for external
 // probes that do not have any "proper" targets.  Such as ilbprober.
 type DummyTargets struct {
@@ -693,7 +799,7 @@ type DummyTargets struct {
 
 func (x *DummyTargets) Reset() {
 	*x = DummyTargets{}
-	mi := &file_github_com_cloudprober_cloudprober_targets_proto_targets_proto_msgTypes[4]
+	mi := &file_github_com_cloudprober_cloudprober_targets_proto_targets_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -705,7 +811,7 @@ func (x *DummyTargets) String() string {
 func (*DummyTargets) ProtoMessage() {}
 
 func (x *DummyTargets) ProtoReflect() protoreflect.Message {
-	mi := &file_github_com_cloudprober_cloudprober_targets_proto_targets_proto_msgTypes[4]
+	mi := &file_github_com_cloudprober_cloudprober_targets_proto_targets_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -718,7 +824,7 @@ func (x *DummyTargets) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DummyTargets.ProtoReflect.Descriptor instead.
 func (*DummyTargets) Descriptor() ([]byte, []int) {
-	return file_github_com_cloudprober_cloudprober_targets_proto_targets_proto_rawDescGZIP(), []int{4}
+	return file_github_com_cloudprober_cloudprober_targets_proto_targets_proto_rawDescGZIP(), []int{5}
 }
 
 // Global targets options. These options are independent of the per-probe
@@ -755,7 +861,7 @@ type GlobalTargetsOptions struct {
 
 func (x *GlobalTargetsOptions) Reset() {
 	*x = GlobalTargetsOptions{}
-	mi := &file_github_com_cloudprober_cloudprober_targets_proto_targets_proto_msgTypes[5]
+	mi := &file_github_com_cloudprober_cloudprober_targets_proto_targets_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -767,7 +873,7 @@ func (x *GlobalTargetsOptions) String() string {
 func (*GlobalTargetsOptions) ProtoMessage() {}
 
 func (x *GlobalTargetsOptions) ProtoReflect() protoreflect.Message {
-	mi := &file_github_com_cloudprober_cloudprober_targets_proto_targets_proto_msgTypes[5]
+	mi := &file_github_com_cloudprober_cloudprober_targets_proto_targets_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -780,7 +886,7 @@ func (x *GlobalTargetsOptions) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GlobalTargetsOptions.ProtoReflect.Descriptor instead.
 func (*GlobalTargetsOptions) Descriptor() ([]byte, []int) {
-	return file_github_com_cloudprober_cloudprober_targets_proto_targets_proto_rawDescGZIP(), []int{5}
+	return file_github_com_cloudprober_cloudprober_targets_proto_targets_proto_rawDescGZIP(), []int{6}
 }
 
 // Deprecated: Marked as deprecated in github.com/cloudprober/cloudprober/targets/proto/targets.proto.
@@ -843,7 +949,7 @@ const file_github_com_cloudprober_cloudprober_targets_proto_targets_proto_rawDes
 	"\x06server\x18\x01 \x01(\tR\x06server\x12\x1c\n" +
 	"\attl_sec\x18\x02 \x01(\x05:\x03300R\x06ttlSec\x12)\n" +
 	"\x11max_cache_age_sec\x18\x03 \x01(\x05R\x0emaxCacheAgeSec\x126\n" +
-	"\x14backend_timeout_msec\x18\x04 \x01(\x05:\x045000R\x12backendTimeoutMsec\"\xc6\x05\n" +
+	"\x14backend_timeout_msec\x18\x04 \x01(\x05:\x045000R\x12backendTimeoutMsec\"\x88\x06\n" +
 	"\n" +
 	"TargetsDef\x12\x1f\n" +
 	"\n" +
@@ -863,8 +969,16 @@ const file_github_com_cloudprober_cloudprober_targets_proto_targets_proto_rawDes
 	"dnsOptions\x12\x1d\n" +
 	"\n" +
 	"dns_server\x18\x1f \x01(\tR\tdnsServer\x12\x1e\n" +
-	"\vre_eval_sec\x18  \x01(\x05R\treEvalSec*\t\b\xc8\x01\x10\x80\x80\x80\x80\x02B\x06\n" +
-	"\x04type\"\x0e\n" +
+	"\vre_eval_sec\x18  \x01(\x05R\treEvalSec\x12@\n" +
+	"\bsharding\x18! \x01(\v2$.cloudprober.targets.ShardingOptionsR\bsharding*\t\b\xc8\x01\x10\x80\x80\x80\x80\x02B\x06\n" +
+	"\x04type\"\x9e\x01\n" +
+	"\x0fShardingOptions\x12\x1f\n" +
+	"\vshard_index\x18\x01 \x01(\x05R\n" +
+	"shardIndex\x12\x1f\n" +
+	"\vshard_count\x18\x02 \x01(\x05R\n" +
+	"shardCount\x125\n" +
+	"\x05peers\x18\x03 \x01(\v2\x1f.cloudprober.targets.TargetsDefR\x05peers\x12\x12\n" +
+	"\x04self\x18\x04 \x01(\tR\x04self\"\x0e\n" +
 	"\fDummyTargets\"\xd9\x02\n" +
 	"\x14GlobalTargetsOptions\x120\n" +
 	"\x12rds_server_address\x18\x03 \x01(\tB\x02\x18\x01R\x10rdsServerAddress\x12W\n" +
@@ -884,43 +998,46 @@ func file_github_com_cloudprober_cloudprober_targets_proto_targets_proto_rawDesc
 	return file_github_com_cloudprober_cloudprober_targets_proto_targets_proto_rawDescData
 }
 
-var file_github_com_cloudprober_cloudprober_targets_proto_targets_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_github_com_cloudprober_cloudprober_targets_proto_targets_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_github_com_cloudprober_cloudprober_targets_proto_targets_proto_goTypes = []any{
 	(*RDSTargets)(nil),                     // 0: cloudprober.targets.RDSTargets
 	(*K8STargets)(nil),                     // 1: cloudprober.targets.K8sTargets
 	(*DNSOptions)(nil),                     // 2: cloudprober.targets.DNSOptions
 	(*TargetsDef)(nil),                     // 3: cloudprober.targets.TargetsDef
-	(*DummyTargets)(nil),                   // 4: cloudprober.targets.DummyTargets
-	(*GlobalTargetsOptions)(nil),           // 5: cloudprober.targets.GlobalTargetsOptions
-	(*proto.ClientConf_ServerOptions)(nil), // 6: cloudprober.rds.ClientConf.ServerOptions
-	(*proto1.Filter)(nil),                  // 7: cloudprober.rds.Filter
-	(*proto1.IPConfig)(nil),                // 8: cloudprober.rds.IPConfig
-	(*proto3.TargetsConf)(nil),             // 9: cloudprober.targets.gce.TargetsConf
-	(*proto4.TargetsConf)(nil),             // 10: cloudprober.targets.file.TargetsConf
-	(*proto2.Endpoint)(nil),                // 11: cloudprober.targets.Endpoint
-	(*proto3.GlobalOptions)(nil),           // 12: cloudprober.targets.gce.GlobalOptions
-	(*proto5.Options)(nil),                 // 13: cloudprober.targets.lameduck.Options
+	(*ShardingOptions)(nil),                // 4: cloudprober.targets.ShardingOptions
+	(*DummyTargets)(nil),                   // 5: cloudprober.targets.DummyTargets
+	(*GlobalTargetsOptions)(nil),           // 6: cloudprober.targets.GlobalTargetsOptions
+	(*proto.ClientConf_ServerOptions)(nil), // 7: cloudprober.rds.ClientConf.ServerOptions
+	(*proto1.Filter)(nil),                  // 8: cloudprober.rds.Filter
+	(*proto1.IPConfig)(nil),                // 9: cloudprober.rds.IPConfig
+	(*proto3.TargetsConf)(nil),             // 10: cloudprober.targets.gce.TargetsConf
+	(*proto4.TargetsConf)(nil),             // 11: cloudprober.targets.file.TargetsConf
+	(*proto2.Endpoint)(nil),                // 12: cloudprober.targets.Endpoint
+	(*proto3.GlobalOptions)(nil),           // 13: cloudprober.targets.gce.GlobalOptions
+	(*proto5.Options)(nil),                 // 14: cloudprober.targets.lameduck.Options
 }
 var file_github_com_cloudprober_cloudprober_targets_proto_targets_proto_depIdxs = []int32{
-	6,  // 0: cloudprober.targets.RDSTargets.rds_server_options:type_name -> cloudprober.rds.ClientConf.ServerOptions
-	7,  // 1: cloudprober.targets.RDSTargets.filter:type_name -> cloudprober.rds.Filter
-	8,  // 2: cloudprober.targets.RDSTargets.ip_config:type_name -> cloudprober.rds.IPConfig
-	6,  // 3: cloudprober.targets.K8sTargets.rds_server_options:type_name -> cloudprober.rds.ClientConf.ServerOptions
-	9,  // 4: cloudprober.targets.TargetsDef.gce_targets:type_name -> cloudprober.targets.gce.TargetsConf
+	7,  // 0: cloudprober.targets.RDSTargets.rds_server_options:type_name -> cloudprober.rds.ClientConf.ServerOptions
+	8,  // 1: cloudprober.targets.RDSTargets.filter:type_name -> cloudprober.rds.Filter
+	9,  // 2: cloudprober.targets.RDSTargets.ip_config:type_name -> cloudprober.rds.IPConfig
+	7,  // 3: cloudprober.targets.K8sTargets.rds_server_options:type_name -> cloudprober.rds.ClientConf.ServerOptions
+	10, // 4: cloudprober.targets.TargetsDef.gce_targets:type_name -> cloudprober.targets.gce.TargetsConf
 	0,  // 5: cloudprober.targets.TargetsDef.rds_targets:type_name -> cloudprober.targets.RDSTargets
-	10, // 6: cloudprober.targets.TargetsDef.file_targets:type_name -> cloudprober.targets.file.TargetsConf
+	11, // 6: cloudprober.targets.TargetsDef.file_targets:type_name -> cloudprober.targets.file.TargetsConf
 	1,  // 7: cloudprober.targets.TargetsDef.k8s:type_name -> cloudprober.targets.K8sTargets
-	4,  // 8: cloudprober.targets.TargetsDef.dummy_targets:type_name -> cloudprober.targets.DummyTargets
-	11, // 9: cloudprober.targets.TargetsDef.endpoint:type_name -> cloudprober.targets.Endpoint
+	5,  // 8: cloudprober.targets.TargetsDef.dummy_targets:type_name -> cloudprober.targets.DummyTargets
+	12, // 9: cloudprober.targets.TargetsDef.endpoint:type_name -> cloudprober.targets.Endpoint
 	2,  // 10: cloudprober.targets.TargetsDef.dns_options:type_name -> cloudprober.targets.DNSOptions
-	6,  // 11: cloudprober.targets.GlobalTargetsOptions.rds_server_options:type_name -> cloudprober.rds.ClientConf.ServerOptions
-	12, // 12: cloudprober.targets.GlobalTargetsOptions.global_gce_targets_options:type_name -> cloudprober.targets.gce.GlobalOptions
-	13, // 13: cloudprober.targets.GlobalTargetsOptions.lame_duck_options:type_name -> cloudprober.targets.lameduck.Options
-	14, // [14:14] is the sub-list for method output_type
-	14, // [14:14] is the sub-list for method input_type
-	14, // [14:14] is the sub-list for extension type_name
-	14, // [14:14] is the sub-list for extension extendee
-	0,  // [0:14] is the sub-list for field type_name
+	4,  // 11: cloudprober.targets.TargetsDef.sharding:type_name -> cloudprober.targets.ShardingOptions
+	3,  // 12: cloudprober.targets.ShardingOptions.peers:type_name -> cloudprober.targets.TargetsDef
+	7,  // 13: cloudprober.targets.GlobalTargetsOptions.rds_server_options:type_name -> cloudprober.rds.ClientConf.ServerOptions
+	13, // 14: cloudprober.targets.GlobalTargetsOptions.global_gce_targets_options:type_name -> cloudprober.targets.gce.GlobalOptions
+	14, // 15: cloudprober.targets.GlobalTargetsOptions.lame_duck_options:type_name -> cloudprober.targets.lameduck.Options
+	16, // [16:16] is the sub-list for method output_type
+	16, // [16:16] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
 }
 
 func init() { file_github_com_cloudprober_cloudprober_targets_proto_targets_proto_init() }
@@ -949,7 +1066,7 @@ func file_github_com_cloudprober_cloudprober_targets_proto_targets_proto_init()
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_github_com_cloudprober_cloudprober_targets_proto_targets_proto_rawDesc), len(file_github_com_cloudprober_cloudprober_targets_proto_targets_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,211 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package targets
+
+import (
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cloudprober/cloudprober/internal/sysvars"
+	"github.com/cloudprober/cloudprober/logger"
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+	targetspb "github.com/cloudprober/cloudprober/targets/proto"
+)
+
+var (
+	shardIndexFlag = flag.Int("targets_shard_index", -1, "Shard index of this Cloudprober instance, used by the targets that enable sharding but don't specify shard_index.")
+	shardCountFlag = flag.Int("targets_shard_count", -1, "Total number of shards, used by the targets that enable sharding but don't specify shard_count.")
+)
+
+// sharder filters endpoints so that only the endpoints that belong to the
+// local shard are kept.
+type sharder struct {
+	// Static sharding.
+	index, count int
+
+	// Dynamic, peers based sharding.
+	peers endpoint.Lister
+	self  string
+
+	mu        sync.Mutex
+	lastPeers string
+	l         *logger.Logger
+}
+
+// shardKey returns the key used to assign an endpoint to a shard. We don't
+// use endpoint labels in the key so that endpoints don't move between shards
+// when their labels change.
+func shardKey(ep *endpoint.Endpoint) string {
+	return ep.Name + ":" + strconv.Itoa(ep.Port)
+}
+
+func hash64(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+// jumpHash implements the "jump consistent hash" algorithm (Lamping and Veach,
+// https://arxiv.org/abs/1406.2294). It maps a key to a bucket in [0, n) in
+// such a way that when n changes only 1/n of the keys move.
+func jumpHash(key uint64, n int) int {
+	var b, j int64 = -1, 0
+	for j < int64(n) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
+
+// rendezvousOwner returns the peer that owns the key, using rendezvous
+// (highest random weight) hashing. When a peer is added or removed, only the
+// keys owned by that peer move.
+func rendezvousOwner(key string, peers []string) string {
+	var owner string
+	var maxWeight uint64
+	for _, p := range peers {
+		if w := hash64(p + "/" + key); owner == "" || w > maxWeight {
+			owner, maxWeight = p, w
+		}
+	}
+	return owner
+}
+
+// shardingValue returns the shard index or count, looking at the config
+// first, then at the flag and then at the sysvar. It returns -1 if value is
+// not found anywhere.
+func shardingValue(confVal *int32, flagVal int, sysvar string) (int, error) {
+	if confVal != nil {
+		return int(*confVal), nil
+	}
+	if flagVal >= 0 {
+		return flagVal, nil
+	}
+	if v := sysvars.Vars()[sysvar]; v != "" {
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s sysvar (%s): %v", sysvar, v, err)
+		}
+		return i, nil
+	}
+	return -1, nil
+}
+
+func newSharder(opts *targetspb.ShardingOptions, globalOpts *targetspb.GlobalTargetsOptions, globalLogger, l *logger.Logger) (*sharder, error) {
+	s := &sharder{l: l}
+
+	if opts.GetPeers() != nil {
+		if opts.ShardIndex != nil || opts.ShardCount != nil {
+			return nil, fmt.Errorf("shard_index and shard_count cannot be used with peers")
+		}
+		peers, err := New(opts.GetPeers(), nil, globalOpts, globalLogger, l)
+		if err != nil {
+			return nil, fmt.Errorf("error creating sharding peers: %v", err)
+		}
+		s.peers = peers
+
+		s.self = opts.GetSelf()
+		if s.self == "" {
+			s.self = sysvars.Vars()["hostname"]
+		}
+		if s.self == "" {
+			if s.self, err = os.Hostname(); err != nil {
+				return nil, fmt.Errorf("error getting hostname for sharding: %v", err)
+			}
+		}
+		return s, nil
+	}
+
+	var err error
+	if s.index, err = shardingValue(opts.ShardIndex, *shardIndexFlag, "shard_index"); err != nil {
+		return nil, err
+	}
+	if s.count, err = shardingValue(opts.ShardCount, *shardCountFlag, "shard_count"); err != nil {
+		return nil, err
+	}
+	if s.count < 1 {
+		return nil, fmt.Errorf("shard count not specified, or is less than 1 (%d)", s.count)
+	}
+	if s.index < 0 || s.index >= s.count {
+		return nil, fmt.Errorf("shard index not specified, or is not in [0, %d) range (%d)", s.count, s.index)
+	}
+	return s, nil
+}
+
+// peerNames returns the sorted list of peer names and the name under which
+// this instance appears in that list. Peers are always listed under their
+// endpoint names, so that all instances compute the same list. If self
+// matches a peer by IP, that peer's name is used as self; if self doesn't
+// match any peer, it's added to the list. It also logs if the peer set has
+// changed since the last call.
+func (s *sharder) peerNames() ([]string, string) {
+	var names []string
+	self, foundSelf := s.self, false
+	for _, ep := range s.peers.ListEndpoints() {
+		if !foundSelf && (ep.Name == s.self || (ep.IP != nil && ep.IP.String() == s.self)) {
+			self, foundSelf = ep.Name, true
+		}
+		names = append(names, ep.Name)
+	}
+	if !foundSelf {
+		names = append(names, self)
+	}
+	sort.Strings(names)
+	names = slices.Compact(names)
+
+	peersStr := strings.Join(names, ",")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if peersStr != s.lastPeers {
+		s.l.Infof("sharding: peer set changed, rebalancing targets among %d peers: %s", len(names), peersStr)
+		s.lastPeers = peersStr
+	}
+	return names, self
+}
+
+// filter returns the endpoints that belong to this shard.
+func (s *sharder) filter(eps []endpoint.Endpoint) []endpoint.Endpoint {
+	var peers []string
+	var self string
+	if s.peers != nil {
+		peers, self = s.peerNames()
+		if len(peers) == 1 {
+			return eps
+		}
+	}
+
+	var result []endpoint.Endpoint
+	for _, ep := range eps {
+		key := shardKey(&ep)
+		if peers != nil {
+			if rendezvousOwner(key, peers) == self {
+				result = append(result, ep)
+			}
+			continue
+		}
+		if jumpHash(hash64(key), s.count) == s.index {
+			result = append(result, ep)
+		}
+	}
+	return result
+}
@@ -0,0 +1,240 @@
+// Copyright 2026 The Cloudprober Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package targets
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/cloudprober/cloudprober/targets/endpoint"
+	targetspb "github.com/cloudprober/cloudprober/targets/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func testShardingEndpoints(n int) []endpoint.Endpoint {
+	var names []string
+	for i := 0; i < n; i++ {
+		names = append(names, fmt.Sprintf("host-%d", i))
+	}
+	return endpoint.EndpointsFromNames(names)
+}
+
+func TestJumpHash(t *testing.T) {
+	// Verify that going from n to n+1 buckets, keys only move to the new
+	// bucket.
+	for i := 0; i < 1000; i++ {
+		key := hash64(fmt.Sprintf("key-%d", i))
+		for n := 1; n < 10; n++ {
+			b1, b2 := jumpHash(key, n), jumpHash(key, n+1)
+			assert.True(t, b1 >= 0 && b1 < n)
+			if b1 != b2 {
+				assert.Equal(t, n, b2, "key moved to an old bucket")
+			}
+		}
+	}
+}
+
+func TestStaticSharding(t *testing.T) {
+	eps := testShardingEndpoints(1000)
+
+	seen := make(map[string]int)
+	for i := 0; i < 4; i++ {
+		s, err := newSharder(&targetspb.ShardingOptions{
+			ShardIndex: proto.Int32(int32(i)),
+			ShardCount: proto.Int32(4),
+		}, nil, nil, nil)
+		assert.NoError(t, err)
+
+		got := s.filter(eps)
+		assert.InDelta(t, 250, len(got), 60, "shard %d is unbalanced", i)
+		for _, ep := range got {
+			seen[ep.Name]++
+		}
+	}
+
+	// Every endpoint should be in exactly one shard.
+	assert.Len(t, seen, len(eps))
+	for name, count := range seen {
+		assert.Equal(t, 1, count, "endpoint %s in %d shards", name, count)
+	}
+}
+
+func TestNewSharder(t *testing.T) {
+	oldIndex, oldCount := *shardIndexFlag, *shardCountFlag
+	defer func() { *shardIndexFlag, *shardCountFlag = oldIndex, oldCount }()
+
+	tests := []struct {
+		name       string
+		opts       *targetspb.ShardingOptions
+		flagIndex  int
+		flagCount  int
+		wantIndex  int
+		wantCount  int
+		wantErrStr string
+	}{
+		{
+			name:       "no_count",
+			opts:       &targetspb.ShardingOptions{ShardIndex: proto.Int32(0)},
+			flagIndex:  -1,
+			flagCount:  -1,
+			wantErrStr: "shard count",
+		},
+		{
+			name:       "bad_index",
+			opts:       &targetspb.ShardingOptions{ShardIndex: proto.Int32(3), ShardCount: proto.Int32(3)},
+			flagIndex:  -1,
+			flagCount:  -1,
+			wantErrStr: "shard index",
+		},
+		{
+			name:      "from_flags",
+			opts:      &targetspb.ShardingOptions{},
+			flagIndex: 2,
+			flagCount: 5,
+			wantIndex: 2,
+			wantCount: 5,
+		},
+		{
+			name:      "config_overrides_flags",
+			opts:      &targetspb.ShardingOptions{ShardIndex: proto.Int32(1)},
+			flagIndex: 2,
+			flagCount: 5,
+			wantIndex: 1,
+			wantCount: 5,
+		},
+		{
+			name: "peers_and_index",
+			opts: &targetspb.ShardingOptions{
+				ShardIndex: proto.Int32(1),
+				Peers:      &targetspb.TargetsDef{Type: &targetspb.TargetsDef_HostNames{HostNames: "p1,p2"}},
+			},
+			flagIndex:  -1,
+			flagCount:  -1,
+			wantErrStr: "cannot be used with peers",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*shardIndexFlag, *shardCountFlag = tt.flagIndex, tt.flagCount
+			s, err := newSharder(tt.opts, nil, nil, nil)
+			if tt.wantErrStr != "" {
+				assert.ErrorContains(t, err, tt.wantErrStr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantIndex, s.index)
+			assert.Equal(t, tt.wantCount, s.count)
+		})
+	}
+}
+
+func TestPeersSharding(t *testing.T) {
+	eps := testShardingEndpoints(1000)
+	peers := &mockLister{}
+
+	sharders := make(map[string]*sharder)
+	for _, name := range []string{"p1", "p2", "p3", "p4"} {
+		sharders[name] = &sharder{peers: peers, self: name}
+	}
+
+	assignment := func(selves ...string) map[string]string {
+		owner := make(map[string]string)
+		for _, self := range selves {
+			for _, ep := range sharders[self].filter(eps) {
+				assert.Empty(t, owner[ep.Name], "%s assigned to multiple peers", ep.Name)
+				owner[ep.Name] = self
+			}
+		}
+		assert.Len(t, owner, len(eps))
+		return owner
+	}
+
+	peers.list = endpoint.EndpointsFromNames([]string{"p1", "p2", "p3"})
+	before := assignment("p1", "p2", "p3")
+
+	// Add a peer, only the targets that move to the new peer should move.
+	peers.list = endpoint.EndpointsFromNames([]string{"p1", "p2", "p3", "p4"})
+	after := assignment("p1", "p2", "p3", "p4")
+	moved := 0
+	for name, owner := range after {
+		if owner != before[name] {
+			assert.Equal(t, "p4", owner)
+			moved++
+		}
+	}
+	assert.InDelta(t, 250, moved, 60)
+
+	// Remove a peer, only its targets should move.
+	peers.list = endpoint.EndpointsFromNames([]string{"p1", "p3", "p4"})
+	afterRemove := assignment("p1", "p3", "p4")
+	for name, owner := range after {
+		if owner != "p2" {
+			assert.Equal(t, owner, afterRemove[name])
+		}
+	}
+	assert.Equal(t, "p1,p3,p4", sharders["p1"].lastPeers)
+}
+
+func TestPeersShardingSelf(t *testing.T) {
+	eps := testShardingEndpoints(100)
+
+	// Self not in peers list yet, it should be added implicitly.
+	s := &sharder{peers: &mockLister{}, self: "p1"}
+	assert.Len(t, s.filter(eps), 100)
+
+	// Self matched by IP: peer is still listed under its name, so that all
+	// replicas compute the same peer list.
+	peers := &mockLister{list: []endpoint.Endpoint{
+		{Name: "pod-a", IP: net.ParseIP("10.0.0.1")},
+		{Name: "pod-b", IP: net.ParseIP("10.0.0.2")},
+	}}
+	owner := make(map[string]string)
+	for _, self := range []string{"10.0.0.1", "10.0.0.2"} {
+		s = &sharder{peers: peers, self: self}
+		got := s.filter(eps)
+		assert.Less(t, len(got), 100)
+		assert.Equal(t, "pod-a,pod-b", s.lastPeers)
+		for _, ep := range got {
+			assert.Empty(t, owner[ep.Name], "%s assigned to multiple peers", ep.Name)
+			owner[ep.Name] = self
+		}
+	}
+	assert.Len(t, owner, len(eps))
+}
+
+func TestNewWithSharding(t *testing.T) {
+	var names []string
+	for _, ep := range testShardingEndpoints(20) {
+		names = append(names, ep.Name)
+	}
+
+	total := 0
+	for i := 0; i < 2; i++ {
+		tgts, err := New(&targetspb.TargetsDef{
+			Type: &targetspb.TargetsDef_HostNames{HostNames: strings.Join(names, ",")},
+			Sharding: &targetspb.ShardingOptions{
+				ShardIndex: proto.Int32(int32(i)),
+				ShardCount: proto.Int32(2),
+			},
+		}, nil, nil, nil, nil)
+		assert.NoError(t, err)
+		total += len(tgts.ListEndpoints())
+	}
+	assert.Equal(t, 20, total)
+}
@@ -124,14 +124,15 @@ func (d *dummy) Resolve(name string, ipVer int) (net.IP, error) {
 
 // targets is the main implementation of the Targets interface, composed of a core
 // lister and resolver. Essentially it provides a wrapper around the core lister,
-// providing various filtering options. Currently filtering by regex and lameduck
-// is supported.
+// providing various filtering options. Currently filtering by regex, lameduck
+// and sharding is supported.
 type targets struct {
 	lister          endpoint.Lister
 	resolver        endpoint.Resolver
 	staticEndpoints []endpoint.Endpoint
 	re              *regexp.Regexp
 	ldLister        endpoint.Lister
+	sharder         *sharder
 	l               *logger.Logger
 }
 
@@ -185,7 +186,9 @@ func (t *targets) includeInResult(ep endpoint.Endpoint, ldMap map[string]endpoin
 // consists of a name and associated metadata like port and target labels.
 //
 // It gets the list of targets from the configured targets type, filters them
-// by the configured regex, excludes lame ducks and returns the resultant list.
+// by the configured regex, excludes lame ducks, keeps only the targets that
+// belong to the local shard (if sharding is enabled) and returns the resultant
+// list.
 //
 // This method should be concurrency safe as it doesn't modify any shared
 // variables and doesn't rely on multiple accesses to same variable being
@@ -216,6 +219,10 @@ func (t *targets) ListEndpoints() []endpoint.Endpoint {
 		list = result
 	}
 
+	if t.sharder != nil {
+		list = t.sharder.filter(list)
+	}
+
 	return list
 }
 
@@ -408,6 +415,12 @@ func New(targetsDef *targetspb.TargetsDef, ldLister endpoint.Lister, globalOpts
 		return nil, fmt.Errorf("targets.New(): no targets type specified and no static endpoints")
 	}
 
+	if targetsDef.GetSharding() != nil {
+		if t.sharder, err = newSharder(targetsDef.GetSharding(), globalOpts, globalLogger, t.l); err != nil {
+			return nil, fmt.Errorf("targets.New(): error configuring sharding: %v", err)
+		}
+	}
+
 	return t, nil
 }
 
@@ -30,6 +30,7 @@ import (
 	"github.com/cloudprober/cloudprober/internal/servers"
 	"github.com/cloudprober/cloudprober/logger"
 	"github.com/cloudprober/cloudprober/probes"
+	"github.com/cloudprober/cloudprober/probes/common/failedresponses"
 	"github.com/cloudprober/cloudprober/state"
 	"github.com/cloudprober/cloudprober/surfacers"
 	"github.com/cloudprober/cloudprober/web/resources"
@@ -162,6 +163,17 @@ func InitWithDataFuncs(fn DataFuncs) error {
 		return err
 	}
 
+	if err := state.AddWebHandler(failedresponses.URL, func(w http.ResponseWriter, r *http.Request) {
+		status, err := failedresponses.StatusHTML(r.URL.Query()["probe"], r.URL.Query()["target"])
+		if err != nil {
+			w.Write([]byte(resources.RenderPage(failedresponses.URL, template.HTML(template.HTMLEscapeString(err.Error())))))
+			return
+		}
+		w.Write([]byte(resources.RenderPage(failedresponses.URL, template.HTML(status))))
+	}); err != nil {
+		return err
+	}
+
 	if err := state.AddWebHandler(urlMap.Links, func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte(resources.LinksPage(urlMap.Links, "All Links", allLinksPageLinks(state.AllLinks()))))
 	}); err != nil {